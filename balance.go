@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"stellar-siege/game"
+	"stellar-siege/game/core"
+)
+
+// runBalance implements the "balance" subcommand. It plays seeded headless
+// games with the autopilot on each difficulty and writes per-wave statistics
+// as CSV tables and a JSON report.
+func runBalance(args []string) int {
+	fs := flag.NewFlagSet("balance", flag.ExitOnError)
+	runs := fs.Int("runs", 20, "number of runs per difficulty")
+	difficulties := fs.String("difficulty", "all", "difficulties to simulate: all, or a comma-separated list of easy, normal, hard")
+	skillName := fs.String("skill", core.BotSkillExpert.Name, "bot skill: novice, average or expert")
+	seed := fs.Int64("seed", 1, "seed of the first run; run i uses seed+i")
+	minutes := fs.Float64("minutes", 30, "stop runs that survive this many minutes of game time")
+	outDir := fs.String("out", "balance", "directory the reports are written to")
+	quiet := fs.Bool("q", false, "do not print progress")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: stellar-siege balance [flags]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	skill, ok := core.BotSkillByName(*skillName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "balance: unknown skill %q\n", *skillName)
+		return 2
+	}
+	modes, err := parseDifficulties(*difficulties)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "balance:", err)
+		return 2
+	}
+	if *runs < 1 {
+		fmt.Fprintln(os.Stderr, "balance: -runs must be at least 1")
+		return 2
+	}
+
+	cfg := game.BalanceConfig{
+		Runs:         *runs,
+		Difficulties: modes,
+		Skill:        skill,
+		BaseSeed:     *seed,
+		MaxTicks:     int(*minutes * 60 * 60),
+	}
+	report := game.RunBalance(cfg, func(run game.BalanceRun) {
+		if !*quiet {
+			fmt.Fprintf(os.Stderr, "%-6s seed %-6d wave %-3d score %-8d %s\n",
+				run.Difficulty, run.Seed, run.Wave, run.Score, run.Stats.DeathCause)
+		}
+	})
+
+	if err := report.WriteCSV(*outDir); err != nil {
+		fmt.Fprintln(os.Stderr, "balance:", err)
+		return 1
+	}
+	if err := report.WriteJSON(filepath.Join(*outDir, "report.json")); err != nil {
+		fmt.Fprintln(os.Stderr, "balance:", err)
+		return 1
+	}
+
+	for _, s := range report.Summaries {
+		fmt.Printf("%-6s %d runs: mean wave %.1f, median %.1f, max %d, mean score %.0f\n",
+			s.Difficulty, s.Runs, s.MeanWave, s.MedianWave, s.MaxWave, s.MeanScore)
+	}
+	fmt.Printf("Reports written to %s\n", *outDir)
+	return 0
+}
+
+// parseDifficulties parses the -difficulty flag
+func parseDifficulties(value string) ([]game.DifficultyMode, error) {
+	if value == "all" {
+		return []game.DifficultyMode{game.DifficultyEasy, game.DifficultyNormal, game.DifficultyHard}, nil
+	}
+	var modes []game.DifficultyMode
+	for _, name := range strings.Split(value, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "easy":
+			modes = append(modes, game.DifficultyEasy)
+		case "normal":
+			modes = append(modes, game.DifficultyNormal)
+		case "hard":
+			modes = append(modes, game.DifficultyHard)
+		default:
+			return nil, fmt.Errorf("unknown difficulty %q", name)
+		}
+	}
+	return modes, nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"stellar-siege/game"
+)
+
+// runBench implements the "bench" subcommand. It runs the headless stress
+// scenarios, prints time and allocations per tick and, given a baseline
+// report, fails when a scenario has regressed.
+func runBench(args []string) int {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	ticks := fs.Int("ticks", game.BenchDefaultTicks, "ticks measured per scenario")
+	scenarios := fs.String("scenario", "all", "scenarios to run: all, or a comma-separated list")
+	baselinePath := fs.String("baseline", "", "compare against this report and exit with status 1 on regressions")
+	outPath := fs.String("out", "", "write the report to this file (use it as a later -baseline)")
+	margin := fs.Float64("margin", game.BenchDefaultMargin, "relative slowdown tolerated before a metric counts as a regression")
+	list := fs.Bool("list", false, "list the scenarios and exit")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: stellar-siege bench [flags]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *list {
+		for _, s := range game.BenchScenarios {
+			fmt.Printf("%-20s %s\n", s.Name, s.Description)
+		}
+		return 0
+	}
+	selected, err := parseScenarios(*scenarios)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "bench:", err)
+		return 2
+	}
+	if *ticks < 1 {
+		fmt.Fprintln(os.Stderr, "bench: -ticks must be at least 1")
+		return 2
+	}
+
+	var baseline *game.BenchReport
+	if *baselinePath != "" {
+		if baseline, err = game.LoadBenchReport(*baselinePath); err != nil {
+			fmt.Fprintln(os.Stderr, "bench:", err)
+			return 1
+		}
+	}
+
+	report := game.NewBenchReport()
+	for _, s := range selected {
+		res, err := game.RunBenchScenario(s, *ticks)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "bench:", err)
+			return 1
+		}
+		report.Results = append(report.Results, res)
+		fmt.Printf("%-20s %10.0f ns/tick %8.1f allocs/tick %10.0f B/tick\n",
+			res.Scenario, res.NsPerTick, res.AllocsPerTick, res.BytesPerTick)
+	}
+
+	if *outPath != "" {
+		if err := report.WriteJSON(*outPath); err != nil {
+			fmt.Fprintln(os.Stderr, "bench:", err)
+			return 1
+		}
+		fmt.Printf("Report written to %s\n", *outPath)
+	}
+
+	if baseline == nil {
+		return 0
+	}
+	fmt.Printf("\nCompared with %s (%s, %s):\n", *baselinePath, baseline.Platform, baseline.GoVersion)
+	regressions := 0
+	for _, d := range game.CompareBench(baseline, report, *margin) {
+		fmt.Println(d)
+		if d.Regressed {
+			regressions++
+		}
+	}
+	if regressions > 0 {
+		fmt.Printf("%d regression(s)\n", regressions)
+		return 1
+	}
+	return 0
+}
+
+// parseScenarios parses the -scenario flag
+func parseScenarios(value string) ([]game.BenchScenario, error) {
+	if value == "all" {
+		return game.BenchScenarios, nil
+	}
+	var selected []game.BenchScenario
+	for _, name := range strings.Split(value, ",") {
+		s, ok := game.BenchScenarioByName(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unknown scenario %q", name)
+		}
+		selected = append(selected, s)
+	}
+	return selected, nil
+}
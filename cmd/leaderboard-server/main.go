@@ -0,0 +1,40 @@
+// Command leaderboard-server runs a self-hosted Stellar Siege online
+// leaderboard. Point the game at it with LEADERBOARD_URL; clients need no
+// credentials, so no GitHub token has to ship with the game.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"stellar-siege/game/scoreserver"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	dataDir := flag.String("data", "leaderboard-data", "directory holding the scores and replays")
+	maxEntries := flag.Int("max-entries", scoreserver.DefaultMaxEntries, "number of scores to keep")
+	adminToken := flag.String("admin-token", os.Getenv("LEADERBOARD_ADMIN_TOKEN"), "token the replay verifier uses to store verification records (default $LEADERBOARD_ADMIN_TOKEN)")
+	flag.Parse()
+
+	server, err := scoreserver.New(*dataDir, *adminToken, *maxEntries)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *adminToken == "" {
+		log.Println("No admin token set; the replay verifier cannot store verification records")
+	}
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           server,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+	log.Printf("Leaderboard server listening on %s, data in %s", *addr, *dataDir)
+	log.Fatal(httpServer.ListenAndServe())
+}
@@ -0,0 +1,41 @@
+package game
+
+import (
+	"testing"
+
+	"stellar-siege/game/core"
+)
+
+// playWithBot runs a headless game driven by an autopilot until the player
+// dies or the tick limit is reached
+func playWithBot(seed int64, difficulty DifficultyMode, skill core.BotSkill, maxTicks int) *Game {
+	g := NewHeadlessGame(seed, difficulty)
+	bot := core.NewAutopilot(skill, seed)
+	for i := 0; i < maxTicks && g.Step(bot.NextInput(g.BotView())); i++ {
+	}
+	return g
+}
+
+func TestAutopilotOutlastsIdlePlayer(t *testing.T) {
+	idle := playUntilDeath(t, 7)
+
+	bot := playWithBot(7, DifficultyHard, core.BotSkillExpert, idle.Tick()*4)
+	if bot.Tick() <= idle.Tick() {
+		t.Errorf("expert bot survived %d ticks, idle player %d", bot.Tick(), idle.Tick())
+	}
+	if bot.Score() <= idle.Score() {
+		t.Errorf("expert bot scored %d, idle player %d", bot.Score(), idle.Score())
+	}
+}
+
+func TestAutopilotRunsVerify(t *testing.T) {
+	g := playWithBot(11, DifficultyNormal, core.BotSkillNovice, 60*60*20)
+	if g.state == StatePlaying {
+		t.Skip("novice bot survived the tick limit; nothing to verify")
+	}
+
+	record := VerifyReplay(g.Replay())
+	if !record.Verified {
+		t.Fatalf("bot run failed verification: %s", record.Reason)
+	}
+}
@@ -0,0 +1,337 @@
+package game
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"stellar-siege/game/core"
+	"stellar-siege/game/rng"
+	"stellar-siege/game/systems"
+)
+
+// SurvivedCause is reported as the death cause of runs that reached the tick limit
+const SurvivedCause = "Survived"
+
+// BalanceConfig describes a batch of bot-played runs used to tune difficulty
+type BalanceConfig struct {
+	Runs         int              // Runs per difficulty
+	Difficulties []DifficultyMode // Difficulties to simulate
+	Skill        core.BotSkill    // Skill of the bot pilot
+	BaseSeed     int64            // Run i uses seed BaseSeed+i on every difficulty
+	MaxTicks     int              // Runs still alive after this many ticks are stopped
+}
+
+// BalanceRun is the outcome of a single bot-played run
+type BalanceRun struct {
+	Difficulty string            `json:"difficulty"`
+	Seed       int64             `json:"seed"`
+	Score      int64             `json:"score"`
+	Wave       int               `json:"wave"`
+	Ticks      int               `json:"ticks"`
+	Survived   bool              `json:"survived"`
+	Stats      *systems.RunStats `json:"stats"`
+}
+
+// WaveSummary aggregates one wave over all runs that reached it
+type WaveSummary struct {
+	Wave         int     `json:"wave"`
+	Reached      int     `json:"reached"`   // Runs that started this wave
+	Completed    int     `json:"completed"` // Runs that cleared it
+	MeanDuration float64 `json:"mean_duration"`
+	MeanDamage   float64 `json:"mean_damage"`
+}
+
+// BossSummary aggregates fights against bosses of one level
+type BossSummary struct {
+	Level        int     `json:"level"`
+	Fights       int     `json:"fights"`
+	Defeated     int     `json:"defeated"`
+	MeanDuration float64 `json:"mean_duration"` // Seconds, defeated fights only
+}
+
+// BalanceSummary aggregates all runs of one difficulty
+type BalanceSummary struct {
+	Difficulty     string         `json:"difficulty"`
+	Runs           int            `json:"runs"`
+	MeanWave       float64        `json:"mean_wave"`
+	MedianWave     float64        `json:"median_wave"`
+	MaxWave        int            `json:"max_wave"`
+	MeanScore      float64        `json:"mean_score"`
+	MeanDuration   float64        `json:"mean_duration"`
+	Waves          []WaveSummary  `json:"waves"`
+	DamageBySource map[string]int `json:"damage_by_source"`
+	KillsByWeapon  map[string]int `json:"kills_by_weapon"`
+	DeathCauses    map[string]int `json:"death_causes"`
+	Bosses         []BossSummary  `json:"bosses"`
+}
+
+// BalanceReport is the result of a balance simulation
+type BalanceReport struct {
+	GameVersion string           `json:"game_version"`
+	Skill       string           `json:"skill"`
+	MaxTicks    int              `json:"max_ticks"`
+	Summaries   []BalanceSummary `json:"summaries"`
+	Runs        []BalanceRun     `json:"runs"`
+}
+
+// RunBalance plays cfg.Runs seeded headless games per difficulty with the
+// autopilot and aggregates their statistics. progress, if not nil, is called
+// after every finished run.
+func RunBalance(cfg BalanceConfig, progress func(BalanceRun)) *BalanceReport {
+	// Keep whatever source was active before so a running game is unaffected
+	prev := rng.Current()
+	defer rng.Use(prev)
+
+	report := &BalanceReport{
+		GameVersion: Version,
+		Skill:       cfg.Skill.Name,
+		MaxTicks:    cfg.MaxTicks,
+	}
+	for _, difficulty := range cfg.Difficulties {
+		runs := make([]BalanceRun, 0, cfg.Runs)
+		for i := 0; i < cfg.Runs; i++ {
+			run := playBalanceRun(cfg.BaseSeed+int64(i), difficulty, cfg.Skill, cfg.MaxTicks)
+			if progress != nil {
+				progress(run)
+			}
+			runs = append(runs, run)
+		}
+		report.Runs = append(report.Runs, runs...)
+		report.Summaries = append(report.Summaries, summarizeRuns(GetDifficultyName(difficulty), runs))
+	}
+	return report
+}
+
+// playBalanceRun plays a single run with the autopilot
+func playBalanceRun(seed int64, difficulty DifficultyMode, skill core.BotSkill, maxTicks int) BalanceRun {
+	g := NewHeadlessGame(seed, difficulty)
+	bot := core.NewAutopilot(skill, seed)
+	alive := true
+	for g.Tick() < maxTicks && alive {
+		alive = g.Step(bot.NextInput(g.BotView()))
+	}
+
+	stats := g.RunStats()
+	if alive {
+		stats.Finish(g.gameTime, SurvivedCause)
+	}
+	return BalanceRun{
+		Difficulty: GetDifficultyName(difficulty),
+		Seed:       seed,
+		Score:      g.Score(),
+		Wave:       g.Wave(),
+		Ticks:      g.Tick(),
+		Survived:   alive,
+		Stats:      stats,
+	}
+}
+
+// summarizeRuns aggregates the runs of one difficulty
+func summarizeRuns(difficulty string, runs []BalanceRun) BalanceSummary {
+	summary := BalanceSummary{
+		Difficulty:     difficulty,
+		Runs:           len(runs),
+		DamageBySource: make(map[string]int),
+		KillsByWeapon:  make(map[string]int),
+		DeathCauses:    make(map[string]int),
+	}
+	if len(runs) == 0 {
+		return summary
+	}
+
+	waves := make([]int, 0, len(runs))
+	waveTotals := make(map[int]*WaveSummary)
+	bossTotals := make(map[int]*BossSummary)
+	for _, run := range runs {
+		waves = append(waves, run.Wave)
+		summary.MeanWave += float64(run.Wave)
+		summary.MeanScore += float64(run.Score)
+		summary.MeanDuration += run.Stats.Duration
+		if run.Wave > summary.MaxWave {
+			summary.MaxWave = run.Wave
+		}
+
+		for source, amount := range run.Stats.DamageBySource {
+			summary.DamageBySource[source] += amount
+		}
+		for weapon, kills := range run.Stats.KillsByWeapon {
+			summary.KillsByWeapon[weapon] += kills
+		}
+		cause := run.Stats.DeathCause
+		if cause == "" {
+			cause = systems.SourceUnknown
+		}
+		summary.DeathCauses[cause]++
+
+		for _, w := range run.Stats.Waves {
+			total := waveTotals[w.Wave]
+			if total == nil {
+				total = &WaveSummary{Wave: w.Wave}
+				waveTotals[w.Wave] = total
+			}
+			total.Reached++
+			total.MeanDamage += float64(w.DamageTaken)
+			if w.Completed {
+				total.Completed++
+				total.MeanDuration += w.Duration
+			}
+		}
+
+		for _, fight := range run.Stats.BossFights {
+			total := bossTotals[fight.Level]
+			if total == nil {
+				total = &BossSummary{Level: fight.Level}
+				bossTotals[fight.Level] = total
+			}
+			total.Fights++
+			if fight.Defeated {
+				total.Defeated++
+				total.MeanDuration += fight.Duration
+			}
+		}
+	}
+
+	n := float64(len(runs))
+	summary.MeanWave /= n
+	summary.MeanScore /= n
+	summary.MeanDuration /= n
+
+	sort.Ints(waves)
+	if len(waves)%2 == 1 {
+		summary.MedianWave = float64(waves[len(waves)/2])
+	} else {
+		summary.MedianWave = float64(waves[len(waves)/2-1]+waves[len(waves)/2]) / 2
+	}
+
+	for _, total := range waveTotals {
+		total.MeanDamage /= float64(total.Reached)
+		if total.Completed > 0 {
+			total.MeanDuration /= float64(total.Completed)
+		}
+		summary.Waves = append(summary.Waves, *total)
+	}
+	sort.Slice(summary.Waves, func(i, j int) bool { return summary.Waves[i].Wave < summary.Waves[j].Wave })
+
+	for _, total := range bossTotals {
+		if total.Defeated > 0 {
+			total.MeanDuration /= float64(total.Defeated)
+		}
+		summary.Bosses = append(summary.Bosses, *total)
+	}
+	sort.Slice(summary.Bosses, func(i, j int) bool { return summary.Bosses[i].Level < summary.Bosses[j].Level })
+
+	return summary
+}
+
+// WriteJSON writes the full report, including every run, as JSON
+func (r *BalanceReport) WriteJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode balance report: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write balance report: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV writes the report as a set of CSV tables into dir: runs, waves,
+// damage, kills, bosses and deaths
+func (r *BalanceReport) WriteCSV(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	tables := map[string][][]string{
+		"runs.csv":   {{"difficulty", "seed", "score", "wave", "ticks", "duration", "damage_taken", "kills", "death_cause"}},
+		"waves.csv":  {{"difficulty", "wave", "reached", "completed", "mean_duration", "mean_damage"}},
+		"damage.csv": {{"difficulty", "source", "damage", "damage_per_run"}},
+		"kills.csv":  {{"difficulty", "weapon", "kills", "kills_per_run"}},
+		"bosses.csv": {{"difficulty", "seed", "wave", "level", "duration", "defeated"}},
+		"deaths.csv": {{"difficulty", "cause", "runs", "share"}},
+	}
+
+	for _, run := range r.Runs {
+		tables["runs.csv"] = append(tables["runs.csv"], []string{
+			run.Difficulty, itoa64(run.Seed), itoa64(run.Score), strconv.Itoa(run.Wave), strconv.Itoa(run.Ticks),
+			ftoa(run.Stats.Duration), strconv.Itoa(run.Stats.TotalDamage()), strconv.Itoa(run.Stats.TotalKills()), run.Stats.DeathCause,
+		})
+		for _, fight := range run.Stats.BossFights {
+			tables["bosses.csv"] = append(tables["bosses.csv"], []string{
+				run.Difficulty, itoa64(run.Seed), strconv.Itoa(fight.Wave), strconv.Itoa(fight.Level),
+				ftoa(fight.Duration), strconv.FormatBool(fight.Defeated),
+			})
+		}
+	}
+
+	for _, s := range r.Summaries {
+		n := float64(max(s.Runs, 1))
+		for _, w := range s.Waves {
+			tables["waves.csv"] = append(tables["waves.csv"], []string{
+				s.Difficulty, strconv.Itoa(w.Wave), strconv.Itoa(w.Reached), strconv.Itoa(w.Completed),
+				ftoa(w.MeanDuration), ftoa(w.MeanDamage),
+			})
+		}
+		for _, source := range sortedKeys(s.DamageBySource) {
+			amount := s.DamageBySource[source]
+			tables["damage.csv"] = append(tables["damage.csv"], []string{
+				s.Difficulty, source, strconv.Itoa(amount), ftoa(float64(amount) / n),
+			})
+		}
+		for _, weapon := range sortedKeys(s.KillsByWeapon) {
+			kills := s.KillsByWeapon[weapon]
+			tables["kills.csv"] = append(tables["kills.csv"], []string{
+				s.Difficulty, weapon, strconv.Itoa(kills), ftoa(float64(kills) / n),
+			})
+		}
+		for _, cause := range sortedKeys(s.DeathCauses) {
+			count := s.DeathCauses[cause]
+			tables["deaths.csv"] = append(tables["deaths.csv"], []string{
+				s.Difficulty, cause, strconv.Itoa(count), ftoa(float64(count) / n),
+			})
+		}
+	}
+
+	for _, name := range sortedKeys(tables) {
+		if err := writeCSVFile(filepath.Join(dir, name), tables[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCSVFile(path string, rows [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Base(path), err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func itoa64(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+func ftoa(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"stellar-siege/game/core"
+)
+
+func TestRunBalanceAggregatesRuns(t *testing.T) {
+	cfg := BalanceConfig{
+		Runs:         3,
+		Difficulties: []DifficultyMode{DifficultyEasy, DifficultyHard},
+		Skill:        core.BotSkillNovice,
+		BaseSeed:     5,
+		MaxTicks:     60 * 60 * 3,
+	}
+	report := RunBalance(cfg, nil)
+
+	if len(report.Runs) != 6 || len(report.Summaries) != 2 {
+		t.Fatalf("got %d runs and %d summaries, want 6 and 2", len(report.Runs), len(report.Summaries))
+	}
+
+	for _, run := range report.Runs {
+		if run.Stats.DeathCause == "" {
+			t.Errorf("%s seed %d: run has no death cause", run.Difficulty, run.Seed)
+		}
+		waveDamage, waveKills := 0, 0
+		for _, w := range run.Stats.Waves {
+			waveDamage += w.DamageTaken
+			waveKills += w.Kills
+		}
+		if waveDamage != run.Stats.TotalDamage() || waveKills != run.Stats.TotalKills() {
+			t.Errorf("%s seed %d: per-wave totals (%d damage, %d kills) do not match run totals (%d, %d)",
+				run.Difficulty, run.Seed, waveDamage, waveKills, run.Stats.TotalDamage(), run.Stats.TotalKills())
+		}
+		if last := run.Stats.Waves[len(run.Stats.Waves)-1]; last.Wave != run.Wave {
+			t.Errorf("%s seed %d: last recorded wave %d, run reached %d", run.Difficulty, run.Seed, last.Wave, run.Wave)
+		}
+	}
+
+	for _, s := range report.Summaries {
+		causes := 0
+		for _, n := range s.DeathCauses {
+			causes += n
+		}
+		if causes != s.Runs {
+			t.Errorf("%s: %d death causes for %d runs", s.Difficulty, causes, s.Runs)
+		}
+		if len(s.Waves) == 0 || s.Waves[0].Reached != s.Runs {
+			t.Errorf("%s: wave 0 should be reached by every run", s.Difficulty)
+		}
+	}
+
+	// Same seeds must give the same report
+	again := RunBalance(cfg, nil)
+	for i := range report.Runs {
+		if report.Runs[i].Score != again.Runs[i].Score || report.Runs[i].Ticks != again.Runs[i].Ticks {
+			t.Fatalf("run %d differs between identical balance runs", i)
+		}
+	}
+}
+
+func TestBalanceReportWritesCSV(t *testing.T) {
+	report := RunBalance(BalanceConfig{
+		Runs:         1,
+		Difficulties: []DifficultyMode{DifficultyNormal},
+		Skill:        core.BotSkillNovice,
+		BaseSeed:     1,
+		MaxTicks:     60 * 60,
+	}, nil)
+
+	dir := t.TempDir()
+	if err := report.WriteCSV(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := report.WriteJSON(filepath.Join(dir, "report.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(filepath.Join(dir, "runs.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[1][0] != "NORMAL" {
+		t.Fatalf("runs.csv = %v, want a header and one NORMAL run", rows)
+	}
+}
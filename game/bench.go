@@ -0,0 +1,322 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"time"
+
+	"stellar-siege/game/entities"
+	"stellar-siege/game/rng"
+)
+
+// Benchmark defaults
+const (
+	BenchSeed          = 1
+	BenchDefaultTicks  = 60 * 60 // One minute of game time
+	BenchWarmupTicks   = 120     // Ticks run before measuring so pools and slices are sized
+	BenchDefaultMargin = 0.15    // Slowdown tolerated before a comparison counts as a regression
+	BenchNoiseFloorNs  = 1000    // Subsystem slowdowns smaller than this are never regressions
+)
+
+// BenchScenario is a named stress load for the headless simulation. Setup
+// prepares a fresh run; Sustain runs before every tick to hold the load steady.
+type BenchScenario struct {
+	Name        string
+	Description string
+	Setup       func(g *Game)
+	Sustain     func(g *Game)
+}
+
+// benchEnemyTypes are cycled through when topping up enemies
+var benchEnemyTypes = []entities.EnemyType{
+	entities.EnemyScout,
+	entities.EnemyDrone,
+	entities.EnemyHunter,
+	entities.EnemyTank,
+	entities.EnemyBomber,
+	entities.EnemySniper,
+	entities.EnemySplitter,
+	entities.EnemyShieldBearer,
+}
+
+// BenchScenarios are the scenarios run by the bench subcommand and benchmarks
+var BenchScenarios = []BenchScenario{
+	{
+		Name:        "baseline",
+		Description: "normal run with no extra load",
+	},
+	{
+		Name:        "projectiles_enemies",
+		Description: "150 projectiles + 40 enemies",
+		Sustain: func(g *Game) {
+			g.fillEnemies(MaxEnemies)
+			g.fillProjectiles(MaxProjectiles)
+		},
+	},
+	{
+		Name:        "boss_chaos",
+		Description: "boss level 6 firing its chaos pattern",
+		Setup: func(g *Game) {
+			g.bossWave = true
+			g.boss = entities.NewBoss(ScreenWidth, 6)
+			g.boss.Y = g.boss.EntryY
+			g.boss.Phase = entities.BossPhaseAttacking
+		},
+		Sustain: func(g *Game) {
+			if g.boss == nil || !g.boss.Active {
+				return
+			}
+			// The boss advances its pattern before attacking, so holding it
+			// one short of chaos makes every attack the chaos pattern
+			g.boss.AttackPattern = 8
+			g.boss.Health = g.boss.MaxHealth
+		},
+	},
+	{
+		Name:        "max_asteroids",
+		Description: fmt.Sprintf("%d large asteroids", MaxAsteroids),
+		Sustain: func(g *Game) {
+			for len(g.asteroids) < MaxAsteroids {
+				a := g.asteroidPool.Get()
+				*a = *entities.NewAsteroid(rng.Float64()*ScreenWidth, rng.Float64()*ScreenHeight/2, entities.AsteroidLarge)
+				g.asteroids = append(g.asteroids, a)
+			}
+		},
+	},
+}
+
+// BenchScenarioByName returns the scenario with the given name
+func BenchScenarioByName(name string) (BenchScenario, bool) {
+	for _, s := range BenchScenarios {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return BenchScenario{}, false
+}
+
+// fillEnemies spawns enemies until n are on screen
+func (g *Game) fillEnemies(n int) {
+	for i := len(g.enemies); i < n; i++ {
+		e := g.enemyPool.Get()
+		enemyType := benchEnemyTypes[i%len(benchEnemyTypes)]
+		*e = *entities.NewEnemyWithDifficulty(50+rng.Float64()*(ScreenWidth-100), rng.Float64()*ScreenHeight/2, enemyType,
+			g.difficultyConfig.EnemyHealthMult, g.difficultyConfig.EnemySpeedMult)
+		g.enemies = append(g.enemies, e)
+	}
+}
+
+// fillProjectiles fires projectiles until n are on screen, alternating
+// friendly shots travelling up and enemy shots travelling down
+func (g *Game) fillProjectiles(n int) {
+	for i := len(g.projectiles); i < n; i++ {
+		p := g.projectilePool.Get()
+		x := rng.Float64() * ScreenWidth
+		if i%2 == 0 {
+			*p = *entities.NewProjectile(x, ScreenHeight-rng.Float64()*100, 0, -400, true, 10)
+		} else {
+			*p = *entities.NewProjectile(x, rng.Float64()*100, 0, 250, false, 10)
+		}
+		g.projectiles = append(g.projectiles, p)
+	}
+}
+
+// benchInput shoots constantly while sweeping the player left and right
+func benchInput(tick int) entities.InputFrame {
+	if tick/entities.TicksPerSecond%2 == 0 {
+		return entities.InputShoot | entities.InputLeft
+	}
+	return entities.InputShoot | entities.InputRight
+}
+
+// newBenchGame starts a headless run prepared for the scenario. The player
+// cannot die and no replay is recorded, so the load lasts for any tick count.
+func newBenchGame(s BenchScenario) *Game {
+	g := NewHeadlessGame(BenchSeed, DifficultyNormal)
+	g.godMode = true
+	g.replay = nil
+	rng.Use(g.rng)
+	if s.Setup != nil {
+		s.Setup(g)
+	}
+	return g
+}
+
+// benchTick tops up the scenario's load and advances the run one tick
+func (g *Game) benchTick(s BenchScenario) error {
+	rng.Use(g.rng)
+	if s.Sustain != nil {
+		s.Sustain(g)
+	}
+	if !g.Step(benchInput(g.tick)) {
+		return fmt.Errorf("run ended at tick %d", g.tick)
+	}
+	return nil
+}
+
+// BenchResult holds the measurements of one scenario
+type BenchResult struct {
+	Scenario      string             `json:"scenario"`
+	Description   string             `json:"description"`
+	Ticks         int                `json:"ticks"`
+	NsPerTick     float64            `json:"ns_per_tick"`
+	AllocsPerTick float64            `json:"allocs_per_tick"`
+	BytesPerTick  float64            `json:"bytes_per_tick"`
+	Subsystems    map[string]float64 `json:"subsystem_ns_per_tick"`
+	Entities      map[string]int     `json:"entities"` // Counts on the last tick
+}
+
+// RunBenchScenario runs a scenario for the given number of ticks after a
+// warm-up and reports time and allocations per tick
+func RunBenchScenario(s BenchScenario, ticks int) (BenchResult, error) {
+	g := newBenchGame(s)
+	for i := 0; i < BenchWarmupTicks; i++ {
+		if err := g.benchTick(s); err != nil {
+			return BenchResult{}, fmt.Errorf("scenario %s: %w", s.Name, err)
+		}
+	}
+	g.perfMon.ResetSubsystemTotals()
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for i := 0; i < ticks; i++ {
+		if err := g.benchTick(s); err != nil {
+			return BenchResult{}, fmt.Errorf("scenario %s: %w", s.Name, err)
+		}
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	g.recordFrameStats()
+	result := BenchResult{
+		Scenario:      s.Name,
+		Description:   s.Description,
+		Ticks:         ticks,
+		NsPerTick:     float64(elapsed.Nanoseconds()) / float64(ticks),
+		AllocsPerTick: float64(after.Mallocs-before.Mallocs) / float64(ticks),
+		BytesPerTick:  float64(after.TotalAlloc-before.TotalAlloc) / float64(ticks),
+		Subsystems:    make(map[string]float64),
+		Entities:      g.perfMon.GetEntityCounts(),
+	}
+	for name, total := range g.perfMon.GetSubsystemTotals() {
+		result.Subsystems[name] = float64(total.Nanoseconds()) / float64(ticks)
+	}
+	return result, nil
+}
+
+// BenchReport is the output of a benchmark run, also used as a baseline
+type BenchReport struct {
+	GameVersion string        `json:"game_version"`
+	GoVersion   string        `json:"go_version"`
+	Platform    string        `json:"platform"`
+	Results     []BenchResult `json:"results"`
+}
+
+// NewBenchReport creates an empty report for this build and platform
+func NewBenchReport() *BenchReport {
+	return &BenchReport{
+		GameVersion: Version,
+		GoVersion:   runtime.Version(),
+		Platform:    runtime.GOOS + "/" + runtime.GOARCH,
+	}
+}
+
+// Result returns the result recorded for a scenario
+func (r *BenchReport) Result(scenario string) (BenchResult, bool) {
+	for _, res := range r.Results {
+		if res.Scenario == scenario {
+			return res, true
+		}
+	}
+	return BenchResult{}, false
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *BenchReport) WriteJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bench report: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write bench report: %w", err)
+	}
+	return nil
+}
+
+// LoadBenchReport reads a report written by WriteJSON
+func LoadBenchReport(path string) (*BenchReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bench report: %w", err)
+	}
+	var r BenchReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse bench report: %w", err)
+	}
+	return &r, nil
+}
+
+// BenchDelta compares one metric of a scenario against the baseline
+type BenchDelta struct {
+	Scenario  string
+	Metric    string
+	Baseline  float64
+	Current   float64
+	Change    float64 // Relative change, 0.1 is 10% higher than the baseline
+	Regressed bool
+}
+
+// CompareBench compares the time and allocations per tick of every scenario
+// present in both reports, plus each subsystem's time. A metric regresses
+// when it grows by more than margin; allocations regress on any increase
+// of at least one per tick.
+func CompareBench(baseline, current *BenchReport, margin float64) []BenchDelta {
+	var deltas []BenchDelta
+	for _, cur := range current.Results {
+		base, ok := baseline.Result(cur.Scenario)
+		if !ok {
+			continue
+		}
+		add := func(metric string, b, c float64, regressed bool) {
+			change := 0.0
+			if b > 0 {
+				change = c/b - 1
+			}
+			deltas = append(deltas, BenchDelta{cur.Scenario, metric, b, c, change, regressed})
+		}
+		add("ns/tick", base.NsPerTick, cur.NsPerTick, cur.NsPerTick > base.NsPerTick*(1+margin))
+		add("allocs/tick", base.AllocsPerTick, cur.AllocsPerTick, cur.AllocsPerTick-base.AllocsPerTick >= 1)
+		add("B/tick", base.BytesPerTick, cur.BytesPerTick, false)
+
+		for _, name := range sortedKeys(cur.Subsystems) {
+			if b, ok := base.Subsystems[name]; ok {
+				c := cur.Subsystems[name]
+				add(name+" ns/tick", b, c, c > b*(1+margin) && c-b > BenchNoiseFloorNs)
+			}
+		}
+	}
+	return deltas
+}
+
+// formatBenchValue formats a metric value for the comparison table
+func formatBenchValue(v float64) string {
+	if math.Abs(v) >= 1000 {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+// String formats the delta as a row of the comparison table
+func (d BenchDelta) String() string {
+	flag := ""
+	if d.Regressed {
+		flag = "  REGRESSION"
+	}
+	return fmt.Sprintf("%-20s %-24s %12s -> %-12s %+7.1f%%%s",
+		d.Scenario, d.Metric, formatBenchValue(d.Baseline), formatBenchValue(d.Current), d.Change*100, flag)
+}
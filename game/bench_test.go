@@ -0,0 +1,81 @@
+package game
+
+import "testing"
+
+func BenchmarkScenarios(b *testing.B) {
+	for _, s := range BenchScenarios {
+		b.Run(s.Name, func(b *testing.B) {
+			g := newBenchGame(s)
+			for i := 0; i < BenchWarmupTicks; i++ {
+				if err := g.benchTick(s); err != nil {
+					b.Fatal(err)
+				}
+			}
+			g.perfMon.ResetSubsystemTotals()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := g.benchTick(s); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+			for name, total := range g.perfMon.GetSubsystemTotals() {
+				b.ReportMetric(float64(total.Nanoseconds())/float64(b.N), name+"-ns/tick")
+			}
+		})
+	}
+}
+
+func TestBenchScenariosHoldTheirLoad(t *testing.T) {
+	for _, s := range BenchScenarios {
+		res, err := RunBenchScenario(s, 120)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.NsPerTick <= 0 || res.Subsystems["collisions"] <= 0 {
+			t.Errorf("%s: no timings recorded: %+v", s.Name, res)
+		}
+		switch s.Name {
+		case "projectiles_enemies":
+			if res.Entities["enemies"] < MaxEnemies/2 || res.Entities["projectiles"] < MaxProjectiles/2 {
+				t.Errorf("%s: load dropped to %v", s.Name, res.Entities)
+			}
+		case "max_asteroids":
+			if res.Entities["asteroids"] < MaxAsteroids/2 {
+				t.Errorf("%s: load dropped to %v", s.Name, res.Entities)
+			}
+		}
+	}
+}
+
+func TestCompareBenchFlagsRegressions(t *testing.T) {
+	baseline := &BenchReport{Results: []BenchResult{
+		{Scenario: "a", NsPerTick: 1000, AllocsPerTick: 10, Subsystems: map[string]float64{"enemies": 50000}},
+		{Scenario: "b", NsPerTick: 1000, AllocsPerTick: 10},
+	}}
+	current := &BenchReport{Results: []BenchResult{
+		{Scenario: "a", NsPerTick: 1100, AllocsPerTick: 10.5, Subsystems: map[string]float64{"enemies": 80000}},
+		{Scenario: "b", NsPerTick: 1300, AllocsPerTick: 12},
+		{Scenario: "new", NsPerTick: 5000},
+	}}
+
+	regressed := map[string]bool{}
+	for _, d := range CompareBench(baseline, current, 0.15) {
+		if d.Scenario == "new" {
+			t.Errorf("scenario missing from the baseline was compared: %v", d)
+		}
+		if d.Regressed {
+			regressed[d.Scenario+" "+d.Metric] = true
+		}
+	}
+	want := []string{"a enemies ns/tick", "b ns/tick", "b allocs/tick"}
+	if len(regressed) != len(want) {
+		t.Errorf("regressions = %v, want %v", regressed, want)
+	}
+	for _, w := range want {
+		if !regressed[w] {
+			t.Errorf("%q not flagged as a regression", w)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package game
+
+import (
+	"math"
+	"testing"
+
+	"stellar-siege/game/entities"
+)
+
+func TestTimeScaleAppliesUniformly(t *testing.T) {
+	// flyRight moves the ship right for one simulated second at the given scale
+	flyRight := func(scale float64) (*Game, float64) {
+		g := NewHeadlessGame(11, DifficultyEasy)
+		g.clock.SetScale(scale)
+		startX := g.player.X
+		for g.gameTime < 1-1e-9 {
+			if !g.Step(entities.InputRight) {
+				t.Fatalf("run ended at tick %d", g.Tick())
+			}
+		}
+		return g, g.player.X - startX
+	}
+
+	normal, normalDist := flyRight(1)
+	fast, fastDist := flyRight(2)
+	slow, slowDist := flyRight(0.5)
+
+	if normal.Tick() != 60 || fast.Tick() != 30 || slow.Tick() != 120 {
+		t.Fatalf("ticks for one second = %d, %d, %d; want 60, 30, 120", normal.Tick(), fast.Tick(), slow.Tick())
+	}
+	for _, dist := range []float64{fastDist, slowDist} {
+		if math.Abs(dist-normalDist) > 1e-6 {
+			t.Errorf("distance flown in one second = %v, want %v at every time scale", dist, normalDist)
+		}
+	}
+}
+
+func TestHitStopSlowsThenRecovers(t *testing.T) {
+	g := NewHeadlessGame(11, DifficultyEasy)
+	g.clock.HitStop(0.1, 0.25)
+
+	g.Step(0)
+	if want := entities.FixedTimestep * 0.25; math.Abs(g.deltaTime-want) > 1e-12 {
+		t.Fatalf("timestep during hit-stop = %v, want %v", g.deltaTime, want)
+	}
+	for i := 0; i < 6; i++ {
+		g.Step(0)
+	}
+	if math.Abs(g.deltaTime-entities.FixedTimestep) > 1e-12 {
+		t.Fatalf("timestep after hit-stop = %v, want %v", g.deltaTime, entities.FixedTimestep)
+	}
+}
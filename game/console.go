@@ -0,0 +1,389 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"stellar-siege/game/entities"
+	"stellar-siege/game/rng"
+	"stellar-siege/game/systems"
+)
+
+// Console command limits
+const (
+	ConsoleMaxSpawn = 20 // Most enemies a single spawn command creates
+	ConsoleMaxBoss  = 10 // Highest boss level the boss command accepts
+)
+
+// consoleEnemyTypes maps console names to enemy types
+var consoleEnemyTypes = map[string]entities.EnemyType{
+	"scout":         entities.EnemyScout,
+	"drone":         entities.EnemyDrone,
+	"hunter":        entities.EnemyHunter,
+	"tank":          entities.EnemyTank,
+	"bomber":        entities.EnemyBomber,
+	"sniper":        entities.EnemySniper,
+	"splitter":      entities.EnemySplitter,
+	"shield_bearer": entities.EnemyShieldBearer,
+}
+
+// consoleWeaponTypes lists the weapons the weapon command accepts
+var consoleWeaponTypes = []entities.WeaponType{
+	entities.WeaponTypeSpread,
+	entities.WeaponTypeLaser,
+	entities.WeaponTypeShotgun,
+	entities.WeaponTypePlasma,
+	entities.WeaponTypeHoming,
+	entities.WeaponTypeRailgun,
+	entities.WeaponTypeEnergyLance,
+	entities.WeaponTypePulse,
+	entities.WeaponTypeBlaster,
+	entities.WeaponTypeFollowingRocket,
+	entities.WeaponTypeChainLightning,
+	entities.WeaponTypeFlamethrower,
+	entities.WeaponTypeIonBeam,
+}
+
+// consoleHazardTypes maps console names to hazard types
+var consoleHazardTypes = map[string]entities.HazardType{
+	"barrier":   entities.HazardTypeBarrier,
+	"magnetic":  entities.HazardTypeMagneticField,
+	"radiation": entities.HazardTypeRadiationZone,
+	"blackhole": entities.HazardTypeBlackHole,
+}
+
+// newGameConsole creates the developer console with the game's commands
+func (g *Game) newGameConsole() *systems.Console {
+	c := systems.NewConsole()
+
+	c.Register(systems.ConsoleCommand{
+		Name:  "help",
+		Usage: "help",
+		Run: func(args []string) (string, error) {
+			for _, cmd := range c.Commands() {
+				c.Print("  " + cmd.Usage)
+			}
+			return "", nil
+		},
+	})
+	c.Register(systems.ConsoleCommand{
+		Name:  "clear",
+		Usage: "clear",
+		Run: func(args []string) (string, error) {
+			c.Clear()
+			return "", nil
+		},
+	})
+	c.Register(systems.ConsoleCommand{
+		Name:  "spawn",
+		Usage: "spawn <enemy> [count]",
+		Args:  argCompletions(sortedKeys(consoleEnemyTypes)),
+		Run:   g.consoleSpawn,
+	})
+	c.Register(systems.ConsoleCommand{
+		Name:  "boss",
+		Usage: "boss <level>",
+		Run:   g.consoleBoss,
+	})
+	c.Register(systems.ConsoleCommand{
+		Name:  "wave",
+		Usage: "wave <number>",
+		Run:   g.consoleWave,
+	})
+	weaponNames := make([]string, len(consoleWeaponTypes))
+	for i, wt := range consoleWeaponTypes {
+		weaponNames[i] = string(wt)
+	}
+	c.Register(systems.ConsoleCommand{
+		Name:  "weapon",
+		Usage: "weapon <type> [level 1-5]",
+		Args:  argCompletions(weaponNames),
+		Run:   g.consoleWeapon,
+	})
+	c.Register(systems.ConsoleCommand{
+		Name:  "god",
+		Usage: "god",
+		Run:   g.consoleGod,
+	})
+	c.Register(systems.ConsoleCommand{
+		Name:  "give",
+		Usage: "give <scrap|health|shield> <amount>",
+		Args:  argCompletions([]string{"health", "scrap", "shield"}),
+		Run:   g.consoleGive,
+	})
+	c.Register(systems.ConsoleCommand{
+		Name:  "timescale",
+		Usage: "timescale <scale>",
+		Run:   g.consoleTimeScale,
+	})
+	c.Register(systems.ConsoleCommand{
+		Name:  "hazard",
+		Usage: "hazard <barrier|magnetic|radiation|blackhole>",
+		Args:  argCompletions(sortedKeys(consoleHazardTypes)),
+		Run:   g.consoleHazard,
+	})
+
+	c.Print("Developer console - type help for commands. Using it makes the run unranked.")
+	return c
+}
+
+// argCompletions completes the first argument of a command from names
+func argCompletions(names []string) func(int) []string {
+	return func(argIndex int) []string {
+		if argIndex == 0 {
+			return names
+		}
+		return nil
+	}
+}
+
+// markConsoleUsed makes the run ineligible for leaderboards. Console commands
+// are not part of the recorded input, so the replay is dropped as well.
+func (g *Game) markConsoleUsed() {
+	g.consoleUsed = true
+	g.replay = nil
+}
+
+// requireRun fails console commands that need a run in progress
+func (g *Game) requireRun() error {
+	if g.player == nil || !g.player.Active || (g.state != StatePlaying && g.state != StatePaused) {
+		return errors.New("no run in progress")
+	}
+	return nil
+}
+
+// parseIntArg parses a whole-number argument within [lo, hi]
+func parseIntArg(arg, name string, lo, hi int) (int, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < lo || n > hi {
+		return 0, fmt.Errorf("%s must be a number from %d to %d", name, lo, hi)
+	}
+	return n, nil
+}
+
+// consoleSpawn spawns enemies of a type from the enemy pool
+func (g *Game) consoleSpawn(args []string) (string, error) {
+	if err := g.requireRun(); err != nil {
+		return "", err
+	}
+	if len(args) < 1 {
+		return "", errors.New("missing enemy type")
+	}
+	enemyType, ok := consoleEnemyTypes[strings.ToLower(args[0])]
+	if !ok {
+		return "", fmt.Errorf("unknown enemy type %q", args[0])
+	}
+	count := 1
+	if len(args) > 1 {
+		n, err := parseIntArg(args[1], "count", 1, ConsoleMaxSpawn)
+		if err != nil {
+			return "", err
+		}
+		count = n
+	}
+	count = min(count, MaxEnemies-len(g.enemies))
+	if count <= 0 {
+		return "", fmt.Errorf("enemy limit of %d reached", MaxEnemies)
+	}
+
+	g.markConsoleUsed()
+	for i := 0; i < count; i++ {
+		x := 50 + rng.Float64()*(ScreenWidth-100)
+		e := g.enemyPool.Get()
+		*e = *entities.NewEnemyWithDifficulty(x, -30, enemyType, g.difficultyConfig.EnemyHealthMult, g.difficultyConfig.EnemySpeedMult)
+		g.enemies = append(g.enemies, e)
+	}
+	return fmt.Sprintf("spawned %d %s", count, enemyType), nil
+}
+
+// consoleBoss replaces any boss fight with one against a boss of the given level
+func (g *Game) consoleBoss(args []string) (string, error) {
+	if err := g.requireRun(); err != nil {
+		return "", err
+	}
+	if len(args) < 1 {
+		return "", errors.New("missing boss level")
+	}
+	level, err := parseIntArg(args[0], "level", 1, ConsoleMaxBoss)
+	if err != nil {
+		return "", err
+	}
+
+	g.markConsoleUsed()
+	if g.boss != nil {
+		g.runStats.EndBossFight(g.gameTime, false)
+	}
+	g.bossWave = true
+	g.boss = entities.NewBoss(ScreenWidth, level)
+	g.miniBossSpawnTimer = 0
+	g.miniBossesSpawned = 0
+	g.runStats.StartBossFight(g.wave, level, g.gameTime)
+	g.sound.PlaySound(systems.SoundBossAppear)
+	return fmt.Sprintf("level %d boss incoming", level), nil
+}
+
+// consoleWave clears the field and jumps to the given wave
+func (g *Game) consoleWave(args []string) (string, error) {
+	if err := g.requireRun(); err != nil {
+		return "", err
+	}
+	if len(args) < 1 {
+		return "", errors.New("missing wave number")
+	}
+	wave, err := parseIntArg(args[0], "wave", 1, 999)
+	if err != nil {
+		return "", err
+	}
+
+	g.markConsoleUsed()
+	for _, e := range g.enemies {
+		e.Active = false
+	}
+	if g.boss != nil {
+		g.runStats.EndBossFight(g.gameTime, false)
+		g.boss = nil
+	}
+	g.bossWave = false
+	g.miniBossSpawnTimer = 0
+	g.miniBossesSpawned = 0
+	g.beginWave(wave)
+	return fmt.Sprintf("jumped to wave %d", wave), nil
+}
+
+// consoleWeapon grants a weapon, upgrades it to a level and equips it
+func (g *Game) consoleWeapon(args []string) (string, error) {
+	if err := g.requireRun(); err != nil {
+		return "", err
+	}
+	if len(args) < 1 {
+		return "", errors.New("missing weapon type")
+	}
+	weaponType := entities.WeaponType(strings.ToLower(args[0]))
+	known := false
+	for _, wt := range consoleWeaponTypes {
+		known = known || wt == weaponType
+	}
+	if !known {
+		return "", fmt.Errorf("unknown weapon type %q", args[0])
+	}
+	level := 1
+	if len(args) > 1 {
+		n, err := parseIntArg(args[1], "level", 1, int(entities.WeaponLevelMkV))
+		if err != nil {
+			return "", err
+		}
+		level = n
+	}
+
+	g.markConsoleUsed()
+	wm := g.player.WeaponMgr
+	wm.AddWeapon(weaponType)
+	weapon := wm.GetWeapon(weaponType)
+	if weapon == nil {
+		return "", fmt.Errorf("weapon %q cannot be equipped", weaponType)
+	}
+	for int(weapon.Level) < level {
+		if !wm.UpgradeWeapon(weaponType) {
+			break
+		}
+	}
+	wm.SwitchWeapon(weaponType)
+	if weaponType == entities.WeaponTypeSpread {
+		g.player.WeaponLevel = int(weapon.Level) // Keep deprecated WeaponLevel in sync
+	}
+	return fmt.Sprintf("equipped %s level %d", weapon.Name, weapon.Level), nil
+}
+
+// consoleGod toggles invulnerability
+func (g *Game) consoleGod(args []string) (string, error) {
+	if err := g.requireRun(); err != nil {
+		return "", err
+	}
+	g.markConsoleUsed()
+	g.godMode = !g.godMode
+	if g.godMode {
+		return "god mode on", nil
+	}
+	return "god mode off", nil
+}
+
+// consoleGive adds scrap to the persistent progression or restores health and shield
+func (g *Game) consoleGive(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", errors.New("missing resource or amount")
+	}
+	amount, err := parseIntArg(args[1], "amount", 1, 1000000)
+	if err != nil {
+		return "", err
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "scrap":
+		if g.progression == nil {
+			return "", errors.New("progression is not available")
+		}
+		g.markConsoleUsed()
+		g.progression.AddScrap(amount)
+		if err := g.progression.Save(); err != nil {
+			return "", fmt.Errorf("failed to save progression: %w", err)
+		}
+		return fmt.Sprintf("scrap total %d", g.progression.GetTotalScrap()), nil
+	case "health":
+		if err := g.requireRun(); err != nil {
+			return "", err
+		}
+		g.markConsoleUsed()
+		g.player.Health = min(g.player.Health+amount, g.player.MaxHealth)
+		return fmt.Sprintf("health %d/%d", g.player.Health, g.player.MaxHealth), nil
+	case "shield":
+		if err := g.requireRun(); err != nil {
+			return "", err
+		}
+		g.markConsoleUsed()
+		g.player.Shield = min(g.player.Shield+amount, g.player.MaxShield)
+		return fmt.Sprintf("shield %d/%d", g.player.Shield, g.player.MaxShield), nil
+	default:
+		return "", fmt.Errorf("unknown resource %q", args[0])
+	}
+}
+
+// consoleTimeScale sets the global time scale of the simulation
+func (g *Game) consoleTimeScale(args []string) (string, error) {
+	if err := g.requireRun(); err != nil {
+		return "", err
+	}
+	if len(args) < 1 {
+		return fmt.Sprintf("time scale %.2f", g.clock.Scale), nil
+	}
+	scale, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid time scale %q", args[0])
+	}
+
+	g.markConsoleUsed()
+	g.clock.SetScale(scale)
+	return fmt.Sprintf("time scale %.2f", g.clock.Scale), nil
+}
+
+// consoleHazard places an environmental hazard in the upper play area
+func (g *Game) consoleHazard(args []string) (string, error) {
+	if err := g.requireRun(); err != nil {
+		return "", err
+	}
+	if len(args) < 1 {
+		return "", errors.New("missing hazard type")
+	}
+	hazardType, ok := consoleHazardTypes[strings.ToLower(args[0])]
+	if !ok {
+		return "", fmt.Errorf("unknown hazard type %q", args[0])
+	}
+
+	g.markConsoleUsed()
+	x := 150 + rng.Float64()*(ScreenWidth-300)
+	y := 150 + rng.Float64()*(ScreenHeight/2-150)
+	h := entities.NewHazard(x, y, hazardType)
+	g.hazards = append(g.hazards, h)
+	return "placed " + h.Name, nil
+}
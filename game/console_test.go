@@ -0,0 +1,86 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"stellar-siege/game/entities"
+)
+
+func TestConsoleCommandsAlterRun(t *testing.T) {
+	g := NewHeadlessGame(5, DifficultyNormal)
+	run := func(line string) {
+		t.Helper()
+		before := len(g.console.Output())
+		g.console.SetInput(line)
+		g.console.Execute()
+		for _, out := range g.console.Output()[before:] {
+			if strings.HasPrefix(out, "error:") {
+				t.Fatalf("%s: %s", line, out)
+			}
+		}
+	}
+
+	enemies := len(g.enemies)
+	run("spawn hunter 5")
+	if len(g.enemies) != enemies+5 || g.enemies[len(g.enemies)-1].Type != entities.EnemyHunter {
+		t.Fatalf("spawn hunter 5 left %d enemies, want %d hunters added", len(g.enemies), 5)
+	}
+
+	run("weapon ion_beam 5")
+	if w := g.player.WeaponMgr.GetCurrentWeapon(); w.Type != entities.WeaponTypeIonBeam || w.Level != entities.WeaponLevelMkV {
+		t.Fatalf("equipped %s level %d, want ion_beam level 5", w.Type, w.Level)
+	}
+
+	run("wave 12")
+	if g.wave != 12 || g.bossWave {
+		t.Fatalf("wave 12 gave wave %d (boss %v)", g.wave, g.bossWave)
+	}
+
+	run("boss 3")
+	if g.boss == nil || g.boss.BossLevel != 3 || !g.bossWave {
+		t.Fatal("boss 3 did not start a level 3 boss fight")
+	}
+
+	run("timescale 0.5")
+	if g.clock.Scale != 0.5 {
+		t.Fatalf("time scale = %v, want 0.5", g.clock.Scale)
+	}
+
+	run("god")
+	health := g.player.Health
+	g.damagePlayer(0, 1000, "test")
+	if g.player.Health != health {
+		t.Fatal("god mode did not prevent damage")
+	}
+
+	run("hazard blackhole")
+	if len(g.hazards) != 1 || g.hazards[0].Type != entities.HazardTypeBlackHole {
+		t.Fatal("hazard blackhole did not place a black hole")
+	}
+	for i := 0; i < 60; i++ {
+		g.Step(0)
+	}
+}
+
+func TestConsoleUseMakesRunUnranked(t *testing.T) {
+	g := NewHeadlessGame(5, DifficultyNormal)
+	g.console.SetInput("help")
+	g.console.Execute()
+	if g.consoleUsed || g.replay == nil {
+		t.Fatal("help alone should not make the run unranked")
+	}
+
+	g.console.SetInput("spawn scout")
+	g.console.Execute()
+	if !g.consoleUsed || g.replay != nil {
+		t.Fatal("a cheat command should make the run unranked and drop its replay")
+	}
+
+	g.player.Health = 0
+	g.player.Active = false
+	g.Step(0)
+	if g.state != StateGameOver || g.nameInputMode {
+		t.Fatal("an unranked run should end without leaderboard name entry")
+	}
+}
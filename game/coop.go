@@ -0,0 +1,252 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"stellar-siege/game/entities"
+	"stellar-siege/game/systems"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Co-op tuning
+const (
+	ReviveRange         = 70.0 // A partner must stay this close to a downed ship to revive it
+	ReviveTime          = 3.0  // Seconds the partner must stay close
+	ReviveHealth        = 0.5  // Share of max health a revived ship returns with
+	ReviveInvincibility = 2.0  // Seconds a revived ship cannot be hit
+)
+
+// pilot is one player's ship in a run. Solo runs have a single pilot whose
+// ship is g.player; co-op runs have one per player, each with its own
+// weapons, abilities, health and shield.
+type pilot struct {
+	ship   *entities.Player
+	down   bool    // The ship was destroyed and waits for a partner to revive it
+	revive float64 // Seconds a partner has spent next to the downed ship
+}
+
+// pilotColors tell the pilots' labels and HUD panels apart
+var pilotColors = []color.RGBA{
+	{100, 200, 255, 255},
+	{255, 170, 80, 255},
+	{140, 255, 120, 255},
+	{230, 120, 255, 255},
+}
+
+// coop reports whether the run has more than one pilot
+func (g *Game) coop() bool {
+	return len(g.pilots) > 1
+}
+
+// spawnPilots places a ship for each pilot of the new run, with the
+// difficulty's health and shield
+func (g *Game) spawnPilots(count int) {
+	g.pilots = g.pilots[:0]
+	for i := 0; i < count; i++ {
+		x := float64(ScreenWidth) * float64(i+1) / float64(count+1)
+		ship := entities.NewPlayer(x, ScreenHeight-100)
+		ship.Health = g.difficultyConfig.PlayerHealth
+		ship.MaxHealth = g.difficultyConfig.PlayerHealth
+		ship.Shield = g.difficultyConfig.PlayerMaxShield
+		ship.MaxShield = g.difficultyConfig.PlayerMaxShield
+		ship.ShieldRegenRate = g.difficultyConfig.ShieldRegenRate
+		ship.InvincibilityTime = g.difficultyConfig.InvincibilityTime
+		ship.ShieldRegenDelay = g.difficultyConfig.ShieldRegenDelay
+		ship.LastDamageTime = -999 // Start with regen available
+		g.pilots = append(g.pilots, &pilot{ship: ship})
+	}
+	g.player = g.pilots[0].ship
+	g.nextTarget = 0
+	if g.coop() {
+		g.replay.Pilots = count
+		g.runStats.Pilot(count - 1)
+	}
+}
+
+// pilotStats returns the co-op stats of pilot i, or nil in solo runs
+func (g *Game) pilotStats(i int) *systems.PilotStats {
+	if !g.coop() || i < 0 || i >= len(g.pilots) {
+		return nil
+	}
+	return g.runStats.Pilot(i)
+}
+
+// creditPilot adds points and kills to the co-op stats of the pilot whose
+// projectile earned them
+func (g *Game) creditPilot(owner int, points int64, kills int) {
+	if s := g.pilotStats(owner); s != nil {
+		s.Score += points
+		s.Kills += kills
+	}
+}
+
+// leaderboardMode returns the leaderboard mode the run is ranked in
+func (g *Game) leaderboardMode() string {
+	if g.coop() {
+		return systems.LeaderboardModeCoop
+	}
+	return systems.ChallengeModeEndless.String()
+}
+
+// anyShipActive reports whether any pilot is still flying
+func (g *Game) anyShipActive() bool {
+	for _, pl := range g.pilots {
+		if pl.ship.Active {
+			return true
+		}
+	}
+	return false
+}
+
+// assignTargets spreads newly spawned enemies across the pilots in turn
+func (g *Game) assignTargets(enemies []*entities.Enemy) {
+	for _, e := range enemies {
+		e.Target = g.nextTarget % len(g.pilots)
+		g.nextTarget++
+	}
+}
+
+// targetShip returns the ship an enemy assigned to pilot i goes after: that
+// pilot's ship while it flies, otherwise the flying ship nearest to x, y.
+// Pass -1 for the nearest ship. g.player is the target once all are down.
+func (g *Game) targetShip(i int, x, y float64) *entities.Player {
+	if i >= 0 && i < len(g.pilots) && g.pilots[i].ship.Active {
+		return g.pilots[i].ship
+	}
+	target, best := g.player, math.Inf(1)
+	for _, pl := range g.pilots {
+		if !pl.ship.Active {
+			continue
+		}
+		if d := math.Hypot(pl.ship.X-x, pl.ship.Y-y); d < best {
+			target, best = pl.ship, d
+		}
+	}
+	return target
+}
+
+// updateRevives brings back downed ships whose partner stays next to them
+// for ReviveTime. Progress is lost as soon as no partner is in range.
+func (g *Game) updateRevives() {
+	if !g.coop() {
+		return
+	}
+	for i, pl := range g.pilots {
+		if pl.ship.Active {
+			continue
+		}
+		if !pl.down {
+			pl.down, pl.revive = true, 0
+			g.pilotStats(i).Downs++
+			g.announcements.AddMysteryBoxAnnouncement(fmt.Sprintf("PILOT %d DOWN - FLY CLOSE TO REVIVE", i+1), false, ScreenWidth/2, ScreenHeight/2)
+		}
+
+		rescuer := -1
+		for j, partner := range g.pilots {
+			if j != i && partner.ship.Active &&
+				math.Hypot(partner.ship.X-pl.ship.X, partner.ship.Y-pl.ship.Y) <= ReviveRange {
+				rescuer = j
+				break
+			}
+		}
+		if rescuer < 0 {
+			pl.revive = 0
+			continue
+		}
+		pl.revive += g.deltaTime
+		if pl.revive >= ReviveTime {
+			g.reviveShip(i)
+			g.pilotStats(rescuer).Revives++
+		}
+	}
+}
+
+// reviveShip returns pilot i's downed ship to the fight
+func (g *Game) reviveShip(i int) {
+	pl := g.pilots[i]
+	ship := pl.ship
+	ship.Active = true
+	ship.Health = max(1, int(float64(ship.MaxHealth)*ReviveHealth))
+	ship.Shield = 0
+	ship.InvincTimer = ReviveInvincibility
+	ship.LastDamageTime = g.gameTime
+	pl.down, pl.revive = false, 0
+	g.deathCause = "" // The run goes on, so the killing blow is yet to come
+
+	g.sound.PlaySound(systems.SoundPowerUpCollect)
+	ft := entities.NewFloatingText(ship.X, ship.Y-50, "REVIVED", color.RGBA{50, 255, 50, 255})
+	g.floatingTexts = append(g.floatingTexts, ft)
+}
+
+// drawPilotMarkers labels each ship in co-op runs, and marks downed ships
+// with their revive progress
+func (g *Game) drawPilotMarkers(screen *ebiten.Image, shakeX, shakeY float64) {
+	if !g.coop() {
+		return
+	}
+	for i, pl := range g.pilots {
+		c := pilotColors[i%len(pilotColors)]
+		x, y := pl.ship.X+shakeX, pl.ship.Y+shakeY
+		label := fmt.Sprintf("P%d", i+1)
+		if g.net != nil && i == g.net.Slot() {
+			label = "YOU"
+		}
+		if pl.ship.Active {
+			systems.DrawTextCentered(screen, label, int(x), int(y+pl.ship.Radius+8), 1, c)
+			continue
+		}
+
+		// Downed: the revive range, and a bar that fills up while a partner
+		// stays within it
+		vector.StrokeCircle(screen, float32(x), float32(y), ReviveRange, 1, color.RGBA{c.R, c.G, c.B, 80}, true)
+		if pl.revive > 0 {
+			drawPanelBar(screen, float32(x)-30, float32(y)+14, 60, 6, pl.revive/ReviveTime, c)
+		}
+		systems.DrawTextCentered(screen, label+" DOWN", int(x), int(y-6), 1.2, c)
+	}
+}
+
+// drawPilotPanel draws pilot 2's health, shield and weapon at the bottom
+// left; pilot 1 keeps the usual HUD at the top left
+func (g *Game) drawPilotPanel(screen *ebiten.Image) {
+	if !g.coop() {
+		return
+	}
+	for i, pl := range g.pilots[1:] {
+		n := i + 2
+		ship := pl.ship
+		c := pilotColors[(n-1)%len(pilotColors)]
+		x, y := float32(20), float32(ScreenHeight-110-70*i)
+		systems.DrawText(screen, fmt.Sprintf("PILOT %d", n), int(x), int(y)-18, 1.2, c)
+		health := max(0, float64(ship.Health)) / float64(ship.MaxHealth)
+		drawPanelBar(screen, x, y, 200, 16, health, color.RGBA{50, 200, 50, 255})
+		if ship.MaxShield > 0 {
+			drawPanelBar(screen, x, y+20, 150, 10, float64(ship.Shield)/float64(ship.MaxShield), color.RGBA{50, 150, 255, 255})
+		}
+		if w := ship.WeaponMgr.GetCurrentWeapon(); w != nil {
+			systems.DrawText(screen, fmt.Sprintf("%s Mk%d", w.Name, int(w.Level)), int(x), int(y)+36, 1, color.RGBA{200, 200, 200, 255})
+		}
+	}
+}
+
+// drawPanelBar draws a filled bar of a pilot panel
+func drawPanelBar(screen *ebiten.Image, x, y, width, height float32, ratio float64, fill color.RGBA) {
+	vector.DrawFilledRect(screen, x, y, width, height, color.RGBA{30, 30, 30, 200}, true)
+	if ratio > 0 {
+		vector.DrawFilledRect(screen, x, y, width*float32(math.Min(ratio, 1)), height, fill, true)
+	}
+	vector.StrokeRect(screen, x, y, width, height, 1, color.RGBA{255, 255, 255, 150}, true)
+}
+
+// drawPilotSummary lists each pilot's share of a finished co-op run
+func (g *Game) drawPilotSummary(screen *ebiten.Image, y int) {
+	for i, s := range g.runStats.Pilots {
+		line := fmt.Sprintf("P%d  %s pts  %d kills  %d damage taken  %d downs  %d revives",
+			i+1, systems.FormatNumber(s.Score), s.Kills, s.DamageTaken, s.Downs, s.Revives)
+		systems.DrawTextCentered(screen, line, ScreenWidth/2, y+i*24, 1.3, pilotColors[i%len(pilotColors)])
+	}
+}
@@ -0,0 +1,76 @@
+package game
+
+import (
+	"testing"
+
+	"stellar-siege/game/entities"
+)
+
+func TestCoopPilotsHaveTheirOwnControls(t *testing.T) {
+	frame := entities.CombineInputs(entities.InputLeft, entities.InputRight|entities.InputShoot)
+	if frame.Pilot(0) != entities.InputLeft || frame.Pilot(1) != entities.InputRight|entities.InputShoot {
+		t.Fatalf("frame %s splits into %s and %s", frame, frame.Pilot(0), frame.Pilot(1))
+	}
+
+	g := NewHeadlessCoopGame(3, DifficultyNormal, 2)
+	if len(g.pilots) != 2 || g.player != g.pilots[0].ship || g.Replay().Pilots != 2 {
+		t.Fatalf("co-op run started with %d pilots", len(g.pilots))
+	}
+	one, two := g.pilots[0].ship, g.pilots[1].ship
+	x1, x2 := one.X, two.X
+	for i := 0; i < 30; i++ {
+		g.Step(entities.CombineInputs(0, entities.InputRight))
+	}
+	if one.X != x1 || two.X <= x2 {
+		t.Errorf("pilot 2 steering right moved ship 1 by %v and ship 2 by %v", one.X-x1, two.X-x2)
+	}
+	if one.WeaponMgr == two.WeaponMgr || one.AbilityMgr == two.AbilityMgr {
+		t.Error("pilots share weapon or ability managers")
+	}
+}
+
+func TestCoopDownedPilotIsRevivedByPartner(t *testing.T) {
+	g := NewHeadlessCoopGame(3, DifficultyNormal, 2)
+	one, two := g.pilots[0].ship, g.pilots[1].ship
+
+	two.Health, two.Active = 0, false
+	if !g.Step(0) {
+		t.Fatal("run ended with a pilot still flying")
+	}
+	if s := g.runStats.Pilots[1]; s.Downs != 1 {
+		t.Fatalf("pilot 2 downs = %d, want 1", s.Downs)
+	}
+
+	// Park pilot 1 on the wreck until it is revived
+	for i := 0; i < int(ReviveTime*60)+30 && !two.Active; i++ {
+		one.X, one.Y = two.X, two.Y
+		one.InvincTimer = 1 // Keep pilot 1 alive while it waits
+		g.Step(0)
+	}
+	if !two.Active || two.Health != int(float64(two.MaxHealth)*ReviveHealth) {
+		t.Fatalf("pilot 2 active %v with %d health after waiting to be revived", two.Active, two.Health)
+	}
+	if s := g.runStats.Pilots[0]; s.Revives != 1 {
+		t.Errorf("pilot 1 revives = %d, want 1", s.Revives)
+	}
+
+	one.Health, one.Active = 0, false
+	two.Health, two.Active = 0, false
+	if g.Step(0) {
+		t.Error("run went on with every pilot down")
+	}
+}
+
+func TestCoopReplayVerifies(t *testing.T) {
+	// Pilot 1 idles while pilot 2 holds fire; neither moves, so both are
+	// eventually destroyed
+	g := NewHeadlessCoopGame(11, DifficultyHard, 2)
+	for i := 0; i < 60*60*20 && g.Step(entities.CombineInputs(0, entities.InputShoot)); i++ {
+	}
+	if g.state != StateGameOver {
+		t.Fatalf("co-op run still going after %d ticks", g.Tick())
+	}
+	if record := VerifyReplay(g.Replay()); !record.Verified {
+		t.Fatalf("co-op run failed verification: %s", record.Reason)
+	}
+}
@@ -0,0 +1,315 @@
+package core
+
+import (
+	"math"
+	"strings"
+
+	"stellar-siege/game/entities"
+	"stellar-siege/game/rng"
+)
+
+// BotSkill tunes how well the autopilot plays. Lower skill reacts later,
+// looks less far ahead and makes more mistakes.
+type BotSkill struct {
+	Name          string
+	ReactionTicks int     // Ticks between decisions; input is held in between
+	ThreatRadius  float64 // How far around the ship hostile projectiles are scanned
+	LookAhead     int     // How many ticks ahead projectile paths are predicted
+	DodgeMargin   float64 // Extra clearance kept around predicted impacts
+	AimTolerance  float64 // Horizontal distance to a target considered "lined up"
+	PowerUpRange  float64 // Maximum detour taken to collect a power-up
+	MistakeChance float64 // Probability a decision is replaced with random input
+}
+
+// Bot skill presets used by the demo mode and balance runs
+var (
+	BotSkillNovice = BotSkill{
+		Name:          "novice",
+		ReactionTicks: 18,
+		ThreatRadius:  120,
+		LookAhead:     12,
+		DodgeMargin:   4,
+		AimTolerance:  60,
+		PowerUpRange:  120,
+		MistakeChance: 0.15,
+	}
+	BotSkillAverage = BotSkill{
+		Name:          "average",
+		ReactionTicks: 8,
+		ThreatRadius:  180,
+		LookAhead:     24,
+		DodgeMargin:   10,
+		AimTolerance:  30,
+		PowerUpRange:  250,
+		MistakeChance: 0.05,
+	}
+	BotSkillExpert = BotSkill{
+		Name:          "expert",
+		ReactionTicks: 2,
+		ThreatRadius:  260,
+		LookAhead:     40,
+		DodgeMargin:   16,
+		AimTolerance:  12,
+		PowerUpRange:  400,
+		MistakeChance: 0,
+	}
+)
+
+// BotSkillByName returns the preset with the given name
+func BotSkillByName(name string) (BotSkill, bool) {
+	for _, skill := range []BotSkill{BotSkillNovice, BotSkillAverage, BotSkillExpert} {
+		if strings.EqualFold(skill.Name, name) {
+			return skill, true
+		}
+	}
+	return BotSkill{}, false
+}
+
+// BotView is the read-only view of the world the autopilot decides from
+type BotView struct {
+	Player       *entities.Player
+	Enemies      []*entities.Enemy
+	Boss         *entities.Boss
+	PowerUps     []*entities.PowerUp
+	Asteroids    []*entities.Asteroid
+	Grid         *SpatialGrid
+	ScreenWidth  float64
+	ScreenHeight float64
+}
+
+// Autopilot produces input frames for the player's ship: it dodges hostile
+// projectiles, collects power-ups and lines up with the most pressing target.
+// It draws from its own random source so that it never disturbs the
+// simulation's RNG and bot runs stay reproducible from their replays.
+type Autopilot struct {
+	skill     BotSkill
+	rand      *rng.Rand
+	held      entities.InputFrame
+	nextThink int
+	tick      int
+}
+
+// NewAutopilot creates an autopilot with the given skill
+func NewAutopilot(skill BotSkill, seed int64) *Autopilot {
+	if skill.ReactionTicks < 1 {
+		skill.ReactionTicks = 1
+	}
+	return &Autopilot{
+		skill: skill,
+		rand:  rng.New(seed),
+	}
+}
+
+// Skill returns the skill the autopilot plays with
+func (a *Autopilot) Skill() BotSkill {
+	return a.skill
+}
+
+// NextInput returns the input frame for the coming tick
+func (a *Autopilot) NextInput(view BotView) entities.InputFrame {
+	a.tick++
+	if a.tick < a.nextThink {
+		return a.held
+	}
+	a.nextThink = a.tick + a.skill.ReactionTicks
+
+	if view.Player == nil || !view.Player.Active {
+		a.held = 0
+		return a.held
+	}
+
+	if a.skill.MistakeChance > 0 && a.rand.Float64() < a.skill.MistakeChance {
+		a.held = entities.InputFrame(a.rand.Intn(int(entities.InputShoot) << 1))
+		return a.held
+	}
+
+	a.held = a.decide(view)
+	return a.held
+}
+
+// decide combines threat avoidance with steering toward targets and power-ups
+func (a *Autopilot) decide(view BotView) entities.InputFrame {
+	p := view.Player
+
+	// Steering toward where we want to be
+	goalX, goalY, hasTarget := a.chooseGoal(view)
+	steerX := clampUnit((goalX - p.X) / 100)
+	steerY := clampUnit((goalY - p.Y) / 100)
+	if math.Abs(goalX-p.X) < a.skill.AimTolerance {
+		steerX = 0
+	}
+	if math.Abs(goalY-p.Y) < a.skill.AimTolerance {
+		steerY = 0
+	}
+
+	// Repulsion from anything about to hit us dominates the steering
+	dodgeX, dodgeY := a.threatAvoidance(view)
+	moveX := steerX + dodgeX*8
+	moveY := steerY + dodgeY*8
+
+	// Keep away from the screen edges so dodges have room
+	margin := p.Radius * 3
+	if p.X < margin {
+		moveX += 1
+	} else if p.X > view.ScreenWidth-margin {
+		moveX -= 1
+	}
+	if p.Y < view.ScreenHeight*0.4 {
+		moveY += 1
+	} else if p.Y > view.ScreenHeight-margin {
+		moveY -= 1
+	}
+
+	var frame entities.InputFrame
+	const deadzone = 0.15
+	if moveX < -deadzone {
+		frame |= entities.InputLeft
+	} else if moveX > deadzone {
+		frame |= entities.InputRight
+	}
+	if moveY < -deadzone {
+		frame |= entities.InputUp
+	} else if moveY > deadzone {
+		frame |= entities.InputDown
+	}
+	if hasTarget {
+		frame |= entities.InputShoot
+	}
+	return frame
+}
+
+// chooseGoal picks the position the ship should head for. Nearby power-ups
+// win over targets; otherwise the ship lines up below the highest-priority
+// enemy (or the boss) at its preferred cruising height.
+func (a *Autopilot) chooseGoal(view BotView) (x, y float64, hasTarget bool) {
+	p := view.Player
+	cruiseY := view.ScreenHeight - 80
+	x, y = view.ScreenWidth/2, cruiseY
+
+	bestScore := math.Inf(-1)
+	for _, e := range view.Enemies {
+		// Enemies already close overhead are threats to avoid, not targets
+		if !e.Active || e.Y < 0 || p.Y-e.Y < 200 {
+			continue
+		}
+		score := enemyPriority(e) - math.Abs(e.X-p.X)/200
+		if score > bestScore {
+			bestScore = score
+			x = e.X
+			hasTarget = true
+		}
+	}
+	if view.Boss != nil && view.Boss.Active && !view.Boss.IsDead() {
+		if !hasTarget || bestScore < 3 {
+			x = view.Boss.X
+		}
+		hasTarget = true
+	}
+	if !hasTarget {
+		for _, ast := range view.Asteroids {
+			if ast.Active && ast.Y > 0 {
+				x = ast.X
+				hasTarget = true
+				break
+			}
+		}
+	}
+
+	// Detour for power-ups within reach
+	bestDist := a.skill.PowerUpRange
+	for _, pu := range view.PowerUps {
+		if !pu.Active {
+			continue
+		}
+		dist := math.Hypot(pu.X-p.X, pu.Y-p.Y)
+		if dist < bestDist {
+			bestDist = dist
+			x, y = pu.X, math.Max(pu.Y, view.ScreenHeight*0.45)
+		}
+	}
+	return x, y, hasTarget
+}
+
+// clampUnit limits v to the range [-1, 1]
+func clampUnit(v float64) float64 {
+	return math.Max(-1, math.Min(1, v))
+}
+
+// enemyPriority ranks enemy types by how urgently they should be destroyed
+func enemyPriority(e *entities.Enemy) float64 {
+	switch e.Type {
+	case entities.EnemySniper, entities.EnemyBomber:
+		return 3
+	case entities.EnemyHunter, entities.EnemyTank:
+		return 2
+	case entities.EnemyShieldBearer, entities.EnemySplitter:
+		return 1.5
+	default:
+		return 1
+	}
+}
+
+// threatAvoidance returns a unit-ish vector pointing away from projectiles,
+// enemies and asteroids predicted to hit the ship within the look-ahead window
+func (a *Autopilot) threatAvoidance(view BotView) (float64, float64) {
+	p := view.Player
+	var ax, ay float64
+	// Velocities are per second, so predict in seconds
+	lookAhead := float64(a.skill.LookAhead) * entities.FixedTimestep
+	falloff := float64(a.skill.LookAhead+1) * entities.FixedTimestep
+
+	avoid := func(ox, oy, vx, vy, radius float64) {
+		// Closest approach of the object's straight-line path to the ship
+		rx, ry := ox-p.X, oy-p.Y
+		t := 0.0
+		if speed2 := vx*vx + vy*vy; speed2 > 0 {
+			t = -(rx*vx + ry*vy) / speed2
+		}
+		if t < 0 || t > lookAhead {
+			return
+		}
+		cx, cy := rx+vx*t, ry+vy*t
+		dist := math.Hypot(cx, cy)
+		clearance := p.Radius + radius + a.skill.DodgeMargin
+		if dist >= clearance {
+			return
+		}
+
+		// Push perpendicular to the path, away from the impact point;
+		// sooner impacts push harder
+		weight := (1 - dist/clearance) * (1 - t/falloff)
+		if dist < 0.001 {
+			// Dead-on hit: sidestep toward the roomier side of the screen
+			if p.X < view.ScreenWidth/2 {
+				cx = -1
+			} else {
+				cx = 1
+			}
+			dist = 1
+		}
+		ax -= cx / dist * weight
+		ay -= cy / dist * weight
+	}
+
+	if view.Grid != nil {
+		for _, proj := range view.Grid.GetNearbyProjectiles(p.X, p.Y, a.skill.ThreatRadius) {
+			if proj.Active && !proj.Friendly {
+				avoid(proj.X, proj.Y, proj.VelX, proj.VelY, proj.Radius)
+			}
+		}
+	}
+	for _, e := range view.Enemies {
+		if e.Active && math.Abs(e.X-p.X) < a.skill.ThreatRadius && math.Abs(e.Y-p.Y) < a.skill.ThreatRadius {
+			avoid(e.X, e.Y, e.VelX, e.VelY, e.Radius)
+		}
+	}
+	for _, ast := range view.Asteroids {
+		if ast.Active && math.Abs(ast.X-p.X) < a.skill.ThreatRadius && math.Abs(ast.Y-p.Y) < a.skill.ThreatRadius {
+			avoid(ast.X, ast.Y, ast.VelX, ast.VelY, ast.Radius)
+		}
+	}
+	if b := view.Boss; b != nil && b.Active {
+		avoid(b.X, b.Y, b.VelX, b.VelY, b.Radius*0.5)
+	}
+	return ax, ay
+}
@@ -314,3 +314,42 @@ func (sg *SpatialGrid) PopulateGrid(
 		sg.AddAsteroid(asteroid)
 	}
 }
+
+// CellCount is the number of entities filed under one grid cell
+type CellCount struct {
+	Col, Row    int
+	Enemies     int
+	Projectiles int
+	Powerups    int
+	Asteroids   int
+}
+
+// Total returns the number of entities in the cell
+func (c CellCount) Total() int {
+	return c.Enemies + c.Projectiles + c.Powerups + c.Asteroids
+}
+
+// CellSize returns the edge length of a grid cell in pixels
+func (sg *SpatialGrid) CellSize() float64 {
+	return sg.cellSize
+}
+
+// OccupiedCells appends every cell holding at least one entity to buf
+func (sg *SpatialGrid) OccupiedCells(buf []CellCount) []CellCount {
+	for y := 0; y < sg.gridHeight; y++ {
+		for x := 0; x < sg.gridWidth; x++ {
+			c := CellCount{
+				Col:         x,
+				Row:         y,
+				Enemies:     len(sg.enemyGrid[y][x].Enemies),
+				Projectiles: len(sg.projectileGrid[y][x].Projectiles),
+				Powerups:    len(sg.powerupGrid[y][x].Powerups),
+				Asteroids:   len(sg.asteroidGrid[y][x].Asteroids),
+			}
+			if c.Total() > 0 {
+				buf = append(buf, c)
+			}
+		}
+	}
+	return buf
+}
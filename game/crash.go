@@ -0,0 +1,238 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"stellar-siege/game/entities"
+	"stellar-siege/game/systems"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// CrashInputTicks is how many of the latest input frames a crash report holds
+const CrashInputTicks = 5 * entities.TicksPerSecond
+
+// inputRing keeps the latest CrashInputTicks input frames
+type inputRing struct {
+	frames [CrashInputTicks]entities.InputFrame
+	next   int
+	count  int
+}
+
+// Push records the input of one tick
+func (r *inputRing) Push(frame entities.InputFrame) {
+	r.frames[r.next] = frame
+	r.next = (r.next + 1) % CrashInputTicks
+	if r.count < CrashInputTicks {
+		r.count++
+	}
+}
+
+// Frames returns the recorded frames, oldest first
+func (r *inputRing) Frames() []entities.InputFrame {
+	frames := make([]entities.InputFrame, 0, r.count)
+	start := (r.next - r.count + CrashInputTicks) % CrashInputTicks
+	for i := 0; i < r.count; i++ {
+		frames = append(frames, r.frames[(start+i)%CrashInputTicks])
+	}
+	return frames
+}
+
+// CrashReport describes a panic recovered from the game loop
+type CrashReport struct {
+	Time          time.Time
+	Phase         string // "update" or "draw"
+	Panic         string
+	Stack         string
+	Version       string
+	Seed          int64
+	Difficulty    string
+	Tick          int
+	State         string
+	PreviousState string
+	Wave          int
+	Score         int64
+	Entities      map[string]int
+	Inputs        []entities.InputFrame // Latest inputs, oldest first; the last is Tick's
+}
+
+// captureCrash builds a crash report from the game's current state
+func (g *Game) captureCrash(phase string, value any, stack []byte) *CrashReport {
+	r := &CrashReport{
+		Time:       time.Now(),
+		Phase:      phase,
+		Panic:      fmt.Sprint(value),
+		Stack:      string(stack),
+		Version:    Version,
+		Seed:       g.seed,
+		Difficulty: GetDifficultyName(g.selectedDifficulty),
+		Tick:       g.tick,
+		State:      gameStateNames[g.state],
+		Wave:       g.wave,
+		Score:      g.score,
+		Entities: map[string]int{
+			"enemies":     len(g.enemies),
+			"projectiles": len(g.projectiles),
+			"explosions":  len(g.explosions),
+			"powerups":    len(g.powerups),
+			"asteroids":   len(g.asteroids),
+			"hazards":     len(g.hazards),
+		},
+		Inputs: g.recentInputs.Frames(),
+	}
+	if g.stateMachine != nil {
+		r.State = g.stateMachine.GetCurrentStateType().String()
+		r.PreviousState = g.stateMachine.GetPreviousStateType().String()
+	}
+	if g.boss != nil && g.boss.Active {
+		r.Entities["boss"] = 1
+	}
+	return r
+}
+
+// String formats the report as plain text. Inputs are run-length encoded
+// and labelled with the tick they started on.
+func (r *CrashReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "STELLAR SIEGE CRASH REPORT\n\n")
+	fmt.Fprintf(&b, "time:       %s\n", r.Time.Format(time.RFC3339))
+	fmt.Fprintf(&b, "version:    %s (%s %s/%s)\n", r.Version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "panic:      %s (during %s)\n", r.Panic, r.Phase)
+	fmt.Fprintf(&b, "state:      %s", r.State)
+	if r.PreviousState != "" {
+		fmt.Fprintf(&b, " (previous: %s)", r.PreviousState)
+	}
+	fmt.Fprintf(&b, "\nseed:       %d\n", r.Seed)
+	fmt.Fprintf(&b, "difficulty: %s\n", r.Difficulty)
+	fmt.Fprintf(&b, "tick:       %d\n", r.Tick)
+	fmt.Fprintf(&b, "wave:       %d\n", r.Wave)
+	fmt.Fprintf(&b, "score:      %d\n", r.Score)
+
+	b.WriteString("\nentities:\n")
+	for _, name := range sortedKeys(r.Entities) {
+		fmt.Fprintf(&b, "  %-12s %d\n", name, r.Entities[name])
+	}
+
+	fmt.Fprintf(&b, "\nlast %d inputs:\n", len(r.Inputs))
+	tick := r.Tick - len(r.Inputs) + 1
+	for i := 0; i < len(r.Inputs); {
+		j := i
+		for j < len(r.Inputs) && r.Inputs[j] == r.Inputs[i] {
+			j++
+		}
+		fmt.Fprintf(&b, "  tick %-8d %-20s x%d\n", tick+i, r.Inputs[i], j-i)
+		i = j
+	}
+
+	fmt.Fprintf(&b, "\nstack:\n%s", r.Stack)
+	return b.String()
+}
+
+// Save writes the report to a file, creating parent directories as needed
+func (r *CrashReport) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create crash report directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(r.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write crash report: %w", err)
+	}
+	return nil
+}
+
+// crashScreen is shown in place of the game after a recovered panic
+type crashScreen struct {
+	report     *CrashReport
+	reportPath string // Empty if the report could not be written
+	replay     *systems.Replay
+	message    string // Result of the last save attempt
+}
+
+// recoverCrash is deferred at the top of Update and Draw. It turns a panic
+// into a crash report and replaces the game with the crash screen.
+func (g *Game) recoverCrash(phase string) {
+	value := recover()
+	if value == nil {
+		return
+	}
+	report := g.captureCrash(phase, value, debug.Stack())
+	log.Printf("Recovered from panic during %s: %v", phase, value)
+
+	name := fmt.Sprintf("crash_%s.txt", report.Time.Format("20060102_150405"))
+	path := filepath.Join(g.crashDir, name)
+	if g.crashDir == "" {
+		path = systems.GetDataPath(name)
+	}
+	if err := report.Save(path); err != nil {
+		log.Printf("Failed to save crash report: %v", err)
+		path = ""
+	} else {
+		log.Printf("Crash report written to %s", path)
+	}
+
+	g.crash = &crashScreen{report: report, reportPath: path, replay: g.replay}
+	g.stopPerfTrace()
+}
+
+// saveCrashReplay writes the replay of the crashed run up to the crash. It
+// is left unfinished, so it re-simulates the run rather than verifying a score.
+func (g *Game) saveCrashReplay(name string) error {
+	if g.crash == nil || g.crash.replay == nil {
+		return fmt.Errorf("no replay was recorded for this run")
+	}
+	if err := g.crash.replay.SaveTo(g.storage, name); err != nil {
+		return fmt.Errorf("failed to save partial replay: %w", err)
+	}
+	return nil
+}
+
+// updateCrashScreen handles input on the crash screen
+func (g *Game) updateCrashScreen() error {
+	if ebiten.IsWindowBeingClosed() || inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyQ) {
+		return ebiten.Termination
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyS) && g.crash.replay != nil {
+		name := fmt.Sprintf("crash_replay_%s.json", g.crash.report.Time.Format("20060102_150405"))
+		if err := g.saveCrashReplay(name); err != nil {
+			g.crash.message = err.Error()
+		} else {
+			g.crash.message = "Replay saved to " + systems.StoragePath(g.storage, name)
+		}
+	}
+	return nil
+}
+
+// drawCrashScreen draws the crash screen
+func (g *Game) drawCrashScreen(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{20, 5, 10, 255})
+	c := g.crash
+
+	systems.DrawTextCentered(screen, "SOMETHING WENT WRONG", ScreenWidth/2, 140, 3, color.RGBA{255, 80, 80, 255})
+	systems.DrawTextCentered(screen, "Stellar Siege hit an error and had to stop the game.", ScreenWidth/2, 210, 1.5, color.RGBA{220, 220, 220, 255})
+	systems.DrawTextCentered(screen, c.report.Panic, ScreenWidth/2, 250, 1, color.RGBA{255, 160, 160, 255})
+
+	if c.reportPath != "" {
+		systems.DrawTextCentered(screen, "A crash report was saved to "+c.reportPath, ScreenWidth/2, 310, 1.2, color.RGBA{200, 200, 200, 255})
+	} else {
+		systems.DrawTextCentered(screen, "The crash report could not be saved (see the log)", ScreenWidth/2, 310, 1.2, color.RGBA{255, 200, 100, 255})
+	}
+
+	y := 380
+	if c.replay != nil {
+		systems.DrawTextCentered(screen, "Press S to save the replay of this run up to the crash", ScreenWidth/2, y, 1.5, color.RGBA{100, 255, 100, 255})
+	} else {
+		systems.DrawTextCentered(screen, "No replay was recorded for this run", ScreenWidth/2, y, 1.5, color.RGBA{150, 150, 150, 255})
+	}
+	if c.message != "" {
+		systems.DrawTextCentered(screen, c.message, ScreenWidth/2, y+40, 1.2, color.RGBA{255, 255, 100, 255})
+	}
+	systems.DrawTextCentered(screen, "Press ESC to quit", ScreenWidth/2, ScreenHeight-80, 1.5, color.RGBA{150, 150, 150, 255})
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stellar-siege/game/systems"
+)
+
+func TestCrashReportAndPartialReplay(t *testing.T) {
+	const ticks = 600
+	g := NewHeadlessGame(11, DifficultyNormal)
+	for i := 0; i < ticks; i++ {
+		if !g.Step(scriptedInput(i)) {
+			t.Fatalf("run ended at tick %d", g.Tick())
+		}
+	}
+
+	g.crashDir = t.TempDir()
+	func() {
+		defer g.recoverCrash("update")
+		var enemies []int
+		_ = enemies[len(g.enemies)+1] // index out of range
+	}()
+	if g.crash == nil {
+		t.Fatal("the panic did not bring up the crash screen")
+	}
+
+	report := g.crash.report
+	if len(report.Inputs) != CrashInputTicks {
+		t.Fatalf("report holds %d inputs, want %d", len(report.Inputs), CrashInputTicks)
+	}
+	for i, in := range report.Inputs {
+		if want := scriptedInput(ticks - CrashInputTicks + i); in != want {
+			t.Fatalf("input %d = %v, want %v", i, in, want)
+		}
+	}
+	if filepath.Dir(g.crash.reportPath) != g.crashDir {
+		t.Fatalf("report written to %q, want it in %s", g.crash.reportPath, g.crashDir)
+	}
+	data, err := os.ReadFile(g.crash.reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	if text != report.String() {
+		t.Errorf("report file differs from the report:\n%s", text)
+	}
+	for _, want := range []string{"index out of range", "seed:       11", "tick:       600", "TestCrashReportAndPartialReplay"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report is missing %q:\n%s", want, text)
+		}
+	}
+
+	if err := g.saveCrashReplay("crash_replay.json"); err != nil {
+		t.Fatal(err)
+	}
+	replay, err := systems.LoadReplayFrom(g.storage, "crash_replay.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replay.Finished || len(replay.Frames()) != ticks {
+		t.Errorf("partial replay finished=%v with %d frames, want unfinished with %d", replay.Finished, len(replay.Frames()), ticks)
+	}
+}
@@ -0,0 +1,324 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"stellar-siege/game/core"
+	"stellar-siege/game/entities"
+	"stellar-siege/game/systems"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// DebugLayer is one independently toggled layer of the debug overlay
+type DebugLayer int
+
+const (
+	DebugColliders  DebugLayer = iota // Collision circles of every entity
+	DebugGrid                         // Occupied spatial grid cells with entity counts
+	DebugHoming                       // Homing projectiles and the enemy they track
+	DebugAITargets                    // Where enemy AI is steering or aiming
+	DebugFormations                   // Links between members of a formation
+	DebugPools                        // Object pool usage
+	debugLayerCount
+)
+
+// debugLayerKeys are the function keys toggling each layer
+var debugLayerKeys = [debugLayerCount]ebiten.Key{
+	ebiten.KeyF1, ebiten.KeyF2, ebiten.KeyF3, ebiten.KeyF4, ebiten.KeyF5, ebiten.KeyF6,
+}
+
+// debugLayerNames label each layer in the overlay legend
+var debugLayerNames = [debugLayerCount]string{
+	"colliders", "grid", "homing", "AI targets", "formations", "pools",
+}
+
+// Debug overlay colors
+var (
+	debugPlayerColor     = color.RGBA{100, 255, 100, 255}
+	debugEnemyColor      = color.RGBA{255, 90, 90, 255}
+	debugBossColor       = color.RGBA{255, 60, 200, 255}
+	debugFriendlyColor   = color.RGBA{120, 200, 255, 200}
+	debugHostileColor    = color.RGBA{255, 170, 60, 200}
+	debugPowerUpColor    = color.RGBA{255, 255, 100, 255}
+	debugAsteroidColor   = color.RGBA{180, 140, 100, 255}
+	debugHazardColor     = color.RGBA{200, 120, 255, 255}
+	debugGridColor       = color.RGBA{80, 160, 255, 60}
+	debugGridLineColor   = color.RGBA{80, 160, 255, 160}
+	debugHomingColor     = color.RGBA{0, 255, 220, 200}
+	debugNoTargetColor   = color.RGBA{140, 140, 140, 160}
+	debugAITargetColor   = color.RGBA{255, 60, 60, 140}
+	debugFormationColor  = color.RGBA{255, 255, 255, 150}
+	debugPanelColor      = color.RGBA{0, 0, 0, 170}
+	debugPanelTextColor  = color.RGBA{220, 220, 220, 255}
+	debugLegendTextColor = color.RGBA{255, 220, 120, 255}
+)
+
+// formationKey identifies one formation
+type formationKey struct {
+	formation entities.FormationType
+	id        int
+}
+
+// DebugOverlay draws collision and AI internals on top of the game. Each
+// layer is toggled with its own function key.
+type DebugOverlay struct {
+	layers [debugLayerCount]bool
+
+	// Reused between frames to avoid per-frame allocations
+	cells   []core.CellCount
+	leaders map[formationKey]*entities.Enemy
+	members map[formationKey][]*entities.Enemy
+}
+
+// NewDebugOverlay creates a debug overlay with every layer off
+func NewDebugOverlay() *DebugOverlay {
+	return &DebugOverlay{
+		leaders: make(map[formationKey]*entities.Enemy),
+		members: make(map[formationKey][]*entities.Enemy),
+	}
+}
+
+// Toggle switches a layer on or off
+func (d *DebugOverlay) Toggle(layer DebugLayer) {
+	d.layers[layer] = !d.layers[layer]
+}
+
+// Enabled reports whether a layer is shown
+func (d *DebugOverlay) Enabled(layer DebugLayer) bool {
+	return d.layers[layer]
+}
+
+// Active reports whether any layer is shown
+func (d *DebugOverlay) Active() bool {
+	for _, on := range d.layers {
+		if on {
+			return true
+		}
+	}
+	return false
+}
+
+// Update toggles layers from their function keys
+func (d *DebugOverlay) Update() {
+	for layer, key := range debugLayerKeys {
+		if inpututil.IsKeyJustPressed(key) {
+			d.Toggle(DebugLayer(layer))
+		}
+	}
+}
+
+// drawDebugOverlay draws the enabled debug layers over the gameplay
+func (g *Game) drawDebugOverlay(screen *ebiten.Image, shakeX, shakeY float64) {
+	d := g.debug
+	if d == nil || !d.Active() {
+		return
+	}
+	if d.Enabled(DebugGrid) {
+		g.drawDebugGrid(screen, shakeX, shakeY)
+	}
+	if d.Enabled(DebugColliders) {
+		g.drawDebugColliders(screen, shakeX, shakeY)
+	}
+	if d.Enabled(DebugFormations) {
+		g.drawDebugFormations(screen, shakeX, shakeY)
+	}
+	if d.Enabled(DebugAITargets) {
+		g.drawDebugAITargets(screen, shakeX, shakeY)
+	}
+	if d.Enabled(DebugHoming) {
+		g.drawDebugHoming(screen, shakeX, shakeY)
+	}
+	if d.Enabled(DebugPools) {
+		g.drawDebugPools(screen)
+	}
+	g.drawDebugLegend(screen)
+}
+
+// debugCircle outlines a collider
+func debugCircle(screen *ebiten.Image, x, y, r, shakeX, shakeY float64, clr color.RGBA) {
+	vector.StrokeCircle(screen, float32(x+shakeX), float32(y+shakeY), float32(r), 1, clr, true)
+}
+
+// debugLine draws a line between two world positions
+func debugLine(screen *ebiten.Image, x1, y1, x2, y2, shakeX, shakeY float64, clr color.RGBA) {
+	vector.StrokeLine(screen, float32(x1+shakeX), float32(y1+shakeY), float32(x2+shakeX), float32(y2+shakeY), 1, clr, true)
+}
+
+// drawDebugColliders outlines the radius every collision check uses
+func (g *Game) drawDebugColliders(screen *ebiten.Image, shakeX, shakeY float64) {
+	for _, pl := range g.pilots {
+		if pl.ship.Active {
+			debugCircle(screen, pl.ship.X, pl.ship.Y, pl.ship.Radius, shakeX, shakeY, debugPlayerColor)
+		}
+	}
+	for _, e := range g.enemies {
+		if e.Active {
+			debugCircle(screen, e.X, e.Y, e.Radius, shakeX, shakeY, debugEnemyColor)
+		}
+	}
+	if b := g.boss; b != nil && b.Active {
+		// Projectiles hit the full radius; ramming the player uses half of it
+		debugCircle(screen, b.X, b.Y, b.Radius, shakeX, shakeY, debugBossColor)
+		debugCircle(screen, b.X, b.Y, b.Radius*0.5, shakeX, shakeY, debugBossColor)
+	}
+	for _, p := range g.projectiles {
+		if !p.Active {
+			continue
+		}
+		clr := debugHostileColor
+		if p.Friendly {
+			clr = debugFriendlyColor
+		}
+		debugCircle(screen, p.X, p.Y, p.Radius, shakeX, shakeY, clr)
+	}
+	for _, pu := range g.powerups {
+		if pu.Active {
+			debugCircle(screen, pu.X, pu.Y, pu.Radius, shakeX, shakeY, debugPowerUpColor)
+		}
+	}
+	for _, a := range g.asteroids {
+		if a.Active {
+			debugCircle(screen, a.X, a.Y, a.Radius, shakeX, shakeY, debugAsteroidColor)
+		}
+	}
+	for _, h := range g.hazards {
+		if h.Active {
+			debugCircle(screen, h.X, h.Y, h.GetCollisionRadius(), shakeX, shakeY, debugHazardColor)
+		}
+	}
+}
+
+// drawDebugGrid shades the occupied spatial grid cells and labels their counts
+func (g *Game) drawDebugGrid(screen *ebiten.Image, shakeX, shakeY float64) {
+	d := g.debug
+	size := g.spatialGrid.CellSize()
+	d.cells = g.spatialGrid.OccupiedCells(d.cells[:0])
+	for _, c := range d.cells {
+		x := float32(float64(c.Col)*size + shakeX)
+		y := float32(float64(c.Row)*size + shakeY)
+		vector.DrawFilledRect(screen, x, y, float32(size), float32(size), debugGridColor, false)
+		vector.StrokeRect(screen, x, y, float32(size), float32(size), 1, debugGridLineColor, false)
+		label := fmt.Sprintf("%d  e%d p%d", c.Total(), c.Enemies, c.Projectiles)
+		systems.DrawText(screen, label, int(x)+4, int(y)+4, 0.8, debugPanelTextColor)
+	}
+}
+
+// drawDebugHoming links homing projectiles to the enemy they track; those
+// without a target show the straight path they are flying
+func (g *Game) drawDebugHoming(screen *ebiten.Image, shakeX, shakeY float64) {
+	for _, p := range g.projectiles {
+		if !p.Active || !p.Homing {
+			continue
+		}
+		if p.TargetEnemyIdx >= 0 && p.TargetEnemyIdx < len(g.enemies) && g.enemies[p.TargetEnemyIdx].Active {
+			e := g.enemies[p.TargetEnemyIdx]
+			debugLine(screen, p.X, p.Y, e.X, e.Y, shakeX, shakeY, debugHomingColor)
+			debugCircle(screen, e.X, e.Y, e.Radius+4, shakeX, shakeY, debugHomingColor)
+			continue
+		}
+		speed := math.Hypot(p.VelX, p.VelY)
+		if speed > 0 {
+			debugLine(screen, p.X, p.Y, p.X+p.VelX/speed*40, p.Y+p.VelY/speed*40, shakeX, shakeY, debugNoTargetColor)
+		}
+	}
+}
+
+// drawDebugAITargets draws a line from each enemy to what its AI is after
+func (g *Game) drawDebugAITargets(screen *ebiten.Image, shakeX, shakeY float64) {
+	if g.player == nil {
+		return
+	}
+	for _, e := range g.enemies {
+		if !e.Active {
+			continue
+		}
+		target := g.targetShip(e.Target, e.X, e.Y)
+		if x, y, ok := e.AITarget(target.X, target.Y); ok {
+			debugLine(screen, e.X, e.Y, x, y, shakeX, shakeY, debugAITargetColor)
+		}
+	}
+	if b := g.boss; b != nil && b.Active {
+		// The boss tracks the nearest ship horizontally
+		debugLine(screen, b.X, b.Y, g.targetShip(-1, b.X, b.Y).X, b.Y, shakeX, shakeY, debugAITargetColor)
+	}
+}
+
+// drawDebugFormations links formation members to their leader, or to each
+// other in formation order once the leader is gone
+func (g *Game) drawDebugFormations(screen *ebiten.Image, shakeX, shakeY float64) {
+	d := g.debug
+	clear(d.leaders)
+	for key, list := range d.members {
+		d.members[key] = list[:0]
+	}
+	for _, e := range g.enemies {
+		if !e.Active || e.FormationType == entities.FormationTypeNone {
+			continue
+		}
+		key := formationKey{e.FormationType, e.FormationID}
+		if e.IsFormationLeader {
+			d.leaders[key] = e
+		} else {
+			d.members[key] = append(d.members[key], e)
+		}
+	}
+
+	for key, list := range d.members {
+		leader := d.leaders[key]
+		for i, e := range list {
+			switch {
+			case leader != nil:
+				debugLine(screen, e.X, e.Y, leader.X, leader.Y, shakeX, shakeY, debugFormationColor)
+			case i > 0:
+				prev := list[i-1]
+				debugLine(screen, e.X, e.Y, prev.X, prev.Y, shakeX, shakeY, debugFormationColor)
+			}
+		}
+	}
+	for _, leader := range d.leaders {
+		debugCircle(screen, leader.X, leader.Y, leader.Radius+6, shakeX, shakeY, debugFormationColor)
+	}
+}
+
+// drawDebugPools lists the usage of every object pool
+func (g *Game) drawDebugPools(screen *ebiten.Image) {
+	pools := []struct {
+		name  string
+		stats core.PoolStats
+	}{
+		{"projectiles", g.projectilePool.GetStats()},
+		{"explosions", g.explosionPool.GetStats()},
+		{"enemies", g.enemyPool.GetStats()},
+		{"floating text", g.floatingTextPool.GetStats()},
+		{"impacts", g.impactEffectPool.GetStats()},
+		{"asteroids", g.asteroidPool.GetStats()},
+		{"powerups", g.powerUpPool.GetStats()},
+	}
+
+	const x, y, lineHeight = 20, 110, 18
+	vector.DrawFilledRect(screen, x-8, y-8, 420, float32(len(pools)+1)*lineHeight+12, debugPanelColor, false)
+	systems.DrawText(screen, "POOL          active  max  size  created  reuse", x, y, 0.9, debugLegendTextColor)
+	for i, p := range pools {
+		line := fmt.Sprintf("%-13s %6d %4d %5d %8d %5.0f%%",
+			p.name, p.stats.CurrentActive, p.stats.MaxActive, p.stats.PoolSize, p.stats.TotalCreated, p.stats.ReuseRate)
+		systems.DrawText(screen, line, x, y+(i+1)*lineHeight, 0.9, debugPanelTextColor)
+	}
+}
+
+// drawDebugLegend lists the layer keys and which layers are on
+func (g *Game) drawDebugLegend(screen *ebiten.Image) {
+	y := ScreenHeight - 20*int(debugLayerCount) - 10
+	for layer := DebugLayer(0); layer < debugLayerCount; layer++ {
+		clr := debugNoTargetColor
+		if g.debug.Enabled(layer) {
+			clr = debugLegendTextColor
+		}
+		label := fmt.Sprintf("F%d %s", layer+1, debugLayerNames[layer])
+		systems.DrawText(screen, label, ScreenWidth-170, y+int(layer)*20, 0.9, clr)
+	}
+}
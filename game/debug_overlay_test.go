@@ -0,0 +1,45 @@
+package game
+
+import "testing"
+
+func TestDebugLayersToggleIndependently(t *testing.T) {
+	d := NewDebugOverlay()
+	d.Toggle(DebugGrid)
+	d.Toggle(DebugPools)
+	for layer := DebugLayer(0); layer < debugLayerCount; layer++ {
+		want := layer == DebugGrid || layer == DebugPools
+		if d.Enabled(layer) != want {
+			t.Errorf("layer %s enabled = %v, want %v", debugLayerNames[layer], d.Enabled(layer), want)
+		}
+	}
+	d.Toggle(DebugGrid)
+	d.Toggle(DebugPools)
+	if d.Active() {
+		t.Fatal("overlay still active after toggling every layer off")
+	}
+}
+
+func TestOccupiedCellsCoverEveryEnemy(t *testing.T) {
+	g := NewHeadlessGame(7, DifficultyNormal)
+	g.console.SetInput("spawn tank 6")
+	g.console.Execute()
+	g.Step(0)
+
+	seen := 0
+	for _, c := range g.spatialGrid.OccupiedCells(nil) {
+		if c.Total() == 0 {
+			t.Fatalf("cell %d,%d reported with no entities", c.Col, c.Row)
+		}
+		seen += c.Enemies
+	}
+	active := 0
+	for _, e := range g.enemies {
+		if e.Active {
+			active++
+		}
+	}
+	// Enemies overlapping a cell border are filed under every cell they touch
+	if active == 0 || seen < active {
+		t.Fatalf("grid cells hold %d enemy entries for %d active enemies", seen, active)
+	}
+}
@@ -0,0 +1,74 @@
+package game
+
+import (
+	"time"
+
+	"stellar-siege/game/core"
+	"stellar-siege/game/systems"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	demoIdleTicks     = 20 * 60 // Title screen idle time before the attract demo starts
+	demoGameOverTicks = 5 * 60  // How long the demo's game over screen is shown
+)
+
+// startDemo starts an attract-mode run played by the autopilot
+func (g *Game) startDemo() {
+	seed := time.Now().UnixNano()
+	g.selectedDifficulty = DifficultyNormal
+	g.players = 1
+	g.startGameWithSeed(seed)
+	g.demoMode = true
+	g.demoTimer = 0
+	g.autopilot = core.NewAutopilot(core.BotSkillExpert, seed)
+}
+
+// stopDemo ends the attract demo and returns to the title screen
+func (g *Game) stopDemo() {
+	g.demoMode = false
+	g.autopilot = nil
+	g.menuIdleTicks = 0
+	g.transitionToState(StateMenu)
+}
+
+// updateMenuIdle counts title screen idle time and starts the attract demo
+// once nobody has touched the keyboard or mouse for a while
+func (g *Game) updateMenuIdle() {
+	if anyInputJustPressed() || g.menu.ShowDifficultySelect || g.menu.InfoMenu.IsActive() {
+		g.menuIdleTicks = 0
+		return
+	}
+	g.menuIdleTicks++
+	if g.menuIdleTicks >= demoIdleTicks {
+		g.menuIdleTicks = 0
+		g.startDemo()
+	}
+}
+
+// updateDemoPlaying advances the attract demo; any input returns to the menu
+func (g *Game) updateDemoPlaying() {
+	if anyInputJustPressed() {
+		g.sound.PlaySound(systems.SoundUIClick)
+		g.stopDemo()
+		return
+	}
+	g.stepSimulation(g.autopilot.NextInput(g.BotView()))
+}
+
+// updateDemoGameOver shows the demo's result briefly before returning to the menu
+func (g *Game) updateDemoGameOver() {
+	g.demoTimer++
+	if anyInputJustPressed() || g.demoTimer >= demoGameOverTicks {
+		g.stopDemo()
+	}
+}
+
+// anyInputJustPressed reports whether any key or mouse button was pressed this frame
+func anyInputJustPressed() bool {
+	return len(inpututil.AppendJustPressedKeys(nil)) > 0 ||
+		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) ||
+		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight)
+}
@@ -141,4 +141,6 @@ const (
 	ServiceStarfield          = "Starfield"
 	ServiceMenu               = "Menu"
 	ServiceInfoMenu           = "InfoMenu"
+	ServiceStorage            = "Storage"
+	ServiceProfileManager     = "ProfileManager"
 )
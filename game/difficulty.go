@@ -12,7 +12,7 @@ type DifficultyConfig struct {
 	Name              string
 	PlayerHealth      int
 	PlayerMaxShield   int
-	ShieldRegenRate   float64 // HP per frame
+	ShieldRegenRate   float64 // HP per second
 	InvincibilityTime float64 // seconds
 	ShieldRegenDelay  float64 // seconds before regen starts
 	SpawnMultiplier   float64 // multiplier on enemy count
@@ -28,7 +28,7 @@ func GetDifficultyConfig(mode DifficultyMode) DifficultyConfig {
 			Name:              "EASY",
 			PlayerHealth:      120,
 			PlayerMaxShield:   60,
-			ShieldRegenRate:   0.75,
+			ShieldRegenRate:   45,
 			InvincibilityTime: 0.4,
 			ShieldRegenDelay:  2.0,
 			SpawnMultiplier:   0.7,
@@ -41,7 +41,7 @@ func GetDifficultyConfig(mode DifficultyMode) DifficultyConfig {
 			Name:              "NORMAL",
 			PlayerHealth:      85,
 			PlayerMaxShield:   40,
-			ShieldRegenRate:   0.5,
+			ShieldRegenRate:   30,
 			InvincibilityTime: 0.25,
 			ShieldRegenDelay:  3.5,
 			SpawnMultiplier:   1.0,
@@ -54,7 +54,7 @@ func GetDifficultyConfig(mode DifficultyMode) DifficultyConfig {
 			Name:              "HARD",
 			PlayerHealth:      60,
 			PlayerMaxShield:   25,
-			ShieldRegenRate:   0.25,
+			ShieldRegenRate:   15,
 			InvincibilityTime: 0.15,
 			ShieldRegenDelay:  6.0,
 			SpawnMultiplier:   1.35,
@@ -70,3 +70,15 @@ func GetDifficultyConfig(mode DifficultyMode) DifficultyConfig {
 func GetDifficultyName(mode DifficultyMode) string {
 	return GetDifficultyConfig(mode).Name
 }
+
+// DifficultyLabel returns the difficulty name leaderboards record, such as
+// "Normal"
+func DifficultyLabel(mode DifficultyMode) string {
+	switch mode {
+	case DifficultyEasy:
+		return "Easy"
+	case DifficultyHard:
+		return "Hard"
+	}
+	return "Normal"
+}
@@ -169,17 +169,17 @@ func (am *AbilityManager) IsAbilityActive(abilityType AbilityType) bool {
 }
 
 // Update updates all ability cooldowns and timers
-func (am *AbilityManager) Update() {
+func (am *AbilityManager) Update(deltaTime float64) {
 	for _, ability := range am.Abilities {
 		if ability.CooldownTimer > 0 {
-			ability.CooldownTimer -= 1.0 / 60.0 // 60 FPS
+			ability.CooldownTimer -= deltaTime
 		}
 	}
 
 	// Update active ability timers
 	for abilityType, timer := range am.ActiveAbilityTimers {
 		if timer > 0 {
-			am.ActiveAbilityTimers[abilityType] = timer - 1.0/60.0
+			am.ActiveAbilityTimers[abilityType] = timer - deltaTime
 		} else if timer <= 0 && am.ActiveAbilities[abilityType] {
 			am.ActiveAbilities[abilityType] = false
 		}
@@ -192,11 +192,11 @@ func (am *AnnouncementManager) AddMysteryBoxAnnouncement(text string, isPositive
 }
 
 // Update updates all announcements
-func (am *AnnouncementManager) Update() {
+func (am *AnnouncementManager) Update(deltaTime float64) {
 	// Only reallocate slice if we actually need to remove announcements
 	hasExpired := false
 	for _, ann := range am.Announcements {
-		ann.TimeAlive += 1.0 / 60.0 // 60 FPS
+		ann.TimeAlive += deltaTime
 		if ann.TimeAlive >= ann.Duration {
 			hasExpired = true
 		}
@@ -215,7 +215,7 @@ func (am *AnnouncementManager) Update() {
 		am.Announcements = am.Announcements[:writeIdx]
 	}
 
-	am.LastComboTime += 1.0 / 60.0
+	am.LastComboTime += deltaTime
 }
 
 // GetAnnouncements returns all active announcements
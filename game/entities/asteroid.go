@@ -3,7 +3,8 @@ package entities
 import (
 	"image/color"
 	"math"
-	"math/rand"
+
+	"stellar-siege/game/rng"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
@@ -19,13 +20,13 @@ const (
 
 type Asteroid struct {
 	X, Y       float64
-	VelX, VelY float64
+	VelX, VelY float64 // Pixels per second
 	Radius     float64
 	Size       AsteroidSize
 	Health     int
 	MaxHealth  int
 	Rotation   float64
-	RotSpeed   float64
+	RotSpeed   float64 // Radians per second
 	Active     bool
 }
 
@@ -46,22 +47,23 @@ func NewAsteroid(x, y float64, size AsteroidSize) *Asteroid {
 	return &Asteroid{
 		X:         x,
 		Y:         y,
-		VelX:      (rand.Float64() - 0.5) * 2,
-		VelY:      rand.Float64()*1.5 + 0.5, // Always moving down
+		VelX:      (rng.Float64() - 0.5) * 120,
+		VelY:      rng.Float64()*90 + 30, // Always moving down
 		Radius:    float64(radius),
 		Size:      size,
 		Health:    health,
 		MaxHealth: health,
-		Rotation:  rand.Float64() * math.Pi * 2,
-		RotSpeed:  (rand.Float64() - 0.5) * 0.1,
+		Rotation:  rng.Float64() * math.Pi * 2,
+		RotSpeed:  (rng.Float64() - 0.5) * 6,
 		Active:    true,
 	}
 }
 
-func (a *Asteroid) Update() {
-	a.X += a.VelX
-	a.Y += a.VelY
-	a.Rotation += a.RotSpeed
+// Update moves the asteroid by deltaTime seconds
+func (a *Asteroid) Update(deltaTime float64) {
+	a.X += a.VelX * deltaTime
+	a.Y += a.VelY * deltaTime
+	a.Rotation += a.RotSpeed * deltaTime
 
 	// Wrap around screen
 	if a.X < -a.Radius {
@@ -89,13 +91,13 @@ func (a *Asteroid) Reset() {
 	a.X = 0
 	a.Y = 0
 	a.VelX = 0
-	a.VelY = 1.0
+	a.VelY = 60
 	a.Radius = 20
 	a.Size = AsteroidMedium
 	a.Health = 2
 	a.MaxHealth = 2
 	a.Rotation = 0
-	a.RotSpeed = 0.05
+	a.RotSpeed = 3
 	a.Active = false
 }
 
@@ -18,6 +18,24 @@ const (
 	BossPhaseDying
 )
 
+// String returns the name of the boss phase
+func (p BossPhase) String() string {
+	switch p {
+	case BossPhaseEntering:
+		return "entering"
+	case BossPhaseAttacking:
+		return "attacking"
+	case BossPhaseSpecialAttack:
+		return "special_attack"
+	case BossPhaseRage:
+		return "rage"
+	case BossPhaseDying:
+		return "dying"
+	default:
+		return "unknown"
+	}
+}
+
 type Boss struct {
 	X, Y          float64
 	VelX, VelY    float64
@@ -108,16 +126,17 @@ func NewBoss(screenWidth int, bossLevel int) *Boss {
 	}
 }
 
-func (b *Boss) Update(playerX, playerY float64, screenWidth, screenHeight int) []*Projectile {
-	b.AnimTimer += 0.05
+// Update advances the boss by deltaTime seconds and returns the projectiles it fired
+func (b *Boss) Update(deltaTime, playerX, playerY float64, screenWidth, screenHeight int) []*Projectile {
+	b.AnimTimer += 3 * deltaTime
 	var projectiles []*Projectile
 
 	switch b.Phase {
 	case BossPhaseEntering:
-		b.updateEntryPhase(playerX)
+		b.updateEntryPhase(deltaTime, playerX)
 
 	case BossPhaseAttacking, BossPhaseRage, BossPhaseSpecialAttack:
-		projectiles = b.updateAttackingPhase(playerX, playerY, screenWidth)
+		projectiles = b.updateAttackingPhase(deltaTime, playerX, playerY, screenWidth)
 
 	case BossPhaseDying:
 		// Explosion sequence handled elsewhere
@@ -157,12 +176,12 @@ func (b *Boss) executeAttack(playerX, playerY float64) []*Projectile {
 
 // Helper function to create projectiles with proper damage scaling
 func (b *Boss) createProjectile(angle float64) *Projectile {
-	speed := 5.0
+	speed := 300.0
 	if b.Phase == BossPhaseRage {
-		speed = 6.5
+		speed = 390.0
 	}
 	if b.Phase == BossPhaseSpecialAttack {
-		speed = 7.0
+		speed = 420.0
 	}
 	velX := math.Cos(angle) * speed
 	velY := math.Sin(angle) * speed
@@ -24,8 +24,8 @@ func (b *Boss) executeAimedShot(playerX, playerY float64) []*Projectile {
 	dist := math.Sqrt(dx*dx + dy*dy)
 
 	if dist > 0 {
-		velX := (dx / dist) * 7
-		velY := (dy / dist) * 7
+		velX := (dx / dist) * 420
+		velY := (dy / dist) * 420
 		projectiles = append(projectiles, NewProjectile(b.X, b.Y+b.Radius, velX, velY, false, b.Damage))
 
 		// Add side shots for higher levels
@@ -69,7 +69,7 @@ func (b *Boss) executeLaserLines() []*Projectile {
 	}
 	for i := 0; i < count; i++ {
 		offsetX := float64(i-(count-1)/2) * 40
-		projectiles = append(projectiles, NewProjectile(b.X+offsetX, b.Y+b.Radius, 0, 6, false, b.Damage))
+		projectiles = append(projectiles, NewProjectile(b.X+offsetX, b.Y+b.Radius, 0, 360, false, b.Damage))
 	}
 	return projectiles
 }
@@ -80,8 +80,8 @@ func (b *Boss) executeSpiralPattern() []*Projectile {
 	if b.BossLevel >= 2 {
 		for i := 0; i < 6; i++ {
 			angle := float64(i)*math.Pi/3 + b.AnimTimer*0.1
-			velX := math.Cos(angle) * 5
-			velY := math.Sin(angle) * 5
+			velX := math.Cos(angle) * 300
+			velY := math.Sin(angle) * 300
 			projectiles = append(projectiles, NewProjectile(b.X, b.Y, velX, velY, false, b.Damage-5))
 		}
 	}
@@ -94,8 +94,8 @@ func (b *Boss) executeDoubleArc() []*Projectile {
 	if b.BossLevel >= 2 {
 		for i := -3; i <= 3; i++ {
 			angle := math.Pi/2 + float64(i)*0.15
-			velX := math.Cos(angle) * 6
-			velY := math.Sin(angle) * 6
+			velX := math.Cos(angle) * 360
+			velY := math.Sin(angle) * 360
 			projectiles = append(projectiles, NewProjectile(b.X-40, b.Y+b.Radius, velX, velY, false, b.Damage-5))
 			projectiles = append(projectiles, NewProjectile(b.X+40, b.Y+b.Radius, velX, velY, false, b.Damage-5))
 		}
@@ -109,8 +109,8 @@ func (b *Boss) executeTrackingSpiral() []*Projectile {
 	if b.BossLevel >= 3 {
 		for i := 0; i < 6; i++ {
 			angle := float64(i)*math.Pi/3 + b.AnimTimer*0.2
-			velX := math.Cos(angle) * 5.5
-			velY := math.Sin(angle) * 5.5
+			velX := math.Cos(angle) * 330
+			velY := math.Sin(angle) * 330
 			projectiles = append(projectiles, NewProjectile(b.X, b.Y, velX, velY, false, b.Damage))
 		}
 	}
@@ -125,7 +125,7 @@ func (b *Boss) executeWavePattern() []*Projectile {
 		for i := 0; i < waveCount; i++ {
 			offsetX := float64(i-(waveCount-1)/2) * 30
 			waveY := math.Sin(float64(i)*math.Pi/5) * 50
-			projectiles = append(projectiles, NewProjectile(b.X+offsetX, b.Y+waveY, 0, 6, false, b.Damage-5))
+			projectiles = append(projectiles, NewProjectile(b.X+offsetX, b.Y+waveY, 0, 360, false, b.Damage-5))
 		}
 	}
 	return projectiles
@@ -139,7 +139,7 @@ func (b *Boss) executeCrossBurst() []*Projectile {
 			angle := float64(i)*math.Pi/2 + math.Pi/4
 			for j := 0; j < 3; j++ {
 				ratio := float64(j) / 2.0
-				vel := 4.0 + ratio*3.0
+				vel := 240.0 + ratio*180.0
 				velX := math.Cos(angle) * vel
 				velY := math.Sin(angle) * vel
 				projectiles = append(projectiles, NewProjectile(b.X, b.Y+b.Radius, velX, velY, false, b.Damage))
@@ -155,7 +155,7 @@ func (b *Boss) executeChaosPattern() []*Projectile {
 	if b.BossLevel >= 4 {
 		for i := 0; i < 10; i++ {
 			angle := float64(i)*math.Pi/5 + b.AnimTimer*0.3
-			speed := 4.0 + math.Sin(b.AnimTimer+float64(i))*2.0
+			speed := 240.0 + math.Sin(b.AnimTimer+float64(i))*120.0
 			velX := math.Cos(angle) * speed
 			velY := math.Sin(angle) * speed
 			projectiles = append(projectiles, NewProjectile(b.X, b.Y, velX, velY, false, b.Damage))
@@ -13,8 +13,8 @@ func (b *Boss) checkProximityAttack(playerX float64) bool {
 }
 
 // updateEntryPhase handles the entry phase movement and transition logic
-func (b *Boss) updateEntryPhase(playerX float64) {
-	b.Y += 1
+func (b *Boss) updateEntryPhase(deltaTime, playerX float64) {
+	b.Y += 60 * deltaTime
 
 	if b.Y >= b.EntryY {
 		// Reached target position
@@ -31,9 +31,9 @@ func (b *Boss) updateEntryPhase(playerX float64) {
 }
 
 // updateTelegraphWarning manages the telegraph warning timer
-func (b *Boss) updateTelegraphWarning() {
+func (b *Boss) updateTelegraphWarning(deltaTime float64) {
 	if b.TelegraphActive {
-		b.TelegraphTimer -= 1.0 / 60.0
+		b.TelegraphTimer -= deltaTime
 		if b.TelegraphTimer <= 0 {
 			b.TelegraphActive = false
 		}
@@ -41,15 +41,15 @@ func (b *Boss) updateTelegraphWarning() {
 }
 
 // updateMovement handles horizontal movement tracking the player
-func (b *Boss) updateMovement(playerX float64, screenWidth int) {
+func (b *Boss) updateMovement(deltaTime, playerX float64, screenWidth int) {
 	dx := playerX - b.X
-	b.VelX = dx * 0.01 * b.Speed
+	b.VelX = dx * 0.6 * b.Speed
 
 	if b.Phase == BossPhaseRage {
 		b.VelX *= 1.5
 	}
 
-	b.X += b.VelX
+	b.X += b.VelX * deltaTime
 
 	// Keep in bounds
 	margin := b.Radius + 20
@@ -76,14 +76,14 @@ func (b *Boss) getAttackInterval() float64 {
 }
 
 // updateAttackTimer manages attack timing and triggers attacks
-func (b *Boss) updateAttackTimer(playerX, playerY float64) []*Projectile {
+func (b *Boss) updateAttackTimer(deltaTime, playerX, playerY float64) []*Projectile {
 	var projectiles []*Projectile
 
 	if b.TelegraphActive {
 		return projectiles
 	}
 
-	b.AttackTimer += 1.0 / 60.0
+	b.AttackTimer += deltaTime
 	attackInterval := b.getAttackInterval()
 
 	if b.AttackTimer >= attackInterval {
@@ -121,8 +121,8 @@ func (b *Boss) checkPhaseTransitions() {
 }
 
 // updateShieldMechanic manages shield activation and deactivation
-func (b *Boss) updateShieldMechanic() {
-	b.ShieldTimer += 1.0 / 60.0
+func (b *Boss) updateShieldMechanic(deltaTime float64) {
+	b.ShieldTimer += deltaTime
 	shieldInterval := 5.0 / (float64(b.BossLevel) * 0.5)
 	shieldDuration := 2.0 - float64(b.BossLevel)*0.2
 
@@ -137,12 +137,12 @@ func (b *Boss) updateShieldMechanic() {
 }
 
 // updateAttackingPhase handles all logic for attacking phases
-func (b *Boss) updateAttackingPhase(playerX, playerY float64, screenWidth int) []*Projectile {
-	b.updateTelegraphWarning()
-	b.updateMovement(playerX, screenWidth)
-	projectiles := b.updateAttackTimer(playerX, playerY)
+func (b *Boss) updateAttackingPhase(deltaTime, playerX, playerY float64, screenWidth int) []*Projectile {
+	b.updateTelegraphWarning(deltaTime)
+	b.updateMovement(deltaTime, playerX, screenWidth)
+	projectiles := b.updateAttackTimer(deltaTime, playerX, playerY)
 	b.checkPhaseTransitions()
-	b.updateShieldMechanic()
+	b.updateShieldMechanic(deltaTime)
 	return projectiles
 }
 
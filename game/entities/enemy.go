@@ -9,7 +9,7 @@ type Enemy struct {
 	X, Y       float64
 	VelX, VelY float64
 	Radius     float64
-	Speed      float64
+	Speed      float64 // Pixels per second
 	Health     int
 	MaxHealth  int
 	Points     int
@@ -19,6 +19,7 @@ type Enemy struct {
 	ShootRate  float64
 	AnimTimer  float64
 	Phase      float64 // For wave movement
+	Target     int     // Pilot the enemy goes after in co-op runs
 
 	// Burning DoT system
 	Burning       bool
@@ -33,7 +34,7 @@ type Enemy struct {
 	FormationTargetX  float64 // Target position for formation
 	FormationTargetY  float64
 	FormationIndex    int      // Position in formation (0 = leader)
-	NearbyAllies      []*Enemy // References to nearby allies in formation
+	NearbyAllies      []*Enemy `json:"-"` // References to nearby allies in formation (rebuilt by UpdateFormation)
 	LastShootTime     float64
 	CoorditatedShoot  bool // Should coordinate fire with formation
 
@@ -57,9 +58,9 @@ func (e *Enemy) TryShoot() *Projectile {
 
 	switch e.Type {
 	case EnemyDrone, EnemyHunter:
-		return NewProjectile(e.X, e.Y+e.Radius, 0, 6, false, 10)
+		return NewProjectile(e.X, e.Y+e.Radius, 0, 360, false, 10)
 	case EnemyTank:
-		return NewProjectile(e.X, e.Y+e.Radius, 0, 5, false, 20)
+		return NewProjectile(e.X, e.Y+e.Radius, 0, 300, false, 20)
 	case EnemySniper:
 		// Shoots precise fast projectiles at locked position
 		if e.SniperLocked {
@@ -67,7 +68,7 @@ func (e *Enemy) TryShoot() *Projectile {
 			dy := e.SniperTargetY - e.Y
 			dist := math.Sqrt(dx*dx + dy*dy)
 			if dist > 0 {
-				speed := 9.0 // Fast projectile
+				speed := 540.0 // Fast projectile
 				velX := (dx / dist) * speed
 				velY := (dy / dist) * speed
 				// Reset lock after shooting
@@ -78,19 +79,19 @@ func (e *Enemy) TryShoot() *Projectile {
 		}
 	case EnemyShieldBearer:
 		// Shoots straight down, medium speed
-		return NewProjectile(e.X, e.Y+e.Radius, 0, 5, false, 15)
+		return NewProjectile(e.X, e.Y+e.Radius, 0, 300, false, 15)
 	}
 	return nil
 }
 
-// UpdateBurning updates the burning DoT effect
-func (e *Enemy) UpdateBurning() {
+// UpdateBurning advances the burning DoT effect by deltaTime seconds
+func (e *Enemy) UpdateBurning(deltaTime float64) {
 	if !e.Burning {
 		return
 	}
 
-	e.BurnDuration -= 1.0 / 60.0
-	e.BurnTickTimer -= 1.0 / 60.0
+	e.BurnDuration -= deltaTime
+	e.BurnTickTimer -= deltaTime
 
 	if e.BurnDuration <= 0 {
 		e.Burning = false
@@ -225,7 +226,7 @@ func (e *Enemy) Reset() {
 	e.VelX = 0
 	e.VelY = 0
 	e.Radius = 15
-	e.Speed = 2
+	e.Speed = 120
 	e.Health = 10
 	e.MaxHealth = 10
 	e.Points = 10
@@ -4,26 +4,26 @@ import (
 	"math"
 )
 
-// Update updates enemy AI behavior and movement
-func (e *Enemy) Update(playerX, playerY float64, screenWidth, screenHeight int) {
-	e.AnimTimer += 0.1
-	e.ShootTimer += 1.0 / 60.0
+// Update advances enemy AI behavior and movement by deltaTime seconds
+func (e *Enemy) Update(deltaTime, playerX, playerY float64, screenWidth, screenHeight int) {
+	e.AnimTimer += 6 * deltaTime
+	e.ShootTimer += deltaTime
 
 	// Update burning DoT
-	e.UpdateBurning()
+	e.UpdateBurning(deltaTime)
 
 	switch e.Type {
 	case EnemyScout:
 		// Straight down movement
 		e.VelY = e.Speed
-		e.Y += e.VelY
+		e.Y += e.VelY * deltaTime
 	case EnemyDrone:
 		// Wave pattern
-		e.Phase += 0.05
-		e.VelX = math.Sin(e.Phase) * 3
+		e.Phase += 3 * deltaTime
+		e.VelX = math.Sin(e.Phase) * 180
 		e.VelY = e.Speed
-		e.X += e.VelX
-		e.Y += e.VelY
+		e.X += e.VelX * deltaTime
+		e.Y += e.VelY * deltaTime
 	case EnemyHunter:
 		// Track player horizontally
 		dx := playerX - e.X
@@ -37,12 +37,12 @@ func (e *Enemy) Update(playerX, playerY float64, screenWidth, screenHeight int)
 			e.VelX = 0
 		}
 		e.VelY = e.Speed * 0.6
-		e.X += e.VelX
-		e.Y += e.VelY
+		e.X += e.VelX * deltaTime
+		e.Y += e.VelY * deltaTime
 	case EnemyTank:
 		// Slow descent
 		e.VelY = e.Speed
-		e.Y += e.VelY
+		e.Y += e.VelY * deltaTime
 	case EnemyBomber:
 		// Dive toward player
 		dx := playerX - e.X
@@ -52,8 +52,8 @@ func (e *Enemy) Update(playerX, playerY float64, screenWidth, screenHeight int)
 			e.VelX = (dx / dist) * e.Speed
 			e.VelY = (dy / dist) * e.Speed
 		}
-		e.X += e.VelX
-		e.Y += e.VelY
+		e.X += e.VelX * deltaTime
+		e.Y += e.VelY * deltaTime
 	case EnemySniper:
 		// Stay near top of screen, slight horizontal drift
 		targetY := 80.0 // Stay near top
@@ -65,13 +65,13 @@ func (e *Enemy) Update(playerX, playerY float64, screenWidth, screenHeight int)
 			e.VelY = 0
 		}
 		// Slow drift side to side
-		e.Phase += 0.02
-		e.VelX = math.Sin(e.Phase) * 0.8
-		e.X += e.VelX
-		e.Y += e.VelY
+		e.Phase += 1.2 * deltaTime
+		e.VelX = math.Sin(e.Phase) * 48
+		e.X += e.VelX * deltaTime
+		e.Y += e.VelY * deltaTime
 
 		// Lock-on timer
-		e.SniperLockTimer += 1.0 / 60.0
+		e.SniperLockTimer += deltaTime
 		if e.SniperLockTimer >= 1.5 { // 1.5 second lock-on time
 			e.SniperLocked = true
 			e.SniperTargetX = playerX
@@ -79,18 +79,18 @@ func (e *Enemy) Update(playerX, playerY float64, screenWidth, screenHeight int)
 		}
 	case EnemySplitter:
 		// Wave pattern similar to drone
-		e.Phase += 0.05
-		e.VelX = math.Sin(e.Phase) * 2.5
+		e.Phase += 3 * deltaTime
+		e.VelX = math.Sin(e.Phase) * 150
 		e.VelY = e.Speed
-		e.X += e.VelX
-		e.Y += e.VelY
+		e.X += e.VelX * deltaTime
+		e.Y += e.VelY * deltaTime
 	case EnemyShieldBearer:
 		// Slow advance straight down
 		e.VelY = e.Speed
-		e.Y += e.VelY
+		e.Y += e.VelY * deltaTime
 
 		// Shield regeneration (2 shields per second after 3 seconds)
-		e.ShieldRegenTimer += 1.0 / 60.0
+		e.ShieldRegenTimer += deltaTime
 		if e.ShieldRegenTimer >= 3.5 && e.ShieldPoints < e.MaxShieldPoints {
 			// Regen 1 shield every 0.5 seconds
 			e.ShieldPoints++
@@ -115,11 +115,26 @@ func (e *Enemy) Update(playerX, playerY float64, screenWidth, screenHeight int)
 	}
 }
 
+// AITarget returns the position the enemy's AI is steering or aiming at,
+// if it has one: hunters and bombers chase the player, locked-on snipers
+// aim at the position they locked
+func (e *Enemy) AITarget(playerX, playerY float64) (x, y float64, ok bool) {
+	switch e.Type {
+	case EnemyHunter, EnemyBomber:
+		return playerX, playerY, true
+	case EnemySniper:
+		if e.SniperLocked {
+			return e.SniperTargetX, e.SniperTargetY, true
+		}
+	}
+	return 0, 0, false
+}
+
 // UpdateFormation updates enemy position based on formation behavior
 // Performance note: This iterates through all enemies to find formation allies.
 // Optimized with early termination checks (FormationID, FormationType) so only
 // formation members are processed. Typical formations have 3-7 enemies.
-func (e *Enemy) UpdateFormation(allEnemies []*Enemy) {
+func (e *Enemy) UpdateFormation(deltaTime float64, allEnemies []*Enemy) {
 	if e.FormationType == FormationTypeNone {
 		return
 	}
@@ -145,9 +160,9 @@ func (e *Enemy) UpdateFormation(allEnemies []*Enemy) {
 	case FormationTypeVFormation:
 		e.updateVFormation()
 	case FormationTypeCircular:
-		e.updateCircularFormation()
+		e.updateCircularFormation(deltaTime)
 	case FormationTypeWave:
-		e.updateWaveFormation()
+		e.updateWaveFormation(deltaTime)
 	case FormationTypePincer:
 		e.updatePincerFormation()
 	case FormationTypeConvoy:
@@ -174,12 +189,12 @@ func (e *Enemy) updateVFormation() {
 
 		// Maintain formation angle
 		if len(e.NearbyAllies) > 0 && e.FormationIndex%2 == 0 {
-			e.VelX += math.Sin(angleOffset) * 1.5
+			e.VelX += math.Sin(angleOffset) * 90
 		}
 	}
 }
 
-func (e *Enemy) updateCircularFormation() {
+func (e *Enemy) updateCircularFormation(deltaTime float64) {
 	// Enemies orbit around a center point
 	if !e.IsFormationLeader && len(e.NearbyAllies) > 0 {
 		// Calculate rotation around center
@@ -187,7 +202,7 @@ func (e *Enemy) updateCircularFormation() {
 		centerY := (e.Y + e.FormationTargetY) / 2.0
 
 		angle := math.Atan2(e.Y-centerY, e.X-centerX)
-		angle += 0.02 // Rotation speed
+		angle += 1.2 * deltaTime // Rotation speed
 
 		orbitRadius := 80.0
 		e.FormationTargetX = centerX + orbitRadius*math.Cos(angle)
@@ -205,19 +220,19 @@ func (e *Enemy) updateCircularFormation() {
 	}
 }
 
-func (e *Enemy) updateWaveFormation() {
+func (e *Enemy) updateWaveFormation(deltaTime float64) {
 	// All enemies move together in undulating pattern
-	e.Phase += 0.03
-	e.VelX = math.Sin(e.Phase) * 2.5
+	e.Phase += 1.8 * deltaTime
+	e.VelX = math.Sin(e.Phase) * 150
 	e.VelY = e.Speed
 }
 
 func (e *Enemy) updatePincerFormation() {
 	// Split formation: left and right flanks
 	if e.FormationIndex%2 == 0 {
-		e.VelX = -2.0 // Move left
+		e.VelX = -120 // Move left
 	} else {
-		e.VelX = 2.0 // Move right
+		e.VelX = 120 // Move right
 	}
 	e.VelY = e.Speed * 0.8
 }
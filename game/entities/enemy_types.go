@@ -2,7 +2,8 @@ package entities
 
 import (
 	"math"
-	"math/rand"
+
+	"stellar-siege/game/rng"
 )
 
 type EnemyType int
@@ -18,6 +19,30 @@ const (
 	EnemyShieldBearer // Heavily armored with regenerating shield
 )
 
+// String returns the display name of the enemy type
+func (t EnemyType) String() string {
+	switch t {
+	case EnemyScout:
+		return "Scout"
+	case EnemyDrone:
+		return "Drone"
+	case EnemyHunter:
+		return "Hunter"
+	case EnemyTank:
+		return "Tank"
+	case EnemyBomber:
+		return "Bomber"
+	case EnemySniper:
+		return "Sniper"
+	case EnemySplitter:
+		return "Splitter"
+	case EnemyShieldBearer:
+		return "Shield Bearer"
+	default:
+		return "Unknown"
+	}
+}
+
 // FormationType represents different enemy formation patterns
 type FormationType int
 
@@ -37,49 +62,49 @@ func NewEnemy(x, y float64, enemyType EnemyType) *Enemy {
 		Y:         y,
 		Type:      enemyType,
 		Active:    true,
-		Phase:     rand.Float64() * math.Pi * 2,
+		Phase:     rng.Float64() * math.Pi * 2,
 		AnimTimer: 0,
 	}
 
 	switch enemyType {
 	case EnemyScout:
 		e.Radius = 15
-		e.Speed = 4
+		e.Speed = 240
 		e.Health = 20
 		e.MaxHealth = 20
 		e.Points = 100
 		e.ShootRate = 0 // Doesn't shoot
 	case EnemyDrone:
 		e.Radius = 18
-		e.Speed = 2.5
+		e.Speed = 150
 		e.Health = 30
 		e.MaxHealth = 30
 		e.Points = 150
 		e.ShootRate = 2.0
 	case EnemyHunter:
 		e.Radius = 20
-		e.Speed = 3
+		e.Speed = 180
 		e.Health = 50
 		e.MaxHealth = 50
 		e.Points = 250
 		e.ShootRate = 1.5
 	case EnemyTank:
 		e.Radius = 30
-		e.Speed = 1.5
+		e.Speed = 90
 		e.Health = 100
 		e.MaxHealth = 100
 		e.Points = 400
 		e.ShootRate = 1.0
 	case EnemyBomber:
 		e.Radius = 22
-		e.Speed = 3.5
+		e.Speed = 210
 		e.Health = 40
 		e.MaxHealth = 40
 		e.Points = 300
 		e.ShootRate = 0 // Explodes instead
 	case EnemySniper:
 		e.Radius = 16
-		e.Speed = 1.0 // Very slow, stays at top
+		e.Speed = 60 // Very slow, stays at top
 		e.Health = 35
 		e.MaxHealth = 35
 		e.Points = 350
@@ -88,7 +113,7 @@ func NewEnemy(x, y float64, enemyType EnemyType) *Enemy {
 		e.SniperLocked = false
 	case EnemySplitter:
 		e.Radius = 20
-		e.Speed = 2.0
+		e.Speed = 120
 		e.Health = 45
 		e.MaxHealth = 45
 		e.Points = 200  // Lower points since it splits
@@ -96,7 +121,7 @@ func NewEnemy(x, y float64, enemyType EnemyType) *Enemy {
 		e.HasSplit = false
 	case EnemyShieldBearer:
 		e.Radius = 25
-		e.Speed = 1.2 // Slow like tank
+		e.Speed = 72 // Slow like tank
 		e.Health = 80
 		e.MaxHealth = 80
 		e.ShieldPoints = 50 // Starts with shield
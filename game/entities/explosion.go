@@ -61,7 +61,7 @@ func NewExplosionWithType(x, y, size float64, expType ExplosionType) *Explosion
 		switch expType {
 		case ExplosionBlast:
 			// Bigger, faster burst
-			speed = rand.Float64()*6 + 3
+			speed = rand.Float64()*360 + 180
 			life = rand.Float64()*0.6 + 0.2
 			r = uint8(255)
 			g = uint8(180 + rand.Intn(75))
@@ -69,14 +69,14 @@ func NewExplosionWithType(x, y, size float64, expType ExplosionType) *Explosion
 
 		case ExplosionSmoke:
 			// Slower, lingering smoke
-			speed = rand.Float64()*2 + 0.5
+			speed = rand.Float64()*120 + 30
 			life = rand.Float64()*1.2 + 0.8
 			gray := uint8(100 + rand.Intn(80))
 			r, g, b = gray, gray, gray
 
 		case ExplosionEnergy:
 			// Blue/cyan energy burst
-			speed = rand.Float64()*5 + 2
+			speed = rand.Float64()*300 + 120
 			life = rand.Float64()*0.7 + 0.3
 			r = uint8(100 + rand.Intn(100))
 			g = uint8(150 + rand.Intn(100))
@@ -84,7 +84,7 @@ func NewExplosionWithType(x, y, size float64, expType ExplosionType) *Explosion
 
 		default: // ExplosionStandard
 			// Standard fire explosion
-			speed = rand.Float64()*4 + 2
+			speed = rand.Float64()*240 + 120
 			life = rand.Float64()*0.5 + 0.3
 			r = uint8(200 + rand.Intn(55))
 			g = uint8(100 + rand.Intn(100))
@@ -114,19 +114,22 @@ func NewExplosionWithType(x, y, size float64, expType ExplosionType) *Explosion
 	}
 }
 
-func (e *Explosion) Update() {
-	e.Timer += 1.0 / 60.0
+// Update advances the explosion by deltaTime seconds
+func (e *Explosion) Update(deltaTime float64) {
+	e.Timer += deltaTime
+	damping := Decay(0.93, deltaTime)
+	shrink := Decay(0.96, deltaTime)
 	allDead := true
 
 	for i := range e.Particles {
 		p := &e.Particles[i]
 		if p.Life > 0 {
-			p.X += p.VelX
-			p.Y += p.VelY
-			p.VelX *= 0.93 // Slow down slightly
-			p.VelY *= 0.93
-			p.Life -= 1.0 / 60.0
-			p.Size *= 0.96 // Shrink slower for better effect
+			p.X += p.VelX * deltaTime
+			p.Y += p.VelY * deltaTime
+			p.VelX *= damping // Slow down slightly
+			p.VelY *= damping
+			p.Life -= deltaTime
+			p.Size *= shrink // Shrink slower for better effect
 			allDead = false
 		}
 	}
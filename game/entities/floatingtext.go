@@ -14,7 +14,7 @@ import (
 // FloatingText represents a temporary text that floats and fades
 type FloatingText struct {
 	X, Y      float64
-	VelY      float64 // Upward velocity
+	VelY      float64 // Upward velocity in pixels per second
 	Text      string
 	TextColor color.RGBA
 	Life      float64 // Time remaining (seconds)
@@ -27,7 +27,7 @@ func NewFloatingText(x, y float64, text string, col color.RGBA) *FloatingText {
 	return &FloatingText{
 		X:         x,
 		Y:         y,
-		VelY:      -2.0, // Float upward
+		VelY:      -120, // Float upward
 		Text:      text,
 		TextColor: col,
 		Life:      2.0, // 2 seconds lifetime
@@ -55,20 +55,20 @@ func NewFloatingUpgrade(x, y float64, level int) *FloatingText {
 }
 
 // Update moves the floating text
-func (ft *FloatingText) Update() {
+func (ft *FloatingText) Update(deltaTime float64) {
 	if !ft.Active {
 		return
 	}
 
-	ft.Life -= 1.0 / 60.0
+	ft.Life -= deltaTime
 	if ft.Life <= 0 {
 		ft.Active = false
 		ft.Life = 0
 		return
 	}
 
-	ft.Y += ft.VelY
-	ft.VelY *= 0.98 // Slow down slightly
+	ft.Y += ft.VelY * deltaTime
+	ft.VelY *= Decay(0.98, deltaTime) // Slow down slightly
 }
 
 // Draw renders the floating text
@@ -104,7 +104,7 @@ func (ft *FloatingText) Draw(screen *ebiten.Image, shakeX, shakeY float64) {
 func (ft *FloatingText) Reset() {
 	ft.X = 0
 	ft.Y = 0
-	ft.VelY = -2.0
+	ft.VelY = -120
 	ft.Text = ""
 	ft.TextColor = color.RGBA{255, 255, 255, 255}
 	ft.Life = 2.0
@@ -138,7 +138,7 @@ type FloatingParticle struct {
 // NewFloatingParticle creates a particle at (x, y) moving in a random direction
 func NewFloatingParticle(x, y float64, col color.RGBA) *FloatingParticle {
 	angle := math.Pi * 2 * rand.Float64()
-	speed := 2.0 + rand.Float64()*3.0
+	speed := 120.0 + rand.Float64()*180.0
 	return &FloatingParticle{
 		X:       x,
 		Y:       y,
@@ -152,22 +152,23 @@ func NewFloatingParticle(x, y float64, col color.RGBA) *FloatingParticle {
 	}
 }
 
-// Update moves and fades the particle
-func (fp *FloatingParticle) Update() {
+// Update moves and fades the particle over deltaTime seconds
+func (fp *FloatingParticle) Update(deltaTime float64) {
 	if !fp.Active {
 		return
 	}
 
-	fp.Life -= 1.0 / 60.0
+	fp.Life -= deltaTime
 	if fp.Life <= 0 {
 		fp.Active = false
 		return
 	}
 
-	fp.X += fp.VelX
-	fp.Y += fp.VelY
-	fp.VelX *= 0.95
-	fp.VelY *= 0.95
+	fp.X += fp.VelX * deltaTime
+	fp.Y += fp.VelY * deltaTime
+	damping := Decay(0.95, deltaTime)
+	fp.VelX *= damping
+	fp.VelY *= damping
 
 	// Scale down as life decreases
 	fp.Scale = (fp.Life / fp.MaxLife) * 1.0
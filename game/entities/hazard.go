@@ -84,10 +84,10 @@ func NewHazard(x, y float64, hazardType HazardType) *Hazard {
 	return h
 }
 
-// Update updates the hazard state
-func (h *Hazard) Update() {
-	h.AnimTimer += 0.1
-	h.LastDamage += 1.0 / 60.0
+// Update advances the hazard by deltaTime seconds
+func (h *Hazard) Update(deltaTime float64) {
+	h.AnimTimer += 6 * deltaTime
+	h.LastDamage += deltaTime
 }
 
 // TakeDamage applies damage to the hazard
@@ -259,8 +259,8 @@ func NewHazardSpawner() *HazardSpawner {
 }
 
 // Update updates hazard spawning
-func (hs *HazardSpawner) Update() []*Hazard {
-	hs.spawnTimer += 1.0 / 60.0
+func (hs *HazardSpawner) Update(deltaTime float64) []*Hazard {
+	hs.spawnTimer += deltaTime
 
 	newHazards := make([]*Hazard, 0)
 
@@ -273,7 +273,7 @@ func (hs *HazardSpawner) Update() []*Hazard {
 	// Update existing hazards
 	var active []*Hazard
 	for _, h := range hs.hazards {
-		h.Update()
+		h.Update(deltaTime)
 		if h.Active {
 			active = append(active, h)
 		}
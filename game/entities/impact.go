@@ -33,11 +33,12 @@ func NewImpactEffect(x, y float64, maxRadius float64, color color.RGBA) *ImpactE
 	}
 }
 
-func (i *ImpactEffect) Update() {
-	i.Life -= 1.0 / 60.0
+// Update advances the effect by deltaTime seconds
+func (i *ImpactEffect) Update(deltaTime float64) {
+	i.Life -= deltaTime
 
 	if i.Expanding {
-		i.Radius += i.MaxRadius / (i.MaxLife * 60)
+		i.Radius += i.MaxRadius / i.MaxLife * deltaTime
 		if i.Radius >= i.MaxRadius {
 			i.Radius = i.MaxRadius
 			i.Expanding = false
@@ -0,0 +1,83 @@
+package entities
+
+import "fmt"
+
+// InputFrame is a bitmask of the player controls held during one simulation tick.
+// Frames are captured from the keyboard during live play and replayed verbatim
+// when a run is re-simulated.
+type InputFrame uint32
+
+const (
+	InputUp InputFrame = 1 << iota
+	InputDown
+	InputLeft
+	InputRight
+	InputShoot
+)
+
+// Co-op runs pack every pilot's controls into one frame: pilot i holds the
+// bits from i*PilotInputBits. A solo frame is pilot 0's controls alone.
+const (
+	PilotInputBits = 8
+	MaxPilots      = 4 // Pilots that fit in a frame
+)
+
+// CombineInputs packs the controls of each pilot, in order, into one frame
+func CombineInputs(pilots ...InputFrame) InputFrame {
+	var f InputFrame
+	for i, p := range pilots[:min(len(pilots), MaxPilots)] {
+		f |= p.Pilot(0) << (i * PilotInputBits)
+	}
+	return f
+}
+
+// Pilot returns the controls held by pilot i in a co-op frame
+func (f InputFrame) Pilot(i int) InputFrame {
+	return f >> (i * PilotInputBits) & (1<<PilotInputBits - 1)
+}
+
+// Has returns true if every control in flag is held in this frame
+func (f InputFrame) Has(flag InputFrame) bool {
+	return f&flag == flag
+}
+
+// inputNames names each control for String
+var inputNames = []struct {
+	flag InputFrame
+	name string
+}{
+	{InputUp, "UP"},
+	{InputDown, "DOWN"},
+	{InputLeft, "LEFT"},
+	{InputRight, "RIGHT"},
+	{InputShoot, "SHOOT"},
+}
+
+// String lists the held controls joined by "+", or "-" when none are held.
+// Controls of further pilots follow as "P2:..." when any are held.
+func (f InputFrame) String() string {
+	s := f.Pilot(0).controls()
+	for i := 1; i < MaxPilots; i++ {
+		if p := f.Pilot(i); p != 0 {
+			s += fmt.Sprintf(" P%d:%s", i+1, p.controls())
+		}
+	}
+	return s
+}
+
+// controls lists the controls of a single pilot's frame
+func (f InputFrame) controls() string {
+	s := ""
+	for _, in := range inputNames {
+		if f.Has(in.flag) {
+			if s != "" {
+				s += "+"
+			}
+			s += in.name
+		}
+	}
+	if s == "" {
+		return "-"
+	}
+	return s
+}
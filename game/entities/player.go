@@ -2,8 +2,6 @@ package entities
 
 import (
 	"math/rand"
-
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 // ThrusterParticle represents a particle in the player's thruster trail
@@ -18,7 +16,7 @@ type Player struct {
 	X, Y         float64
 	VelX, VelY   float64
 	Radius       float64
-	Speed        float64
+	Speed        float64 // Pixels per second
 	Health       int
 	MaxHealth    int
 	Shield       int
@@ -33,7 +31,7 @@ type Player struct {
 	EngineGlow   float64
 
 	// Difficulty-dependent settings
-	ShieldRegenRate   float64 // HP per frame
+	ShieldRegenRate   float64 // HP per second
 	InvincibilityTime float64 // seconds
 	ShieldRegenDelay  float64 // seconds before regen starts
 	LastDamageTime    float64 // when damage was last taken
@@ -64,6 +62,8 @@ type Player struct {
 	SlowFireTimer        float64 // Slow fire duration
 	SlowFireMultiplier   float64 // Fire rate reduction
 	InvincibilityTimer   float64 // Invincibility from power-up
+
+	SideBlasterCounter int // Tracks which side blaster fires next (alternates each shot)
 }
 
 func NewPlayer(x, y float64) *Player {
@@ -71,7 +71,7 @@ func NewPlayer(x, y float64) *Player {
 		X:                 x,
 		Y:                 y,
 		Radius:            20,
-		Speed:             6,
+		Speed:             360,
 		Health:            100,
 		MaxHealth:         100,
 		Shield:            50,
@@ -82,7 +82,7 @@ func NewPlayer(x, y float64) *Player {
 		FireRate:          0.12,
 		Active:            true,
 		EngineGlow:        0,
-		ShieldRegenRate:   0.5,  // Default (Normal difficulty)
+		ShieldRegenRate:   30,   // Default (Normal difficulty)
 		InvincibilityTime: 0.25, // Default (Normal difficulty)
 		ShieldRegenDelay:  3.5,  // Default (Normal difficulty)
 		LastDamageTime:    -999, // Initialize to long ago so regen starts immediately
@@ -109,9 +109,10 @@ func NewPlayer(x, y float64) *Player {
 	}
 }
 
-func (p *Player) Update(screenWidth, screenHeight int, gameTime float64) {
+// Update advances the player by deltaTime seconds using the controls held in input
+func (p *Player) Update(deltaTime float64, screenWidth, screenHeight int, gameTime float64, input InputFrame) {
 	// Update weapon manager
-	p.WeaponMgr.Update()
+	p.WeaponMgr.Update(deltaTime)
 
 	// Apply fire rate modifiers from mystery power-ups to weapon manager
 	if p.RapidFireTimer > 0 {
@@ -125,37 +126,37 @@ func (p *Player) Update(screenWidth, screenHeight int, gameTime float64) {
 
 	// Update mystery power-up effect timers
 	if p.SpeedBoostTimer > 0 {
-		p.SpeedBoostTimer -= 1.0 / 60.0
+		p.SpeedBoostTimer -= deltaTime
 		if p.SpeedBoostTimer <= 0 {
 			p.SpeedBoostMultiplier = 1.0
 		}
 	}
 	if p.RapidFireTimer > 0 {
-		p.RapidFireTimer -= 1.0 / 60.0
+		p.RapidFireTimer -= deltaTime
 		if p.RapidFireTimer <= 0 {
 			p.RapidFireMultiplier = 1.0
 		}
 	}
 	if p.ScoreMultiplierTimer > 0 {
-		p.ScoreMultiplierTimer -= 1.0 / 60.0
+		p.ScoreMultiplierTimer -= deltaTime
 		if p.ScoreMultiplierTimer <= 0 {
 			p.ScoreMultiplier = 1.0
 		}
 	}
 	if p.ControlReversalTimer > 0 {
-		p.ControlReversalTimer -= 1.0 / 60.0
+		p.ControlReversalTimer -= deltaTime
 		if p.ControlReversalTimer <= 0 {
 			p.ControlReversed = false
 		}
 	}
 	if p.SlowFireTimer > 0 {
-		p.SlowFireTimer -= 1.0 / 60.0
+		p.SlowFireTimer -= deltaTime
 		if p.SlowFireTimer <= 0 {
 			p.SlowFireMultiplier = 1.0
 		}
 	}
 	if p.InvincibilityTimer > 0 {
-		p.InvincibilityTimer -= 1.0 / 60.0
+		p.InvincibilityTimer -= deltaTime
 	}
 
 	// Handle movement
@@ -168,16 +169,16 @@ func (p *Player) Update(screenWidth, screenHeight int, gameTime float64) {
 		controlMult = -1.0
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeyW) || ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
+	if input.Has(InputUp) {
 		p.VelY = -p.Speed * controlMult
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyS) || ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
+	if input.Has(InputDown) {
 		p.VelY = p.Speed * controlMult
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyA) || ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
+	if input.Has(InputLeft) {
 		p.VelX = -p.Speed * controlMult
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyD) || ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
+	if input.Has(InputRight) {
 		p.VelX = p.Speed * controlMult
 	}
 
@@ -191,8 +192,8 @@ func (p *Player) Update(screenWidth, screenHeight int, gameTime float64) {
 	p.VelX *= p.SpeedBoostMultiplier
 	p.VelY *= p.SpeedBoostMultiplier
 
-	p.X += p.VelX
-	p.Y += p.VelY
+	p.X += p.VelX * deltaTime
+	p.Y += p.VelY * deltaTime
 
 	// Add thruster trail particles when moving (ring buffer - no allocations)
 	if (p.VelX != 0 || p.VelY != 0) && rand.Float64() < 0.6 {
@@ -211,8 +212,8 @@ func (p *Player) Update(screenWidth, screenHeight int, gameTime float64) {
 	// For ring buffer, we iterate through all valid particles
 	for i := 0; i < p.ThrusterTrailLen; i++ {
 		idx := (p.ThrusterTrailHead - p.ThrusterTrailLen + i + MaxThrusterTrailLen) % MaxThrusterTrailLen
-		p.ThrusterTrail[idx].Life -= 1.0 / 60.0
-		p.ThrusterTrail[idx].Y += 1.5 // Trail drifts down slightly
+		p.ThrusterTrail[idx].Life -= deltaTime
+		p.ThrusterTrail[idx].Y += 90 * deltaTime // Trail drifts down slightly
 	}
 
 	// Remove expired particles from the tail of the ring buffer
@@ -243,17 +244,17 @@ func (p *Player) Update(screenWidth, screenHeight int, gameTime float64) {
 
 	// Update cooldowns
 	if p.FireCooldown > 0 {
-		p.FireCooldown -= 1.0 / 60.0
+		p.FireCooldown -= deltaTime
 	}
 	if p.InvincTimer > 0 {
-		p.InvincTimer -= 1.0 / 60.0
+		p.InvincTimer -= deltaTime
 	}
 
 	// Regenerate shield slowly - only after delay since last damage
 	timeSinceDamage := gameTime - p.LastDamageTime
 	if p.Shield < p.MaxShield && p.InvincTimer <= 0 && timeSinceDamage >= p.ShieldRegenDelay {
 		// Use accumulator for fractional regeneration
-		p.ShieldRegenAccum += p.ShieldRegenRate
+		p.ShieldRegenAccum += p.ShieldRegenRate * deltaTime
 		if p.ShieldRegenAccum >= 1.0 {
 			regenAmount := int(p.ShieldRegenAccum)
 			p.Shield += regenAmount
@@ -267,14 +268,14 @@ func (p *Player) Update(screenWidth, screenHeight int, gameTime float64) {
 	}
 
 	// Engine glow animation
-	p.EngineGlow += 0.2
+	p.EngineGlow += 12 * deltaTime
 
 	// Charge mechanics
 	// Handle charge attack (hold space to charge)
-	if ebiten.IsKeyPressed(ebiten.KeySpace) {
+	if input.Has(InputShoot) {
 		// Charging shot (slower than normal shooting)
 		if p.ChargeLevel < 1.0 {
-			p.ChargeLevel += 0.02 // Charge over ~3 seconds
+			p.ChargeLevel += 1.2 * deltaTime // Charge over ~3 seconds
 		}
 	} else {
 		p.ChargeLevel = 0 // Reset when not charging
@@ -283,7 +284,7 @@ func (p *Player) Update(screenWidth, screenHeight int, gameTime float64) {
 	// Ultimate ability mechanics - builds from combat
 	if p.UltimateActive {
 		// Ultimate is active
-		p.UltimateTimer -= 1.0 / 60.0
+		p.UltimateTimer -= deltaTime
 		if p.UltimateTimer <= 0 {
 			p.UltimateActive = false
 			p.UltimateTimer = 0
@@ -292,7 +293,7 @@ func (p *Player) Update(screenWidth, screenHeight int, gameTime float64) {
 
 	// Slow ultimate charge regen (1% per second during gameplay)
 	if !p.UltimateActive && p.UltimateCharge < p.MaxUltimateCharge {
-		p.UltimateCharge += 0.01 / 60.0
+		p.UltimateCharge += 0.01 * deltaTime
 		if p.UltimateCharge > p.MaxUltimateCharge {
 			p.UltimateCharge = p.MaxUltimateCharge
 		}
@@ -2,7 +2,8 @@ package entities
 
 import (
 	"math"
-	"math/rand"
+
+	"stellar-siege/game/rng"
 )
 
 // MysteryEffect represents a mystery power-up effect
@@ -82,7 +83,7 @@ func (p *Player) ApplyPowerUp(puType PowerUpType) (string, bool) {
 		}
 		return "", false
 	case PowerUpSpeed:
-		p.Speed = math.Min(p.Speed+0.5, 10)
+		p.Speed = math.Min(p.Speed+30, 600)
 		return "", false
 	case PowerUpMystery:
 		effect := p.ApplyMysteryEffect()
@@ -95,14 +96,14 @@ func (p *Player) ApplyPowerUp(puType PowerUpType) (string, bool) {
 
 // ApplyMysteryEffect applies a random mystery power-up effect (60% positive, 40% negative)
 func (p *Player) ApplyMysteryEffect() MysteryEffect {
-	roll := rand.Float64()
+	roll := rng.Float64()
 
 	var effect MysteryEffect
 
 	// 60% chance for positive effects
 	if roll < 0.60 {
 		// Positive effects
-		posRoll := rand.Float64()
+		posRoll := rng.Float64()
 		switch {
 		case posRoll < 0.25: // 15% of total (25% of 60%)
 			effect = MysteryEffectSuperWeaponUpgrade
@@ -134,7 +135,7 @@ func (p *Player) ApplyMysteryEffect() MysteryEffect {
 		}
 	} else {
 		// 40% chance for negative effects
-		negRoll := rand.Float64()
+		negRoll := rng.Float64()
 		switch {
 		case negRoll < 0.25: // 10% of total (25% of 40%)
 			effect = MysteryEffectWeaponDowngrade
@@ -58,19 +58,22 @@ func (p *Player) createProjectilesForWeapon(weapon *Weapon) []*Projectile {
 	default:
 		projectiles = p.createStandardProjectiles(weapon)
 	}
+	for _, proj := range projectiles {
+		proj.Source = weapon.Name
+	}
 
 	// Add side blasters if in mixed mode
 	if useMixedMode {
 		sideBlasters := p.createSideBlasters()
+		for _, proj := range sideBlasters {
+			proj.Source = "Side Blaster"
+		}
 		projectiles = append(projectiles, sideBlasters...)
 	}
 
 	return projectiles
 }
 
-// sideBlasterCounter tracks which side to fire next (alternates each shot)
-var sideBlasterCounter int
-
 // createSideBlasters creates 1 angled shot (alternating sides) - optimized for performance
 // OPTIMIZED: Reduced from 2 simultaneous shots to 1 alternating shot (-50% projectiles)
 func (p *Player) createSideBlasters() []*Projectile {
@@ -86,15 +89,15 @@ func (p *Player) createSideBlasters() []*Projectile {
 
 	// Alternate between left (-1) and right (+1)
 	side := float64(-1)
-	if sideBlasterCounter%2 == 1 {
+	if p.SideBlasterCounter%2 == 1 {
 		side = 1
 	}
-	sideBlasterCounter++
+	p.SideBlasterCounter++
 
 	spreadAngle := side * spread * 2.0
 	angle := -math.Pi/2 + spreadAngle
-	velX := math.Cos(angle) * basicGun.ProjectileSpeed / 60.0
-	velY := math.Sin(angle) * basicGun.ProjectileSpeed / 60.0
+	velX := math.Cos(angle) * basicGun.ProjectileSpeed
+	velY := math.Sin(angle) * basicGun.ProjectileSpeed
 
 	proj := NewProjectileWithColor(
 		p.X,
@@ -125,7 +128,7 @@ func (p *Player) createStandardProjectiles(weapon *Weapon) []*Projectile {
 				p.X,
 				p.Y-p.Radius,
 				0,
-				-weapon.ProjectileSpeed/60.0,
+				-weapon.ProjectileSpeed,
 				true,
 				int(weapon.Damage),
 				weapon.Color,
@@ -141,7 +144,7 @@ func (p *Player) createStandardProjectiles(weapon *Weapon) []*Projectile {
 				p.X,
 				p.Y-p.Radius,
 				0,
-				-weapon.ProjectileSpeed/60.0,
+				-weapon.ProjectileSpeed,
 				true,
 				int(weapon.Damage),
 				weapon.Color,
@@ -153,8 +156,8 @@ func (p *Player) createStandardProjectiles(weapon *Weapon) []*Projectile {
 			angles := []float64{-weapon.Spread * 2.0, weapon.Spread * 2.0}
 			for _, spreadAngle := range angles {
 				angle := -math.Pi/2 + spreadAngle
-				velX := math.Cos(angle) * weapon.ProjectileSpeed / 60.0
-				velY := math.Sin(angle) * weapon.ProjectileSpeed / 60.0
+				velX := math.Cos(angle) * weapon.ProjectileSpeed
+				velY := math.Sin(angle) * weapon.ProjectileSpeed
 
 				proj := NewProjectileWithColor(
 					p.X,
@@ -177,7 +180,7 @@ func (p *Player) createStandardProjectiles(weapon *Weapon) []*Projectile {
 				p.X,
 				p.Y-p.Radius,
 				0,
-				-weapon.ProjectileSpeed/60.0,
+				-weapon.ProjectileSpeed,
 				true,
 				int(weapon.Damage),
 				weapon.Color,
@@ -192,8 +195,8 @@ func (p *Player) createStandardProjectiles(weapon *Weapon) []*Projectile {
 			}
 			for _, spreadAngle := range angles {
 				angle := -math.Pi/2 + spreadAngle
-				velX := math.Cos(angle) * weapon.ProjectileSpeed / 60.0
-				velY := math.Sin(angle) * weapon.ProjectileSpeed / 60.0
+				velX := math.Cos(angle) * weapon.ProjectileSpeed
+				velY := math.Sin(angle) * weapon.ProjectileSpeed
 
 				proj := NewProjectileWithColor(
 					p.X,
@@ -223,8 +226,8 @@ func (p *Player) createStandardProjectiles(weapon *Weapon) []*Projectile {
 
 		// Calculate velocity with spread
 		angle := -math.Pi/2 + spreadAngle // -90 degrees (up) + spread
-		velX := math.Cos(angle) * weapon.ProjectileSpeed / 60.0
-		velY := math.Sin(angle) * weapon.ProjectileSpeed / 60.0
+		velX := math.Cos(angle) * weapon.ProjectileSpeed
+		velY := math.Sin(angle) * weapon.ProjectileSpeed
 
 		// Create projectile
 		proj := NewProjectileWithColor(
@@ -257,8 +260,8 @@ func (p *Player) createFollowingRockets(weapon *Weapon) []*Projectile {
 
 		// Calculate initial velocity with spread
 		angle := -math.Pi/2 + spreadAngle
-		velX := math.Cos(angle) * weapon.ProjectileSpeed / 60.0
-		velY := math.Sin(angle) * weapon.ProjectileSpeed / 60.0
+		velX := math.Cos(angle) * weapon.ProjectileSpeed
+		velY := math.Sin(angle) * weapon.ProjectileSpeed
 
 		proj := NewProjectileWithColor(
 			p.X,
@@ -273,7 +276,7 @@ func (p *Player) createFollowingRockets(weapon *Weapon) []*Projectile {
 
 		// Enable homing behavior
 		proj.Homing = true
-		proj.HomingSpeed = 0.08 // Turn rate in radians per frame
+		proj.HomingSpeed = 4.8 // Turn rate in radians per second
 
 		projectiles = append(projectiles, proj)
 	}
@@ -286,7 +289,7 @@ func (p *Player) createChainLightning(weapon *Weapon) []*Projectile {
 	var projectiles []*Projectile
 
 	// Single lightning bolt (chains on hit)
-	velY := -weapon.ProjectileSpeed / 60.0
+	velY := -weapon.ProjectileSpeed
 
 	proj := NewProjectileWithColor(
 		p.X,
@@ -321,7 +324,7 @@ func (p *Player) createFlamethrower(weapon *Weapon) []*Projectile {
 		p.X,
 		p.Y-p.Radius,
 		0,
-		-weapon.ProjectileSpeed/60.0,
+		-weapon.ProjectileSpeed,
 		true,
 		int(weapon.Damage),
 		weapon.Color,
@@ -346,8 +349,8 @@ func (p *Player) createFlamethrower(weapon *Weapon) []*Projectile {
 
 		// Calculate velocity with spread
 		angle := -math.Pi/2 + spreadAngle
-		velX := math.Cos(angle) * weapon.ProjectileSpeed / 60.0
-		velY := math.Sin(angle) * weapon.ProjectileSpeed / 60.0
+		velX := math.Cos(angle) * weapon.ProjectileSpeed
+		velY := math.Sin(angle) * weapon.ProjectileSpeed
 
 		proj := NewProjectileWithColor(
 			p.X,
@@ -376,7 +379,7 @@ func (p *Player) createIonBeam(weapon *Weapon) []*Projectile {
 	var projectiles []*Projectile
 
 	// Single beam projectile
-	velY := -weapon.ProjectileSpeed / 60.0
+	velY := -weapon.ProjectileSpeed
 
 	proj := NewProjectileWithColor(
 		p.X,
@@ -430,16 +433,16 @@ func (p *Player) GetChargedProjectiles() []*Projectile {
 		// Single powerful shot at full charge, or multiple weaker shots at lower charge
 		if p.ChargeLevel > 0.8 {
 			// Full charge - massive central shot
-			projectiles = append(projectiles, NewProjectile(p.X, p.Y-p.Radius, 0, -15, true, baseDamage))
+			projectiles = append(projectiles, NewProjectile(p.X, p.Y-p.Radius, 0, -900, true, baseDamage))
 		} else if p.ChargeLevel > 0.5 {
 			// Medium charge - 3 shots
-			projectiles = append(projectiles, NewProjectile(p.X-10, p.Y-p.Radius, 0, -14, true, baseDamage-5))
-			projectiles = append(projectiles, NewProjectile(p.X, p.Y-p.Radius, 0, -15, true, baseDamage))
-			projectiles = append(projectiles, NewProjectile(p.X+10, p.Y-p.Radius, 0, -14, true, baseDamage-5))
+			projectiles = append(projectiles, NewProjectile(p.X-10, p.Y-p.Radius, 0, -840, true, baseDamage-5))
+			projectiles = append(projectiles, NewProjectile(p.X, p.Y-p.Radius, 0, -900, true, baseDamage))
+			projectiles = append(projectiles, NewProjectile(p.X+10, p.Y-p.Radius, 0, -840, true, baseDamage-5))
 		} else {
 			// Light charge - standard spread
-			projectiles = append(projectiles, NewProjectile(p.X-8, p.Y-p.Radius, 0, -13, true, baseDamage-2))
-			projectiles = append(projectiles, NewProjectile(p.X+8, p.Y-p.Radius, 0, -13, true, baseDamage-2))
+			projectiles = append(projectiles, NewProjectile(p.X-8, p.Y-p.Radius, 0, -780, true, baseDamage-2))
+			projectiles = append(projectiles, NewProjectile(p.X+8, p.Y-p.Radius, 0, -780, true, baseDamage-2))
 		}
 	} else {
 		// Regular shooting if not charged enough
@@ -447,25 +450,25 @@ func (p *Player) GetChargedProjectiles() []*Projectile {
 
 		switch p.WeaponLevel {
 		case 1:
-			projectiles = append(projectiles, NewProjectile(p.X, p.Y-p.Radius, 0, -12, true, 10))
+			projectiles = append(projectiles, NewProjectile(p.X, p.Y-p.Radius, 0, -720, true, 10))
 		case 2:
-			projectiles = append(projectiles, NewProjectile(p.X-10, p.Y-p.Radius, 0, -12, true, 10))
-			projectiles = append(projectiles, NewProjectile(p.X+10, p.Y-p.Radius, 0, -12, true, 10))
+			projectiles = append(projectiles, NewProjectile(p.X-10, p.Y-p.Radius, 0, -720, true, 10))
+			projectiles = append(projectiles, NewProjectile(p.X+10, p.Y-p.Radius, 0, -720, true, 10))
 		case 3:
-			projectiles = append(projectiles, NewProjectile(p.X, p.Y-p.Radius, 0, -12, true, 12))
-			projectiles = append(projectiles, NewProjectile(p.X-15, p.Y-p.Radius+5, -1, -11, true, 10))
-			projectiles = append(projectiles, NewProjectile(p.X+15, p.Y-p.Radius+5, 1, -11, true, 10))
+			projectiles = append(projectiles, NewProjectile(p.X, p.Y-p.Radius, 0, -720, true, 12))
+			projectiles = append(projectiles, NewProjectile(p.X-15, p.Y-p.Radius+5, -60, -660, true, 10))
+			projectiles = append(projectiles, NewProjectile(p.X+15, p.Y-p.Radius+5, 60, -660, true, 10))
 		case 4:
-			projectiles = append(projectiles, NewProjectile(p.X-8, p.Y-p.Radius, 0, -13, true, 15))
-			projectiles = append(projectiles, NewProjectile(p.X+8, p.Y-p.Radius, 0, -13, true, 15))
-			projectiles = append(projectiles, NewProjectile(p.X-20, p.Y-p.Radius+5, -2, -11, true, 12))
-			projectiles = append(projectiles, NewProjectile(p.X+20, p.Y-p.Radius+5, 2, -11, true, 12))
+			projectiles = append(projectiles, NewProjectile(p.X-8, p.Y-p.Radius, 0, -780, true, 15))
+			projectiles = append(projectiles, NewProjectile(p.X+8, p.Y-p.Radius, 0, -780, true, 15))
+			projectiles = append(projectiles, NewProjectile(p.X-20, p.Y-p.Radius+5, -120, -660, true, 12))
+			projectiles = append(projectiles, NewProjectile(p.X+20, p.Y-p.Radius+5, 120, -660, true, 12))
 		default: // Level 5+
-			projectiles = append(projectiles, NewProjectile(p.X, p.Y-p.Radius, 0, -14, true, 20))
-			projectiles = append(projectiles, NewProjectile(p.X-12, p.Y-p.Radius, 0, -13, true, 15))
-			projectiles = append(projectiles, NewProjectile(p.X+12, p.Y-p.Radius, 0, -13, true, 15))
-			projectiles = append(projectiles, NewProjectile(p.X-25, p.Y-p.Radius+5, -2.5, -11, true, 12))
-			projectiles = append(projectiles, NewProjectile(p.X+25, p.Y-p.Radius+5, 2.5, -11, true, 12))
+			projectiles = append(projectiles, NewProjectile(p.X, p.Y-p.Radius, 0, -840, true, 20))
+			projectiles = append(projectiles, NewProjectile(p.X-12, p.Y-p.Radius, 0, -780, true, 15))
+			projectiles = append(projectiles, NewProjectile(p.X+12, p.Y-p.Radius, 0, -780, true, 15))
+			projectiles = append(projectiles, NewProjectile(p.X-25, p.Y-p.Radius+5, -150, -660, true, 12))
+			projectiles = append(projectiles, NewProjectile(p.X+25, p.Y-p.Radius+5, 150, -660, true, 12))
 		}
 	}
 
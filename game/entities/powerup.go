@@ -3,7 +3,8 @@ package entities
 import (
 	"image/color"
 	"math"
-	"math/rand"
+
+	"stellar-siege/game/rng"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
@@ -21,7 +22,7 @@ const (
 
 type PowerUp struct {
 	X, Y      float64
-	VelY      float64
+	VelY      float64 // Pixels per second
 	Radius    float64
 	Type      PowerUpType
 	Active    bool
@@ -31,33 +32,34 @@ type PowerUp struct {
 func NewPowerUp(x, y float64) *PowerUp {
 	// 15% chance for mystery power-up
 	var puType PowerUpType
-	if rand.Float64() < 0.15 {
+	if rng.Float64() < 0.15 {
 		puType = PowerUpMystery
 	} else {
-		puType = PowerUpType(rand.Intn(4)) // Health, Shield, Weapon, Speed
+		puType = PowerUpType(rng.Intn(4)) // Health, Shield, Weapon, Speed
 	}
 
 	return &PowerUp{
 		X:         x,
 		Y:         y,
-		VelY:      1.5,
+		VelY:      90,
 		Radius:    15,
 		Type:      puType,
 		Active:    true,
-		AnimTimer: rand.Float64() * math.Pi * 2,
+		AnimTimer: rng.Float64() * math.Pi * 2,
 	}
 }
 
-func (p *PowerUp) Update() {
-	p.Y += p.VelY
-	p.AnimTimer += 0.15 // Increased animation speed
+// Update moves the powerup by deltaTime seconds
+func (p *PowerUp) Update(deltaTime float64) {
+	p.Y += p.VelY * deltaTime
+	p.AnimTimer += 9 * deltaTime // Increased animation speed
 }
 
 // Reset resets the powerup to default state (for object pooling)
 func (p *PowerUp) Reset() {
 	p.X = 0
 	p.Y = 0
-	p.VelY = 1.5
+	p.VelY = 90
 	p.Radius = 15
 	p.Type = PowerUpHealth
 	p.Active = false
@@ -21,10 +21,12 @@ const MaxTrailLength = 5
 
 type Projectile struct {
 	X, Y       float64
-	VelX, VelY float64
+	VelX, VelY float64 // Pixels per second
 	Radius     float64
 	Damage     int
-	Friendly   bool // true = player's projectile
+	Friendly   bool   // true = player's projectile
+	Source     string // Weapon or enemy that fired it, for run statistics
+	Owner      int    // Pilot that fired a friendly projectile, for co-op statistics
 	Active     bool
 	Trail      [MaxTrailLength]TrailPoint // Fixed-size array for ring buffer
 	TrailHead  int                        // Current write position in ring buffer
@@ -36,7 +38,7 @@ type Projectile struct {
 
 	// Special behavior flags
 	Homing         bool    // Following rocket behavior
-	HomingSpeed    float64 // Turn rate for homing (radians per frame)
+	HomingSpeed    float64 // Turn rate for homing (radians per second)
 	TargetEnemyIdx int     // Index of target enemy (-1 = no target)
 
 	Chaining   bool    // Chain lightning behavior
@@ -64,8 +66,8 @@ func NewProjectile(x, y, velX, velY float64, friendly bool, damage int) *Project
 	}
 
 	// Calculate speed and set lifetime based on velocity
-	speed := math.Sqrt(velX*velX+velY*velY) * 60.0 // Convert to pixels per second
-	lifetime := 2.5                                // Default lifetime
+	speed := math.Sqrt(velX*velX + velY*velY) // Pixels per second
+	lifetime := 2.5                           // Default lifetime
 
 	// Fast projectiles get shorter lifetime for better cleanup
 	if speed > 350 {
@@ -94,8 +96,8 @@ func NewProjectile(x, y, velX, velY float64, friendly bool, damage int) *Project
 // NewProjectileWithColor creates a projectile with custom colors (for weapon variety)
 func NewProjectileWithColor(x, y, velX, velY float64, friendly bool, damage int, mainColor, glowColor color.RGBA) *Projectile {
 	// Calculate speed and set lifetime based on velocity
-	speed := math.Sqrt(velX*velX+velY*velY) * 60.0 // Convert to pixels per second
-	lifetime := 2.5                                // Default lifetime
+	speed := math.Sqrt(velX*velX + velY*velY) // Pixels per second
+	lifetime := 2.5                           // Default lifetime
 
 	// Fast projectiles get shorter lifetime for better cleanup
 	if speed > 350 {
@@ -121,9 +123,9 @@ func NewProjectileWithColor(x, y, velX, velY float64, friendly bool, damage int,
 	}
 }
 
-func (p *Projectile) Update() {
+func (p *Projectile) Update(deltaTime float64) {
 	// Update age
-	p.Age += 1.0 / 60.0 // Assuming 60 FPS
+	p.Age += deltaTime
 
 	// Check if projectile has expired
 	if p.Lifetime > 0 && p.Age >= p.Lifetime {
@@ -138,8 +140,8 @@ func (p *Projectile) Update() {
 		p.TrailLen++
 	}
 
-	p.X += p.VelX
-	p.Y += p.VelY
+	p.X += p.VelX * deltaTime
+	p.Y += p.VelY * deltaTime
 }
 
 // IsOffScreen checks if projectile is clearly beyond screen bounds for early culling
@@ -282,7 +284,7 @@ const MaxHomingRangeSq = 500.0 * 500.0
 
 // UpdateHoming updates projectile trajectory to home in on enemies
 // enemies parameter should be passed from game loop
-func (p *Projectile) UpdateHoming(enemies []*Enemy) {
+func (p *Projectile) UpdateHoming(deltaTime float64, enemies []*Enemy) {
 	if !p.Homing {
 		return
 	}
@@ -341,7 +343,7 @@ func (p *Projectile) UpdateHoming(enemies []*Enemy) {
 	}
 
 	// Apply turn rate limit
-	turnAmount := math.Min(math.Abs(angleDiff), p.HomingSpeed)
+	turnAmount := math.Min(math.Abs(angleDiff), p.HomingSpeed*deltaTime)
 	if angleDiff < 0 {
 		turnAmount = -turnAmount
 	}
@@ -0,0 +1,17 @@
+package entities
+
+import "math"
+
+// TicksPerSecond is the rate the simulation is stepped at
+const TicksPerSecond = 60
+
+// FixedTimestep is the length of one simulation tick in seconds. Entity
+// updates take the timestep explicitly; it only differs from FixedTimestep
+// while the simulation is time-scaled.
+const FixedTimestep = 1.0 / TicksPerSecond
+
+// Decay returns the factor that applies a per-tick damping factor over
+// deltaTime seconds, so damping is the same at every time scale
+func Decay(perTick, deltaTime float64) float64 {
+	return math.Pow(perTick, deltaTime*TicksPerSecond)
+}
@@ -42,7 +42,7 @@ type Weapon struct {
 	Damage          float64
 	FireRate        float64 // Shots per second
 	FireTimer       float64 // Current cooldown
-	ProjectileSpeed float64
+	ProjectileSpeed float64 // Pixels per second
 	Spread          float64 // Angle spread in radians
 	ProjectileCount int     // Number of projectiles per shot
 	Unlocked        bool
@@ -448,10 +448,10 @@ func minInt(a, b int) int {
 }
 
 // Update updates weapon cooldowns
-func (wm *WeaponManager) Update() {
+func (wm *WeaponManager) Update(deltaTime float64) {
 	for _, weapon := range wm.Weapons {
 		if weapon.FireTimer > 0 {
-			weapon.FireTimer -= 1.0 / 60.0 // 60 FPS
+			weapon.FireTimer -= deltaTime
 		}
 	}
 }
@@ -1,8 +1,11 @@
 package game
 
 import (
+	"crypto/ed25519"
+	"encoding/hex"
 	"fmt"
 	"image/color"
+	"log"
 	"math"
 	"math/rand"
 	"sync"
@@ -11,6 +14,10 @@ import (
 	"stellar-siege/game/core"
 	"stellar-siege/game/di"
 	"stellar-siege/game/entities"
+	"stellar-siege/game/interfaces"
+	"stellar-siege/game/netplay"
+	"stellar-siege/game/rng"
+	"stellar-siege/game/spectate"
 	"stellar-siege/game/states"
 	"stellar-siege/game/systems"
 
@@ -34,6 +41,15 @@ const (
 	MaxImpactEffects = 20  // Maximum impact effects
 )
 
+// Time scale effects applied through the simulation clock
+const (
+	BulletTimeScale    = 0.5  // Time scale while Bullet Time is active
+	HitStopPlayerHit   = 0.05 // Seconds of hit-stop when the player takes damage
+	HitStopPlayerScale = 0.25 // Time scale during that hit-stop
+	HitStopBossDefeat  = 0.4  // Seconds of hit-stop when a boss is destroyed
+	HitStopBossScale   = 0.1  // Time scale during that hit-stop
+)
+
 type GameState int
 
 const (
@@ -88,6 +104,13 @@ type Game struct {
 	sprites     *systems.SpriteManager
 	perfMon     *systems.PerformanceMonitor
 
+	// Pilots of the run, one per player; pilots[0] flies g.player. players
+	// is how many the next run starts with, and nextTarget the pilot the
+	// next enemy to spawn goes after.
+	pilots     []*pilot
+	players    int
+	nextTarget int
+
 	// Spatial grid for collision optimization
 	spatialGrid *core.SpatialGrid
 
@@ -148,16 +171,106 @@ type Game struct {
 	miniBossSpawnTimer float64 // Timer for spawning mini-bosses during boss fight
 	miniBossesSpawned  int     // Number of mini-bosses spawned in current boss wave
 
-	// Online leaderboard (GitHub Gist)
-	onlineLeaderboard *systems.GistLeaderboard
+	// Online leaderboard (GitHub Gist or a self-hosted server)
+	onlineLeaderboard interfaces.LeaderboardManager
 	gistConfig        *systems.GistConfig
 	submitScorePrompt bool                  // Whether to prompt user to submit score
 	scoreSubmitted    bool                  // Whether score was submitted this session
 	onlineScores      []systems.OnlineScore // Cached online scores
 	onlineScoresMu    sync.RWMutex          // Protects onlineScores from concurrent access
 
+	// Scores wait in the outbox until the online leaderboard accepts them;
+	// runSubmissionID is the last run's entry, for the game over screen
+	outbox          *systems.ScoreOutbox
+	outboxWake      chan struct{}
+	outboxScreen    outboxScreen
+	runSubmissionID string
+
+	globalBoard    globalBoardScreen // Browser of the whole online leaderboard
+	netLobby       netLobbyScreen    // Hosting and joining online co-op runs
+	localBoard     localBoardScreen  // Local leaderboard tables and run details
+	scorePublicKey string            // This install's score signing key, to find its entries
+
 	// Auto-updater
 	updateManager *systems.UpdateManager
+
+	// Deterministic simulation: every run is reproducible from its seed and
+	// the input frames recorded in its replay
+	seed       int64
+	rng        *rng.Rand
+	tick       int
+	clock      *core.Clock // Time scale and hit-stop of the simulation
+	deltaTime  float64     // Simulated seconds covered by the current tick
+	replay     *systems.Replay
+	replayFile string // Where the last run's replay was saved, for its leaderboard entry
+	headless   bool   // Simulation only: no window, audio or rendering
+
+	// Gameplay statistics for the current run (balance reports)
+	runStats   *systems.RunStats
+	deathCause string // Source of the damage that destroyed the player
+
+	// Attract mode: the autopilot plays while the title screen sits idle
+	autopilot     *core.Autopilot
+	demoMode      bool
+	demoTimer     int // Ticks spent on the demo's game over screen
+	menuIdleTicks int // Ticks the title screen has gone without input
+
+	// Online co-op session of the current run; nil in local runs
+	net *netplay.Session
+
+	// Live view of every run for other screens; nil unless started
+	spectators *spectate.Server
+
+	// Developer console; using it makes the run ineligible for leaderboards
+	console     *systems.Console
+	consoleUsed bool
+	godMode     bool
+
+	// F-key debug visualisation of colliders, grid cells, AI and pools
+	debug *DebugOverlay
+
+	// Live performance HUD (F7) drawn from perfMon
+	perfHUD *systems.PerformanceHUD
+
+	// Environmental hazards placed in the play area
+	hazards []*entities.Hazard
+
+	// Persistent progression (scrap and upgrades) and achievements of the
+	// active pilot; nil in headless games
+	progression  *systems.ProgressionManager
+	achievements *systems.AchievementManager
+
+	// Where all persistent data is kept; in memory for headless games
+	storage systems.Storage
+
+	// Pilot profiles; profileStore holds the active pilot's files, keys
+	// are its control bindings and country the flag shown with its scores.
+	// The profile screen opens from the title screen.
+	profiles      *systems.ProfileManager
+	profileStore  systems.Storage
+	keys          systems.KeyBindings
+	country       string
+	profileScreen profileScreen
+
+	// Save sync with a private gist; nil unless configured. Results come
+	// back on syncDone and are applied outside of runs.
+	saveSync         *systems.GistSync
+	syncDevice       string
+	syncDone         chan syncResult
+	syncing          bool
+	pendingSync      *syncResult
+	syncConflicts    []systems.SyncConflict // Resolved by the last sync
+	settingsModified time.Time              // When the player last changed a setting
+
+	// Crash capture: the latest inputs for crash reports, and the crash
+	// screen shown after a panic was recovered
+	recentInputs inputRing
+	crash        *crashScreen
+	crashDir     string // Where crash reports are written; empty for the data directory
+
+	// Copy of the game state published after each update for the metrics server
+	stateSnapshot GameStateSnapshot
+	stateMu       sync.RWMutex
 }
 
 func NewGame() *Game {
@@ -197,9 +310,25 @@ func NewGame() *Game {
 		return systems.NewStarField(ScreenWidth, ScreenHeight), nil
 	})
 
+	// Storage for all persistent data
+	container.RegisterSingleton(di.ServiceStorage, func(c *di.Container) (interface{}, error) {
+		return systems.NewFileStorage(systems.DefaultDataDir()), nil
+	})
+
 	// Leaderboard
 	container.RegisterSingleton(di.ServiceLeaderboardManager, func(c *di.Container) (interface{}, error) {
-		return systems.NewLeaderboard("data/leaderboard.json"), nil
+		store := c.MustResolve(di.ServiceStorage).(systems.Storage)
+		return systems.NewLeaderboard(store, "leaderboard.json"), nil
+	})
+
+	// Pilot profiles
+	container.RegisterSingleton(di.ServiceProfileManager, func(c *di.Container) (interface{}, error) {
+		store := c.MustResolve(di.ServiceStorage).(systems.Storage)
+		profiles, err := systems.NewProfileManager(store, ProfileFileNames)
+		if err != nil {
+			log.Printf("Failed to save pilot profiles: %v", err)
+		}
+		return profiles, nil
 	})
 
 	// Menu
@@ -214,17 +343,77 @@ func NewGame() *Game {
 	})
 
 	// Resolve initial services
+	g.storage = container.MustResolve(di.ServiceStorage).(systems.Storage)
 	g.sound = container.MustResolve(di.ServiceSoundManager).(*systems.SoundManager)
 	g.sprites = container.MustResolve(di.ServiceSpriteManager).(*systems.SpriteManager)
 	g.stars = container.MustResolve(di.ServiceStarfield).(*systems.StarField)
 	g.leaderboard = container.MustResolve(di.ServiceLeaderboardManager).(*systems.Leaderboard)
 	g.menu = container.MustResolve(di.ServiceMenu).(*systems.Menu)
 	g.perfMon = container.MustResolve("PerformanceMonitor").(*systems.PerformanceMonitor)
+	g.profiles = container.MustResolve(di.ServiceProfileManager).(*systems.ProfileManager)
+	g.outbox = systems.NewScoreOutbox(g.storage)
+	g.console = g.newGameConsole()
+	g.debug = NewDebugOverlay()
+	g.perfHUD = systems.NewPerformanceHUD(g.perfMon)
 
 	// Initialize spatial grid for collision optimization (100x100 pixel cells)
 	g.spatialGrid = core.NewSpatialGrid(float64(ScreenWidth), float64(ScreenHeight), 100.0)
 
 	// Initialize object pools for reducing allocations
+	g.initializePools()
+
+	// Load the active pilot's progression and settings, and offer to
+	// continue a run they suspended in an earlier session
+	g.loadProfile()
+
+	// Load the online leaderboard configuration from environment variables
+	gistConfig, _ := systems.LoadGistConfig("")
+	g.gistConfig = gistConfig
+	if gistConfig.Enabled {
+		// Scores are signed with this install's key; unsigned without one
+		key, err := systems.LoadScoreKey(g.storage)
+		if err != nil {
+			log.Printf("Failed to load score signing key: %v", err)
+		} else {
+			g.scorePublicKey = hex.EncodeToString(key.Public().(ed25519.PublicKey))
+		}
+		g.onlineLeaderboard = NewOnlineLeaderboard(gistConfig, key)
+	}
+	if g.onlineLeaderboard != nil {
+		// Submit scores queued while offline
+		g.startOutboxWorker()
+
+		// Pre-fetch online scores in background
+		go g.refreshOnlineScores()
+	}
+
+	// Sync the pilot's save with a private gist when one is configured
+	g.initSaveSync(gistConfig)
+	g.startSaveSync()
+
+	// Initialize auto-updater
+	g.updateManager = systems.NewUpdateManager(Version, GitHubOwner, GitHubRepo)
+
+	// Connect update manager to menu
+	g.menu.SetUpdateManager(g.updateManager)
+
+	// Start background update check after a short delay (non-blocking).
+	// It contacts GitHub, so it only runs when the player turned it on.
+	if gistConfig.CheckUpdates {
+		go func() {
+			time.Sleep(2 * time.Second) // Wait 2s after startup
+			g.updateManager.CheckForUpdatesAsync()
+		}()
+	}
+
+	// Initialize state machine
+	g.initializeStateMachine()
+
+	return g
+}
+
+// initializePools creates the object pools used to avoid per-frame allocations
+func (g *Game) initializePools() {
 	g.projectilePool = core.NewEntityPool[*entities.Projectile](
 		func() *entities.Projectile {
 			return &entities.Projectile{}
@@ -267,56 +456,37 @@ func NewGame() *Game {
 		},
 		15, // Typical max powerups
 	)
-
-	// Load Gist configuration for online leaderboard from environment variables
-	gistConfig, _ := systems.LoadGistConfig("")
-	g.gistConfig = gistConfig
-	if gistConfig.Enabled && gistConfig.GistID != "" && gistConfig.GitHubToken != "" {
-		g.onlineLeaderboard = systems.NewGistLeaderboard(gistConfig.GistID, gistConfig.GitHubToken)
-		// Pre-fetch online scores in background
-		go func() {
-			if scores, err := g.onlineLeaderboard.GetTopScores(100); err == nil {
-				g.onlineScoresMu.Lock()
-				g.onlineScores = scores
-				g.onlineScoresMu.Unlock()
-			}
-		}()
-	}
-
-	// Initialize auto-updater
-	g.updateManager = systems.NewUpdateManager(Version, GitHubOwner, GitHubRepo)
-
-	// Connect update manager to menu
-	g.menu.SetUpdateManager(g.updateManager)
-
-	// Start background update check after a short delay (non-blocking)
-	go func() {
-		time.Sleep(2 * time.Second) // Wait 2s after startup
-		g.updateManager.CheckForUpdatesAsync()
-	}()
-
-	// Initialize state machine
-	g.initializeStateMachine()
-
-	return g
 }
 
 func (g *Game) startGame() {
+	g.demoMode = false
+	g.autopilot = nil
+	g.leaveNetRun()
+	g.discardSuspendedRun() // A new run abandons the suspended one
+	g.startGameWithSeed(time.Now().UnixNano())
+}
+
+// startGameWithSeed starts a new run whose randomness is derived from seed
+func (g *Game) startGameWithSeed(seed int64) {
+	// Seed the simulation and start recording the replay
+	g.seed = seed
+	g.rng = rng.New(seed)
+	rng.Use(g.rng)
+	g.tick = 0
+	g.clock = core.NewClock()
+	g.replay = systems.NewReplay(Version, seed, int(g.selectedDifficulty))
+	g.runStats = systems.NewRunStats()
+	g.runStats.StartWave(0, 0)
+	g.deathCause = ""
+	g.consoleUsed = false
+	g.godMode = false
+	g.recentInputs = inputRing{}
+
 	// Get difficulty config
 	g.difficultyConfig = GetDifficultyConfig(g.selectedDifficulty)
 
 	g.transitionToState(StatePlaying)
-	g.player = entities.NewPlayer(ScreenWidth/2, ScreenHeight-100)
-
-	// Apply difficulty settings to player
-	g.player.Health = g.difficultyConfig.PlayerHealth
-	g.player.MaxHealth = g.difficultyConfig.PlayerHealth
-	g.player.Shield = g.difficultyConfig.PlayerMaxShield
-	g.player.MaxShield = g.difficultyConfig.PlayerMaxShield
-	g.player.ShieldRegenRate = g.difficultyConfig.ShieldRegenRate
-	g.player.InvincibilityTime = g.difficultyConfig.InvincibilityTime
-	g.player.ShieldRegenDelay = g.difficultyConfig.ShieldRegenDelay
-	g.player.LastDamageTime = -999 // Start with regen available
+	g.spawnPilots(max(g.players, 1))
 
 	// Clear slices efficiently (keep backing arrays, just reset length to 0)
 	g.enemies = g.enemies[:0]
@@ -326,6 +496,7 @@ func (g *Game) startGame() {
 	g.asteroids = g.asteroids[:0]
 	g.floatingTexts = g.floatingTexts[:0]
 	g.impactEffects = g.impactEffects[:0]
+	g.hazards = nil
 	g.boss = nil
 	g.score = 0
 	g.wave = 0
@@ -345,53 +516,45 @@ func (g *Game) startGame() {
 	g.playerName = ""
 	g.submitScorePrompt = false
 	g.scoreSubmitted = false
+	g.runSubmissionID = ""
 }
 
 func (g *Game) Update() error {
+	// After a recovered panic only the crash screen runs
+	if g.crash != nil {
+		return g.updateCrashScreen()
+	}
+	defer g.recoverCrash("update")
+
 	// Track frame time
 	frameStart := time.Now()
 	defer func() {
 		g.perfMon.RecordFrame(time.Since(frameStart))
 
-		// Update entity counts for monitoring
-		g.perfMon.UpdateEntityCount("player", func() int {
-			if g.player != nil && g.player.Active {
-				return 1
-			}
-			return 0
-		}())
-		g.perfMon.UpdateEntityCount("enemies", len(g.enemies))
-		g.perfMon.UpdateEntityCount("projectiles", len(g.projectiles))
-		g.perfMon.UpdateEntityCount("explosions", len(g.explosions))
-		g.perfMon.UpdateEntityCount("powerups", len(g.powerups))
-		g.perfMon.UpdateEntityCount("asteroids", len(g.asteroids))
-
-		// Update pool statistics
-		projStats := g.projectilePool.GetStats()
-		g.perfMon.UpdatePoolStats("projectiles", systems.PoolStatsSnapshot{
-			TotalCreated:  projStats.TotalCreated,
-			TotalReused:   projStats.TotalReused,
-			CurrentActive: projStats.CurrentActive,
-			MaxActive:     projStats.MaxActive,
-			PoolSize:      projStats.PoolSize,
-			ReuseRate:     projStats.ReuseRate,
-		})
-		expStats := g.explosionPool.GetStats()
-		g.perfMon.UpdatePoolStats("explosions", systems.PoolStatsSnapshot{
-			TotalCreated:  expStats.TotalCreated,
-			TotalReused:   expStats.TotalReused,
-			CurrentActive: expStats.CurrentActive,
-			MaxActive:     expStats.MaxActive,
-			PoolSize:      expStats.PoolSize,
-			ReuseRate:     expStats.ReuseRate,
-		})
-
-		// Update memory stats periodically
-		g.perfMon.UpdateMemoryStats()
+		g.recordFrameStats()
 	}()
 
+	// Closing the window mid-run suspends it so it can be continued later
+	if ebiten.IsWindowBeingClosed() {
+		if err := g.suspendRun(); err != nil {
+			log.Printf("Failed to suspend run: %v", err)
+		}
+		g.leaveNetRun()
+		g.stopPerfTrace()
+		return ebiten.Termination
+	}
+
 	// Update starfield always (visual effect)
-	g.stars.Update()
+	g.stars.Update(entities.FixedTimestep)
+
+	// Debug overlay layers and the performance HUD can be toggled in any state
+	g.debug.Update()
+	if inpututil.IsKeyJustPressed(ebiten.KeyF7) {
+		g.perfHUD.Toggle()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF8) {
+		g.togglePerfTrace()
+	}
 
 	// Poll update manager status (non-blocking)
 	if g.updateManager != nil {
@@ -404,6 +567,9 @@ func (g *Game) Update() error {
 		}
 	}
 
+	// Apply a finished save sync
+	g.pollSaveSync()
+
 	// Use state machine if initialized, otherwise fall back to manual state handling
 	if g.stateMachine != nil {
 		return g.stateMachine.Update()
@@ -425,7 +591,32 @@ func (g *Game) Update() error {
 }
 
 func (g *Game) updateMenu() {
+	// The profile, outbox and leaderboard screens take over the title
+	// screen while open
+	if g.profileScreen.open {
+		g.updateProfileScreen()
+		return
+	}
+	if g.outboxScreen.open {
+		g.updateOutboxScreen()
+		return
+	}
+	if g.globalBoard.open {
+		g.updateGlobalBoard()
+		return
+	}
+	if g.localBoard.open {
+		g.updateLocalBoard()
+		return
+	}
+	if g.netLobby.open {
+		g.updateNetLobby()
+		return
+	}
+
 	// Update menu input handling
+	g.menu.QueuedScores = g.outbox.Pending()
+	g.menu.OnlineLeaderboard = g.onlineLeaderboard != nil
 	g.menu.Update()
 
 	// If showing difficulty select, allow selection
@@ -433,7 +624,12 @@ func (g *Game) updateMenu() {
 		if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
 			// Set the selected difficulty and start game
 			g.selectedDifficulty = DifficultyMode(g.menu.SelectedDifficulty)
+			g.players = 1
+			if g.menu.Coop {
+				g.players = 2
+			}
 			g.sound.PlaySound(systems.SoundUIClick)
+			g.saveSettings()
 			g.startGame()
 		}
 	} else {
@@ -443,10 +639,17 @@ func (g *Game) updateMenu() {
 			g.menu.ShowDifficultySelectMenu()
 			g.sound.PlaySound(systems.SoundUIClick)
 		}
-		if inpututil.IsKeyJustPressed(ebiten.KeyL) {
-			// Toggle leaderboard view in menu
-			g.menu.ToggleLeaderboard()
+		if inpututil.IsKeyJustPressed(ebiten.KeyL) && !g.menu.InfoMenu.IsActive() {
+			// Browse the local leaderboard and the runs behind it
+			g.sound.PlaySound(systems.SoundUIClick)
+			g.openLocalBoard()
+			return
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyG) && g.onlineLeaderboard != nil && !g.menu.InfoMenu.IsActive() {
+			// Browse the online leaderboard
 			g.sound.PlaySound(systems.SoundUIClick)
+			g.openGlobalBoard()
+			return
 		}
 		if inpututil.IsKeyJustPressed(ebiten.KeyI) {
 			// Show information menu
@@ -458,18 +661,67 @@ func (g *Game) updateMenu() {
 			g.menu.SoundEnabled = !g.menu.SoundEnabled
 			g.sound.SetEnabled(g.menu.SoundEnabled)
 			g.sound.PlaySound(systems.SoundUIClick)
+			g.saveSettings()
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyC) && g.menu.ContinueRun != "" {
+			// Continue the suspended run
+			g.sound.PlaySound(systems.SoundUIClick)
+			if err := g.resumeSuspendedRun(); err != nil {
+				log.Printf("Failed to continue suspended run: %v", err)
+				g.discardSuspendedRun()
+			}
+			return
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyP) && !g.menu.InfoMenu.IsActive() {
+			// Manage pilot profiles
+			g.sound.PlaySound(systems.SoundUIClick)
+			g.openProfileScreen()
+			return
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyO) && g.menu.QueuedScores > 0 && !g.menu.InfoMenu.IsActive() {
+			// View and retry scores waiting to be submitted online
+			g.sound.PlaySound(systems.SoundUIClick)
+			g.openOutboxScreen()
+			return
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyN) && !g.menu.InfoMenu.IsActive() {
+			// Host or join an online co-op run
+			g.sound.PlaySound(systems.SoundUIClick)
+			g.openNetLobby("")
+			return
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyD) && !g.menu.InfoMenu.IsActive() {
+			// Watch the autopilot play
+			g.sound.PlaySound(systems.SoundUIClick)
+			g.startDemo()
+			return
 		}
 		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyQ) {
 			// Could exit, but for now just ignore
 		}
 	}
+
+	g.updateMenuIdle()
 }
 
 func (g *Game) updatePlaying() {
-	g.gameTime += 1.0 / 60.0
+	if g.demoMode {
+		g.updateDemoPlaying()
+		return
+	}
+	if g.net != nil {
+		g.updateNetPlaying()
+		return
+	}
 
-	// Update camera system
-	g.updateCamera()
+	// Developer console; the simulation waits while it is open
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackquote) {
+		g.console.Toggle()
+	}
+	if g.console.Open {
+		g.console.Update()
+		return
+	}
 
 	// Pause
 	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyP) {
@@ -477,46 +729,101 @@ func (g *Game) updatePlaying() {
 		return
 	}
 
+	if g.coop() {
+		g.stepSimulation(entities.CombineInputs(captureCoopInputFrame(g.keys), capturePilotTwoFrame()))
+		return
+	}
+	g.stepSimulation(captureInputFrame(g.keys))
+}
+
+// stepSimulation advances the run by one tick using the given player input.
+// Everything that can change the outcome of a run must happen in here so
+// that replays re-simulate identically.
+func (g *Game) stepSimulation(input entities.InputFrame) {
+	rng.Use(g.rng)
+	g.tick++
+	if g.replay != nil {
+		g.replay.Record(input)
+	}
+	g.recentInputs.Push(input)
+
+	g.deltaTime = g.clock.Tick(g.timeModifier())
+	g.gameTime += g.deltaTime
+
+	// Update camera system
+	g.timeSubsystem("camera", g.updateCamera)
+
 	// Update game systems in order
-	g.updatePlayerState()
-	g.updateBossWave()
-	g.updateRegularWaveSpawning()
-	g.updateEnemies()
-	g.updateProjectiles()
-	g.updateExplosions()
-	g.updatePowerups()
-	g.updateAsteroids()
-	g.checkCollisions()
-	g.updateComboSystem()
-	g.updateVisualEffects()
+	g.timeSubsystem("player", func() { g.updatePlayerState(input) })
+	g.timeSubsystem("boss", g.updateBossWave)
+	g.timeSubsystem("spawning", g.updateRegularWaveSpawning)
+	g.timeSubsystem("enemies", g.updateEnemies)
+	g.timeSubsystem("projectiles", g.updateProjectiles)
+	g.timeSubsystem("explosions", g.updateExplosions)
+	g.timeSubsystem("powerups", g.updatePowerups)
+	g.timeSubsystem("asteroids", g.updateAsteroids)
+	g.timeSubsystem("hazards", g.updateHazards)
+	g.timeSubsystem("collisions", g.checkCollisions)
+	g.updateRevives()
+	g.timeSubsystem("combo", g.updateComboSystem)
+	g.timeSubsystem("effects", g.updateVisualEffects)
 	g.updateLowHealthWarning()
-	g.cleanupEntities()
+	g.timeSubsystem("cleanup", g.cleanupEntities)
 	g.checkGameOver()
+	g.broadcastSpectatorFrame()
+}
+
+// timeSubsystem runs one step of the simulation and reports how long it took
+func (g *Game) timeSubsystem(name string, step func()) {
+	start := time.Now()
+	step()
+	g.perfMon.RecordSubsystemTime(name, time.Since(start))
 }
 
-// updatePlayerState handles player update, shield recharge, and shooting
-func (g *Game) updatePlayerState() {
-	if g.player != nil && g.player.Active {
+// timeModifier returns the time scale of game-state driven effects such as
+// Bullet Time, applied on top of the clock's global scale
+func (g *Game) timeModifier() float64 {
+	for _, pl := range g.pilots {
+		if pl.ship.AbilityMgr.IsAbilityActive(entities.AbilityTypeSlowTime) {
+			return BulletTimeScale
+		}
+	}
+	return 1
+}
+
+// updatePlayerState handles each pilot's ship update, shield recharge, and
+// shooting, with that pilot's controls from the input frame
+func (g *Game) updatePlayerState(input entities.InputFrame) {
+	for i, pl := range g.pilots {
+		ship := pl.ship
+		if !ship.Active {
+			continue
+		}
+		controls := input.Pilot(i)
+
 		// Store previous shield value
-		prevShield := g.player.Shield
+		prevShield := ship.Shield
 
-		g.player.Update(ScreenWidth, ScreenHeight, g.gameTime)
+		ship.Update(g.deltaTime, ScreenWidth, ScreenHeight, g.gameTime, controls)
 
 		// Check if shield reached max from a lower value (fully recharged)
-		if g.player.Shield >= g.player.MaxShield && prevShield < g.player.MaxShield && prevShield > 0 {
+		if ship.Shield >= ship.MaxShield && prevShield < ship.MaxShield && prevShield > 0 {
 			g.sound.PlaySound(systems.SoundShieldRecharge)
 		}
 
 		// Player shooting (respect projectile limit)
-		if ebiten.IsKeyPressed(ebiten.KeySpace) || ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		if controls.Has(entities.InputShoot) {
 			if len(g.projectiles) < MaxProjectiles {
-				newProjectiles := g.player.Shoot()
+				newProjectiles := ship.Shoot()
 				if len(newProjectiles) > 0 {
 					// Only add projectiles up to the limit
 					spaceLeft := MaxProjectiles - len(g.projectiles)
 					if len(newProjectiles) > spaceLeft {
 						newProjectiles = newProjectiles[:spaceLeft]
 					}
+					for _, proj := range newProjectiles {
+						proj.Owner = i
+					}
 					g.projectiles = append(g.projectiles, newProjectiles...)
 					g.sound.PlaySound(systems.SoundPlayerShoot)
 				}
@@ -535,8 +842,12 @@ func (g *Game) updateBossWave() {
 		// Track previous phase to detect transitions
 		prevPhase := g.boss.Phase
 
-		bossProjectiles := g.boss.Update(g.player.X, g.player.Y, ScreenWidth, ScreenHeight)
+		target := g.targetShip(-1, g.boss.X, g.boss.Y)
+		bossProjectiles := g.boss.Update(g.deltaTime, target.X, target.Y, ScreenWidth, ScreenHeight)
 		// Respect projectile limit for boss projectiles
+		for _, proj := range bossProjectiles {
+			proj.Source = systems.SourceBoss
+		}
 		if len(bossProjectiles) > 0 {
 			spaceLeft := MaxProjectiles - len(g.projectiles)
 			if spaceLeft > 0 {
@@ -577,8 +888,10 @@ func (g *Game) updateBossWave() {
 		g.spawnExplosion(g.boss.X+40, g.boss.Y+20, 60)
 		g.addScore(int64(g.boss.Points))
 		g.screenShake = 30
+		g.clock.HitStop(HitStopBossDefeat, HitStopBossScale)
 		g.sound.PlaySound(systems.SoundExplosionBoss) // Boss explosion
 		g.sound.PlaySound(systems.SoundBossDefeat)    // Victory fanfare
+		g.runStats.EndBossFight(g.gameTime, true)
 		g.boss = nil
 		g.bossWave = false
 		g.miniBossSpawnTimer = 0
@@ -599,27 +912,34 @@ func (g *Game) updateRegularWaveSpawning() {
 	}
 
 	// Regular wave spawning (respect enemy limit)
-	newEnemies := g.spawner.Update(g.gameTime, g.wave)
+	newEnemies := g.spawner.Update(g.deltaTime, g.gameTime, g.wave)
 	if len(newEnemies) > 0 && len(g.enemies) < MaxEnemies {
 		spaceLeft := MaxEnemies - len(g.enemies)
 		if len(newEnemies) > spaceLeft {
 			newEnemies = newEnemies[:spaceLeft]
 		}
+		g.assignTargets(newEnemies)
 		g.enemies = append(g.enemies, newEnemies...)
 	}
 	if g.spawner.WaveCompleted && len(g.enemies) == 0 {
-		g.wave++
-		g.score += int64(g.wave * 1000) // Wave bonus
-
-		// Every 5 waves, spawn a boss
-		if g.wave%5 == 0 {
-			g.bossWave = true
-			g.boss = entities.NewBoss(ScreenWidth, g.wave/5)
-			g.sound.PlaySound(systems.SoundBossAppear)
-		} else {
-			g.spawner.StartWave(g.wave)
-			g.sound.PlaySound(systems.SoundWaveStart)
-		}
+		g.score += int64((g.wave + 1) * 1000) // Wave bonus
+		g.beginWave(g.wave + 1)
+	}
+}
+
+// beginWave starts the given wave; every 5th wave is a boss fight
+func (g *Game) beginWave(wave int) {
+	g.wave = wave
+	g.runStats.StartWave(g.wave, g.gameTime)
+
+	if g.wave%5 == 0 {
+		g.bossWave = true
+		g.boss = entities.NewBoss(ScreenWidth, g.wave/5)
+		g.runStats.StartBossFight(g.wave, g.wave/5, g.gameTime)
+		g.sound.PlaySound(systems.SoundBossAppear)
+	} else {
+		g.spawner.StartWave(g.wave)
+		g.sound.PlaySound(systems.SoundWaveStart)
 	}
 }
 
@@ -627,12 +947,14 @@ func (g *Game) updateRegularWaveSpawning() {
 func (g *Game) updateEnemies() {
 	for _, e := range g.enemies {
 		if e.Active {
-			e.Update(g.player.X, g.player.Y, ScreenWidth, ScreenHeight)
+			target := g.targetShip(e.Target, e.X, e.Y)
+			e.Update(g.deltaTime, target.X, target.Y, ScreenWidth, ScreenHeight)
 			// Enemy shooting (respect projectile limit)
 			if len(g.projectiles) < MaxProjectiles {
 				if proj := e.TryShoot(); proj != nil {
 					// Scale projectile damage by difficulty multiplier
 					proj.Damage = int(float64(proj.Damage) * g.difficultyConfig.DamageMultiplier)
+					proj.Source = e.Type.String()
 					g.projectiles = append(g.projectiles, proj)
 					g.sound.PlaySound(systems.SoundEnemyShoot)
 				}
@@ -645,7 +967,7 @@ func (g *Game) updateEnemies() {
 func (g *Game) updateProjectiles() {
 	for _, p := range g.projectiles {
 		if p.Active {
-			p.Update()
+			p.Update(g.deltaTime)
 			// Off-screen check
 			if p.Y < -20 || p.Y > ScreenHeight+20 || p.X < -20 || p.X > ScreenWidth+20 {
 				p.Active = false
@@ -658,7 +980,7 @@ func (g *Game) updateProjectiles() {
 func (g *Game) updateExplosions() {
 	for _, ex := range g.explosions {
 		if ex.Active {
-			ex.Update()
+			ex.Update(g.deltaTime)
 		}
 	}
 }
@@ -667,7 +989,7 @@ func (g *Game) updateExplosions() {
 func (g *Game) updatePowerups() {
 	for _, pu := range g.powerups {
 		if pu.Active {
-			pu.Update()
+			pu.Update(g.deltaTime)
 			if pu.Y > ScreenHeight+20 {
 				pu.Active = false
 			}
@@ -678,12 +1000,12 @@ func (g *Game) updatePowerups() {
 // updateAsteroids handles asteroid spawning and updates
 func (g *Game) updateAsteroids() {
 	// Spawn asteroids (respect asteroid limit)
-	g.asteroidSpawn += 1.0 / 60.0
+	g.asteroidSpawn += g.deltaTime
 	if g.asteroidSpawn > 2.0 && len(g.asteroids) < MaxAsteroids {
 		g.asteroidSpawn = 0
 		// Spawn 1-2 asteroids per spawn (limited by available space)
 		spawnCount := 1
-		if rand.Float64() < 0.3 {
+		if rng.Float64() < 0.3 {
 			spawnCount = 2
 		}
 		spaceLeft := MaxAsteroids - len(g.asteroids)
@@ -691,8 +1013,8 @@ func (g *Game) updateAsteroids() {
 			spawnCount = spaceLeft
 		}
 		for i := 0; i < spawnCount; i++ {
-			x := rand.Float64() * ScreenWidth
-			size := entities.AsteroidSize(rand.Intn(3))
+			x := rng.Float64() * ScreenWidth
+			size := entities.AsteroidSize(rng.Intn(3))
 			// Get asteroid from pool instead of allocating new one
 			asteroid := g.asteroidPool.Get()
 			*asteroid = *entities.NewAsteroid(x, -40, size)
@@ -703,7 +1025,7 @@ func (g *Game) updateAsteroids() {
 	// Update asteroids
 	for _, a := range g.asteroids {
 		if a.Active {
-			a.Update()
+			a.Update(g.deltaTime)
 		}
 	}
 }
@@ -711,7 +1033,7 @@ func (g *Game) updateAsteroids() {
 // updateComboSystem handles combo timer and multiplier decay
 func (g *Game) updateComboSystem() {
 	if g.comboTimer > 0 {
-		g.comboTimer -= 1.0 / 60.0
+		g.comboTimer -= g.deltaTime
 		if g.comboTimer <= 0 {
 			g.multiplier = 1.0
 		}
@@ -722,7 +1044,7 @@ func (g *Game) updateComboSystem() {
 func (g *Game) updateVisualEffects() {
 	// Update screen shake
 	if g.screenShake > 0 {
-		g.screenShake -= 0.5
+		g.screenShake -= 30 * g.deltaTime
 		if g.screenShake < 0 {
 			g.screenShake = 0
 		}
@@ -730,7 +1052,7 @@ func (g *Game) updateVisualEffects() {
 
 	// Update damage flash
 	if g.damageFlash > 0 {
-		g.damageFlash -= 1.0 / 60.0
+		g.damageFlash -= g.deltaTime
 		if g.damageFlash < 0 {
 			g.damageFlash = 0
 		}
@@ -739,25 +1061,28 @@ func (g *Game) updateVisualEffects() {
 	// Update floating text (damage/score indicators)
 	for _, ft := range g.floatingTexts {
 		if ft.Active {
-			ft.Update()
+			ft.Update(g.deltaTime)
 		}
 	}
 
 	// Update impact effects
 	for _, ie := range g.impactEffects {
 		if ie.Active {
-			ie.Update()
+			ie.Update(g.deltaTime)
 		}
 	}
 
 	// Update announcements
-	g.announcements.Update()
+	g.announcements.Update(g.deltaTime)
 }
 
 // updateLowHealthWarning plays low health warning sound when appropriate
 func (g *Game) updateLowHealthWarning() {
-	if g.player != nil && g.player.Active {
-		healthPercent := float64(g.player.Health) / float64(g.player.MaxHealth)
+	for _, pl := range g.pilots {
+		if !pl.ship.Active {
+			continue
+		}
+		healthPercent := float64(pl.ship.Health) / float64(pl.ship.MaxHealth)
 		if healthPercent < 0.3 && g.gameTime-g.lastLowHealthWarning > 3.0 {
 			g.sound.PlaySound(systems.SoundLowHealthWarn)
 			g.lastLowHealthWarning = g.gameTime
@@ -767,20 +1092,49 @@ func (g *Game) updateLowHealthWarning() {
 
 // checkGameOver handles game over condition and cleanup
 func (g *Game) checkGameOver() {
-	if g.player == nil || !g.player.Active {
+	// Co-op runs last while any pilot is still flying
+	if g.player == nil || !g.anyShipActive() {
 		g.transitionToState(StateGameOver)
 		g.nameInputMode = true
 		g.sound.PlaySound(systems.SoundGameOver)
+		g.runStats.Finish(g.gameTime, g.deathCause)
+
+		// Demo runs are not recorded or entered on the leaderboard
+		if g.demoMode {
+			g.nameInputMode = false
+			g.demoTimer = 0
+			return
+		}
+
+		// Runs altered from the developer console are unranked
+		if g.consoleUsed {
+			g.nameInputMode = false
+			return
+		}
+
+		// Seal the replay with the final result and keep a local copy
+		g.replayFile = ""
+		if g.replay != nil {
+			g.replay.Finish(g.score, g.wave)
+			if !g.headless {
+				name, err := g.replay.SaveToHistory(g.profileStore)
+				if err != nil {
+					log.Printf("Failed to save replay: %v", err)
+				} else {
+					g.replayFile = systems.StoragePath(g.profileStore, name)
+				}
+			}
+		}
+
+		// Record the best wave and sync the run's progress
+		if g.progression != nil {
+			g.progression.RecordWave(g.wave)
+			g.startSaveSync()
+		}
 
 		// Refresh online leaderboard scores for qualification check
 		if g.onlineLeaderboard != nil {
-			go func() {
-				if scores, err := g.onlineLeaderboard.GetTopScores(100); err == nil {
-					g.onlineScoresMu.Lock()
-					g.onlineScores = scores
-					g.onlineScoresMu.Unlock()
-				}
-			}()
+			go g.refreshOnlineScores()
 		}
 	}
 }
@@ -790,6 +1144,10 @@ func (g *Game) updatePaused() {
 		g.transitionToState(StatePlaying)
 	}
 	if inpututil.IsKeyJustPressed(ebiten.KeyQ) {
+		// Keep the run so it can be continued from the menu
+		if err := g.suspendRun(); err != nil {
+			log.Printf("Failed to suspend run: %v", err)
+		}
 		g.transitionToState(StateMenu)
 	}
 	if inpututil.IsKeyJustPressed(ebiten.KeyS) {
@@ -797,10 +1155,19 @@ func (g *Game) updatePaused() {
 		g.menu.SoundEnabled = !g.menu.SoundEnabled
 		g.sound.SetEnabled(g.menu.SoundEnabled)
 		g.sound.PlaySound(systems.SoundUIClick)
+		g.saveSettings()
 	}
 }
 
 func (g *Game) updateGameOver() {
+	if g.demoMode {
+		g.updateDemoGameOver()
+		return
+	}
+	if g.net != nil {
+		g.updateNetGameOver() // Name input below still runs
+	}
+
 	if g.nameInputMode {
 		// Handle name input
 		for _, r := range ebiten.AppendInputChars(nil) {
@@ -812,7 +1179,15 @@ func (g *Game) updateGameOver() {
 			g.playerName = g.playerName[:len(g.playerName)-1]
 		}
 		if inpututil.IsKeyJustPressed(ebiten.KeyEnter) && len(g.playerName) > 0 {
-			g.leaderboard.AddEntry(g.playerName, g.score, g.wave)
+			g.leaderboard.AddEntry(systems.LeaderboardEntry{
+				Name:       g.playerName,
+				Score:      g.score,
+				Wave:       g.wave,
+				Country:    g.country,
+				Difficulty: DifficultyLabel(g.selectedDifficulty),
+				Mode:       g.leaderboardMode(),
+				Details:    systems.NewRunDetails(g.runStats, g.seed, g.replay, g.replayFile),
+			})
 			g.nameInputMode = false
 
 			// Automatically submit to online leaderboard if score qualifies
@@ -821,6 +1196,9 @@ func (g *Game) updateGameOver() {
 		}
 	} else {
 		// Game over controls
+		if g.state != StateGameOver {
+			return // An online run went back to the menu
+		}
 		if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace) {
 			g.startGame()
 		}
@@ -836,25 +1214,16 @@ func (g *Game) submitScoreOnline() {
 		return
 	}
 
-	difficulty := ""
-	switch g.selectedDifficulty {
-	case DifficultyEasy:
-		difficulty = "Easy"
-	case DifficultyNormal:
-		difficulty = "Normal"
-	case DifficultyHard:
-		difficulty = "Hard"
-	}
+	difficulty := DifficultyLabel(g.selectedDifficulty)
 
-	// Submit asynchronously to not block game
-	go func() {
-		if err := g.onlineLeaderboard.SubmitScore(g.playerName, g.score, difficulty, g.wave); err != nil {
-			// Silently fail - no UI feedback for now
-			return
-		}
-		// Clear cache to force refresh on next leaderboard view
-		g.onlineLeaderboard.ClearCache()
-	}()
+	// Queue the score; the outbox worker submits it in the background and
+	// keeps retrying it, across launches, until it is accepted
+	id, err := g.outbox.Add(g.playerName, g.country, g.score, difficulty, g.wave, g.replay)
+	if err != nil {
+		log.Printf("Failed to save score outbox: %v", err)
+	}
+	g.runSubmissionID = id
+	g.wakeOutbox()
 }
 
 // autoSubmitScoreIfQualified automatically submits score if it's high enough
@@ -863,6 +1232,11 @@ func (g *Game) autoSubmitScoreIfQualified() {
 		return
 	}
 
+	// The online leaderboard ranks solo runs only
+	if g.coop() {
+		return
+	}
+
 	// Define minimum thresholds for auto-submission
 	const minScore = 1000 // Minimum score to consider
 	const minWave = 3     // Minimum wave reached
@@ -915,23 +1289,33 @@ func (g *Game) drawOnlineLeaderboard(screen *ebiten.Image) {
 	systems.DrawText(screen, "Difficulty", 550, startY+30, 1, color.RGBA{150, 150, 150, 255})
 	systems.DrawText(screen, "Wave", 750, startY+30, 1, color.RGBA{150, 150, 150, 255})
 
-	// Display top 10 (copy data under lock to minimize lock duration)
+	// Display top 10, leaving out tampered and impersonating entries
+	// (copy data under lock to minimize lock duration)
+	const limit = 10
+	scoresToDraw := make([]systems.OnlineScore, 0, limit)
 	g.onlineScoresMu.RLock()
-	limit := 10
-	if len(g.onlineScores) < 10 {
-		limit = len(g.onlineScores)
+	for _, score := range g.onlineScores {
+		if len(scoresToDraw) == limit {
+			break
+		}
+		if !score.Trust.Hidden() {
+			scoresToDraw = append(scoresToDraw, score)
+		}
 	}
-	// Copy scores to avoid holding lock during draw calls
-	scoresToDraw := make([]systems.OnlineScore, limit)
-	copy(scoresToDraw, g.onlineScores[:limit])
 	g.onlineScoresMu.RUnlock()
 
-	for i := 0; i < limit; i++ {
-		score := scoresToDraw[i]
+	flagged := false
+	for i, score := range scoresToDraw {
 		y := startY + 60 + (i * lineHeight)
 
-		// Highlight if this is the current player's score
+		// Highlight if this is the current player's score; dim entries
+		// whose signature does not tie them to a registered pilot
 		textColor := color.RGBA{200, 200, 200, 255}
+		if score.Trust != systems.ScoreVerified {
+			textColor = color.RGBA{140, 140, 140, 255}
+			systems.DrawText(screen, "?", 85, y, 0.8, color.RGBA{255, 200, 100, 255})
+			flagged = true
+		}
 		if score.PlayerName == g.playerName && score.Score == g.score {
 			textColor = color.RGBA{255, 255, 100, 255}
 		}
@@ -941,11 +1325,16 @@ func (g *Game) drawOnlineLeaderboard(screen *ebiten.Image) {
 		waveStr := systems.FormatNumber(int64(score.Wave))
 
 		systems.DrawText(screen, rankStr, 100, y, 0.8, textColor)
+		systems.DrawFlag(screen, score.Country, 138, y+1, 0.8)
 		systems.DrawText(screen, score.PlayerName, 160, y, 0.8, textColor)
 		systems.DrawText(screen, scoreStr, 400, y, 0.8, textColor)
 		systems.DrawText(screen, score.Difficulty, 550, y, 0.8, textColor)
 		systems.DrawText(screen, waveStr, 750, y, 0.8, textColor)
 	}
+	if flagged {
+		y := startY + 60 + len(scoresToDraw)*lineHeight + 4
+		systems.DrawText(screen, "? Unsigned, or signed by an unregistered install", 100, y, 0.8, color.RGBA{255, 200, 100, 255})
+	}
 }
 
 func (g *Game) checkCollisions() {
@@ -980,6 +1369,7 @@ func (g *Game) checkCollisions() {
 
 				if e.Health <= 0 {
 					e.Active = false
+					g.runStats.RecordKill(p.Source)
 					g.spawnExplosion(e.X, e.Y, e.Radius)
 
 					// Play appropriate explosion sound based on enemy type
@@ -999,12 +1389,12 @@ func (g *Game) checkCollisions() {
 					}
 
 					points := int64(e.Points)
-					g.addScore(points)
+					g.creditPilot(p.Owner, g.addScore(points), 1)
 					g.spawnFloatingScore(e.X, e.Y, int(points)) // Show score popup
 					g.screenShake = 5
 
 					// Chance to spawn powerup (respect limit)
-					if rand.Float64() < 0.15 && len(g.powerups) < MaxPowerUps {
+					if rng.Float64() < 0.15 && len(g.powerups) < MaxPowerUps {
 						powerup := g.powerUpPool.Get()
 						*powerup = *entities.NewPowerUp(e.X, e.Y)
 						g.powerups = append(g.powerups, powerup)
@@ -1022,7 +1412,9 @@ func (g *Game) checkCollisions() {
 				g.spawnImpactEffect(g.boss.X, g.boss.Y, 40, color.RGBA{255, 150, 100, 255})
 
 				if g.boss.TakeDamage(p.Damage) {
-					// Boss defeated
+					// Boss defeated; its points are shared once it explodes
+					g.runStats.RecordKill(p.Source)
+					g.creditPilot(p.Owner, 0, 1)
 					g.screenShake = 20
 				} else {
 					g.screenShake = 3
@@ -1031,154 +1423,10 @@ func (g *Game) checkCollisions() {
 		}
 	}
 
-	// Enemy projectiles vs player
-	if g.player != nil && g.player.Active {
-		for _, p := range g.projectiles {
-			if !p.Active || p.Friendly {
-				continue
-			}
-			if g.checkCircleCollision(p.X, p.Y, p.Radius, g.player.X, g.player.Y, g.player.Radius) {
-				p.Active = false
-				g.player.TakeDamage(p.Damage, g.gameTime)
-				g.spawnFloatingDamage(g.player.X, g.player.Y-20, p.Damage) // Show damage popup
-				g.screenShake = 10
-				g.damageFlash = 0.2 // Red flash for 0.2 seconds
-				g.sound.PlaySound(systems.SoundHitPlayer)
-				if g.player.Health <= 0 {
-					g.spawnExplosionWithType(g.player.X, g.player.Y, 40, entities.ExplosionBlast)
-					g.sound.PlaySound(systems.SoundExplosionLarge)
-					g.player.Active = false
-				}
-			}
-		}
-
-		// Enemies vs player (collision)
-		for _, e := range g.enemies {
-			if !e.Active {
-				continue
-			}
-			if g.checkCircleCollision(e.X, e.Y, e.Radius, g.player.X, g.player.Y, g.player.Radius) {
-				e.Active = false
-				g.spawnExplosion(e.X, e.Y, e.Radius)
-				// Play appropriate explosion sound based on enemy type
-				switch e.Type {
-				case entities.EnemyScout:
-					g.sound.PlaySound(systems.SoundExplosionSmall)
-				case entities.EnemyDrone:
-					g.sound.PlaySound(systems.SoundExplosionSmall)
-				case entities.EnemyHunter:
-					g.sound.PlaySound(systems.SoundExplosionMedium)
-				case entities.EnemyTank:
-					g.sound.PlaySound(systems.SoundExplosionLarge)
-				case entities.EnemyBomber:
-					g.sound.PlaySound(systems.SoundExplosionMedium)
-				}
-				g.sound.PlaySound(systems.SoundHitPlayer)
-				collisionDamage := int(float64(30) * g.difficultyConfig.DamageMultiplier)
-				g.player.TakeDamage(collisionDamage, g.gameTime)
-				g.spawnFloatingDamage(g.player.X, g.player.Y-20, collisionDamage) // Show damage popup
-				g.screenShake = 15
-				if g.player.Health <= 0 {
-					g.spawnExplosion(g.player.X, g.player.Y, 40)
-					g.sound.PlaySound(systems.SoundExplosionLarge)
-					g.player.Active = false
-				}
-			}
-		}
-
-		// Boss vs player (collision)
-		if g.boss != nil && g.boss.Active {
-			if g.checkCircleCollision(g.boss.X, g.boss.Y, g.boss.Radius*0.5, g.player.X, g.player.Y, g.player.Radius) {
-				bossDamage := int(float64(50) * g.difficultyConfig.DamageMultiplier)
-				g.player.TakeDamage(bossDamage, g.gameTime)
-				g.screenShake = 20
-				if g.player.Health <= 0 {
-					g.spawnExplosion(g.player.X, g.player.Y, 40)
-					g.player.Active = false
-				}
-			}
-		}
-
-		// Powerups vs player
-		for _, pu := range g.powerups {
-			if !pu.Active {
-				continue
-			}
-			if g.checkCircleCollision(pu.X, pu.Y, pu.Radius, g.player.X, g.player.Y, g.player.Radius) {
-				pu.Active = false
-
-				// Play appropriate sound based on power-up type
-				switch pu.Type {
-				case entities.PowerUpHealth:
-					g.sound.PlaySound(systems.SoundPowerUpCollect)
-				case entities.PowerUpShield:
-					g.sound.PlaySound(systems.SoundShieldRecharge)
-				case entities.PowerUpWeapon:
-					// Store old weapon level to check if it actually leveled up
-					oldLevel := g.player.WeaponLevel
-					g.player.ApplyPowerUp(pu.Type)
-					if g.player.WeaponLevel > oldLevel {
-						g.sound.PlaySound(systems.SoundWeaponLevelUp)
-						g.spawnFloatingUpgrade(g.player.X, g.player.Y-30, g.player.WeaponLevel)
-					}
-					return // Skip ApplyPowerUp call below since we already called it
-				case entities.PowerUpSpeed:
-					g.sound.PlaySound(systems.SoundPowerUpCollect)
-				case entities.PowerUpMystery:
-					// Handle mystery box separately for announcements
-					message, isPositive := g.player.ApplyPowerUp(pu.Type)
-					if message != "" {
-						// Add large center-screen announcement
-						g.announcements.AddMysteryBoxAnnouncement(message, isPositive, ScreenWidth/2, ScreenHeight/2)
-						// Also add floating text above player
-						var textColor color.RGBA
-						if isPositive {
-							textColor = color.RGBA{50, 255, 50, 255}
-							g.sound.PlaySound(systems.SoundPowerUpCollect)
-						} else {
-							textColor = color.RGBA{255, 50, 50, 255}
-							g.sound.PlaySound(systems.SoundHitPlayer)
-						}
-						ft := entities.NewFloatingText(g.player.X, g.player.Y-50, message, textColor)
-						g.floatingTexts = append(g.floatingTexts, ft)
-					}
-					return // Skip ApplyPowerUp call below since we already handled it
-				}
-
-				// Apply power-up effect (skip for weapon and mystery since we already handled them)
-				if pu.Type != entities.PowerUpWeapon && pu.Type != entities.PowerUpMystery {
-					g.player.ApplyPowerUp(pu.Type)
-				}
-			}
-		}
-
-		// Asteroids vs player
-		for _, a := range g.asteroids {
-			if !a.Active {
-				continue
-			}
-			if g.checkCircleCollision(a.X, a.Y, a.Radius, g.player.X, g.player.Y, g.player.Radius) {
-				asteroidDamage := int(float64(15) * g.difficultyConfig.DamageMultiplier)
-				g.player.TakeDamage(asteroidDamage, g.gameTime)
-				g.spawnExplosion(a.X, a.Y, a.Radius)
-				g.sound.PlaySound(systems.SoundHitAsteroid)
-				// Play appropriate explosion sound based on asteroid size
-				switch a.Size {
-				case entities.AsteroidSmall:
-					g.sound.PlaySound(systems.SoundExplosionSmall)
-				case entities.AsteroidMedium:
-					g.sound.PlaySound(systems.SoundExplosionMedium)
-				case entities.AsteroidLarge:
-					g.sound.PlaySound(systems.SoundExplosionLarge)
-				}
-				a.Active = false
-				g.screenShake = 8
-				if g.player.Health <= 0 {
-					g.spawnExplosion(g.player.X, g.player.Y, 40)
-					g.sound.PlaySound(systems.SoundExplosionLarge)
-					g.player.Active = false
-				}
-			}
+	// Everything that can hit the pilots' ships
+	for i, pl := range g.pilots {
+		if pl.ship.Active {
+			g.checkShipCollisions(i)
 		}
 	}
 
@@ -1212,7 +1460,7 @@ func (g *Game) checkCollisions() {
 						g.sound.PlaySound(systems.SoundExplosionLarge)
 					}
 					points := int64(10 + int(a.Radius))
-					g.addScore(points)
+					g.creditPilot(p.Owner, g.addScore(points), 0)
 					g.spawnFloatingScore(a.X, a.Y, int(points)) // Show score popup
 				}
 				break // Projectile can only hit one asteroid
@@ -1221,6 +1469,182 @@ func (g *Game) checkCollisions() {
 	}
 }
 
+// checkShipCollisions handles everything that can hit pilot i's ship:
+// enemy fire, rams, the boss, power-ups and asteroids
+func (g *Game) checkShipCollisions(i int) {
+	ship := g.pilots[i].ship
+
+	// Enemy projectiles vs player
+	for _, p := range g.projectiles {
+		if !p.Active || p.Friendly {
+			continue
+		}
+		if g.checkCircleCollision(p.X, p.Y, p.Radius, ship.X, ship.Y, ship.Radius) {
+			p.Active = false
+			g.damagePlayer(i, p.Damage, p.Source)
+			g.spawnFloatingDamage(ship.X, ship.Y-20, p.Damage) // Show damage popup
+			g.screenShake = 10
+			g.damageFlash = 0.2 // Red flash for 0.2 seconds
+			g.sound.PlaySound(systems.SoundHitPlayer)
+			if ship.Health <= 0 {
+				g.spawnExplosionWithType(ship.X, ship.Y, 40, entities.ExplosionBlast)
+				g.sound.PlaySound(systems.SoundExplosionLarge)
+				ship.Active = false
+			}
+		}
+	}
+
+	// Enemies vs player (collision)
+	for _, e := range g.enemies {
+		if !e.Active {
+			continue
+		}
+		if g.checkCircleCollision(e.X, e.Y, e.Radius, ship.X, ship.Y, ship.Radius) {
+			e.Active = false
+			g.spawnExplosion(e.X, e.Y, e.Radius)
+			// Play appropriate explosion sound based on enemy type
+			switch e.Type {
+			case entities.EnemyScout:
+				g.sound.PlaySound(systems.SoundExplosionSmall)
+			case entities.EnemyDrone:
+				g.sound.PlaySound(systems.SoundExplosionSmall)
+			case entities.EnemyHunter:
+				g.sound.PlaySound(systems.SoundExplosionMedium)
+			case entities.EnemyTank:
+				g.sound.PlaySound(systems.SoundExplosionLarge)
+			case entities.EnemyBomber:
+				g.sound.PlaySound(systems.SoundExplosionMedium)
+			}
+			g.sound.PlaySound(systems.SoundHitPlayer)
+			collisionDamage := int(float64(30) * g.difficultyConfig.DamageMultiplier)
+			g.damagePlayer(i, collisionDamage, e.Type.String())
+			g.spawnFloatingDamage(ship.X, ship.Y-20, collisionDamage) // Show damage popup
+			g.screenShake = 15
+			if ship.Health <= 0 {
+				g.spawnExplosion(ship.X, ship.Y, 40)
+				g.sound.PlaySound(systems.SoundExplosionLarge)
+				ship.Active = false
+			}
+		}
+	}
+
+	// Boss vs player (collision)
+	if g.boss != nil && g.boss.Active {
+		if g.checkCircleCollision(g.boss.X, g.boss.Y, g.boss.Radius*0.5, ship.X, ship.Y, ship.Radius) {
+			bossDamage := int(float64(50) * g.difficultyConfig.DamageMultiplier)
+			g.damagePlayer(i, bossDamage, systems.SourceBoss)
+			g.screenShake = 20
+			if ship.Health <= 0 {
+				g.spawnExplosion(ship.X, ship.Y, 40)
+				ship.Active = false
+			}
+		}
+	}
+
+	// Powerups vs player
+	for _, pu := range g.powerups {
+		if !pu.Active {
+			continue
+		}
+		if g.checkCircleCollision(pu.X, pu.Y, pu.Radius, ship.X, ship.Y, ship.Radius) {
+			pu.Active = false
+
+			// Play appropriate sound based on power-up type
+			switch pu.Type {
+			case entities.PowerUpHealth:
+				g.sound.PlaySound(systems.SoundPowerUpCollect)
+			case entities.PowerUpShield:
+				g.sound.PlaySound(systems.SoundShieldRecharge)
+			case entities.PowerUpWeapon:
+				// Store old weapon level to check if it actually leveled up
+				oldLevel := ship.WeaponLevel
+				ship.ApplyPowerUp(pu.Type)
+				if ship.WeaponLevel > oldLevel {
+					g.sound.PlaySound(systems.SoundWeaponLevelUp)
+					g.spawnFloatingUpgrade(ship.X, ship.Y-30, ship.WeaponLevel)
+				}
+				continue // Skip ApplyPowerUp call below since we already called it
+			case entities.PowerUpSpeed:
+				g.sound.PlaySound(systems.SoundPowerUpCollect)
+			case entities.PowerUpMystery:
+				// Handle mystery box separately for announcements
+				message, isPositive := ship.ApplyPowerUp(pu.Type)
+				if message != "" {
+					// Add large center-screen announcement
+					g.announcements.AddMysteryBoxAnnouncement(message, isPositive, ScreenWidth/2, ScreenHeight/2)
+					// Also add floating text above player
+					var textColor color.RGBA
+					if isPositive {
+						textColor = color.RGBA{50, 255, 50, 255}
+						g.sound.PlaySound(systems.SoundPowerUpCollect)
+					} else {
+						textColor = color.RGBA{255, 50, 50, 255}
+						g.sound.PlaySound(systems.SoundHitPlayer)
+					}
+					ft := entities.NewFloatingText(ship.X, ship.Y-50, message, textColor)
+					g.floatingTexts = append(g.floatingTexts, ft)
+				}
+				continue // Skip ApplyPowerUp call below since we already handled it
+			}
+
+			// Apply power-up effect (skip for weapon and mystery since we already handled them)
+			if pu.Type != entities.PowerUpWeapon && pu.Type != entities.PowerUpMystery {
+				ship.ApplyPowerUp(pu.Type)
+			}
+		}
+	}
+
+	// Asteroids vs player
+	for _, a := range g.asteroids {
+		if !a.Active {
+			continue
+		}
+		if g.checkCircleCollision(a.X, a.Y, a.Radius, ship.X, ship.Y, ship.Radius) {
+			asteroidDamage := int(float64(15) * g.difficultyConfig.DamageMultiplier)
+			g.damagePlayer(i, asteroidDamage, systems.SourceAsteroid)
+			g.spawnExplosion(a.X, a.Y, a.Radius)
+			g.sound.PlaySound(systems.SoundHitAsteroid)
+			// Play appropriate explosion sound based on asteroid size
+			switch a.Size {
+			case entities.AsteroidSmall:
+				g.sound.PlaySound(systems.SoundExplosionSmall)
+			case entities.AsteroidMedium:
+				g.sound.PlaySound(systems.SoundExplosionMedium)
+			case entities.AsteroidLarge:
+				g.sound.PlaySound(systems.SoundExplosionLarge)
+			}
+			a.Active = false
+			g.screenShake = 8
+			if ship.Health <= 0 {
+				g.spawnExplosion(ship.X, ship.Y, 40)
+				g.sound.PlaySound(systems.SoundExplosionLarge)
+				ship.Active = false
+			}
+		}
+	}
+}
+
+// damagePlayer applies damage to pilot i's ship and records what dealt it
+func (g *Game) damagePlayer(i int, amount int, source string) {
+	if g.godMode {
+		return
+	}
+	ship := g.pilots[i].ship
+	before := ship.Health + ship.Shield
+	ship.TakeDamage(amount, g.gameTime)
+	taken := before - (ship.Health + ship.Shield)
+	g.runStats.RecordDamage(source, taken)
+	if s := g.pilotStats(i); s != nil && taken > 0 {
+		s.DamageTaken += taken
+	}
+	if taken > 0 {
+		g.clock.HitStop(HitStopPlayerHit, HitStopPlayerScale)
+	}
+	if ship.Health <= 0 && g.deathCause == "" {
+		g.deathCause = source
+	}
+}
+
 func (g *Game) checkCircleCollision(x1, y1, r1, x2, y2, r2 float64) bool {
 	dx := x2 - x1
 	dy := y2 - y1
@@ -1228,10 +1652,13 @@ func (g *Game) checkCircleCollision(x1, y1, r1, x2, y2, r2 float64) bool {
 	return dist < r1+r2
 }
 
-func (g *Game) addScore(points int64) {
-	g.score += int64(float64(points) * g.multiplier)
+// addScore adds points at the current multiplier, returning what was added
+func (g *Game) addScore(points int64) int64 {
+	added := int64(float64(points) * g.multiplier)
+	g.score += added
 	g.comboTimer = 2.0
 	g.multiplier = math.Min(g.multiplier+0.1, 5.0)
+	return added
 }
 
 func (g *Game) spawnExplosion(x, y, size float64) {
@@ -1387,7 +1814,7 @@ func (g *Game) updateCamera() {
 	zoomDifference := g.cameraTargetZoom - g.cameraZoom
 	if math.Abs(zoomDifference) > 0.01 {
 		// Smooth interpolation towards target zoom
-		g.cameraZoom += zoomDifference * 0.1 // Smooth lerp
+		g.cameraZoom += zoomDifference * (1 - entities.Decay(0.9, g.deltaTime)) // Smooth lerp
 	} else {
 		g.cameraZoom = g.cameraTargetZoom
 	}
@@ -1403,7 +1830,7 @@ func (g *Game) updateCamera() {
 
 	// Update cinematic mode timer
 	if g.cameraCinematicMode {
-		g.cameraCinematicTimer += 1.0 / 60.0
+		g.cameraCinematicTimer += g.deltaTime
 		if g.cameraCinematicTimer > 2.5 {
 			// Exit cinematic mode after 2.5 seconds
 			g.cameraCinematicMode = false
@@ -1444,7 +1871,7 @@ func (g *Game) updateCamera() {
 
 	// Decay screen shake more gradually for better feel
 	if g.cameraShakeAmount > 0.1 {
-		g.cameraShakeAmount *= 0.92 // Slightly slower decay for better feel
+		g.cameraShakeAmount *= entities.Decay(0.92, g.deltaTime) // Slightly slower decay for better feel
 	} else {
 		g.cameraShakeAmount = 0
 	}
@@ -1473,6 +1900,18 @@ func (g *Game) applyZoom(x, y float64) (float64, float64) {
 }
 
 func (g *Game) Draw(screen *ebiten.Image) {
+	if g.crash != nil {
+		g.drawCrashScreen(screen)
+		return
+	}
+	defer g.recoverCrash("draw")
+
+	// Track render time
+	renderStart := time.Now()
+	defer func() {
+		g.perfMon.RecordRenderTime(time.Since(renderStart))
+	}()
+
 	// Clear with deep space color
 	screen.Fill(color.RGBA{5, 5, 15, 255})
 
@@ -1489,17 +1928,51 @@ func (g *Game) Draw(screen *ebiten.Image) {
 
 	switch g.state {
 	case StateMenu:
-		g.menu.Draw(screen, g.leaderboard, ScreenWidth, ScreenHeight)
+		if g.profileScreen.open {
+			g.drawProfileScreen(screen)
+			break
+		}
+		if g.outboxScreen.open {
+			g.drawOutboxScreen(screen)
+			break
+		}
+		if g.globalBoard.open {
+			g.drawGlobalBoard(screen)
+			break
+		}
+		if g.localBoard.open {
+			g.drawLocalBoard(screen)
+			break
+		}
+		if g.netLobby.open {
+			g.drawNetLobby(screen)
+			break
+		}
+		g.menu.Draw(screen, ScreenWidth, ScreenHeight)
 		g.menu.InfoMenu.Draw(screen, ScreenWidth, ScreenHeight)
 	case StatePlaying, StatePaused:
 		g.drawGameplay(screen, shakeX, shakeY)
+		g.drawDebugOverlay(screen, shakeX, shakeY)
 		if g.state == StatePaused {
 			g.drawPauseOverlay(screen)
 		}
+		if g.demoMode {
+			g.drawDemoOverlay(screen)
+		}
+		if g.net != nil {
+			g.drawNetOverlay(screen)
+		}
+		if g.console != nil {
+			g.console.Draw(screen, ScreenWidth)
+		}
 	case StateGameOver:
 		g.drawGameplay(screen, shakeX, shakeY)
+		g.drawDebugOverlay(screen, shakeX, shakeY)
 		g.drawGameOverOverlay(screen)
 	}
+
+	// Performance HUD sits on top of every screen
+	g.perfHUD.Draw(screen, ScreenWidth-systems.PerformanceHUDWidth-10, 10)
 }
 
 func (g *Game) drawGameplay(screen *ebiten.Image, shakeX, shakeY float64) {
@@ -1585,13 +2058,22 @@ func (g *Game) drawGameplay(screen *ebiten.Image, shakeX, shakeY float64) {
 		}
 	}
 
-	// Player (index 0 since there's only one)
-	if g.player != nil && g.player.Active {
-		g.drawableEntities = append(g.drawableEntities, drawableEntity{
-			y:     g.player.Y,
-			index: 0,
-			eType: entityTypePlayer,
-		})
+	// Hazards sit beneath everything else
+	for _, h := range g.hazards {
+		if h.Active {
+			h.Draw(screen, shakeX, shakeY)
+		}
+	}
+
+	// Pilots' ships (index into g.pilots)
+	for i, pl := range g.pilots {
+		if pl.ship.Active {
+			g.drawableEntities = append(g.drawableEntities, drawableEntity{
+				y:     pl.ship.Y,
+				index: i,
+				eType: entityTypePlayer,
+			})
+		}
 	}
 
 	// Sort by Y position (lower Y = further back = drawn first)
@@ -1629,9 +2111,10 @@ func (g *Game) drawGameplay(screen *ebiten.Image, shakeX, shakeY float64) {
 			perspScale := g.getPerspectiveScale(a.Y)
 			a.Draw(screen, shakeX, shakeY, perspScale, entity.sprite)
 		case entityTypePlayer:
-			g.player.Draw(screen, shakeX, shakeY)
+			g.pilots[entity.index].ship.Draw(screen, shakeX, shakeY)
 		}
 	}
+	g.drawPilotMarkers(screen, shakeX, shakeY)
 
 	// Draw HUD (always on top, screen space)
 	if g.hud != nil {
@@ -1655,11 +2138,17 @@ func (g *Game) drawGameplay(screen *ebiten.Image, shakeX, shakeY float64) {
 					int(weapon.Level), weapon.FireTimer, weapon.FireRate, g.gameTime)
 			}
 		}
+		g.drawPilotPanel(screen)
 
 		// Boss indicator
 		if g.bossWave && g.boss != nil {
 			systems.DrawTextCentered(screen, "!! BOSS BATTLE !!", ScreenWidth/2, 60, 2, color.RGBA{255, 50, 50, 255})
 		}
+
+		// Console-altered runs are marked as unranked
+		if g.consoleUsed {
+			systems.DrawText(screen, "UNRANKED", 20, ScreenHeight-30, 1.5, color.RGBA{255, 160, 60, 255})
+		}
 	}
 
 	// Draw floating text (damage/score indicators)
@@ -1696,6 +2185,12 @@ func (g *Game) drawGameplay(screen *ebiten.Image, shakeX, shakeY float64) {
 	}
 }
 
+// drawDemoOverlay labels the attract demo so it is not mistaken for a live run
+func (g *Game) drawDemoOverlay(screen *ebiten.Image) {
+	systems.DrawTextCentered(screen, "DEMO", ScreenWidth/2, 90, 3, color.RGBA{200, 150, 200, 255})
+	systems.DrawTextCentered(screen, "Press any key to return to the menu", ScreenWidth/2, 130, 1.5, color.RGBA{180, 180, 180, 255})
+}
+
 func (g *Game) drawPauseOverlay(screen *ebiten.Image) {
 	// Semi-transparent overlay (reuse to avoid per-frame allocation)
 	g.overlayImage.Clear()
@@ -1704,7 +2199,7 @@ func (g *Game) drawPauseOverlay(screen *ebiten.Image) {
 
 	systems.DrawTextCentered(screen, "PAUSED", ScreenWidth/2, ScreenHeight/2-40, 4, color.RGBA{255, 255, 255, 255})
 	systems.DrawTextCentered(screen, "Press P to Resume", ScreenWidth/2, ScreenHeight/2+20, 2, color.RGBA{200, 200, 200, 255})
-	systems.DrawTextCentered(screen, "Press Q to Quit", ScreenWidth/2, ScreenHeight/2+50, 2, color.RGBA{200, 200, 200, 255})
+	systems.DrawTextCentered(screen, "Press Q to Save & Quit", ScreenWidth/2, ScreenHeight/2+50, 2, color.RGBA{200, 200, 200, 255})
 
 	// Sound toggle in pause menu
 	soundStatus := "ON"
@@ -1727,15 +2222,29 @@ func (g *Game) drawGameOverOverlay(screen *ebiten.Image) {
 	scoreText := systems.FormatNumber(g.score)
 	systems.DrawTextCentered(screen, "Final Score: "+scoreText, ScreenWidth/2, 220, 2, color.RGBA{255, 255, 100, 255})
 	systems.DrawTextCentered(screen, "Wave Reached: "+systems.FormatNumber(int64(g.wave)), ScreenWidth/2, 260, 2, color.RGBA{200, 200, 200, 255})
+	if g.coop() && g.nameInputMode {
+		g.drawPilotSummary(screen, 460)
+	}
 
-	if g.nameInputMode {
+	if g.demoMode {
+		systems.DrawTextCentered(screen, "DEMO - Press any key", ScreenWidth/2, ScreenHeight-100, 2, color.RGBA{200, 150, 200, 255})
+	} else if g.consoleUsed {
+		systems.DrawTextCentered(screen, "UNRANKED - the developer console was used this run", ScreenWidth/2, 320, 1.5, color.RGBA{255, 160, 60, 255})
+		systems.DrawTextCentered(screen, "Press ENTER to Play Again", ScreenWidth/2, ScreenHeight-100, 2, color.RGBA{100, 255, 100, 255})
+		systems.DrawTextCentered(screen, "Press Q for Menu", ScreenWidth/2, ScreenHeight-60, 1.5, color.RGBA{150, 150, 150, 255})
+	} else if g.nameInputMode {
 		systems.DrawTextCentered(screen, "Enter Your Name:", ScreenWidth/2, 320, 2, color.RGBA{255, 255, 255, 255})
 		nameDisplay := g.playerName + "_"
 		systems.DrawTextCentered(screen, nameDisplay, ScreenWidth/2, 360, 3, color.RGBA{100, 255, 100, 255})
 		systems.DrawTextCentered(screen, "Press ENTER to confirm", ScreenWidth/2, 420, 1.5, color.RGBA{150, 150, 150, 255})
 	} else {
 		// Show leaderboard (local)
-		g.leaderboard.Draw(screen, ScreenWidth/2, 320, g.score)
+		g.leaderboard.Draw(screen, ScreenWidth/2, 320, DifficultyLabel(g.selectedDifficulty), g.leaderboardMode(), 10, g.score)
+
+		// Online submission of this run's score
+		if status, c := g.submissionStatus(); status != "" {
+			systems.DrawTextCentered(screen, status, ScreenWidth/2, ScreenHeight-140, 1.3, c)
+		}
 
 		// Show online leaderboard if available
 		g.onlineScoresMu.RLock()
@@ -1745,6 +2254,10 @@ func (g *Game) drawGameOverOverlay(screen *ebiten.Image) {
 			g.drawOnlineLeaderboard(screen)
 		}
 
+		if g.net != nil {
+			systems.DrawTextCentered(screen, "Press ENTER to Leave the Session", ScreenWidth/2, ScreenHeight-100, 2, color.RGBA{100, 255, 100, 255})
+			return
+		}
 		systems.DrawTextCentered(screen, "Press ENTER to Play Again", ScreenWidth/2, ScreenHeight-100, 2, color.RGBA{100, 255, 100, 255})
 		systems.DrawTextCentered(screen, "Press Q for Menu", ScreenWidth/2, ScreenHeight-60, 1.5, color.RGBA{150, 150, 150, 255})
 	}
@@ -1759,7 +2272,7 @@ func (g *Game) spawnFloatingScore(x, y float64, score int) {
 	ft := g.floatingTextPool.Get()
 	ft.X = x
 	ft.Y = y
-	ft.VelY = -2.0
+	ft.VelY = -120
 	ft.Text = fmt.Sprintf("+%d", score)
 	ft.TextColor = color.RGBA{255, 200, 100, 255}
 	ft.Life = 2.0
@@ -1777,7 +2290,7 @@ func (g *Game) spawnFloatingDamage(x, y float64, damage int) {
 	ft := g.floatingTextPool.Get()
 	ft.X = x
 	ft.Y = y
-	ft.VelY = -2.0
+	ft.VelY = -120
 	ft.Text = fmt.Sprintf("-%d", damage)
 	ft.TextColor = color.RGBA{255, 50, 50, 255}
 	ft.Life = 2.0
@@ -1795,7 +2308,7 @@ func (g *Game) spawnFloatingUpgrade(x, y float64, level int) {
 	ft := g.floatingTextPool.Get()
 	ft.X = x
 	ft.Y = y
-	ft.VelY = -2.0
+	ft.VelY = -120
 	ft.Text = fmt.Sprintf("Lvl %d", level)
 	ft.TextColor = color.RGBA{100, 255, 200, 255}
 	ft.Life = 2.0
@@ -1827,7 +2340,7 @@ func (g *Game) updateMiniBossSpawning() {
 	}
 
 	// Update spawn timer
-	g.miniBossSpawnTimer += 1.0 / 60.0
+	g.miniBossSpawnTimer += g.deltaTime
 
 	// Count active mini-bosses (enemies that are hunter or tank types - we'll mark them as mini-bosses)
 	activeMiniBosses := 0
@@ -1868,6 +2381,7 @@ func (g *Game) updateMiniBossSpawning() {
 		miniBoss.Speed *= 1.2     // Slightly faster
 		miniBoss.ShootRate *= 0.8 // Shoots more often
 
+		g.assignTargets([]*entities.Enemy{miniBoss})
 		g.enemies = append(g.enemies, miniBoss)
 		g.miniBossesSpawned++
 		g.sound.PlaySound(systems.SoundWaveStart) // Alert sound for mini-boss spawn
@@ -1894,6 +2408,7 @@ func (g *Game) cleanupGameEntities() {
 	g.asteroids = g.asteroids[:0]
 	g.floatingTexts = g.floatingTexts[:0]
 	g.impactEffects = g.impactEffects[:0]
+	g.hazards = nil
 
 	// Clear boss reference
 	g.boss = nil
@@ -52,9 +52,18 @@ func (h *GameStateHandler) Exit(nextState states.StateType) {
 		if nextState == states.TypeMenu {
 			h.game.cleanupGameEntities()
 		}
+	case states.TypePaused:
+		// Quitting to the menu; the run has been suspended by then
+		if nextState == states.TypeMenu {
+			h.game.cleanupGameEntities()
+		}
 	case states.TypePlaying:
 		// When leaving playing state (to pause or game over), no cleanup needed
-		// Entities should remain for the game over screen or pause resume
+		// Entities should remain for the game over screen or pause resume.
+		// Interrupting the attract demo goes straight back to the menu.
+		if nextState == states.TypeMenu {
+			h.game.cleanupGameEntities()
+		}
 	}
 }
 
@@ -123,6 +132,7 @@ func (g *Game) initializeStateMachine() {
 
 	// Configure valid transitions
 	g.stateMachine.ConfigureDefaultTransitions()
+	g.stateMachine.AllowTransition(states.TypePlaying, states.TypeMenu) // Leaving the attract demo
 
 	// Set transition hooks
 	g.stateMachine.SetOnAfterTransition(func(from, to states.StateType) {
@@ -0,0 +1,100 @@
+package game
+
+import (
+	"image/color"
+	"math"
+
+	"stellar-siege/game/entities"
+	"stellar-siege/game/systems"
+)
+
+// Hazard tuning
+const (
+	HazardPullRangeMult     = 2.5 // Pull reaches this many hazard radii from the center
+	HazardRadiationInterval = 0.5 // Seconds between radiation damage ticks
+)
+
+// updateHazards advances environmental hazards: fields pull the ships and
+// enemies in, radiation hurts over time, black holes destroy on contact and
+// solid hazards absorb projectiles
+func (g *Game) updateHazards() {
+	for _, h := range g.hazards {
+		if !h.Active {
+			continue
+		}
+		h.Update(g.deltaTime)
+
+		if h.PullForce > 0 {
+			pullRange := h.Radius * HazardPullRangeMult
+			for _, pl := range g.pilots {
+				if pl.ship.Active {
+					pl.ship.X, pl.ship.Y = g.pullToward(h, pl.ship.X, pl.ship.Y, pullRange)
+				}
+			}
+			for _, e := range g.enemies {
+				if e.Active {
+					e.X, e.Y = g.pullToward(h, e.X, e.Y, pullRange)
+				}
+			}
+		}
+
+		radiationTick := h.DamageRate > 0 && h.LastDamage >= HazardRadiationInterval
+		for i, pl := range g.pilots {
+			ship := pl.ship
+			if !ship.Active {
+				continue
+			}
+			touching := g.checkCircleCollision(h.X, h.Y, h.GetCollisionRadius(), ship.X, ship.Y, ship.Radius)
+			switch {
+			case touching && h.IsDangerous():
+				g.damagePlayer(i, ship.Health+ship.Shield, systems.SourceHazard)
+			case touching && radiationTick:
+				h.LastDamage = 0
+				g.damagePlayer(i, int(h.DamageRate*HazardRadiationInterval), systems.SourceHazard)
+			}
+			if ship.Health <= 0 {
+				g.spawnExplosionWithType(ship.X, ship.Y, 40, entities.ExplosionBlast)
+				g.sound.PlaySound(systems.SoundExplosionLarge)
+				ship.Active = false
+			}
+		}
+
+		if h.Type != entities.HazardTypeBarrier && h.Type != entities.HazardTypeBlackHole {
+			continue
+		}
+		for _, p := range g.projectiles {
+			if !p.Active || !g.checkCircleCollision(h.X, h.Y, h.GetCollisionRadius(), p.X, p.Y, p.Radius) {
+				continue
+			}
+			p.Active = false
+			if p.Friendly {
+				h.TakeDamage(p.Damage)
+				g.spawnImpactEffect(p.X, p.Y, 20, color.RGBA{255, 255, 100, 255})
+			}
+			if !h.Active {
+				g.spawnExplosion(h.X, h.Y, h.Radius)
+				break
+			}
+		}
+	}
+
+	active := g.hazards[:0]
+	for _, h := range g.hazards {
+		if h.Active {
+			active = append(active, h)
+		}
+	}
+	g.hazards = active
+}
+
+// pullToward moves a point within pullRange of the hazard toward its center,
+// more strongly the closer it is
+func (g *Game) pullToward(h *entities.Hazard, x, y, pullRange float64) (float64, float64) {
+	dx, dy := h.X-x, h.Y-y
+	dist := math.Hypot(dx, dy)
+	if dist < 1 || dist > pullRange {
+		return x, y
+	}
+	step := math.Min(h.PullForce*(1-dist/pullRange)*g.deltaTime, dist)
+	return x + dx/dist*step, y + dy/dist*step
+}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"stellar-siege/game/core"
+	"stellar-siege/game/entities"
+	"stellar-siege/game/systems"
+)
+
+// NewHeadlessGame creates a game that runs the simulation without a window,
+// audio or rendering, and immediately starts a run with the given seed.
+// Headless games are driven by calling Step with recorded or generated input.
+func NewHeadlessGame(seed int64, difficulty DifficultyMode) *Game {
+	return NewHeadlessCoopGame(seed, difficulty, 1)
+}
+
+// NewHeadlessCoopGame creates a headless game like NewHeadlessGame whose run
+// has the given number of pilots. Each pilot's controls are packed into the
+// input frames passed to Step with entities.CombineInputs.
+func NewHeadlessCoopGame(seed int64, difficulty DifficultyMode, pilots int) *Game {
+	store := systems.NewMemoryStorage()
+	g := &Game{
+		state:              StateMenu,
+		selectedDifficulty: difficulty,
+		players:            pilots,
+		headless:           true,
+		cameraZoom:         1.0,
+		cameraTargetZoom:   1.0,
+		floatingTexts:      make([]*entities.FloatingText, 0),
+		impactEffects:      make([]*entities.ImpactEffect, 0),
+		announcements:      entities.NewAnnouncementManager(),
+		sound:              systems.NewSilentSoundManager(),
+		perfMon:            systems.NewPerformanceMonitor(),
+		storage:            store,
+		outbox:             systems.NewScoreOutbox(store),
+		profileStore:       store,
+		keys:               systems.DefaultKeyBindings(),
+		spatialGrid:        core.NewSpatialGrid(float64(ScreenWidth), float64(ScreenHeight), 100.0),
+	}
+	g.initializePools()
+	g.console = g.newGameConsole()
+	g.startGameWithSeed(seed)
+	return g
+}
+
+// Step advances a headless run by one tick. It returns false once the run
+// has ended and further steps have no effect.
+func (g *Game) Step(input entities.InputFrame) bool {
+	if g.state != StatePlaying {
+		return false
+	}
+	g.stepSimulation(input)
+	return g.state == StatePlaying
+}
+
+// Score returns the current score of the run
+func (g *Game) Score() int64 {
+	return g.score
+}
+
+// Wave returns the wave the run has reached
+func (g *Game) Wave() int {
+	return g.wave
+}
+
+// Tick returns the number of simulation ticks executed in the current run
+func (g *Game) Tick() int {
+	return g.tick
+}
+
+// Replay returns the replay recorded for the current run
+func (g *Game) Replay() *systems.Replay {
+	return g.replay
+}
+
+// BotView returns the world as seen by an autopilot driving the player
+func (g *Game) BotView() core.BotView {
+	return core.BotView{
+		Player:       g.player,
+		Enemies:      g.enemies,
+		Boss:         g.boss,
+		PowerUps:     g.powerups,
+		Asteroids:    g.asteroids,
+		Grid:         g.spatialGrid,
+		ScreenWidth:  float64(ScreenWidth),
+		ScreenHeight: float64(ScreenHeight),
+	}
+}
+
+// RunStats returns the gameplay statistics collected for the current run
+func (g *Game) RunStats() *systems.RunStats {
+	return g.runStats
+}
@@ -0,0 +1,101 @@
+package game
+
+import (
+	"stellar-siege/game/entities"
+	"stellar-siege/game/systems"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// captureInputFrame samples the keyboard and mouse into the input frame for
+// this tick. The arrow keys and left mouse button work alongside the bindings.
+func captureInputFrame(keys systems.KeyBindings) entities.InputFrame {
+	var frame entities.InputFrame
+	if ebiten.IsKeyPressed(keys.Up) || ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
+		frame |= entities.InputUp
+	}
+	if ebiten.IsKeyPressed(keys.Down) || ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
+		frame |= entities.InputDown
+	}
+	if ebiten.IsKeyPressed(keys.Left) || ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
+		frame |= entities.InputLeft
+	}
+	if ebiten.IsKeyPressed(keys.Right) || ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
+		frame |= entities.InputRight
+	}
+	if ebiten.IsKeyPressed(keys.Shoot) || ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		frame |= entities.InputShoot
+	}
+	return frame
+}
+
+// gamepadDeadZone is how far a stick must be pushed to count as held
+const gamepadDeadZone = 0.35
+
+// captureCoopInputFrame samples pilot 1's controls in a co-op run: the
+// bindings and the left mouse button. The arrow keys belong to pilot 2.
+func captureCoopInputFrame(keys systems.KeyBindings) entities.InputFrame {
+	var frame entities.InputFrame
+	if ebiten.IsKeyPressed(keys.Up) {
+		frame |= entities.InputUp
+	}
+	if ebiten.IsKeyPressed(keys.Down) {
+		frame |= entities.InputDown
+	}
+	if ebiten.IsKeyPressed(keys.Left) {
+		frame |= entities.InputLeft
+	}
+	if ebiten.IsKeyPressed(keys.Right) {
+		frame |= entities.InputRight
+	}
+	if ebiten.IsKeyPressed(keys.Shoot) || ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		frame |= entities.InputShoot
+	}
+	return frame
+}
+
+// capturePilotTwoFrame samples pilot 2's controls in a co-op run: the arrow
+// keys with Enter or right Ctrl to fire, and the first connected gamepad's
+// left stick or d-pad with its bottom face button to fire
+func capturePilotTwoFrame() entities.InputFrame {
+	var frame entities.InputFrame
+	if ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
+		frame |= entities.InputUp
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
+		frame |= entities.InputDown
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
+		frame |= entities.InputLeft
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
+		frame |= entities.InputRight
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyEnter) || ebiten.IsKeyPressed(ebiten.KeyNumpadEnter) || ebiten.IsKeyPressed(ebiten.KeyControlRight) {
+		frame |= entities.InputShoot
+	}
+
+	pads := ebiten.AppendGamepadIDs(nil)
+	if len(pads) == 0 || !ebiten.IsStandardGamepadLayoutAvailable(pads[0]) {
+		return frame
+	}
+	pad := pads[0]
+	x := ebiten.StandardGamepadAxisValue(pad, ebiten.StandardGamepadAxisLeftStickHorizontal)
+	y := ebiten.StandardGamepadAxisValue(pad, ebiten.StandardGamepadAxisLeftStickVertical)
+	if y < -gamepadDeadZone || ebiten.IsStandardGamepadButtonPressed(pad, ebiten.StandardGamepadButtonLeftTop) {
+		frame |= entities.InputUp
+	}
+	if y > gamepadDeadZone || ebiten.IsStandardGamepadButtonPressed(pad, ebiten.StandardGamepadButtonLeftBottom) {
+		frame |= entities.InputDown
+	}
+	if x < -gamepadDeadZone || ebiten.IsStandardGamepadButtonPressed(pad, ebiten.StandardGamepadButtonLeftLeft) {
+		frame |= entities.InputLeft
+	}
+	if x > gamepadDeadZone || ebiten.IsStandardGamepadButtonPressed(pad, ebiten.StandardGamepadButtonLeftRight) {
+		frame |= entities.InputRight
+	}
+	if ebiten.IsStandardGamepadButtonPressed(pad, ebiten.StandardGamepadButtonRightBottom) {
+		frame |= entities.InputShoot
+	}
+	return frame
+}
@@ -1,8 +1,10 @@
 package interfaces
 
 import (
-	"github.com/hajimehoshi/ebiten/v2"
 	"stellar-siege/game/entities"
+	"stellar-siege/game/systems"
+
+	"github.com/hajimehoshi/ebiten/v2"
 )
 
 // SoundManager interface for audio management
@@ -79,20 +81,18 @@ type CameraSystem interface {
 	Reset()
 }
 
-// LeaderboardManager interface for leaderboard management
+// LeaderboardManager interface for online leaderboard backends: the GitHub
+// Gist client and the self-hosted leaderboard server
 type LeaderboardManager interface {
-	SubmitScore(playerName string, score int, wave int) error
-	GetTopScores(limit int) ([]LeaderboardEntry, error)
-	IsOnline() bool
-}
+	SubmitScore(playerName, country string, score int64, difficulty string, wave int, replay *systems.Replay) error
+	GetTopScores(limit int) ([]systems.OnlineScore, error)
+	GetPilotKeys() (map[string]string, error)
+	ClearCache()
 
-// LeaderboardEntry represents a single leaderboard entry
-type LeaderboardEntry struct {
-	Rank       int
-	PlayerName string
-	Score      int
-	Wave       int
-	Timestamp  string
+	// Used by the replay verifier
+	GetAllScores() ([]systems.OnlineScore, error)
+	FetchReplay(replayID string) (*systems.Replay, error)
+	RecordVerifications(records []*systems.VerificationRecord) error
 }
 
 // AchievementManager interface for achievement tracking
@@ -0,0 +1,226 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"time"
+
+	"stellar-siege/game/systems"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// globalBoardPageSize is how many entries a page of the global leaderboard shows
+const globalBoardPageSize = 15
+
+// globalBoardDifficulties are the difficulty tabs; "" shows every difficulty
+var globalBoardDifficulties = []string{"", "Easy", "Normal", "Hard"}
+
+// globalBoardScreen is the screen browsing the whole online leaderboard
+type globalBoardScreen struct {
+	open       bool
+	loading    bool
+	results    chan globalBoardResult // Delivers the background fetch
+	scores     []systems.OnlineScore  // Every entry, highest first
+	err        string
+	difficulty int // Index into globalBoardDifficulties
+	period     systems.ScorePeriod
+	page       int
+	message    string
+}
+
+// globalBoardResult is the outcome of fetching the online leaderboard
+type globalBoardResult struct {
+	scores []systems.OnlineScore
+	err    error
+}
+
+// openGlobalBoard shows the online leaderboard, fetching it in the background
+func (g *Game) openGlobalBoard() {
+	g.globalBoard = globalBoardScreen{open: true}
+	g.fetchGlobalBoard()
+}
+
+// fetchGlobalBoard fetches every online entry and checks its signature
+func (g *Game) fetchGlobalBoard() {
+	gb := &g.globalBoard
+	if gb.loading || g.onlineLeaderboard == nil {
+		return
+	}
+	gb.loading, gb.err = true, ""
+	gb.results = make(chan globalBoardResult, 1)
+	results, leaderboard := gb.results, g.onlineLeaderboard
+	go func() {
+		scores, err := leaderboard.GetAllScores()
+		if err != nil {
+			results <- globalBoardResult{err: err}
+			return
+		}
+		keys, _ := leaderboard.GetPilotKeys()
+		systems.CheckScoreSignatures(scores, keys)
+		sort.SliceStable(scores, func(i, j int) bool {
+			return scores[i].Score > scores[j].Score
+		})
+		results <- globalBoardResult{scores: scores}
+	}()
+}
+
+// filter returns the filter of the selected tabs
+func (gb *globalBoardScreen) filter() systems.ScoreFilter {
+	return systems.ScoreFilter{Difficulty: globalBoardDifficulties[gb.difficulty], Period: gb.period}
+}
+
+// isOwnScore reports whether an entry was set on this install: signed with
+// its key, or unsigned under the active pilot's or the last run's name
+func (g *Game) isOwnScore(s *systems.OnlineScore) bool {
+	if s.PublicKey != "" {
+		return s.PublicKey == g.scorePublicKey
+	}
+	name := systems.PilotKeyName(s.PlayerName)
+	return name == systems.PilotKeyName(g.menu.Pilot) || g.playerName != "" && name == systems.PilotKeyName(g.playerName)
+}
+
+// updateGlobalBoard handles input on the online leaderboard screen
+func (g *Game) updateGlobalBoard() {
+	gb := &g.globalBoard
+	select {
+	case r := <-gb.results:
+		gb.loading = false
+		switch {
+		case r.err == nil:
+			gb.scores = r.scores
+		case gb.scores != nil:
+			gb.message = "Refresh failed: " + r.err.Error()
+		default:
+			gb.err = r.err.Error()
+		}
+	default:
+	}
+
+	entries := gb.filter().Apply(gb.scores, time.Now())
+	pages := max(1, (len(entries)+globalBoardPageSize-1)/globalBoardPageSize)
+	gb.page = min(gb.page, pages-1)
+	changed := true
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyB):
+		gb.open = false
+		return
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) || inpututil.IsKeyJustPressed(ebiten.KeyA):
+		gb.difficulty = (gb.difficulty + len(globalBoardDifficulties) - 1) % len(globalBoardDifficulties)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) || inpututil.IsKeyJustPressed(ebiten.KeyD):
+		gb.difficulty = (gb.difficulty + 1) % len(globalBoardDifficulties)
+	case inpututil.IsKeyJustPressed(ebiten.KeyT):
+		gb.period = gb.period.Next()
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || inpututil.IsKeyJustPressed(ebiten.KeyW) || inpututil.IsKeyJustPressed(ebiten.KeyPageUp):
+		gb.page = (gb.page + pages - 1) % pages
+		changed = false
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) || inpututil.IsKeyJustPressed(ebiten.KeyS) || inpututil.IsKeyJustPressed(ebiten.KeyPageDown):
+		gb.page = (gb.page + 1) % pages
+		changed = false
+	case inpututil.IsKeyJustPressed(ebiten.KeyM):
+		g.jumpToOwnRank(entries)
+		changed = false
+	case inpututil.IsKeyJustPressed(ebiten.KeyR):
+		g.onlineLeaderboard.ClearCache()
+		g.fetchGlobalBoard()
+		changed = false
+	default:
+		changed = false
+	}
+
+	// A new filter starts over on its first page
+	if changed {
+		g.sound.PlaySound(systems.SoundUIClick)
+		gb.page, gb.message = 0, ""
+	}
+}
+
+// jumpToOwnRank turns to the page holding this install's best entry
+func (g *Game) jumpToOwnRank(entries []systems.OnlineScore) {
+	gb := &g.globalBoard
+	for i := range entries {
+		if g.isOwnScore(&entries[i]) {
+			gb.page = i / globalBoardPageSize
+			gb.message = fmt.Sprintf("Your best: rank %d", i+1)
+			return
+		}
+	}
+	gb.message = "You have no score here"
+}
+
+// drawGlobalBoard renders the online leaderboard screen
+func (g *Game) drawGlobalBoard(screen *ebiten.Image) {
+	gb := &g.globalBoard
+	systems.DrawTextCentered(screen, "GLOBAL LEADERBOARD", ScreenWidth/2, 60, 3, color.RGBA{100, 200, 255, 255})
+
+	// Tabs: difficulty and period
+	difficulties := []string{"All"}
+	difficulties = append(difficulties, globalBoardDifficulties[1:]...)
+	drawTabs(screen, difficulties, gb.difficulty, 110)
+	drawTabs(screen, []string{systems.PeriodAllTime.String(), systems.PeriodToday.String(), systems.PeriodWeek.String()}, int(gb.period), 138)
+
+	switch {
+	case g.onlineLeaderboard == nil:
+		systems.DrawTextCentered(screen, "The online leaderboard is not configured", ScreenWidth/2, 300, 1.5, color.RGBA{255, 100, 100, 255})
+	case gb.loading && gb.scores == nil:
+		systems.DrawTextCentered(screen, "Loading...", ScreenWidth/2, 300, 1.5, color.RGBA{200, 200, 200, 255})
+	case gb.err != "":
+		systems.DrawTextCentered(screen, "Failed to load: "+gb.err, ScreenWidth/2, 300, 1.2, color.RGBA{255, 100, 100, 255})
+	default:
+		g.drawGlobalBoardPage(screen)
+	}
+
+	if gb.message != "" {
+		systems.DrawTextCentered(screen, gb.message, ScreenWidth/2, ScreenHeight-90, 1.2, color.RGBA{255, 220, 100, 255})
+	}
+	systems.DrawTextCentered(screen, "LEFT/RIGHT Difficulty   T Period   UP/DOWN Page   M My Rank   R Refresh   ESC Back", ScreenWidth/2, ScreenHeight-50, 1.1, color.RGBA{200, 200, 200, 255})
+}
+
+// drawGlobalBoardPage renders the current page of filtered entries
+func (g *Game) drawGlobalBoardPage(screen *ebiten.Image) {
+	gb := &g.globalBoard
+	entries := gb.filter().Apply(gb.scores, time.Now())
+	if len(entries) == 0 {
+		systems.DrawTextCentered(screen, "No scores match", ScreenWidth/2, 300, 1.5, color.RGBA{170, 170, 170, 255})
+		return
+	}
+	pages := (len(entries) + globalBoardPageSize - 1) / globalBoardPageSize
+
+	header := color.RGBA{150, 150, 150, 255}
+	y := 205
+	systems.DrawText(screen, "Rank", 100, y, 1, header)
+	systems.DrawText(screen, "Player", 170, y, 1, header)
+	systems.DrawText(screen, "Score", 400, y, 1, header)
+	systems.DrawText(screen, "Difficulty", 540, y, 1, header)
+	systems.DrawText(screen, "Wave", 690, y, 1, header)
+	systems.DrawText(screen, "Date", 790, y, 1, header)
+
+	start := gb.page * globalBoardPageSize
+	for i, s := range entries[start:min(start+globalBoardPageSize, len(entries))] {
+		y += 24
+		c := color.RGBA{200, 200, 200, 255}
+		if s.Trust != systems.ScoreVerified {
+			c = color.RGBA{140, 140, 140, 255}
+			systems.DrawText(screen, "?", 85, y, 1, color.RGBA{255, 200, 100, 255})
+		}
+		if g.isOwnScore(&s) {
+			c = color.RGBA{255, 255, 100, 255}
+		}
+		systems.DrawText(screen, systems.FormatNumber(int64(start+i+1)), 100, y, 1, c)
+		systems.DrawFlag(screen, s.Country, 145, y+1, 1)
+		systems.DrawText(screen, s.PlayerName, 170, y, 1, c)
+		systems.DrawText(screen, systems.FormatNumber(s.Score), 400, y, 1, c)
+		systems.DrawText(screen, s.Difficulty, 540, y, 1, c)
+		systems.DrawText(screen, systems.FormatNumber(int64(s.Wave)), 690, y, 1, c)
+		systems.DrawText(screen, s.Date.Local().Format("Jan 2 2006"), 790, y, 1, c)
+	}
+
+	status := fmt.Sprintf("Page %d of %d  (%d scores)", gb.page+1, pages, len(entries))
+	if gb.loading {
+		status += "  - refreshing..."
+	}
+	systems.DrawTextCentered(screen, status, ScreenWidth/2, y+34, 1.1, color.RGBA{170, 170, 170, 255})
+}
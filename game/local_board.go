@@ -0,0 +1,273 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"strings"
+
+	"stellar-siege/game/systems"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// localBoardRows is how many entries of a table fit on the screen at once
+const localBoardRows = 15
+
+// legacyDifficulty is the tab of entries saved before tables were kept per
+// difficulty
+const legacyDifficulty = ""
+
+// localBoardScreen is the screen browsing the local leaderboard tables and
+// the runs behind their entries
+type localBoardScreen struct {
+	open       bool
+	difficulty int // Index into localBoardDifficulties
+	mode       int // Index into systems.LeaderboardModes
+	selected   int
+	details    bool // Showing the selected entry's run
+	message    string
+}
+
+// localBoardDifficulties returns the difficulty tabs: the three difficulties,
+// and one for older entries if there are any
+func (g *Game) localBoardDifficulties() []string {
+	tabs := []string{"Easy", "Normal", "Hard"}
+	if len(g.leaderboard.Table(legacyDifficulty, systems.ChallengeModeEndless.String())) > 0 {
+		tabs = append(tabs, legacyDifficulty)
+	}
+	return tabs
+}
+
+// openLocalBoard shows the table of the difficulty last picked on the menu
+func (g *Game) openLocalBoard() {
+	g.localBoard = localBoardScreen{open: true, difficulty: g.menu.SelectedDifficulty}
+}
+
+// localBoardTable returns the entries of the selected table
+func (g *Game) localBoardTable() []systems.LeaderboardEntry {
+	lb := &g.localBoard
+	tabs := g.localBoardDifficulties()
+	return g.leaderboard.Table(tabs[min(lb.difficulty, len(tabs)-1)], systems.LeaderboardModes()[lb.mode])
+}
+
+// updateLocalBoard handles input on the local leaderboard screen
+func (g *Game) updateLocalBoard() {
+	lb := &g.localBoard
+	if lb.details {
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyB) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+			lb.details = false
+		}
+		return
+	}
+
+	tabs := len(g.localBoardDifficulties())
+	entries := g.localBoardTable()
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyB) || inpututil.IsKeyJustPressed(ebiten.KeyL):
+		lb.open = false
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) || inpututil.IsKeyJustPressed(ebiten.KeyA):
+		lb.difficulty = (min(lb.difficulty, tabs-1) + tabs - 1) % tabs
+		lb.selected, lb.message = 0, ""
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) || inpututil.IsKeyJustPressed(ebiten.KeyD):
+		lb.difficulty = (min(lb.difficulty, tabs-1) + 1) % tabs
+		lb.selected, lb.message = 0, ""
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab):
+		lb.mode = (lb.mode + 1) % len(systems.LeaderboardModes())
+		lb.selected, lb.message = 0, ""
+	case inpututil.IsKeyJustPressed(ebiten.KeyMinus) || inpututil.IsKeyJustPressed(ebiten.KeyEqual):
+		g.cycleLeaderboardDepth(inpututil.IsKeyJustPressed(ebiten.KeyEqual))
+	case len(entries) == 0:
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || inpututil.IsKeyJustPressed(ebiten.KeyW):
+		lb.selected = (lb.selected + len(entries) - 1) % len(entries)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) || inpututil.IsKeyJustPressed(ebiten.KeyS):
+		lb.selected = (lb.selected + 1) % len(entries)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		g.sound.PlaySound(systems.SoundUIClick)
+		lb.details = true
+	}
+	lb.selected = max(0, min(lb.selected, len(g.localBoardTable())-1))
+}
+
+// cycleLeaderboardDepth moves to the next deeper or shallower table depth
+func (g *Game) cycleLeaderboardDepth(deeper bool) {
+	depths := systems.LeaderboardDepths
+	i := 0
+	for i < len(depths)-1 && depths[i] < g.leaderboard.Depth {
+		i++
+	}
+	if deeper {
+		i = min(i+1, len(depths)-1)
+	} else {
+		i = max(i-1, 0)
+	}
+	if depths[i] == g.leaderboard.Depth {
+		return
+	}
+	g.sound.PlaySound(systems.SoundUIClick)
+	if err := g.leaderboard.SetDepth(depths[i]); err != nil {
+		log.Printf("Failed to save leaderboard: %v", err)
+	}
+	g.localBoard.message = fmt.Sprintf("Tables keep the best %d runs", depths[i])
+}
+
+// drawLocalBoard renders the local leaderboard screen
+func (g *Game) drawLocalBoard(screen *ebiten.Image) {
+	lb := &g.localBoard
+	if lb.details {
+		g.drawRunDetails(screen)
+		return
+	}
+	systems.DrawTextCentered(screen, "LOCAL LEADERBOARD", ScreenWidth/2, 60, 3, color.RGBA{255, 200, 50, 255})
+
+	var difficulties []string
+	for _, d := range g.localBoardDifficulties() {
+		if d == legacyDifficulty {
+			d = "Older"
+		}
+		difficulties = append(difficulties, d)
+	}
+	drawTabs(screen, difficulties, min(lb.difficulty, len(difficulties)-1), 110)
+	drawTabs(screen, systems.LeaderboardModes(), lb.mode, 138)
+
+	entries := g.localBoardTable()
+	if len(entries) == 0 {
+		systems.DrawTextCentered(screen, "No scores yet!", ScreenWidth/2, 300, 1.5, color.RGBA{150, 150, 150, 255})
+	} else {
+		header := color.RGBA{150, 150, 150, 255}
+		y := 185
+		systems.DrawText(screen, "Rank", 150, y, 1, header)
+		systems.DrawText(screen, "Pilot", 230, y, 1, header)
+		systems.DrawText(screen, "Score", 450, y, 1, header)
+		systems.DrawText(screen, "Wave", 600, y, 1, header)
+		systems.DrawText(screen, "Time", 690, y, 1, header)
+		systems.DrawText(screen, "Kills", 790, y, 1, header)
+		systems.DrawText(screen, "Date", 900, y, 1, header)
+
+		// Scroll so the selected entry stays on screen
+		start := max(0, min(lb.selected-localBoardRows/2, len(entries)-localBoardRows))
+		for i, e := range entries[start:min(start+localBoardRows, len(entries))] {
+			y += 24
+			c := color.RGBA{200, 200, 200, 255}
+			prefix := ""
+			if start+i == lb.selected {
+				c = color.RGBA{255, 255, 100, 255}
+				prefix = "> "
+			}
+			systems.DrawText(screen, prefix+systems.FormatNumber(int64(e.Rank)), 130, y, 1, c)
+			systems.DrawFlag(screen, e.Country, 204, y+1, 1)
+			systems.DrawText(screen, e.Name, 230, y, 1, c)
+			systems.DrawText(screen, systems.FormatNumber(e.Score), 450, y, 1, c)
+			systems.DrawText(screen, systems.FormatNumber(int64(e.Wave)), 600, y, 1, c)
+			if e.Details != nil {
+				systems.DrawText(screen, formatRunTime(e.Details.Duration), 690, y, 1, c)
+				systems.DrawText(screen, systems.FormatNumber(int64(e.Details.Kills)), 790, y, 1, c)
+			}
+			systems.DrawText(screen, e.Date.Local().Format("Jan 2 2006"), 900, y, 1, c)
+		}
+	}
+
+	if lb.message != "" {
+		systems.DrawTextCentered(screen, lb.message, ScreenWidth/2, ScreenHeight-90, 1.2, color.RGBA{255, 220, 100, 255})
+	}
+	footer := fmt.Sprintf("LEFT/RIGHT Difficulty   TAB Mode   UP/DOWN Choose   ENTER Run Details   -/+ Depth (%d)   ESC Back", g.leaderboard.Depth)
+	systems.DrawTextCentered(screen, footer, ScreenWidth/2, ScreenHeight-50, 1.1, color.RGBA{200, 200, 200, 255})
+}
+
+// drawRunDetails renders the run behind the selected leaderboard entry
+func (g *Game) drawRunDetails(screen *ebiten.Image) {
+	entries := g.localBoardTable()
+	if len(entries) == 0 {
+		return
+	}
+	e := entries[g.localBoard.selected]
+	systems.DrawTextCentered(screen, "RUN DETAILS", ScreenWidth/2, 60, 3, color.RGBA{255, 200, 50, 255})
+
+	difficulty := e.Difficulty
+	if difficulty == legacyDifficulty {
+		difficulty = "Unknown"
+	}
+	lines := []string{
+		fmt.Sprintf("Pilot       %s", e.Name),
+		fmt.Sprintf("Country     %s", countryName(e.Country)),
+		fmt.Sprintf("Score       %s  (rank %d)", systems.FormatNumber(e.Score), e.Rank),
+		fmt.Sprintf("Wave        %d", e.Wave),
+		fmt.Sprintf("Difficulty  %s, %s", difficulty, e.ModeName()),
+		fmt.Sprintf("Date        %s", e.Date.Local().Format("Jan 2 2006 15:04")),
+	}
+	if d := e.Details; d != nil {
+		var weapons []string
+		for _, w := range d.WeaponsUsed() {
+			weapons = append(weapons, fmt.Sprintf("%s (%d)", w, d.KillsByWeapon[w]))
+		}
+		death := d.DeathCause
+		if death == "" {
+			death = systems.SourceUnknown
+		}
+		lines = append(lines,
+			fmt.Sprintf("Duration    %s", formatRunTime(d.Duration)),
+			fmt.Sprintf("Kills       %d", d.Kills),
+		)
+		for i, p := range d.Pilots {
+			lines = append(lines, fmt.Sprintf("  Pilot %d   %s pts, %d kills, %d downs, %d revives",
+				i+1, systems.FormatNumber(p.Score), p.Kills, p.Downs, p.Revives))
+		}
+		lines = append(lines,
+			fmt.Sprintf("Weapons     %s", strings.Join(weapons, ", ")),
+			fmt.Sprintf("Destroyed   by %s", death),
+			fmt.Sprintf("Seed        %d", d.Seed),
+		)
+		if d.ReplayID != "" {
+			lines = append(lines, fmt.Sprintf("Replay      %s", d.ReplayID))
+		}
+		if d.ReplayFile != "" {
+			lines = append(lines, fmt.Sprintf("            %s", d.ReplayFile))
+		}
+	} else {
+		lines = append(lines, "", "This run was recorded before run details were kept.")
+	}
+
+	y := 150
+	for _, line := range lines {
+		systems.DrawText(screen, line, 200, y, 1.3, color.RGBA{200, 200, 200, 255})
+		y += 34
+	}
+	systems.DrawTextCentered(screen, "ESC Back", ScreenWidth/2, ScreenHeight-50, 1.3, color.RGBA{200, 200, 200, 255})
+}
+
+// countryName names the country a leaderboard entry was set from
+func countryName(code string) string {
+	if c, ok := systems.FindCountry(code); ok {
+		return c.Name
+	}
+	if code == "" {
+		return "Not shown"
+	}
+	return code
+}
+
+// formatRunTime formats seconds of game time as minutes and seconds
+func formatRunTime(seconds float64) string {
+	s := int(seconds)
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// drawTabs draws a row of tab labels centred on the screen, marking the
+// selected one
+func drawTabs(screen *ebiten.Image, labels []string, selected, y int) {
+	width := 0
+	for _, l := range labels {
+		width += len(l)*10 + 30
+	}
+	x := ScreenWidth/2 - width/2
+	for i, l := range labels {
+		c := color.RGBA{120, 120, 140, 255}
+		if i == selected {
+			c = color.RGBA{255, 255, 100, 255}
+			l = "[" + l + "]"
+		}
+		systems.DrawText(screen, l, x, y, 1.1, c)
+		x += len(labels[i])*10 + 30
+	}
+}
@@ -0,0 +1,142 @@
+package game
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"stellar-siege/game/systems"
+)
+
+// gameStateNames are the names reported by the state endpoint
+var gameStateNames = map[GameState]string{
+	StateMenu:     "menu",
+	StatePlaying:  "playing",
+	StatePaused:   "paused",
+	StateGameOver: "game_over",
+}
+
+// BossSnapshot describes the active boss in a GameStateSnapshot
+type BossSnapshot struct {
+	Level     int    `json:"level"`
+	Phase     string `json:"phase"`
+	Health    int    `json:"health"`
+	MaxHealth int    `json:"max_health"`
+}
+
+// GameStateSnapshot is a copy of the game state taken at the end of an
+// update, safe to read from the metrics server's goroutines
+type GameStateSnapshot struct {
+	State        string        `json:"state"`
+	Demo         bool          `json:"demo"`
+	Wave         int           `json:"wave"`
+	Score        int64         `json:"score"`
+	Multiplier   float64       `json:"multiplier"`
+	Tick         int           `json:"tick"`
+	GameTime     float64       `json:"game_time"`
+	PlayerHealth int           `json:"player_health"`
+	PlayerShield int           `json:"player_shield"`
+	Boss         *BossSnapshot `json:"boss"`
+	TimeScale    float64       `json:"time_scale"`
+	ConsoleUsed  bool          `json:"console_used"`
+}
+
+// recordFrameStats reports entity counts, pool statistics and memory to the
+// performance monitor and publishes the game state for the metrics server
+func (g *Game) recordFrameStats() {
+	// Update entity counts for monitoring
+	g.perfMon.UpdateEntityCount("player", func() int {
+		if g.player != nil && g.player.Active {
+			return 1
+		}
+		return 0
+	}())
+	g.perfMon.UpdateEntityCount("enemies", len(g.enemies))
+	g.perfMon.UpdateEntityCount("projectiles", len(g.projectiles))
+	g.perfMon.UpdateEntityCount("explosions", len(g.explosions))
+	g.perfMon.UpdateEntityCount("powerups", len(g.powerups))
+	g.perfMon.UpdateEntityCount("asteroids", len(g.asteroids))
+
+	// Update pool statistics
+	g.perfMon.UpdatePoolStats("projectiles", poolSnapshot(g.projectilePool.GetStats()))
+	g.perfMon.UpdatePoolStats("explosions", poolSnapshot(g.explosionPool.GetStats()))
+	g.perfMon.UpdatePoolStats("enemies", poolSnapshot(g.enemyPool.GetStats()))
+	g.perfMon.UpdatePoolStats("floating_texts", poolSnapshot(g.floatingTextPool.GetStats()))
+	g.perfMon.UpdatePoolStats("impacts", poolSnapshot(g.impactEffectPool.GetStats()))
+	g.perfMon.UpdatePoolStats("asteroids", poolSnapshot(g.asteroidPool.GetStats()))
+	g.perfMon.UpdatePoolStats("powerups", poolSnapshot(g.powerUpPool.GetStats()))
+
+	// Update memory stats periodically
+	g.perfMon.UpdateMemoryStats()
+
+	// Append the frame to the CSV trace when one is recording
+	g.perfMon.TraceFrame()
+
+	// Publish the game state for the /state and /metrics endpoints
+	g.publishState()
+}
+
+// publishState records a snapshot of the game state for the HTTP endpoints
+func (g *Game) publishState() {
+	s := GameStateSnapshot{
+		State:       gameStateNames[g.state],
+		Demo:        g.demoMode,
+		Wave:        g.wave,
+		Score:       g.score,
+		Multiplier:  g.multiplier,
+		Tick:        g.tick,
+		GameTime:    g.gameTime,
+		TimeScale:   1,
+		ConsoleUsed: g.consoleUsed,
+	}
+	if g.player != nil {
+		s.PlayerHealth = g.player.Health
+		s.PlayerShield = g.player.Shield
+	}
+	if g.boss != nil && g.boss.Active {
+		s.Boss = &BossSnapshot{
+			Level:     g.boss.BossLevel,
+			Phase:     g.boss.Phase.String(),
+			Health:    g.boss.Health,
+			MaxHealth: g.boss.MaxHealth,
+		}
+	}
+	if g.clock != nil {
+		s.TimeScale = g.clock.Scale
+	}
+
+	g.stateMu.Lock()
+	g.stateSnapshot = s
+	g.stateMu.Unlock()
+}
+
+// StateSnapshot returns the most recently published game state
+func (g *Game) StateSnapshot() GameStateSnapshot {
+	g.stateMu.RLock()
+	defer g.stateMu.RUnlock()
+	return g.stateSnapshot
+}
+
+// MetricsHandler serves performance metrics in the Prometheus text format
+func (g *Game) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		p := systems.NewPrometheusWriter(w)
+		g.perfMon.WritePrometheus(p)
+		p.Gauge("sound_voices", "Sounds currently playing.", float64(g.sound.GetActiveSoundCount()))
+
+		s := g.StateSnapshot()
+		p.Gauge("wave", "Wave the current run has reached.", float64(s.Wave))
+		p.Gauge("score", "Score of the current run.", float64(s.Score))
+		p.Flush()
+	})
+}
+
+// StateHandler serves the latest game state snapshot as JSON
+func (g *Game) StateHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(g.StateSnapshot()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package game
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsAndStateEndpoints(t *testing.T) {
+	g := NewHeadlessGame(5, DifficultyNormal)
+	for i := 0; i < 600 && g.Step(scriptedInput(i)); i++ {
+		g.perfMon.RecordFrame(5 * time.Millisecond)
+		g.recordFrameStats()
+	}
+
+	rec := httptest.NewRecorder()
+	g.MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE stellar_siege_frame_time_seconds histogram",
+		`stellar_siege_frame_time_seconds_bucket{le="0.008"} 600`,
+		`stellar_siege_frame_time_seconds_bucket{le="+Inf"} 600`,
+		"stellar_siege_frame_time_seconds_count 600",
+		`stellar_siege_entities{type="enemies"}`,
+		`stellar_siege_pool_reused_total{pool="projectiles"}`,
+		"stellar_siege_sound_voices 0",
+		"stellar_siege_wave ",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q", want)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	g.StateHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/state", nil))
+	var state GameStateSnapshot
+	if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.Wave != g.Wave() || state.Score != g.Score() || state.State != "playing" {
+		t.Errorf("state = %+v, want wave %d score %d playing", state, g.Wave(), g.Score())
+	}
+}
@@ -0,0 +1,156 @@
+package game
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"image/color"
+	"log"
+	"math"
+
+	"stellar-siege/game/entities"
+	"stellar-siege/game/netplay"
+	"stellar-siege/game/systems"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// netStallNotice is how many ticks a run may wait for other pilots' inputs
+// before the wait is shown
+const netStallNotice = 10
+
+// beginNetRun starts the online run the session agreed on. Every peer
+// starts from the same seed, difficulty and pilots, and from then on only
+// advances when the session hands it the next tick's inputs.
+func (g *Game) beginNetRun(s *netplay.Session) {
+	info, _ := s.Started()
+	g.demoMode = false
+	g.autopilot = nil
+	g.net = s
+	g.players = info.Players
+	g.selectedDifficulty = DifficultyMode(info.Difficulty)
+	g.startGameWithSeed(info.Seed)
+}
+
+// leaveNetRun leaves the online session, if any
+func (g *Game) leaveNetRun() {
+	if g.net == nil {
+		return
+	}
+	if err := g.net.Close(); err != nil {
+		log.Printf("Failed to close online session: %v", err)
+	}
+	g.net = nil
+}
+
+// updateNetPlaying advances an online run. There is no pausing, since the
+// other pilots' games wait for this one; ESC leaves the run instead.
+func (g *Game) updateNetPlaying() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.leaveNetRun()
+		g.transitionToState(StateMenu)
+		return
+	}
+	if !g.stepNetplay(captureInputFrame(g.keys)) {
+		if err := g.net.Err(); err != nil {
+			log.Printf("Online run ended: %v", err)
+			g.leaveNetRun()
+			g.transitionToState(StateMenu)
+			g.openNetLobby("Run ended: " + err.Error())
+		}
+	}
+}
+
+// stepNetplay hands the local pilot's input to the session and simulates
+// the next tick once every pilot's input for it has arrived. It reports
+// whether a tick was simulated.
+func (g *Game) stepNetplay(local entities.InputFrame) bool {
+	frame, ok := g.net.Step(byte(local.Pilot(0)))
+	if !ok {
+		return false
+	}
+	inputs := make([]entities.InputFrame, len(frame))
+	for i, in := range frame {
+		inputs[i] = entities.InputFrame(in)
+	}
+	g.stepSimulation(entities.CombineInputs(inputs...))
+	g.net.ReportChecksum(g.tick, g.stateChecksum())
+	return true
+}
+
+// updateNetGameOver keeps the session alive on the game over screen of an
+// online run; leaving it closes the session
+func (g *Game) updateNetGameOver() {
+	g.net.Poll()
+	if g.nameInputMode {
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace) ||
+		inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyQ) {
+		g.leaveNetRun()
+		g.transitionToState(StateMenu)
+	}
+}
+
+// drawNetOverlay tells the pilots when the run waits for someone's inputs
+func (g *Game) drawNetOverlay(screen *ebiten.Image) {
+	if g.net.Stalls() > netStallNotice {
+		systems.DrawTextCentered(screen, "Waiting for other pilots...", ScreenWidth/2, ScreenHeight/2-60, 2, color.RGBA{255, 220, 100, 255})
+	}
+}
+
+// stateChecksum hashes the parts of the simulation that decide the outcome
+// of a run. Peers of an online run compare it every tick, so any drift
+// between their games is caught the tick it happens.
+func (g *Game) stateChecksum() uint64 {
+	h := fnv.New64a()
+	var buf []byte
+	putInt := func(v int64) { buf = binary.LittleEndian.AppendUint64(buf, uint64(v)) }
+	putFloat := func(v float64) { buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(v)) }
+	putBool := func(v bool) {
+		if v {
+			buf = append(buf, 1)
+		} else {
+			buf = append(buf, 0)
+		}
+	}
+
+	putInt(int64(g.tick))
+	putInt(g.score)
+	putInt(int64(g.wave))
+	if state, err := g.rng.State(); err == nil {
+		buf = append(buf, state...)
+	}
+	for _, pl := range g.pilots {
+		s := pl.ship
+		putFloat(s.X)
+		putFloat(s.Y)
+		putInt(int64(s.Health))
+		putInt(int64(s.Shield))
+		putBool(s.Active)
+	}
+	for _, e := range g.enemies {
+		putFloat(e.X)
+		putFloat(e.Y)
+		putInt(int64(e.Health))
+		putBool(e.Active)
+	}
+	if g.boss != nil {
+		putFloat(g.boss.X)
+		putFloat(g.boss.Y)
+		putInt(int64(g.boss.Health))
+		putBool(g.boss.Active)
+	}
+	for _, p := range g.projectiles {
+		putFloat(p.X)
+		putFloat(p.Y)
+		putBool(p.Active)
+	}
+	for _, a := range g.asteroids {
+		putFloat(a.X)
+		putFloat(a.Y)
+		putInt(int64(a.Health))
+	}
+	h.Write(buf)
+	return h.Sum64()
+}
@@ -0,0 +1,759 @@
+// Package netplay runs online co-op sessions over UDP in deterministic
+// lockstep. Only the pilots' inputs travel: the host combines them into one
+// frame per tick for every peer, each peer simulates a tick once it holds
+// that frame, and exchanged state checksums end the session on a desync.
+package netplay
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Session defaults
+const (
+	DefaultPort       = 7777
+	MaxPlayers        = 4
+	DefaultInputDelay = 3 // Ticks, 50 ms at 60 ticks per second
+	MaxInputDelay     = 15
+	DefaultTimeout    = 5 * time.Second
+
+	protocolVersion byte = 1
+	resendInterval       = 100 * time.Millisecond // Between lobby and keepalive packets
+	checksumHistory      = 600                    // Ticks of own checksums kept for comparison
+)
+
+// Config describes the local peer
+type Config struct {
+	Name       string        // Pilot name shown in the lobby
+	Version    string        // Game version; every peer must run the same one
+	InputDelay int           // Host only: ticks between capturing and simulating an input
+	Timeout    time.Duration // Silence after which a peer counts as gone
+}
+
+// StartInfo is what every peer needs to start the same run
+type StartInfo struct {
+	Seed       int64
+	Difficulty int
+	Players    int
+	InputDelay int
+}
+
+// DesyncError reports that a pilot's game drifted from the host's
+type DesyncError struct {
+	Tick int // First tick whose checksums disagreed
+	Slot int // The pilot whose checksum differed from the host's
+}
+
+func (e *DesyncError) Error() string {
+	return fmt.Sprintf("pilot %d's game drifted from the host's at tick %d", e.Slot+1, e.Tick)
+}
+
+// peer is a client as the host sees it
+type peer struct {
+	addr      net.Addr
+	name      string
+	inputs    map[int]byte   // Inputs received, by tick, until they are in a frame
+	inputAck  int            // Every input up to this tick has arrived
+	frameAck  int            // The client holds every frame up to this tick
+	sums      map[int]uint64 // The client's checksums for ticks the host has not reached
+	started   bool           // The client has confirmed the start
+	lastHeard time.Time
+}
+
+// Session is one peer of an online co-op session, hosting or joined. It is
+// safe for use from the game loop while it receives in the background.
+type Session struct {
+	conn   net.PacketConn
+	cfg    Config
+	host   bool
+	server net.Addr // The host, for clients
+	done   chan struct{}
+
+	mu        sync.Mutex
+	slot      int      // The local pilot's slot; -1 until the host welcomes a client
+	names     []string // Lobby pilots by slot
+	peers     []*peer  // Host: clients by slot; slot 0 is the host itself and nil
+	started   bool
+	info      StartInfo
+	err       error
+	closed    bool
+	lastHeard time.Time // Client: last packet from the host
+	lastSent  time.Time
+	stalls    int // Consecutive Steps without a frame
+
+	tick        int            // Last tick handed out by Step
+	nextLocal   int            // Tick the next local input is scheduled for
+	lastFrame   int            // Every frame up to this tick is complete
+	frames      map[int][]byte // Complete frames not yet simulated, or not yet held by every client
+	local       map[int]byte   // Local inputs not yet in a frame (host) or acknowledged (client)
+	inputAck    int            // Client: the host holds its inputs up to this tick
+	sums        map[int]uint64 // Own checksums by tick
+	lastSumTick int
+	hostSums    map[int]uint64 // Client: the host's checksums for ticks not reached yet
+}
+
+// Host opens a session on addr, such as ":7777", for others to join
+func Host(addr string, cfg Config) (*Session, error) {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to host: %w", err)
+	}
+	return newHost(conn, cfg), nil
+}
+
+// Join connects to the session hosted at addr. DefaultPort is used when addr
+// has none. The host's answer arrives later; see Slot and Err.
+func Join(addr string, cfg Config) (*Session, error) {
+	server, err := net.ResolveUDPAddr("udp", WithDefaultPort(addr))
+	if err != nil {
+		return nil, fmt.Errorf("failed to join %s: %w", addr, err)
+	}
+	conn, err := net.ListenPacket("udp", ":0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to join %s: %w", addr, err)
+	}
+	return newClient(conn, server, cfg), nil
+}
+
+// WithDefaultPort appends DefaultPort to addr when it has no port
+func WithDefaultPort(addr string) string {
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return addr
+	}
+	return net.JoinHostPort(addr, strconv.Itoa(DefaultPort))
+}
+
+func newSession(conn net.PacketConn, cfg Config) *Session {
+	if cfg.InputDelay <= 0 {
+		cfg.InputDelay = DefaultInputDelay
+	}
+	cfg.InputDelay = min(cfg.InputDelay, MaxInputDelay)
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	cfg.Name = cleanName(cfg.Name)
+	return &Session{conn: conn, cfg: cfg, done: make(chan struct{}), lastHeard: time.Now()}
+}
+
+func newHost(conn net.PacketConn, cfg Config) *Session {
+	s := newSession(conn, cfg)
+	s.host = true
+	s.peers = []*peer{nil}
+	s.names = []string{s.cfg.Name}
+	go s.receive()
+	return s
+}
+
+func newClient(conn net.PacketConn, server net.Addr, cfg Config) *Session {
+	s := newSession(conn, cfg)
+	s.server = server
+	s.slot = -1
+	s.send(server, &packet{kind: msgHello, name: s.cfg.Name, version: s.cfg.Version})
+	s.lastSent = time.Now()
+	go s.receive()
+	return s
+}
+
+// cleanName keeps names printable and short
+func cleanName(name string) string {
+	out := make([]rune, 0, len(name))
+	for _, r := range name {
+		if r >= ' ' && r <= '~' && len(out) < maxNameLen {
+			out = append(out, r)
+		}
+	}
+	if len(out) == 0 {
+		return "Pilot"
+	}
+	return string(out)
+}
+
+// LANAddresses lists this machine's IPv4 addresses others on the local
+// network can join
+func LANAddresses() []string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, a := range addrs {
+		if ipnet, ok := a.(*net.IPNet); ok && !ipnet.IP.IsLoopback() && ipnet.IP.To4() != nil {
+			out = append(out, ipnet.IP.String())
+		}
+	}
+	return out
+}
+
+// LocalAddr returns the address the session listens on
+func (s *Session) LocalAddr() net.Addr {
+	return s.conn.LocalAddr()
+}
+
+// IsHost reports whether this peer hosts the session
+func (s *Session) IsHost() bool {
+	return s.host
+}
+
+// Slot returns the local pilot's slot, or -1 while a client waits for the
+// host's welcome
+func (s *Session) Slot() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.slot
+}
+
+// Pilots returns the names of the pilots in the session by slot
+func (s *Session) Pilots() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.names...)
+}
+
+// InputDelay returns the input delay the host uses for its next run
+func (s *Session) InputDelay() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg.InputDelay
+}
+
+// SetInputDelay changes the input delay of the host's next run
+func (s *Session) SetInputDelay(ticks int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg.InputDelay = max(1, min(ticks, MaxInputDelay))
+}
+
+// Started reports whether the run has started, and with what
+func (s *Session) Started() (StartInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.info, s.started
+}
+
+// Err returns the error that ended the session, if any
+func (s *Session) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Stalls returns how many Steps in a row have been waiting for a frame
+func (s *Session) Stalls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stalls
+}
+
+// Start begins the run for every pilot in the lobby. Only the host can
+// start, and only with at least two pilots.
+func (s *Session) Start(seed int64, difficulty int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case !s.host:
+		return errors.New("only the host can start the run")
+	case s.started:
+		return errors.New("the run has already started")
+	case s.err != nil:
+		return s.err
+	case len(s.peers) < 2:
+		return errors.New("waiting for a second pilot")
+	}
+	s.started = true
+	s.info = StartInfo{Seed: seed, Difficulty: difficulty, Players: len(s.peers), InputDelay: s.cfg.InputDelay}
+	s.beginLockstep()
+	now := time.Now()
+	for _, p := range s.peers[1:] {
+		p.lastHeard = now
+	}
+	s.sendLockstep()
+	return nil
+}
+
+// beginLockstep resets the lockstep state for a new run. The first
+// InputDelay frames are empty, since no input can reach them in time.
+func (s *Session) beginLockstep() {
+	delay := s.info.InputDelay
+	s.frames = make(map[int][]byte)
+	for t := 1; t <= delay; t++ {
+		s.frames[t] = make([]byte, s.info.Players)
+	}
+	s.tick, s.lastFrame, s.nextLocal, s.inputAck = 0, delay, delay+1, delay
+	s.local = make(map[int]byte)
+	s.sums = make(map[int]uint64)
+	s.hostSums = make(map[int]uint64)
+	s.lastSumTick = 0
+	for _, p := range s.clients() {
+		p.inputs = make(map[int]byte)
+		p.sums = make(map[int]uint64)
+		p.inputAck, p.frameAck = delay, 0
+	}
+}
+
+// Step schedules the local pilot's input and returns the next tick's frame,
+// one input per pilot by slot. It reports false while the frame has yet to
+// arrive; the caller then skips simulating and calls Step again next tick.
+func (s *Session) Step(local byte) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.started || s.err != nil || s.closed {
+		return nil, false
+	}
+	s.checkTimeouts(time.Now())
+	if s.err != nil {
+		return nil, false
+	}
+
+	// Stay at most InputDelay ticks ahead, so a stalled peer does not
+	// queue up inputs
+	if s.nextLocal <= s.tick+s.info.InputDelay+1 {
+		s.local[s.nextLocal] = local
+		s.nextLocal++
+	}
+	if s.host {
+		s.buildFrames()
+	}
+	s.sendLockstep()
+
+	frame, ok := s.frames[s.tick+1]
+	if !ok {
+		s.stalls++
+		return nil, false
+	}
+	s.stalls = 0
+	s.tick++
+	s.prune()
+	return frame, true
+}
+
+// ReportChecksum records the local state checksum after simulating tick and
+// compares it with any checksum other peers sent for that tick
+func (s *Session) ReportChecksum(tick int, sum uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.started || s.err != nil {
+		return
+	}
+	s.sums[tick] = sum
+	s.lastSumTick = tick
+	if s.host {
+		for slot, p := range s.peers {
+			if p == nil {
+				continue
+			}
+			if r, ok := p.sums[tick]; ok {
+				delete(p.sums, tick)
+				if r != sum {
+					s.fail(&DesyncError{Tick: tick, Slot: slot})
+					return
+				}
+			}
+		}
+	} else if r, ok := s.hostSums[tick]; ok {
+		delete(s.hostSums, tick)
+		if r != sum {
+			s.fail(&DesyncError{Tick: tick, Slot: s.slot})
+		}
+	}
+}
+
+// Poll keeps the session alive outside the run loop: clients repeat their
+// request to join, the host refreshes the lobby, and peers that went quiet
+// are dropped. Call it every tick while Step is not being called.
+func (s *Session) Poll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	now := time.Now()
+	s.checkTimeouts(now)
+	if s.err != nil || now.Sub(s.lastSent) < resendInterval {
+		return
+	}
+	s.lastSent = now
+	switch {
+	case s.started:
+		s.sendLockstep()
+	case s.host:
+		s.sendLobby()
+	default:
+		s.send(s.server, &packet{kind: msgHello, name: s.cfg.Name, version: s.cfg.Version})
+	}
+}
+
+// Close leaves the session. When the host closes, the session ends for
+// everyone.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	if s.err == nil {
+		// Twice, in case one is lost
+		for i := 0; i < 2; i++ {
+			if s.host {
+				s.broadcast(&packet{kind: msgAbort, reason: "the host left"})
+			} else {
+				s.send(s.server, &packet{kind: msgBye})
+			}
+		}
+	}
+	s.mu.Unlock()
+	err := s.conn.Close()
+	<-s.done
+	return err
+}
+
+// fail ends the session with err and tells the other peers why
+func (s *Session) fail(err error) {
+	if s.err != nil {
+		return
+	}
+	s.err = err
+	p := &packet{kind: msgAbort, reason: err.Error()}
+	var desync *DesyncError
+	if errors.As(err, &desync) {
+		p.desyncTick, p.desyncSlot = uint32(desync.Tick), desync.Slot
+	}
+	if s.host {
+		s.broadcast(p)
+	} else {
+		p.kind = msgBye
+		s.send(s.server, p)
+	}
+}
+
+// remoteError rebuilds the error a Bye or Abort carries
+func remoteError(p *packet) error {
+	if p.desyncTick > 0 {
+		return &DesyncError{Tick: int(p.desyncTick), Slot: p.desyncSlot}
+	}
+	return errors.New(p.reason)
+}
+
+// checkTimeouts drops peers that have gone quiet. Once the run started,
+// losing anyone ends the session.
+func (s *Session) checkTimeouts(now time.Time) {
+	if s.err != nil {
+		return
+	}
+	if !s.host {
+		if now.Sub(s.lastHeard) <= s.cfg.Timeout {
+			return
+		}
+		if s.slot < 0 {
+			s.fail(errors.New("no answer from the host"))
+		} else {
+			s.fail(errors.New("lost connection to the host"))
+		}
+		return
+	}
+	for slot := len(s.peers) - 1; slot > 0; slot-- {
+		p := s.peers[slot]
+		if now.Sub(p.lastHeard) <= s.cfg.Timeout {
+			continue
+		}
+		if s.started {
+			s.fail(fmt.Errorf("lost connection to %s", p.name))
+			return
+		}
+		s.removePeer(slot)
+	}
+}
+
+// receive reads packets until the connection closes
+func (s *Session) receive() {
+	defer close(s.done)
+	buf := make([]byte, 2048)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue // Such as an unreachable peer reported by the OS
+		}
+		p, err := decodePacket(buf[:n])
+		if err != nil {
+			continue
+		}
+		s.mu.Lock()
+		if s.host {
+			s.handleHost(p, addr)
+		} else if addr.String() == s.server.String() {
+			s.handleClient(p)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// handleHost handles a packet from a client
+func (s *Session) handleHost(p *packet, addr net.Addr) {
+	if s.closed {
+		return
+	}
+	slot := -1
+	for i, pe := range s.peers {
+		if pe != nil && pe.addr.String() == addr.String() {
+			slot = i
+			break
+		}
+	}
+	now := time.Now()
+
+	switch p.kind {
+	case msgHello:
+		if slot < 0 {
+			var reason string
+			switch {
+			case p.version != s.cfg.Version:
+				reason = fmt.Sprintf("the host runs version %s", s.cfg.Version)
+			case s.started || s.err != nil:
+				reason = "the run has already started"
+			case len(s.peers) >= MaxPlayers:
+				reason = "the session is full"
+			}
+			if reason != "" {
+				s.send(addr, &packet{kind: msgAbort, reason: reason})
+				return
+			}
+			slot = len(s.peers)
+			s.peers = append(s.peers, &peer{addr: addr, name: cleanName(p.name)})
+			s.names = append(s.names, cleanName(p.name))
+			s.sendLobby()
+		}
+		// Answered even after the start: a client whose Welcome was lost
+		// needs its slot to take part in the run
+		s.peers[slot].lastHeard = now
+		s.send(addr, &packet{kind: msgWelcome, slot: slot})
+
+	case msgInput:
+		if slot < 0 || p.slot != slot || !s.started || s.err != nil {
+			return
+		}
+		pe := s.peers[slot]
+		pe.lastHeard, pe.started = now, true
+		pe.frameAck = max(pe.frameAck, int(p.ack))
+		for i, in := range p.inputs {
+			if t := int(p.first) + i; t > pe.inputAck {
+				pe.inputs[t] = in
+			}
+		}
+		for {
+			if _, ok := pe.inputs[pe.inputAck+1]; !ok {
+				break
+			}
+			pe.inputAck++
+		}
+		for _, c := range p.checksums {
+			s.compareRemote(slot, pe.sums, c)
+		}
+		s.buildFrames()
+
+	case msgBye:
+		if slot < 0 {
+			return
+		}
+		switch {
+		case p.desyncTick > 0 || p.reason != "":
+			s.fail(remoteError(p))
+		case s.started:
+			s.fail(fmt.Errorf("%s left the run", s.peers[slot].name))
+		default:
+			s.removePeer(slot)
+		}
+	}
+}
+
+// handleClient handles a packet from the host
+func (s *Session) handleClient(p *packet) {
+	if s.closed || s.err != nil {
+		return
+	}
+	s.lastHeard = time.Now()
+
+	switch p.kind {
+	case msgWelcome:
+		if !s.started {
+			s.slot = p.slot
+		}
+	case msgLobby:
+		if !s.started {
+			s.names = p.names
+		}
+	case msgStart:
+		if s.started || s.slot <= 0 || p.players <= s.slot || p.players > MaxPlayers || p.delay < 1 {
+			return
+		}
+		s.started = true
+		s.info = StartInfo{Seed: p.seed, Difficulty: p.difficulty, Players: p.players, InputDelay: p.delay}
+		s.beginLockstep()
+	case msgFrames:
+		if !s.started || p.players != s.info.Players {
+			return
+		}
+		if ack := int(p.ack); ack > s.inputAck {
+			for t := s.inputAck + 1; t <= ack; t++ {
+				delete(s.local, t)
+			}
+			s.inputAck = ack
+		}
+		n := s.info.Players
+		for i := 0; (i+1)*n <= len(p.inputs); i++ {
+			t := int(p.first) + i
+			if _, ok := s.frames[t]; !ok && t > s.lastFrame {
+				s.frames[t] = append([]byte(nil), p.inputs[i*n:(i+1)*n]...)
+			}
+		}
+		for {
+			if _, ok := s.frames[s.lastFrame+1]; !ok {
+				break
+			}
+			s.lastFrame++
+		}
+		for _, c := range p.checksums {
+			s.compareRemote(s.slot, s.hostSums, c)
+		}
+	case msgAbort:
+		s.err = remoteError(p)
+	}
+}
+
+// compareRemote checks another peer's checksum against the local one, or
+// keeps it in pending until the local simulation reaches its tick
+func (s *Session) compareRemote(slot int, pending map[int]uint64, c checksum) {
+	t := int(c.tick)
+	if own, ok := s.sums[t]; ok {
+		if own != c.sum {
+			s.fail(&DesyncError{Tick: t, Slot: slot})
+		}
+		return
+	}
+	if t > s.lastSumTick {
+		pending[t] = c.sum
+	}
+}
+
+// buildFrames completes every frame whose inputs have all arrived
+func (s *Session) buildFrames() {
+	for {
+		t := s.lastFrame + 1
+		own, ok := s.local[t]
+		if !ok {
+			return
+		}
+		for _, p := range s.peers[1:] {
+			if _, ok := p.inputs[t]; !ok {
+				return
+			}
+		}
+		frame := make([]byte, len(s.peers))
+		frame[0] = own
+		delete(s.local, t)
+		for slot, p := range s.peers[1:] {
+			frame[slot+1] = p.inputs[t]
+			delete(p.inputs, t)
+		}
+		s.frames[t] = frame
+		s.lastFrame = t
+	}
+}
+
+// prune forgets frames every peer has simulated or holds, and old checksums
+func (s *Session) prune() {
+	keep := s.tick
+	if s.host {
+		for _, p := range s.peers[1:] {
+			keep = min(keep, p.frameAck)
+		}
+	}
+	for t := range s.frames {
+		if t <= keep {
+			delete(s.frames, t)
+		}
+	}
+	if old := s.tick - checksumHistory; old > 0 {
+		delete(s.sums, old)
+	}
+}
+
+// latestChecksums returns the checksums of the last few ticks
+func (s *Session) latestChecksums() []checksum {
+	var out []checksum
+	for t := max(1, s.lastSumTick-checksumsPerPacket+1); t <= s.lastSumTick; t++ {
+		if sum, ok := s.sums[t]; ok {
+			out = append(out, checksum{tick: uint32(t), sum: sum})
+		}
+	}
+	return out
+}
+
+// sendLockstep sends the host's frames to every client, or a client's
+// unacknowledged inputs to the host. Both repeat until acknowledged, so
+// lost packets only cost a little latency.
+func (s *Session) sendLockstep() {
+	sums := s.latestChecksums()
+	if !s.host {
+		p := &packet{kind: msgInput, slot: s.slot, ack: uint32(s.lastFrame), first: uint32(s.inputAck + 1), checksums: sums}
+		for t := s.inputAck + 1; t < s.nextLocal && len(p.inputs) < maxInputsPerPacket; t++ {
+			p.inputs = append(p.inputs, s.local[t])
+		}
+		s.send(s.server, p)
+		return
+	}
+
+	start := &packet{kind: msgStart, seed: s.info.Seed, difficulty: s.info.Difficulty, players: s.info.Players, delay: s.info.InputDelay}
+	for _, pe := range s.peers[1:] {
+		if !pe.started {
+			s.send(pe.addr, start)
+		}
+		p := &packet{kind: msgFrames, players: s.info.Players, ack: uint32(pe.inputAck), first: uint32(pe.frameAck + 1), checksums: sums}
+		for t := pe.frameAck + 1; t <= s.lastFrame && t <= pe.frameAck+maxFramesPerPacket; t++ {
+			p.inputs = append(p.inputs, s.frames[t]...)
+		}
+		s.send(pe.addr, p)
+	}
+}
+
+// sendLobby tells every client who is in the lobby
+func (s *Session) sendLobby() {
+	s.broadcast(&packet{kind: msgLobby, names: s.names})
+}
+
+// removePeer drops a client from the lobby; the clients after it move up a
+// slot
+func (s *Session) removePeer(slot int) {
+	s.peers = append(s.peers[:slot], s.peers[slot+1:]...)
+	s.names = append(s.names[:slot], s.names[slot+1:]...)
+	for i, p := range s.peers[1:] {
+		s.send(p.addr, &packet{kind: msgWelcome, slot: i + 1})
+	}
+	s.sendLobby()
+}
+
+// clients returns the host's clients; none for a client
+func (s *Session) clients() []*peer {
+	if len(s.peers) == 0 {
+		return nil
+	}
+	return s.peers[1:]
+}
+
+func (s *Session) broadcast(p *packet) {
+	for _, pe := range s.clients() {
+		s.send(pe.addr, p)
+	}
+}
+
+// send writes a packet; UDP is best-effort, so errors are left to the
+// timeouts
+func (s *Session) send(addr net.Addr, p *packet) {
+	s.conn.WriteTo(p.encode(nil), addr)
+}
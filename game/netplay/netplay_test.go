@@ -0,0 +1,306 @@
+package netplay
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// toySim is a tiny deterministic simulation driven by frames
+type toySim struct {
+	tick  int
+	state uint64
+}
+
+func (t *toySim) step(frame []byte) uint64 {
+	t.tick++
+	for i, in := range frame {
+		t.state = t.state*1099511628211 + uint64(in)<<uint(i*8) + 1
+	}
+	return t.state
+}
+
+// lossyConn drops every nth packet it sends
+type lossyConn struct {
+	net.PacketConn
+	n     int64
+	count atomic.Int64
+}
+
+func (c *lossyConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	if c.count.Add(1)%c.n == 0 {
+		return len(p), nil
+	}
+	return c.PacketConn.WriteTo(p, addr)
+}
+
+// welcomeDropper drops every Welcome it sends until let is set
+type welcomeDropper struct {
+	net.PacketConn
+	let *atomic.Bool
+}
+
+func (c *welcomeDropper) WriteTo(p []byte, addr net.Addr) (int, error) {
+	if pk, err := decodePacket(p); err == nil && pk.kind == msgWelcome && !c.let.Load() {
+		return len(p), nil
+	}
+	return c.PacketConn.WriteTo(p, addr)
+}
+
+func listen(t *testing.T) net.PacketConn {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("no loopback UDP: %v", err)
+	}
+	return conn
+}
+
+// lobby hosts a session and joins it with clients more peers
+func lobby(t *testing.T, clients int, wrap func(net.PacketConn) net.PacketConn) []*Session {
+	t.Helper()
+	cfg := Config{Name: "HOST", Version: "1.0", Timeout: 2 * time.Second}
+	host := newHost(wrap(listen(t)), cfg)
+	sessions := []*Session{host}
+	for i := 0; i < clients; i++ {
+		cfg.Name = "GUEST"
+		sessions = append(sessions, newClient(wrap(listen(t)), host.LocalAddr(), cfg))
+	}
+	t.Cleanup(func() {
+		for _, s := range sessions {
+			s.Close()
+		}
+	})
+
+	waitFor(t, "clients to be welcomed", sessions, func() bool {
+		return len(host.Pilots()) == clients+1
+	})
+	return sessions
+}
+
+// waitFor polls every session until done holds
+func waitFor(t *testing.T, what string, sessions []*Session, done func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		for _, s := range sessions {
+			s.Poll()
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// run steps every peer's toy simulation until each reached ticks, using
+// input(slot, tick) as the local input. sabotage changes a peer's checksum.
+func run(t *testing.T, sessions []*Session, ticks int, input func(slot, tick int) byte, sabotage func(slot, tick int) bool) []*toySim {
+	t.Helper()
+	sims := make([]*toySim, len(sessions))
+	for i := range sims {
+		sims[i] = &toySim{}
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		finished := true
+		for i, s := range sessions {
+			if s.Err() != nil {
+				return sims
+			}
+			if sims[i].tick >= ticks {
+				s.Poll()
+				continue
+			}
+			finished = false
+			frame, ok := s.Step(input(i, sims[i].tick))
+			if !ok {
+				continue
+			}
+			sum := sims[i].step(frame)
+			if sabotage != nil && sabotage(i, sims[i].tick) {
+				sum++
+			}
+			s.ReportChecksum(sims[i].tick, sum)
+		}
+		if finished {
+			return sims
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("peers stuck at ticks %d and %d", sims[0].tick, sims[1].tick)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func startRun(t *testing.T, sessions []*Session) {
+	t.Helper()
+	if err := sessions[0].Start(42, 2); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "the start", sessions, func() bool {
+		for _, s := range sessions {
+			if _, ok := s.Started(); !ok {
+				return false
+			}
+		}
+		return true
+	})
+	for _, s := range sessions[1:] {
+		if info, _ := s.Started(); info.Seed != 42 || info.Difficulty != 2 || info.Players != len(sessions) || info.InputDelay != DefaultInputDelay {
+			t.Fatalf("client started with %+v", info)
+		}
+	}
+}
+
+func TestLoopbackPeersStayInLockstep(t *testing.T) {
+	sessions := lobby(t, 2, func(c net.PacketConn) net.PacketConn { return c })
+	if got := sessions[0].Pilots(); !reflect.DeepEqual(got, []string{"HOST", "GUEST", "GUEST"}) {
+		t.Fatalf("lobby = %v", got)
+	}
+	for i, s := range sessions {
+		if s.Slot() != i {
+			t.Fatalf("peer %d got slot %d", i, s.Slot())
+		}
+	}
+	startRun(t, sessions)
+
+	input := func(slot, tick int) byte { return byte(slot*31 + tick) }
+	sims := run(t, sessions, 300, input, nil)
+	for i, s := range sessions {
+		if err := s.Err(); err != nil {
+			t.Fatalf("peer %d: %v", i, err)
+		}
+		if sims[i].state != sims[0].state {
+			t.Errorf("peer %d ended in state %x, host in %x", i, sims[i].state, sims[0].state)
+		}
+	}
+
+	// The first ticks carry no input, then each pilot's input lands
+	// InputDelay ticks after it was captured
+	probe := &toySim{}
+	for tick := 1; tick <= 300; tick++ {
+		frame := make([]byte, 3)
+		if tick > DefaultInputDelay {
+			for slot := range frame {
+				frame[slot] = input(slot, tick-DefaultInputDelay-1)
+			}
+		}
+		probe.step(frame)
+	}
+	if probe.state != sims[0].state {
+		t.Error("frames do not hold the inputs InputDelay ticks later")
+	}
+}
+
+func TestLockstepSurvivesPacketLoss(t *testing.T) {
+	sessions := lobby(t, 1, func(c net.PacketConn) net.PacketConn { return &lossyConn{PacketConn: c, n: 4} })
+	startRun(t, sessions)
+	sims := run(t, sessions, 200, func(slot, tick int) byte { return byte(tick * (slot + 3)) }, nil)
+	if err := sessions[1].Err(); err != nil {
+		t.Fatal(err)
+	}
+	if sims[0].state != sims[1].state {
+		t.Errorf("states %x and %x differ after packet loss", sims[0].state, sims[1].state)
+	}
+}
+
+func TestClientWelcomedAfterTheStart(t *testing.T) {
+	// The host starts before the client learned its slot
+	var let atomic.Bool
+	sessions := lobby(t, 1, func(c net.PacketConn) net.PacketConn { return &welcomeDropper{PacketConn: c, let: &let} })
+	if slot := sessions[1].Slot(); slot > 0 {
+		t.Fatalf("client got slot %d through a dropped Welcome", slot)
+	}
+	if err := sessions[0].Start(42, 2); err != nil {
+		t.Fatal(err)
+	}
+	let.Store(true)
+	waitFor(t, "the client to start", sessions, func() bool {
+		_, ok := sessions[1].Started()
+		return ok
+	})
+	sims := run(t, sessions, 100, func(slot, tick int) byte { return byte(tick + slot) }, nil)
+	if err := sessions[1].Err(); err != nil {
+		t.Fatal(err)
+	}
+	if sims[0].state != sims[1].state {
+		t.Errorf("states %x and %x differ", sims[0].state, sims[1].state)
+	}
+}
+
+func TestDesyncIsDetected(t *testing.T) {
+	sessions := lobby(t, 1, func(c net.PacketConn) net.PacketConn { return c })
+	startRun(t, sessions)
+	run(t, sessions, 200, func(slot, tick int) byte { return byte(tick) }, func(slot, tick int) bool {
+		return slot == 1 && tick == 50
+	})
+	waitFor(t, "both peers to notice", sessions, func() bool {
+		return sessions[0].Err() != nil && sessions[1].Err() != nil
+	})
+	for i, s := range sessions {
+		var desync *DesyncError
+		if !errors.As(s.Err(), &desync) || desync.Tick != 50 || desync.Slot != 1 {
+			t.Errorf("peer %d ended with %v, want a desync of pilot 2 at tick 50", i, s.Err())
+		}
+	}
+}
+
+func TestHostTurnsAwayMismatchedVersions(t *testing.T) {
+	sessions := lobby(t, 0, func(c net.PacketConn) net.PacketConn { return c })
+	client := newClient(listen(t), sessions[0].LocalAddr(), Config{Name: "OLD", Version: "0.9"})
+	defer client.Close()
+	waitFor(t, "the refusal", []*Session{sessions[0], client}, func() bool { return client.Err() != nil })
+	if !strings.Contains(client.Err().Error(), "version 1.0") {
+		t.Errorf("refused with %v", client.Err())
+	}
+	if err := sessions[0].Start(1, 1); err == nil {
+		t.Error("host started alone")
+	}
+}
+
+func TestPacketRoundTrip(t *testing.T) {
+	packets := []*packet{
+		{kind: msgHello, name: "ACE", version: "1.2.0"},
+		{kind: msgWelcome, slot: 3},
+		{kind: msgLobby, names: []string{"ACE", "BOB"}},
+		{kind: msgStart, seed: -7, difficulty: 2, players: 4, delay: 5},
+		{kind: msgInput, slot: 2, ack: 9, first: 11, inputs: []byte{1, 2, 3}, checksums: []checksum{{tick: 8, sum: 1 << 60}}},
+		{kind: msgFrames, players: 2, ack: 12, first: 4, inputs: []byte{5, 6, 7, 8}},
+		{kind: msgAbort, reason: "desync", desyncTick: 77, desyncSlot: 1},
+	}
+	for _, want := range packets {
+		got, err := decodePacket(want.encode(nil))
+		if err != nil {
+			t.Fatalf("kind %d: %v", want.kind, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("round trip gave %+v, want %+v", got, want)
+		}
+	}
+
+	data := packets[4].encode(nil)
+	if _, err := decodePacket(data[:len(data)-3]); err == nil {
+		t.Error("truncated packet decoded")
+	}
+	if _, err := decodePacket([]byte("hello world")); err == nil {
+		t.Error("stray datagram decoded")
+	}
+}
+
+func TestWithDefaultPort(t *testing.T) {
+	for in, want := range map[string]string{
+		"192.168.1.4":      "192.168.1.4:7777",
+		"192.168.1.4:9000": "192.168.1.4:9000",
+		"::1":              "[::1]:7777",
+		"example.com":      "example.com:7777",
+	} {
+		if got := WithDefaultPort(in); got != want {
+			t.Errorf("WithDefaultPort(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package netplay
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Every packet starts with the magic bytes, the protocol version and the
+// message type
+var packetMagic = [4]byte{'S', 'S', 'N', 'P'}
+
+const headerSize = len(packetMagic) + 2
+
+// Message types
+const (
+	msgHello   byte = iota + 1 // Client asks to join, or keeps its lobby seat
+	msgWelcome                 // Host assigns the client its slot
+	msgLobby                   // Host lists the pilots in the lobby
+	msgStart                   // Host starts the run
+	msgInput                   // Client sends its inputs and checksums
+	msgFrames                  // Host relays every pilot's inputs and its checksums
+	msgBye                     // Client leaves the session, with the reason
+	msgAbort                   // Host ends the session for everyone, with the reason
+)
+
+// Limits that keep a packet well under a typical MTU
+const (
+	maxNameLen         = 20
+	maxInputsPerPacket = 120 // Inputs a client resends until the host acknowledges them
+	maxFramesPerPacket = 60  // Frames the host resends until a client acknowledges them
+	checksumsPerPacket = 8   // Latest checksums repeated in every packet
+)
+
+var errMalformed = errors.New("malformed packet")
+
+// checksum is the state checksum of one simulated tick
+type checksum struct {
+	tick uint32
+	sum  uint64
+}
+
+// packet is a decoded message; only the fields of its type are set
+type packet struct {
+	kind byte
+
+	name    string   // Hello
+	version string   // Hello: the client's game version
+	slot    int      // Welcome, Input
+	names   []string // Lobby
+	reason  string   // Bye, Abort
+
+	// Bye and Abort after a desync: the tick it was found at and the slot
+	// of the pilot whose game drifted; tick 0 when there was none
+	desyncTick uint32
+	desyncSlot int
+
+	// Start
+	seed       int64
+	difficulty int
+	players    int
+	delay      int
+
+	// Input and Frames: the first tick of the inputs or frames, the sender's
+	// acknowledgement of the other side's, and its latest checksums
+	first     uint32
+	ack       uint32
+	inputs    []byte // Input: one per tick; Frames: players per tick
+	checksums []checksum
+}
+
+// encode appends the packet to buf
+func (p *packet) encode(buf []byte) []byte {
+	buf = append(buf, packetMagic[:]...)
+	buf = append(buf, protocolVersion, p.kind)
+	switch p.kind {
+	case msgHello:
+		buf = appendString(buf, p.name)
+		buf = appendString(buf, p.version)
+	case msgWelcome:
+		buf = append(buf, byte(p.slot))
+	case msgLobby:
+		buf = append(buf, byte(len(p.names)))
+		for _, n := range p.names {
+			buf = appendString(buf, n)
+		}
+	case msgStart:
+		buf = binary.BigEndian.AppendUint64(buf, uint64(p.seed))
+		buf = append(buf, byte(p.difficulty), byte(p.players), byte(p.delay))
+	case msgInput, msgFrames:
+		if p.kind == msgInput {
+			buf = append(buf, byte(p.slot))
+		} else {
+			buf = append(buf, byte(p.players))
+		}
+		buf = binary.BigEndian.AppendUint32(buf, p.ack)
+		buf = binary.BigEndian.AppendUint32(buf, p.first)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(p.inputs)))
+		buf = append(buf, p.inputs...)
+		buf = append(buf, byte(len(p.checksums)))
+		for _, c := range p.checksums {
+			buf = binary.BigEndian.AppendUint32(buf, c.tick)
+			buf = binary.BigEndian.AppendUint64(buf, c.sum)
+		}
+	case msgBye, msgAbort:
+		buf = appendString(buf, p.reason)
+		buf = binary.BigEndian.AppendUint32(buf, p.desyncTick)
+		buf = append(buf, byte(p.desyncSlot))
+	}
+	return buf
+}
+
+// decodePacket parses a packet produced by encode
+func decodePacket(data []byte) (*packet, error) {
+	if len(data) < headerSize || [4]byte(data[:4]) != packetMagic {
+		return nil, errMalformed
+	}
+	if data[4] != protocolVersion {
+		return nil, errors.New("different protocol version")
+	}
+	r := reader{data: data[headerSize:]}
+	p := &packet{kind: data[5]}
+	switch p.kind {
+	case msgHello:
+		p.name = r.string()
+		p.version = r.string()
+	case msgWelcome:
+		p.slot = int(r.byte())
+	case msgLobby:
+		n := int(r.byte())
+		for i := 0; i < n && r.err == nil; i++ {
+			p.names = append(p.names, r.string())
+		}
+	case msgStart:
+		p.seed = int64(r.uint64())
+		p.difficulty = int(r.byte())
+		p.players = int(r.byte())
+		p.delay = int(r.byte())
+	case msgInput, msgFrames:
+		if p.kind == msgInput {
+			p.slot = int(r.byte())
+		} else {
+			p.players = int(r.byte())
+		}
+		p.ack = r.uint32()
+		p.first = r.uint32()
+		p.inputs = r.bytes(int(r.uint16()))
+		n := int(r.byte())
+		for i := 0; i < n && r.err == nil; i++ {
+			p.checksums = append(p.checksums, checksum{tick: r.uint32(), sum: r.uint64()})
+		}
+	case msgBye, msgAbort:
+		p.reason = r.string()
+		p.desyncTick = r.uint32()
+		p.desyncSlot = int(r.byte())
+	default:
+		return nil, errMalformed
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return p, nil
+}
+
+// appendString appends a length-prefixed string, cut to 255 bytes
+func appendString(buf []byte, s string) []byte {
+	if len(s) > 255 {
+		s = s[:255]
+	}
+	buf = append(buf, byte(len(s)))
+	return append(buf, s...)
+}
+
+// reader reads big-endian fields, remembering the first error
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil || n > len(r.data) {
+		r.err = errMalformed
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) byte() byte {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) uint16() uint16 {
+	if b := r.bytes(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *reader) uint32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) uint64() uint64 {
+	if b := r.bytes(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (r *reader) string() string {
+	return string(r.bytes(int(r.byte())))
+}
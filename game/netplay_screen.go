@@ -0,0 +1,211 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"time"
+
+	"stellar-siege/game/netplay"
+	"stellar-siege/game/systems"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// netLobbyMode is what the online co-op lobby is doing
+type netLobbyMode int
+
+const (
+	netLobbyChoosing netLobbyMode = iota // Choosing to host or join
+	netLobbyAddress                      // Typing the host's address
+	netLobbyWaiting                      // In a session, waiting for the run to start
+)
+
+// maxNetAddressLen bounds the typed host address
+const maxNetAddressLen = 60
+
+// netLobbyScreen hosts or joins an online co-op session. The host sees the
+// addresses others can join, the pilots who did and the run settings;
+// pilots who joined wait there until the host starts.
+type netLobbyScreen struct {
+	open       bool
+	mode       netLobbyMode
+	address    string
+	message    string // The last error or status
+	session    *netplay.Session
+	difficulty DifficultyMode
+	lan        []string // This machine's addresses, for the host to share
+}
+
+// openNetLobby opens the online co-op lobby with an optional message, such
+// as why the last online run ended
+func (g *Game) openNetLobby(message string) {
+	g.netLobby = netLobbyScreen{
+		open:       true,
+		message:    message,
+		address:    g.netLobby.address, // Remember the last host joined
+		difficulty: g.selectedDifficulty,
+	}
+}
+
+// netConfig describes the local pilot to the session
+func (g *Game) netConfig() netplay.Config {
+	name := systems.DefaultProfileName
+	if g.profiles != nil {
+		name = g.profiles.Active().Name
+	}
+	return netplay.Config{Name: name, Version: Version}
+}
+
+// updateNetLobby handles input on the online co-op lobby
+func (g *Game) updateNetLobby() {
+	nl := &g.netLobby
+	switch nl.mode {
+	case netLobbyChoosing:
+		switch {
+		case inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyB):
+			nl.open = false
+		case inpututil.IsKeyJustPressed(ebiten.KeyH):
+			g.sound.PlaySound(systems.SoundUIClick)
+			s, err := netplay.Host(fmt.Sprintf(":%d", netplay.DefaultPort), g.netConfig())
+			if err != nil {
+				nl.message = err.Error()
+				return
+			}
+			nl.session, nl.mode, nl.message = s, netLobbyWaiting, ""
+			nl.lan = netplay.LANAddresses()
+		case inpututil.IsKeyJustPressed(ebiten.KeyJ):
+			g.sound.PlaySound(systems.SoundUIClick)
+			nl.mode, nl.message = netLobbyAddress, ""
+		}
+
+	case netLobbyAddress:
+		for _, r := range ebiten.AppendInputChars(nil) {
+			if len(nl.address) < maxNetAddressLen && r > ' ' && r <= '~' {
+				nl.address += string(r)
+			}
+		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(nl.address) > 0 {
+			nl.address = nl.address[:len(nl.address)-1]
+		}
+		switch {
+		case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+			nl.mode = netLobbyChoosing
+		case inpututil.IsKeyJustPressed(ebiten.KeyEnter) && nl.address != "":
+			g.sound.PlaySound(systems.SoundUIClick)
+			s, err := netplay.Join(nl.address, g.netConfig())
+			if err != nil {
+				nl.message = err.Error()
+				return
+			}
+			nl.session, nl.mode, nl.message = s, netLobbyWaiting, ""
+		}
+
+	case netLobbyWaiting:
+		g.updateNetLobbySession()
+	}
+}
+
+// updateNetLobbySession keeps the lobby's session going until the run
+// starts, and lets the host pick the run settings
+func (g *Game) updateNetLobbySession() {
+	nl := &g.netLobby
+	s := nl.session
+	s.Poll()
+
+	if err := s.Err(); err != nil || inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.Close()
+		nl.session, nl.mode = nil, netLobbyChoosing
+		if err != nil {
+			nl.message = err.Error()
+		}
+		return
+	}
+	if _, started := s.Started(); started {
+		nl.open, nl.session = false, nil
+		g.beginNetRun(s)
+		return
+	}
+	if !s.IsHost() {
+		return
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) || inpututil.IsKeyJustPressed(ebiten.KeyA):
+		nl.difficulty = max(DifficultyEasy, nl.difficulty-1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) || inpututil.IsKeyJustPressed(ebiten.KeyD):
+		nl.difficulty = min(DifficultyHard, nl.difficulty+1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyMinus) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadSubtract):
+		s.SetInputDelay(s.InputDelay() - 1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEqual) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadAdd):
+		s.SetInputDelay(s.InputDelay() + 1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		if err := s.Start(time.Now().UnixNano(), int(nl.difficulty)); err != nil {
+			nl.message = err.Error()
+			return
+		}
+		g.sound.PlaySound(systems.SoundUIClick)
+		nl.open, nl.session = false, nil
+		g.beginNetRun(s)
+	}
+}
+
+// drawNetLobby renders the online co-op lobby
+func (g *Game) drawNetLobby(screen *ebiten.Image) {
+	nl := &g.netLobby
+	white := color.RGBA{255, 255, 255, 255}
+	grey := color.RGBA{170, 170, 170, 255}
+	systems.DrawTextCentered(screen, "ONLINE CO-OP", ScreenWidth/2, 110, 3, color.RGBA{100, 200, 255, 255})
+
+	y := 200
+	switch nl.mode {
+	case netLobbyChoosing:
+		systems.DrawTextCentered(screen, "Press H to Host a Run", ScreenWidth/2, y, 2, white)
+		systems.DrawTextCentered(screen, "Press J to Join a Run", ScreenWidth/2, y+50, 2, white)
+		systems.DrawTextCentered(screen, fmt.Sprintf("Up to %d pilots. Hosts need UDP port %d open.", netplay.MaxPlayers, netplay.DefaultPort), ScreenWidth/2, y+120, 1.3, grey)
+		systems.DrawTextCentered(screen, "ESC to go back", ScreenWidth/2, ScreenHeight-60, 1.5, grey)
+
+	case netLobbyAddress:
+		systems.DrawTextCentered(screen, "Host address (IP, optionally with :port):", ScreenWidth/2, y, 2, white)
+		systems.DrawTextCentered(screen, nl.address+"_", ScreenWidth/2, y+50, 3, color.RGBA{100, 255, 100, 255})
+		systems.DrawTextCentered(screen, "ENTER to join, ESC to go back", ScreenWidth/2, ScreenHeight-60, 1.5, grey)
+
+	case netLobbyWaiting:
+		s := nl.session
+		if s.IsHost() {
+			addrs := "no network found"
+			if len(nl.lan) > 0 {
+				addrs = strings.Join(nl.lan, ", ")
+			}
+			systems.DrawTextCentered(screen, fmt.Sprintf("Hosting on port %d - others join %s", netplay.DefaultPort, addrs), ScreenWidth/2, y-30, 1.5, white)
+		} else if s.Slot() < 0 {
+			systems.DrawTextCentered(screen, "Connecting to "+netplay.WithDefaultPort(nl.address)+"...", ScreenWidth/2, y-30, 1.5, white)
+		} else {
+			systems.DrawTextCentered(screen, "Waiting for the host to start the run...", ScreenWidth/2, y-30, 1.5, white)
+		}
+
+		for i, name := range s.Pilots() {
+			label := fmt.Sprintf("P%d  %s", i+1, name)
+			if i == 0 {
+				label += "  (host)"
+			}
+			if i == s.Slot() {
+				label += "  - you"
+			}
+			systems.DrawTextCentered(screen, label, ScreenWidth/2, y+20+i*34, 1.8, pilotColors[i%len(pilotColors)])
+		}
+
+		if s.IsHost() {
+			settings := fmt.Sprintf("< %s >   Input delay: %d ticks (-/+)", DifficultyLabel(nl.difficulty), s.InputDelay())
+			systems.DrawTextCentered(screen, settings, ScreenWidth/2, y+200, 1.5, white)
+			systems.DrawTextCentered(screen, "ENTER to start, ESC to close the session", ScreenWidth/2, ScreenHeight-60, 1.5, grey)
+		} else {
+			systems.DrawTextCentered(screen, "ESC to leave", ScreenWidth/2, ScreenHeight-60, 1.5, grey)
+		}
+	}
+
+	if nl.message != "" {
+		systems.DrawTextCentered(screen, nl.message, ScreenWidth/2, ScreenHeight-130, 1.4, color.RGBA{255, 100, 100, 255})
+	}
+}
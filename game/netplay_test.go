@@ -0,0 +1,92 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"stellar-siege/game/entities"
+	"stellar-siege/game/netplay"
+)
+
+func TestOnlineRunStaysInSyncOverLoopback(t *testing.T) {
+	cfg := netplay.Config{Name: "HOST", Version: Version}
+	host, err := netplay.Host("127.0.0.1:0", cfg)
+	if err != nil {
+		t.Skipf("no loopback UDP: %v", err)
+	}
+	defer host.Close()
+	cfg.Name = "GUEST"
+	client, err := netplay.Join(host.LocalAddr().String(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for client.Slot() != 1 || host.Start(5, int(DifficultyHard)) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("client never joined")
+		}
+		client.Poll()
+		time.Sleep(5 * time.Millisecond)
+	}
+	for _, started := client.Started(); !started; _, started = client.Started() {
+		if time.Now().After(deadline) {
+			t.Fatal("client never started")
+		}
+		client.Poll()
+		host.Poll()
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	games := []*Game{NewHeadlessGame(1, DifficultyNormal), NewHeadlessGame(2, DifficultyNormal)}
+	games[0].beginNetRun(host)
+	games[1].beginNetRun(client)
+	for _, g := range games {
+		if len(g.pilots) != 2 || g.seed != 5 || g.selectedDifficulty != DifficultyHard {
+			t.Fatalf("online run started with %d pilots, seed %d", len(g.pilots), g.seed)
+		}
+	}
+
+	// The host weaves and shoots, the guest holds fire
+	inputs := []func(tick int) entities.InputFrame{
+		func(tick int) entities.InputFrame {
+			if tick/40%2 == 0 {
+				return entities.InputLeft | entities.InputShoot
+			}
+			return entities.InputRight | entities.InputShoot
+		},
+		func(int) entities.InputFrame { return entities.InputShoot },
+	}
+	const ticks = 900
+	deadline = time.Now().Add(20 * time.Second)
+	running := func(g *Game) bool { return g.Tick() < ticks && g.state == StatePlaying }
+	for running(games[0]) || running(games[1]) {
+		if time.Now().After(deadline) {
+			t.Fatalf("online run stuck at ticks %d and %d", games[0].Tick(), games[1].Tick())
+		}
+		for i, g := range games {
+			if err := g.net.Err(); err != nil {
+				t.Fatalf("peer %d: %v", i, err)
+			}
+			if running(g) {
+				g.stepNetplay(inputs[i](g.Tick()))
+			} else {
+				g.net.Poll() // Keep relaying for a peer that is behind
+			}
+		}
+		time.Sleep(200 * time.Microsecond)
+	}
+
+	if games[0].Tick() != games[1].Tick() || games[0].stateChecksum() != games[1].stateChecksum() {
+		t.Errorf("peers drifted apart: scores %d and %d", games[0].Score(), games[1].Score())
+	}
+	if games[0].Score() == 0 {
+		t.Error("no pilot scored, so the run proves little")
+	}
+
+	// Both recorded the same co-op replay
+	if len(games[0].Replay().Frames()) != len(games[1].Replay().Frames()) || games[0].Replay().ID() != games[1].Replay().ID() {
+		t.Error("peers recorded different replays")
+	}
+}
@@ -0,0 +1,53 @@
+package game
+
+import (
+	"crypto/ed25519"
+	"log"
+
+	"stellar-siege/game/interfaces"
+	"stellar-siege/game/systems"
+)
+
+// Both online leaderboard backends implement interfaces.LeaderboardManager
+var (
+	_ interfaces.LeaderboardManager = (*systems.GistLeaderboard)(nil)
+	_ interfaces.LeaderboardManager = (*systems.ServerLeaderboard)(nil)
+)
+
+// NewOnlineLeaderboard returns the online leaderboard backend configured in
+// config: the self-hosted server when a server URL is set, otherwise the
+// gist. Submitted scores are signed with key, if it is not nil. It returns
+// nil when neither backend is configured.
+func NewOnlineLeaderboard(config *systems.GistConfig, key ed25519.PrivateKey) interfaces.LeaderboardManager {
+	switch {
+	case config.ServerURL != "":
+		sl := systems.NewServerLeaderboard(config.ServerURL, config.ServerAdminToken)
+		sl.Key = key
+		return sl
+	case config.GistID != "" && config.GitHubToken != "":
+		gl := systems.NewGistLeaderboard(config.GistID, config.GitHubToken)
+		gl.Key = key
+		return gl
+	}
+	return nil
+}
+
+// refreshOnlineScores fetches the online leaderboard and checks the
+// signature of every entry against the registered pilot keys
+func (g *Game) refreshOnlineScores() {
+	scores, err := g.onlineLeaderboard.GetTopScores(100)
+	if err != nil {
+		return
+	}
+	// Without the registry, signed entries are shown as unregistered
+	keys, err := g.onlineLeaderboard.GetPilotKeys()
+	if err != nil {
+		log.Printf("Failed to fetch pilot keys: %v", err)
+	}
+	scores = append([]systems.OnlineScore(nil), scores...)
+	systems.CheckScoreSignatures(scores, keys)
+
+	g.onlineScoresMu.Lock()
+	g.onlineScores = scores
+	g.onlineScoresMu.Unlock()
+}
@@ -0,0 +1,165 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"log"
+	"time"
+
+	"stellar-siege/game/systems"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// outboxPollInterval is how often the outbox is checked for due retries
+const outboxPollInterval = 15 * time.Second
+
+// outboxScreen is the screen listing scores waiting to be submitted online
+type outboxScreen struct {
+	open     bool
+	selected int
+}
+
+// startOutboxWorker submits queued scores in the background: those left
+// from earlier launches right away, then new ones as they are queued and
+// failed ones when their retry is due
+func (g *Game) startOutboxWorker() {
+	g.outboxWake = make(chan struct{}, 1)
+	leaderboard := g.onlineLeaderboard
+	go func() {
+		ticker := time.NewTicker(outboxPollInterval)
+		defer ticker.Stop()
+		for {
+			sent := g.outbox.Flush(func(e systems.QueuedScore) error {
+				return leaderboard.SubmitScore(e.PlayerName, e.Country, e.Score, e.Difficulty, e.Wave, e.Replay)
+			})
+			if sent > 0 {
+				leaderboard.ClearCache()
+				g.refreshOnlineScores()
+			}
+			select {
+			case <-g.outboxWake:
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// wakeOutbox has the outbox worker look for due scores now
+func (g *Game) wakeOutbox() {
+	if g.outboxWake == nil {
+		return
+	}
+	select {
+	case g.outboxWake <- struct{}{}:
+	default:
+	}
+}
+
+// submissionStatus describes the online submission of the last run's score
+// for the game over screen, or returns "" if it was not submitted
+func (g *Game) submissionStatus() (string, color.RGBA) {
+	if g.runSubmissionID == "" {
+		return "", color.RGBA{}
+	}
+	entry, ok := g.outbox.Get(g.runSubmissionID)
+	if !ok {
+		return "", color.RGBA{}
+	}
+	switch entry.State {
+	case systems.SubmissionSubmitted:
+		return "Online: score submitted", color.RGBA{100, 255, 100, 255}
+	case systems.SubmissionSending:
+		return "Online: submitting score...", color.RGBA{200, 200, 200, 255}
+	case systems.SubmissionFailed:
+		return "Online: not submitted (" + entry.LastError + ") - retry from the menu", color.RGBA{255, 100, 100, 255}
+	}
+	if entry.LastError != "" {
+		wait := time.Until(entry.NextAttempt).Round(time.Second)
+		return fmt.Sprintf("Online: queued, retrying in %s (%s)", wait, entry.LastError), color.RGBA{255, 200, 100, 255}
+	}
+	return "Online: score pending", color.RGBA{200, 200, 200, 255}
+}
+
+// openOutboxScreen shows the scores waiting to be submitted
+func (g *Game) openOutboxScreen() {
+	g.outboxScreen = outboxScreen{open: true}
+}
+
+// updateOutboxScreen handles input on the outbox screen
+func (g *Game) updateOutboxScreen() {
+	ob := &g.outboxScreen
+	entries := g.outbox.Entries()
+	if len(entries) > 0 {
+		ob.selected = min(ob.selected, len(entries)-1)
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyB):
+		ob.open = false
+	case len(entries) == 0:
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || inpututil.IsKeyJustPressed(ebiten.KeyW):
+		ob.selected = (ob.selected + len(entries) - 1) % len(entries)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) || inpututil.IsKeyJustPressed(ebiten.KeyS):
+		ob.selected = (ob.selected + 1) % len(entries)
+	case inpututil.IsKeyJustPressed(ebiten.KeyR) || inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		g.retryQueuedScores(entries[ob.selected])
+	case inpututil.IsKeyJustPressed(ebiten.KeyA):
+		g.retryQueuedScores(entries...)
+	case inpututil.IsKeyJustPressed(ebiten.KeyX) || inpututil.IsKeyJustPressed(ebiten.KeyDelete):
+		if err := g.outbox.Discard(entries[ob.selected].ID); err != nil {
+			log.Printf("Failed to save score outbox: %v", err)
+		}
+	}
+}
+
+// retryQueuedScores submits queued scores now
+func (g *Game) retryQueuedScores(entries ...systems.QueuedScore) {
+	g.sound.PlaySound(systems.SoundUIClick)
+	for _, e := range entries {
+		if err := g.outbox.Retry(e.ID); err != nil {
+			log.Printf("Failed to save score outbox: %v", err)
+		}
+	}
+	g.wakeOutbox()
+}
+
+// drawOutboxScreen renders the scores waiting to be submitted
+func (g *Game) drawOutboxScreen(screen *ebiten.Image) {
+	systems.DrawTextCentered(screen, "QUEUED SCORES", ScreenWidth/2, 110, 3, color.RGBA{100, 200, 255, 255})
+
+	entries := g.outbox.Entries()
+	if len(entries) == 0 {
+		systems.DrawTextCentered(screen, "Every score has been submitted", ScreenWidth/2, 200, 1.5, color.RGBA{170, 170, 170, 255})
+	}
+	if g.onlineLeaderboard == nil && len(entries) > 0 {
+		systems.DrawTextCentered(screen, "The online leaderboard is not configured", ScreenWidth/2, 150, 1.2, color.RGBA{255, 100, 100, 255})
+	}
+
+	y := 180
+	for i, e := range entries {
+		c := color.RGBA{170, 170, 170, 255}
+		prefix := "  "
+		if i == g.outboxScreen.selected {
+			c = color.RGBA{255, 255, 100, 255}
+			prefix = "> "
+		}
+		status := string(e.State)
+		if e.State == systems.SubmissionPending && e.Attempts > 0 {
+			status = fmt.Sprintf("retry in %s", time.Until(e.NextAttempt).Round(time.Second))
+		}
+		line := fmt.Sprintf("%s%-10s %10s  wave %-3d %-6s  %s  %s", prefix, e.PlayerName, systems.FormatNumber(e.Score), e.Wave, e.Difficulty, e.Queued.Format("Jan 2 15:04"), status)
+		systems.DrawText(screen, line, 120, y, 1.2, c)
+		y += 26
+		if e.LastError != "" && i == g.outboxScreen.selected {
+			systems.DrawText(screen, fmt.Sprintf("    %d attempt(s), last error: %s", e.Attempts, e.LastError), 120, y, 1.0, color.RGBA{255, 150, 100, 255})
+			y += 24
+		}
+		if y > ScreenHeight-140 {
+			break
+		}
+	}
+
+	systems.DrawTextCentered(screen, "UP/DOWN Choose   R Retry   A Retry all   X Discard   ESC Back", ScreenWidth/2, ScreenHeight-80, 1.3, color.RGBA{200, 200, 200, 255})
+}
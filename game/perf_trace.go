@@ -0,0 +1,48 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"stellar-siege/game/core"
+	"stellar-siege/game/systems"
+)
+
+// poolSnapshot converts pool statistics for the performance monitor
+func poolSnapshot(stats core.PoolStats) systems.PoolStatsSnapshot {
+	return systems.PoolStatsSnapshot{
+		TotalCreated:  stats.TotalCreated,
+		TotalReused:   stats.TotalReused,
+		CurrentActive: stats.CurrentActive,
+		MaxActive:     stats.MaxActive,
+		PoolSize:      stats.PoolSize,
+		ReuseRate:     stats.ReuseRate,
+	}
+}
+
+// togglePerfTrace starts or stops recording the performance CSV trace
+func (g *Game) togglePerfTrace() {
+	if g.perfMon.IsTracing() {
+		g.stopPerfTrace()
+		return
+	}
+	path := systems.GetDataPath(fmt.Sprintf("perf_trace_%s.csv", time.Now().Format("20060102_150405")))
+	if err := g.perfMon.StartTrace(path); err != nil {
+		log.Printf("Failed to start performance trace: %v", err)
+		return
+	}
+	log.Printf("Recording performance trace to %s", path)
+}
+
+// stopPerfTrace finishes the performance CSV trace, if one is recording
+func (g *Game) stopPerfTrace() {
+	path, err := g.perfMon.StopTrace()
+	if err != nil {
+		log.Printf("Failed to finish performance trace: %v", err)
+		return
+	}
+	if path != "" {
+		log.Printf("Performance trace written to %s", path)
+	}
+}
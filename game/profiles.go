@@ -0,0 +1,388 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"log"
+	"path"
+	"strings"
+	"unicode"
+
+	"stellar-siege/game/systems"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Files kept per pilot profile
+const (
+	progressionFileName  = "progression.json"
+	achievementsFileName = "achievements.json"
+)
+
+// ProfileFileNames are the per-pilot files that were global before profiles
+// existed; an existing install moves them into its first profile
+var ProfileFileNames = []string{progressionFileName, achievementsFileName, systems.SettingsFileName, suspendFileName}
+
+// Directories of the data directory used to exchange pilot archives
+const (
+	profileExportDir = "exports"
+	profileImportDir = "imports"
+)
+
+// profileMode is what the profile screen is doing
+type profileMode int
+
+const (
+	profileBrowsing  profileMode = iota
+	profileNaming                // Typing the name of a new pilot
+	profileRenaming              // Typing a new name for the selected pilot
+	profileDeleting              // Waiting for the delete to be confirmed
+	profileRebinding             // Waiting for a key for each control in turn
+)
+
+// profileScreen is the state of the pilot profile screen
+type profileScreen struct {
+	open     bool
+	mode     profileMode
+	selected int
+	input    string              // Name being typed
+	binding  int                 // Control being rebound
+	keys     systems.KeyBindings // Bindings being edited
+	message  string              // Result of the last action
+}
+
+// bindingLabels name the controls in the order they are rebound
+var bindingLabels = []string{"UP", "DOWN", "LEFT", "RIGHT", "FIRE"}
+
+// bindingSlots returns the bindings in the order of bindingLabels
+func bindingSlots(k *systems.KeyBindings) []*ebiten.Key {
+	return []*ebiten.Key{&k.Up, &k.Down, &k.Left, &k.Right, &k.Shoot}
+}
+
+// reservedKeys cannot be bound to a control because the game already uses them
+var reservedKeys = map[ebiten.Key]bool{
+	ebiten.KeyEscape: true, ebiten.KeyP: true, ebiten.KeyBackquote: true,
+	ebiten.KeyF1: true, ebiten.KeyF2: true, ebiten.KeyF3: true, ebiten.KeyF4: true,
+	ebiten.KeyF5: true, ebiten.KeyF6: true, ebiten.KeyF7: true, ebiten.KeyF8: true,
+	ebiten.KeyF9: true, ebiten.KeyF10: true, ebiten.KeyF11: true, ebiten.KeyF12: true,
+}
+
+// loadProfile switches to the active pilot: its progression, achievements,
+// settings and suspended run
+func (g *Game) loadProfile() {
+	p := g.profiles.Active()
+	g.profileStore = g.profiles.Storage(p.ID)
+	g.progression = systems.NewProgressionManager(g.profileStore, progressionFileName)
+	g.achievements = systems.NewAchievementManager(g.profileStore, achievementsFileName)
+	g.menu.Pilot = p.Name
+	g.loadSettings()
+	g.refreshContinueEntry()
+	g.syncConflicts = nil
+	g.startSaveSync()
+}
+
+// openProfileScreen shows the profile screen with the active pilot selected
+func (g *Game) openProfileScreen() {
+	g.profileScreen = profileScreen{open: true}
+	for i, p := range g.profiles.Profiles() {
+		if p.ID == g.profiles.Active().ID {
+			g.profileScreen.selected = i
+		}
+	}
+}
+
+// updateProfileScreen handles input on the profile screen
+func (g *Game) updateProfileScreen() {
+	ps := &g.profileScreen
+	switch ps.mode {
+	case profileNaming, profileRenaming:
+		g.updateProfileNameInput()
+		return
+	case profileDeleting:
+		if inpututil.IsKeyJustPressed(ebiten.KeyY) {
+			g.deleteSelectedProfile()
+		} else if inpututil.IsKeyJustPressed(ebiten.KeyN) || inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			ps.mode = profileBrowsing
+		}
+		return
+	case profileRebinding:
+		g.updateKeyRebinding()
+		return
+	}
+
+	profiles := g.profiles.Profiles()
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyB):
+		ps.open = false
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || inpututil.IsKeyJustPressed(ebiten.KeyW):
+		ps.selected = (ps.selected + len(profiles) - 1) % len(profiles)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) || inpututil.IsKeyJustPressed(ebiten.KeyS):
+		ps.selected = (ps.selected + 1) % len(profiles)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		g.sound.PlaySound(systems.SoundUIClick)
+		if err := g.profiles.Select(profiles[ps.selected].ID); err != nil {
+			log.Printf("Failed to save pilot profiles: %v", err)
+		}
+		g.loadProfile()
+		ps.open = false
+	case inpututil.IsKeyJustPressed(ebiten.KeyN):
+		ps.mode, ps.input, ps.message = profileNaming, "", ""
+	case inpututil.IsKeyJustPressed(ebiten.KeyR):
+		ps.mode, ps.input, ps.message = profileRenaming, profiles[ps.selected].Name, ""
+	case inpututil.IsKeyJustPressed(ebiten.KeyX) || inpututil.IsKeyJustPressed(ebiten.KeyDelete):
+		ps.mode, ps.message = profileDeleting, ""
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) || inpututil.IsKeyJustPressed(ebiten.KeyA):
+		g.setCountry(systems.NextCountry(g.country, -1))
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) || inpututil.IsKeyJustPressed(ebiten.KeyD):
+		g.setCountry(systems.NextCountry(g.country, 1))
+	case inpututil.IsKeyJustPressed(ebiten.KeyK):
+		ps.mode, ps.binding, ps.keys, ps.message = profileRebinding, 0, g.keys, ""
+	case inpututil.IsKeyJustPressed(ebiten.KeyE):
+		ps.message = g.exportProfile(profiles[ps.selected])
+	case inpututil.IsKeyJustPressed(ebiten.KeyI):
+		ps.message = g.importProfiles()
+	case inpututil.IsKeyJustPressed(ebiten.KeyC) && g.saveSync != nil:
+		g.startSaveSync()
+	}
+}
+
+// setCountry changes the country shown with the active pilot's scores
+func (g *Game) setCountry(code string) {
+	g.sound.PlaySound(systems.SoundUIClick)
+	g.country = code
+	g.saveSettings()
+}
+
+// updateProfileNameInput handles typing the name of a new or renamed pilot
+func (g *Game) updateProfileNameInput() {
+	ps := &g.profileScreen
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if len([]rune(ps.input)) < systems.ProfileNameMaxLen && unicode.IsPrint(r) {
+			ps.input += string(r)
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(ps.input) > 0 {
+		r := []rune(ps.input)
+		ps.input = string(r[:len(r)-1])
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		ps.mode = profileBrowsing
+		return
+	}
+	if !inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		return
+	}
+
+	var err error
+	if ps.mode == profileNaming {
+		var p systems.Profile
+		if p, err = g.profiles.Create(ps.input); err == nil {
+			ps.selected = len(g.profiles.Profiles()) - 1
+			ps.message = "Created pilot " + p.Name
+		}
+	} else {
+		p := g.profiles.Profiles()[ps.selected]
+		if err = g.profiles.Rename(p.ID, ps.input); err == nil {
+			ps.message = "Renamed pilot"
+			g.menu.Pilot = g.profiles.Active().Name
+		}
+	}
+	if err != nil {
+		ps.message = err.Error()
+		if errors.Is(err, systems.ErrProfileNameEmpty) || errors.Is(err, systems.ErrProfileNameTaken) {
+			return // Let the player fix the name
+		}
+	}
+	g.sound.PlaySound(systems.SoundUIClick)
+	ps.mode = profileBrowsing
+}
+
+// deleteSelectedProfile deletes the selected pilot after confirmation
+func (g *Game) deleteSelectedProfile() {
+	ps := &g.profileScreen
+	ps.mode = profileBrowsing
+	p := g.profiles.Profiles()[ps.selected]
+	wasActive := p.ID == g.profiles.Active().ID
+	if err := g.profiles.Delete(p.ID); err != nil {
+		ps.message = err.Error()
+		return
+	}
+	ps.message = "Deleted pilot " + p.Name
+	if ps.selected >= len(g.profiles.Profiles()) {
+		ps.selected--
+	}
+	if wasActive {
+		g.loadProfile()
+	}
+}
+
+// updateKeyRebinding binds the next key pressed to each control in turn.
+// A key already bound to another control swaps with it.
+func (g *Game) updateKeyRebinding() {
+	ps := &g.profileScreen
+	for _, key := range inpututil.AppendJustPressedKeys(nil) {
+		if key == ebiten.KeyEscape {
+			ps.mode, ps.message = profileBrowsing, "Controls unchanged"
+			return
+		}
+		if reservedKeys[key] {
+			ps.message = strings.ToUpper(key.String()) + " is reserved"
+			return
+		}
+		slots := bindingSlots(&ps.keys)
+		for _, slot := range slots {
+			if *slot == key {
+				*slot = *slots[ps.binding]
+			}
+		}
+		*slots[ps.binding] = key
+		ps.message = ""
+		ps.binding++
+		if ps.binding == len(slots) {
+			g.keys = ps.keys
+			g.menu.Keys = ps.keys
+			g.saveSettings()
+			ps.mode, ps.message = profileBrowsing, "Controls saved"
+		}
+		return
+	}
+}
+
+// exportProfile writes a pilot's archive to the exports directory and
+// returns a message for the profile screen
+func (g *Game) exportProfile(p systems.Profile) string {
+	data, err := g.profiles.Export(p.ID)
+	if err != nil {
+		return err.Error()
+	}
+	name := path.Join(profileExportDir, profileArchiveName(p.Name))
+	if err := g.storage.Write(name, data); err != nil {
+		return fmt.Sprintf("Export failed: %v", err)
+	}
+	return "Exported to " + systems.StoragePath(g.storage, name)
+}
+
+// importProfiles adds every pilot archive in the imports directory, removing
+// the archives that were imported, and returns a message for the profile screen
+func (g *Game) importProfiles() string {
+	names, err := g.storage.List(profileImportDir)
+	if err != nil {
+		return fmt.Sprintf("Import failed: %v", err)
+	}
+	var imported []string
+	for _, name := range names {
+		if path.Ext(name) != systems.ProfileArchiveExt {
+			continue
+		}
+		data, err := g.storage.Read(name)
+		if err == nil {
+			var p systems.Profile
+			if p, err = g.profiles.Import(data); err == nil {
+				imported = append(imported, p.Name)
+				g.storage.Remove(name)
+				continue
+			}
+		}
+		log.Printf("Failed to import %s: %v", name, err)
+		return fmt.Sprintf("%s: %v", path.Base(name), err)
+	}
+	if len(imported) == 0 {
+		return "Put " + systems.ProfileArchiveExt + " files in " + systems.StoragePath(g.storage, profileImportDir)
+	}
+	return "Imported " + strings.Join(imported, ", ")
+}
+
+// profileArchiveName turns a pilot name into an archive file name
+func profileArchiveName(name string) string {
+	return systems.ProfileSlug(name) + systems.ProfileArchiveExt
+}
+
+// drawProfileScreen draws the profile screen
+func (g *Game) drawProfileScreen(screen *ebiten.Image) {
+	ps := &g.profileScreen
+	systems.DrawTextCentered(screen, "PILOTS", ScreenWidth/2, 110, 3, color.RGBA{100, 200, 255, 255})
+
+	active := g.profiles.Active().ID
+	y := 180
+	for i, p := range g.profiles.Profiles() {
+		label := p.Name
+		if p.ID == active {
+			label += "  (active)"
+		}
+		c := color.RGBA{170, 170, 170, 255}
+		if i == ps.selected {
+			label = "> " + label + " <"
+			c = color.RGBA{255, 255, 100, 255}
+		}
+		systems.DrawTextCentered(screen, label, ScreenWidth/2, y, 1.8, c)
+		y += 36
+	}
+
+	y = ScreenHeight - 230
+	switch ps.mode {
+	case profileNaming:
+		systems.DrawTextCentered(screen, "New pilot name: "+ps.input+"_", ScreenWidth/2, y, 1.8, color.RGBA{100, 255, 100, 255})
+		systems.DrawTextCentered(screen, "ENTER to create, ESC to cancel", ScreenWidth/2, y+40, 1.2, color.RGBA{180, 180, 180, 255})
+	case profileRenaming:
+		systems.DrawTextCentered(screen, "Rename to: "+ps.input+"_", ScreenWidth/2, y, 1.8, color.RGBA{100, 255, 100, 255})
+		systems.DrawTextCentered(screen, "ENTER to rename, ESC to cancel", ScreenWidth/2, y+40, 1.2, color.RGBA{180, 180, 180, 255})
+	case profileDeleting:
+		name := g.profiles.Profiles()[ps.selected].Name
+		systems.DrawTextCentered(screen, "Delete "+name+" and all of their progress?", ScreenWidth/2, y, 1.8, color.RGBA{255, 100, 100, 255})
+		systems.DrawTextCentered(screen, "Y to delete, N to keep", ScreenWidth/2, y+40, 1.2, color.RGBA{180, 180, 180, 255})
+	case profileRebinding:
+		systems.DrawTextCentered(screen, "Press a key for "+bindingLabels[ps.binding], ScreenWidth/2, y, 1.8, color.RGBA{100, 255, 100, 255})
+		systems.DrawTextCentered(screen, "ESC to cancel", ScreenWidth/2, y+40, 1.2, color.RGBA{180, 180, 180, 255})
+	default:
+		systems.DrawTextCentered(screen, "UP/DOWN Choose   ENTER Fly as pilot   LEFT/RIGHT Country   ESC Back", ScreenWidth/2, y, 1.3, color.RGBA{200, 200, 200, 255})
+		actions := "N New   R Rename   X Delete   K Controls   E Export   I Import"
+		if g.saveSync != nil {
+			actions += "   C Sync"
+		}
+		systems.DrawTextCentered(screen, actions, ScreenWidth/2, y+32, 1.3, color.RGBA{200, 200, 200, 255})
+		g.drawSyncConflicts(screen, y-130)
+	}
+
+	if ps.message != "" {
+		systems.DrawTextCentered(screen, ps.message, ScreenWidth/2, y+90, 1.2, color.RGBA{255, 220, 100, 255})
+	}
+
+	g.drawCountry(screen, ScreenHeight-90)
+
+	k := g.keys
+	controls := fmt.Sprintf("Controls: UP %s  DOWN %s  LEFT %s  RIGHT %s  FIRE %s", k.Up, k.Down, k.Left, k.Right, k.Shoot)
+	systems.DrawTextCentered(screen, strings.ToUpper(controls), ScreenWidth/2, ScreenHeight-60, 1.1, color.RGBA{150, 150, 150, 255})
+}
+
+// drawCountry shows the country the active pilot shares with their scores
+func (g *Game) drawCountry(screen *ebiten.Image, y int) {
+	label := "Country: not shown on leaderboards"
+	if c, ok := systems.FindCountry(g.country); ok {
+		label = "Country: " + c.Name
+	}
+	label = strings.ToUpper(label)
+	systems.DrawTextCentered(screen, label, ScreenWidth/2, y, 1.1, color.RGBA{150, 150, 150, 255})
+	left := ScreenWidth/2 - len(label)*7*11/20
+	systems.DrawFlag(screen, g.country, left-systems.FlagWidth-8, y+1, 1)
+}
+
+// maxShownConflicts caps the sync conflicts listed on the profile screen
+const maxShownConflicts = 4
+
+// drawSyncConflicts lists the conflicts the last save sync resolved
+func (g *Game) drawSyncConflicts(screen *ebiten.Image, y int) {
+	if len(g.syncConflicts) == 0 {
+		return
+	}
+	systems.DrawTextCentered(screen, "Last sync kept the newer of conflicting changes:", ScreenWidth/2, y, 1.2, color.RGBA{255, 180, 100, 255})
+	for i, c := range g.syncConflicts {
+		y += 22
+		if i == maxShownConflicts {
+			systems.DrawTextCentered(screen, fmt.Sprintf("...and %d more", len(g.syncConflicts)-i), ScreenWidth/2, y, 1.0, color.RGBA{180, 180, 180, 255})
+			break
+		}
+		systems.DrawTextCentered(screen, c.String(), ScreenWidth/2, y, 1.0, color.RGBA{180, 180, 180, 255})
+	}
+}
@@ -0,0 +1,503 @@
+// Package scoreserver implements the self-hosted leaderboard server: a REST
+// API over JSON, answering systems.ServerLeaderboard, that keeps scores, their
+// replays and the public key each player name is registered to in a directory.
+package scoreserver
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// APIPrefix is the path under which the API is served
+	APIPrefix = "/api/v1"
+
+	// DefaultMaxEntries is how many scores a server keeps by default
+	DefaultMaxEntries = 1000
+
+	// MaxPlayerNameLen is the longest player name accepted
+	MaxPlayerNameLen = 20
+
+	// maxSubmissionBytes caps a submission, replay included
+	maxSubmissionBytes = 8 << 20
+
+	scoresFileName = "scores.json"
+	keysFileName   = "keys.json"
+	replaysDir     = "replays"
+)
+
+// validDifficulties are the difficulty names the game submits
+var validDifficulties = map[string]bool{"Easy": true, "Normal": true, "Hard": true}
+
+// Score is a leaderboard entry
+type Score struct {
+	PlayerName   string          `json:"player_name"`
+	Country      string          `json:"country,omitempty"`
+	Score        int64           `json:"score"`
+	Difficulty   string          `json:"difficulty"`
+	Date         time.Time       `json:"date"`
+	Wave         int             `json:"wave"`
+	ReplayID     string          `json:"replay_id,omitempty"`
+	Verification json.RawMessage `json:"verification,omitempty"` // Signed by the verifier; stored as sent
+	Version      string          `json:"version,omitempty"`
+	Seed         int64           `json:"seed,omitempty"`
+	PublicKey    string          `json:"public_key,omitempty"`
+	Signature    string          `json:"signature,omitempty"`
+}
+
+// Submission is the body of a submitted score
+type Submission struct {
+	PlayerName string          `json:"player_name"`
+	Country    string          `json:"country,omitempty"`
+	Score      int64           `json:"score"`
+	Difficulty string          `json:"difficulty"`
+	Wave       int             `json:"wave"`
+	ReplayID   string          `json:"replay_id,omitempty"`
+	Replay     json.RawMessage `json:"replay,omitempty"`
+	Version    string          `json:"version,omitempty"`
+	Seed       int64           `json:"seed,omitempty"`
+	PublicKey  string          `json:"public_key,omitempty"`
+	Signature  string          `json:"signature,omitempty"`
+}
+
+// replayDifficulties are the difficulty names of a replay's difficulty
+// index, as the game records them
+var replayDifficulties = []string{"Easy", "Normal", "Hard"}
+
+// replayClaims are the replay fields the server checks against a submission,
+// matching systems.Replay
+type replayClaims struct {
+	FormatVersion int    `json:"format_version"`
+	GameVersion   string `json:"game_version"`
+	Seed          int64  `json:"seed"`
+	Difficulty    int    `json:"difficulty"`
+	Pilots        int    `json:"pilots"`
+	Ticks         int    `json:"ticks"`
+	Score         int64  `json:"score"`
+	Wave          int    `json:"wave"`
+	Finished      bool   `json:"finished"`
+	Inputs        []struct {
+		Frame uint64 `json:"f"`
+		Count int    `json:"n"`
+	} `json:"inputs"`
+}
+
+// id derives the replay's ID from its contents exactly as systems.Replay.ID
+// does, so a submission cannot store a replay under another entry's ID
+func (c *replayClaims) id() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d|%s|%d|%d|%d|%d|%d|", c.FormatVersion, c.GameVersion, c.Seed, c.Difficulty, c.Ticks, c.Score, c.Wave)
+	for _, run := range c.Inputs {
+		fmt.Fprintf(h, "%d:%d,", run.Frame, run.Count)
+	}
+	if c.Pilots > 1 {
+		fmt.Fprintf(h, "pilots=%d", c.Pilots)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Server serves the leaderboard API under APIPrefix
+type Server struct {
+	dir        string
+	adminToken string // Required to store verification records; empty disables them
+	maxEntries int
+	mux        *http.ServeMux
+
+	mu     sync.Mutex
+	scores []Score           // Highest first
+	keys   map[string]string // Public key registered for each player name
+}
+
+// New creates a server keeping at most maxEntries scores in dir
+func New(dir, adminToken string, maxEntries int) (*Server, error) {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	if err := os.MkdirAll(filepath.Join(dir, replaysDir), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	s := &Server{dir: dir, adminToken: adminToken, maxEntries: maxEntries}
+	data, err := os.ReadFile(filepath.Join(dir, scoresFileName))
+	if err == nil {
+		err = json.Unmarshal(data, &s.scores)
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to load scores: %w", err)
+	}
+	data, err = os.ReadFile(filepath.Join(dir, keysFileName))
+	if err == nil {
+		err = json.Unmarshal(data, &s.keys)
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to load pilot keys: %w", err)
+	}
+	if s.keys == nil {
+		s.keys = make(map[string]string)
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("GET "+APIPrefix+"/scores", s.handleScores)
+	s.mux.HandleFunc("POST "+APIPrefix+"/scores", s.handleSubmit)
+	s.mux.HandleFunc("GET "+APIPrefix+"/keys", s.handleKeys)
+	s.mux.HandleFunc("GET "+APIPrefix+"/replays/{id}", s.handleReplay)
+	s.mux.HandleFunc("POST "+APIPrefix+"/verifications", s.handleVerifications)
+	return s, nil
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handleScores lists the best scores; ?limit=N returns only the first N
+func (s *Server) handleScores(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	s.mu.Lock()
+	scores := s.scores
+	if limit > 0 && limit < len(scores) {
+		scores = scores[:limit]
+	}
+	scores = append([]Score{}, scores...)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, scores)
+}
+
+// handleSubmit adds a score and stores its replay
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var sub Submission
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSubmissionBytes)).Decode(&sub); err != nil {
+		http.Error(w, "invalid submission: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateSubmission(&sub); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entry := Score{
+		PlayerName: sub.PlayerName,
+		Country:    sub.Country,
+		Score:      sub.Score,
+		Difficulty: sub.Difficulty,
+		Date:       time.Now().UTC(),
+		Wave:       sub.Wave,
+		ReplayID:   sub.ReplayID,
+		Version:    sub.Version,
+		Seed:       sub.Seed,
+		PublicKey:  sub.PublicKey,
+		Signature:  sub.Signature,
+	}
+
+	// Check the name before storing anything; the lock is held until the
+	// key is registered so two installs cannot both claim a name
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if registered, ok := s.keys[pilotKeyName(sub.PlayerName)]; ok && registered != sub.PublicKey {
+		http.Error(w, "player name is registered to another install", http.StatusConflict)
+		return
+	}
+
+	// A client retrying a submission whose response it never got sends the
+	// same run again; it gets the stored entry back instead of a duplicate
+	for _, stored := range s.scores {
+		if sameRun(stored, entry) {
+			writeJSON(w, http.StatusOK, stored)
+			return
+		}
+	}
+
+	if len(sub.Replay) > 0 {
+		if err := storeReplay(s.replayPath(sub.ReplayID), sub.Replay); err != nil {
+			log.Printf("Failed to store replay: %v", err)
+			http.Error(w, "failed to store replay", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if sub.PublicKey != "" && s.keys[pilotKeyName(sub.PlayerName)] == "" {
+		s.keys[pilotKeyName(sub.PlayerName)] = sub.PublicKey
+		if err := s.saveKeysLocked(); err != nil {
+			log.Printf("Failed to save pilot keys: %v", err)
+			http.Error(w, "failed to register key", http.StatusInternalServerError)
+			return
+		}
+	}
+	s.scores = append(s.scores, entry)
+	sort.SliceStable(s.scores, func(i, j int) bool {
+		return s.scores[i].Score > s.scores[j].Score
+	})
+	var evicted []Score
+	if len(s.scores) > s.maxEntries {
+		evicted = append(evicted, s.scores[s.maxEntries:]...)
+		s.scores = s.scores[:s.maxEntries]
+	}
+	if err := s.saveLocked(); err != nil {
+		log.Printf("Failed to save scores: %v", err)
+		http.Error(w, "failed to save score", http.StatusInternalServerError)
+		return
+	}
+	s.removeReplaysLocked(evicted)
+	writeJSON(w, http.StatusCreated, entry)
+}
+
+// handleKeys lists the public key registered for each player name
+func (s *Server) handleKeys(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	keys := make(map[string]string, len(s.keys))
+	for name, key := range s.keys {
+		keys[name] = key
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, keys)
+}
+
+// handleReplay serves the replay stored for an entry
+func (s *Server) handleReplay(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !validReplayID(id) {
+		http.Error(w, "invalid replay id", http.StatusBadRequest)
+		return
+	}
+	data, err := os.ReadFile(s.replayPath(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		http.Error(w, "replay not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to read replay %s: %v", id, err)
+		http.Error(w, "failed to read replay", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// handleVerifications attaches verification records to the entries whose
+// replays they vouch for. Only the verifier, holding the admin token, may.
+func (s *Server) handleVerifications(w http.ResponseWriter, r *http.Request) {
+	if !s.isAdmin(r) {
+		http.Error(w, "admin token required", http.StatusUnauthorized)
+		return
+	}
+	var records []json.RawMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSubmissionBytes)).Decode(&records); err != nil {
+		http.Error(w, "invalid verification records: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	byReplay := make(map[string]json.RawMessage, len(records))
+	for _, record := range records {
+		var ref struct {
+			ReplayID string `json:"replay_id"`
+		}
+		if err := json.Unmarshal(record, &ref); err != nil || ref.ReplayID == "" {
+			http.Error(w, "verification record without a replay id", http.StatusBadRequest)
+			return
+		}
+		byReplay[ref.ReplayID] = record
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.scores {
+		if record, ok := byReplay[s.scores[i].ReplayID]; ok {
+			s.scores[i].Verification = record
+		}
+	}
+	if err := s.saveLocked(); err != nil {
+		log.Printf("Failed to save scores: %v", err)
+		http.Error(w, "failed to save verification records", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// isAdmin reports whether a request carries the admin token
+func (s *Server) isAdmin(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && s.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1
+}
+
+// saveLocked writes the score list; the caller holds s.mu
+func (s *Server) saveLocked() error {
+	data, err := json.MarshalIndent(s.scores, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode scores: %w", err)
+	}
+	return writeFileAtomic(filepath.Join(s.dir, scoresFileName), data)
+}
+
+// saveKeysLocked writes the pilot key registry; the caller holds s.mu
+func (s *Server) saveKeysLocked() error {
+	data, err := json.MarshalIndent(s.keys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode pilot keys: %w", err)
+	}
+	return writeFileAtomic(filepath.Join(s.dir, keysFileName), data)
+}
+
+// removeReplaysLocked deletes the replays of evicted entries that no kept
+// entry shares; the caller holds s.mu
+func (s *Server) removeReplaysLocked(evicted []Score) {
+	kept := make(map[string]bool, len(s.scores))
+	for _, entry := range s.scores {
+		kept[entry.ReplayID] = true
+	}
+	for _, entry := range evicted {
+		if entry.ReplayID == "" || kept[entry.ReplayID] {
+			continue
+		}
+		if err := os.Remove(s.replayPath(entry.ReplayID)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Failed to remove replay %s: %v", entry.ReplayID, err)
+		}
+	}
+}
+
+// sameRun reports whether two entries record the same run of the same pilot
+func sameRun(a, b Score) bool {
+	return pilotKeyName(a.PlayerName) == pilotKeyName(b.PlayerName) && a.Score == b.Score && a.Seed == b.Seed &&
+		a.ReplayID == b.ReplayID && a.Wave == b.Wave && a.Difficulty == b.Difficulty
+}
+
+// replayPath is where the replay of an entry is stored
+func (s *Server) replayPath(id string) string {
+	return filepath.Join(s.dir, replaysDir, id+".json")
+}
+
+// validateSubmission checks a submission's fields and that its replay
+// claims the submitted result. Whether the replay really reaches that result
+// is for the verifier to decide.
+func validateSubmission(sub *Submission) error {
+	sub.PlayerName = strings.TrimSpace(sub.PlayerName)
+	switch {
+	case sub.PlayerName == "":
+		return errors.New("player name is required")
+	case utf8.RuneCountInString(sub.PlayerName) > MaxPlayerNameLen:
+		return fmt.Errorf("player name is longer than %d characters", MaxPlayerNameLen)
+	case sub.Score < 0 || sub.Wave < 0:
+		return errors.New("score and wave must not be negative")
+	case !validDifficulties[sub.Difficulty]:
+		return fmt.Errorf("unknown difficulty %q", sub.Difficulty)
+	case sub.Country != "" && !validCountryCode(sub.Country):
+		return fmt.Errorf("invalid country code %q", sub.Country)
+	case (sub.ReplayID == "") != (len(sub.Replay) == 0):
+		return errors.New("a replay and its id must be sent together")
+	case (sub.PublicKey == "") != (sub.Signature == ""):
+		return errors.New("a signature and its public key must be sent together")
+	case sub.Signature != "" && !checkSignature(sub):
+		return errors.New("signature does not match the submission")
+	}
+	if len(sub.Replay) == 0 {
+		return nil
+	}
+	if !validReplayID(sub.ReplayID) {
+		return errors.New("invalid replay id")
+	}
+	var claims replayClaims
+	if err := json.Unmarshal(sub.Replay, &claims); err != nil {
+		return fmt.Errorf("invalid replay: %w", err)
+	}
+	if !claims.Finished || claims.Score != sub.Score || claims.Wave != sub.Wave || claims.Seed != sub.Seed ||
+		claims.Difficulty < 0 || claims.Difficulty >= len(replayDifficulties) ||
+		replayDifficulties[claims.Difficulty] != sub.Difficulty {
+		return errors.New("replay does not match the submitted score")
+	}
+	if claims.id() != sub.ReplayID {
+		return errors.New("replay id does not match the replay")
+	}
+	return nil
+}
+
+// storeReplay writes a replay under its ID. An existing file is never
+// replaced: IDs are derived from the contents, so a replay already stored
+// under the ID is the same one. The file is written aside and linked into
+// place so readers never see it half written.
+func storeReplay(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Link(tmp.Name(), path); err != nil && !errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("failed to store %s: %w", path, err)
+	}
+	return nil
+}
+
+// validCountryCode reports whether code has the form of an ISO 3166-1
+// alpha-2 country code: two upper case letters
+func validCountryCode(code string) bool {
+	return len(code) == 2 && code[0] >= 'A' && code[0] <= 'Z' && code[1] >= 'A' && code[1] <= 'Z'
+}
+
+// validReplayID reports whether id has the form of a replay id: 16 lower
+// case hex digits
+func validReplayID(id string) bool {
+	if len(id) != 16 {
+		return false
+	}
+	for _, c := range id {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeFileAtomic replaces a file so readers never see it half written
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
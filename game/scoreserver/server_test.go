@@ -0,0 +1,238 @@
+package scoreserver
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"stellar-siege/game/scoresig"
+)
+
+// post sends a JSON body to the server and returns the status code
+func post(t *testing.T, srv *Server, path, body, token string) int {
+	t.Helper()
+	req := httptest.NewRequest("POST", APIPrefix+path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+// testReplay returns a finished replay as the game encodes it, and its ID
+func testReplay(t *testing.T, seed int64, difficulty int, score int64, wave int) (string, string) {
+	t.Helper()
+	replay := fmt.Sprintf(`{"format_version":1,"game_version":"1.1.0","seed":%d,"difficulty":%d,"ticks":90,`+
+		`"score":%d,"wave":%d,"finished":true,"inputs":[{"f":16,"n":60},{"f":20,"n":30}]}`, seed, difficulty, score, wave)
+	var claims replayClaims
+	if err := json.Unmarshal([]byte(replay), &claims); err != nil {
+		t.Fatal(err)
+	}
+	return replay, claims.id()
+}
+
+// submission returns a submission body with a replay
+func submission(name string, score int64, difficulty string, wave int, seed int64, id, replay string) string {
+	return fmt.Sprintf(`{"player_name":%q,"score":%d,"difficulty":%q,"wave":%d,"seed":%d,"replay_id":%q,"replay":%s}`,
+		name, score, difficulty, wave, seed, id, replay)
+}
+
+func TestServerValidatesSubmissions(t *testing.T) {
+	srv, err := New(t.TempDir(), "admin", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replay, id := testReplay(t, 7, 2, 500, 4)
+	easy, easyID := testReplay(t, 7, 0, 500, 4)
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"valid", `{"player_name":"Ace","score":100,"difficulty":"Normal","wave":2}`, http.StatusCreated},
+		{"with replay", submission("Ace", 500, "Hard", 4, 7, id, replay), http.StatusCreated},
+		{"no name", `{"player_name":"  ","score":100,"difficulty":"Normal","wave":2}`, http.StatusBadRequest},
+		{"long name", `{"player_name":"` + strings.Repeat("x", MaxPlayerNameLen+1) + `","score":1,"difficulty":"Easy","wave":1}`, http.StatusBadRequest},
+		{"negative score", `{"player_name":"Ace","score":-1,"difficulty":"Easy","wave":1}`, http.StatusBadRequest},
+		{"unknown difficulty", `{"player_name":"Ace","score":1,"difficulty":"Insane","wave":1}`, http.StatusBadRequest},
+		{"with country", `{"player_name":"Ace","country":"NO","score":1,"difficulty":"Easy","wave":1}`, http.StatusCreated},
+		{"invalid country", `{"player_name":"Ace","country":"Norway","score":1,"difficulty":"Easy","wave":1}`, http.StatusBadRequest},
+		{"replay disagrees", submission("Ace", 900, "Hard", 4, 7, id, replay), http.StatusBadRequest},
+		{"replay of another difficulty", submission("Ace", 500, "Hard", 4, 7, easyID, easy), http.StatusBadRequest},
+		{"replay of another seed", submission("Ace", 500, "Hard", 4, 8, id, replay), http.StatusBadRequest},
+		{"replay under another id", submission("Ace", 500, "Hard", 4, 7, "0123456789abcdef", replay), http.StatusBadRequest},
+		{"path in replay id", submission("Ace", 500, "Hard", 4, 7, "../../../etc/pwd", replay), http.StatusBadRequest},
+		{"not json", `{`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if got := post(t, srv, "/scores", tt.body, ""); got != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	// Verification records need the admin token
+	records := `[{"replay_id":"` + id + `","verified":true}]`
+	if got := post(t, srv, "/verifications", records, "wrong"); got != http.StatusUnauthorized {
+		t.Errorf("verification with a bad token: status %d", got)
+	}
+	if got := post(t, srv, "/verifications", records, "admin"); got != http.StatusNoContent {
+		t.Errorf("verification: status %d", got)
+	}
+}
+
+func TestServerKeepsBestScores(t *testing.T) {
+	dir := t.TempDir()
+	srv, err := New(dir, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayA, idA := testReplay(t, 1, 0, 300, 3)
+	replayB, idB := testReplay(t, 2, 0, 100, 1)
+	for i, body := range []string{
+		submission("A", 300, "Easy", 3, 1, idA, replayA),
+		submission("B", 100, "Easy", 1, 2, idB, replayB),
+		`{"player_name":"C","score":200,"difficulty":"Easy","wave":2}`,
+	} {
+		if got := post(t, srv, "/scores", body, ""); got != http.StatusCreated {
+			t.Fatalf("submission %d: status %d", i, got)
+		}
+	}
+
+	// Reloading keeps the two best; the evicted entry's replay is gone
+	reloaded, err := New(dir, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.scores) != 2 || reloaded.scores[0].PlayerName != "A" || reloaded.scores[1].PlayerName != "C" {
+		t.Errorf("scores = %+v", reloaded.scores)
+	}
+	for id, want := range map[string]int{idA: http.StatusOK, idB: http.StatusNotFound} {
+		rec := httptest.NewRecorder()
+		reloaded.ServeHTTP(rec, httptest.NewRequest("GET", APIPrefix+"/replays/"+id, nil))
+		if rec.Code != want {
+			t.Errorf("replay %s: status %d, want %d", id, rec.Code, want)
+		}
+	}
+}
+
+func TestServerStoresResubmittedScoresOnce(t *testing.T) {
+	srv, err := New(t.TempDir(), "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay, id := testReplay(t, 4, 1, 800, 6)
+
+	// An outbox retrying after a lost response sends the same run again
+	for i, want := range []int{http.StatusCreated, http.StatusOK, http.StatusOK} {
+		if got := post(t, srv, "/scores", submission("Ace", 800, "Normal", 6, 4, id, replay), ""); got != want {
+			t.Errorf("submission %d: status %d, want %d", i, got, want)
+		}
+	}
+	for i, want := range []int{http.StatusCreated, http.StatusOK} {
+		if got := post(t, srv, "/scores", `{"player_name":"Bob","score":50,"difficulty":"Easy","wave":1}`, ""); got != want {
+			t.Errorf("submission without a replay %d: status %d, want %d", i, got, want)
+		}
+	}
+	// Another run with the same result is still a new entry
+	if got := post(t, srv, "/scores", `{"player_name":"Bob","score":50,"difficulty":"Easy","wave":1,"seed":9}`, ""); got != http.StatusCreated {
+		t.Errorf("another run: status %d", got)
+	}
+	if len(srv.scores) != 3 {
+		t.Errorf("scores = %+v", srv.scores)
+	}
+}
+
+// signedSubmission returns a submission body signed with key
+func signedSubmission(key ed25519.PrivateKey, name string, score int64) string {
+	pub, sig := scoresig.Sign(key, scoresig.Fields{PlayerName: name, Score: score, Wave: 2, Difficulty: "Normal", Version: "1.1.0", Seed: 7})
+	return fmt.Sprintf(`{"player_name":%q,"score":%d,"difficulty":"Normal","wave":2,"version":"1.1.0","seed":7,"public_key":%q,"signature":%q}`,
+		name, score, pub, sig)
+}
+
+func TestServerRegistersPilotKeys(t *testing.T) {
+	dir := t.TempDir()
+	srv, err := New(dir, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ace, _ := ed25519.GenerateKey(nil)
+	_, mallory, _ := ed25519.GenerateKey(nil)
+
+	// The first signed score registers the name; later ones need its key
+	if got := post(t, srv, "/scores", signedSubmission(ace, "Ace", 100), ""); got != http.StatusCreated {
+		t.Fatalf("first signed score: status %d", got)
+	}
+	if got := post(t, srv, "/scores", signedSubmission(ace, "Ace", 200), ""); got != http.StatusCreated {
+		t.Errorf("second signed score: status %d", got)
+	}
+	if got := post(t, srv, "/scores", signedSubmission(mallory, "ACE", 900), ""); got != http.StatusConflict {
+		t.Errorf("score signed by another key: status %d", got)
+	}
+	if got := post(t, srv, "/scores", `{"player_name":"Ace","score":900,"difficulty":"Normal","wave":2}`, ""); got != http.StatusConflict {
+		t.Errorf("unsigned score for a registered name: status %d", got)
+	}
+
+	// A signature has to match the submission
+	tampered := strings.Replace(signedSubmission(mallory, "Mallory", 100), `"score":100`, `"score":99999`, 1)
+	if got := post(t, srv, "/scores", tampered, ""); got != http.StatusBadRequest {
+		t.Errorf("tampered score: status %d", got)
+	}
+
+	// The registry survives a restart and is served to clients
+	reloaded, err := New(dir, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	reloaded.ServeHTTP(rec, httptest.NewRequest("GET", APIPrefix+"/keys", nil))
+	var keys map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &keys); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys["ace"] != hex.EncodeToString(ace.Public().(ed25519.PublicKey)) {
+		t.Errorf("keys = %v", keys)
+	}
+}
+
+func TestServerNeverReplacesStoredReplays(t *testing.T) {
+	dir := t.TempDir()
+	srv, err := New(dir, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay, id := testReplay(t, 3, 1, 700, 5)
+	if got := post(t, srv, "/scores", submission("Ace", 700, "Normal", 5, 3, id, replay), ""); got != http.StatusCreated {
+		t.Fatalf("first submission: status %d", got)
+	}
+
+	// A forged replay cannot take over the ID, and the same replay sent again
+	// leaves the stored one as it was
+	forged := strings.Replace(replay, `{"f":20,"n":30}`, `{"f":0,"n":30}`, 1)
+	if got := post(t, srv, "/scores", submission("Mallory", 700, "Normal", 5, 3, id, forged), ""); got != http.StatusBadRequest {
+		t.Errorf("forged replay under a taken id: status %d", got)
+	}
+	if got := post(t, srv, "/scores", submission("Ace", 700, "Normal", 5, 3, id, replay), ""); got != http.StatusOK {
+		t.Errorf("same replay again: status %d", got)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest("GET", APIPrefix+"/replays/"+id, nil))
+	if rec.Body.String() != replay {
+		t.Errorf("stored replay = %s", rec.Body.String())
+	}
+
+	// An existing file is kept even if the server would now write another
+	if err := storeReplay(srv.replayPath(id), []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(srv.replayPath(id)); string(data) != replay {
+		t.Errorf("replay replaced with %s", data)
+	}
+}
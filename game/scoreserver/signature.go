@@ -0,0 +1,27 @@
+package scoreserver
+
+import (
+	"strings"
+
+	"stellar-siege/game/scoresig"
+)
+
+// checkSignature reports whether a submission is signed by its public key,
+// over the same fields systems.OnlineScore.Sign signs
+func checkSignature(sub *Submission) bool {
+	return scoresig.Verify(sub.PublicKey, sub.Signature, scoresig.Fields{
+		PlayerName: sub.PlayerName,
+		Country:    sub.Country,
+		Score:      sub.Score,
+		Wave:       sub.Wave,
+		Difficulty: sub.Difficulty,
+		Version:    sub.Version,
+		Seed:       sub.Seed,
+	})
+}
+
+// pilotKeyName is the name a player's key is registered under, as in
+// systems.PilotKeyName
+func pilotKeyName(playerName string) string {
+	return strings.ToLower(strings.TrimSpace(playerName))
+}
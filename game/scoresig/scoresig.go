@@ -0,0 +1,65 @@
+// Package scoresig defines what a leaderboard entry's signature covers. The
+// game signs and checks entries with it and the score server checks
+// submissions with it, so both always agree on the signed bytes.
+package scoresig
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"encoding/hex"
+)
+
+// payloadTag starts every payload, so a signature over an entry is never
+// valid for anything else signed with the same key
+const payloadTag = "stellar-siege score v1"
+
+// Fields are the signed fields of a leaderboard entry
+type Fields struct {
+	PlayerName string
+	Country    string // Empty when the pilot shares none
+	Score      int64
+	Wave       int
+	Difficulty string
+	Version    string
+	Seed       int64
+}
+
+// Payload returns the bytes covered by an entry's signature: every field in
+// a fixed order, strings prefixed with their length and numbers in eight
+// bytes, so no two entries share a payload
+func (f Fields) Payload() []byte {
+	b := appendString(nil, payloadTag)
+	b = appendString(b, f.PlayerName)
+	b = appendString(b, f.Country)
+	b = binary.BigEndian.AppendUint64(b, uint64(f.Score))
+	b = binary.BigEndian.AppendUint64(b, uint64(f.Wave))
+	b = appendString(b, f.Difficulty)
+	b = appendString(b, f.Version)
+	return binary.BigEndian.AppendUint64(b, uint64(f.Seed))
+}
+
+// Sign signs the fields with key and returns the hex encoded public key and
+// signature
+func Sign(key ed25519.PrivateKey, f Fields) (publicKey, signature string) {
+	return hex.EncodeToString(key.Public().(ed25519.PublicKey)), hex.EncodeToString(ed25519.Sign(key, f.Payload()))
+}
+
+// Verify reports whether signature is a signature of the fields by
+// publicKey, both hex encoded
+func Verify(publicKey, signature string, f Fields) bool {
+	pub, err := hex.DecodeString(publicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return false
+	}
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(pub, f.Payload(), sig)
+}
+
+// appendString appends s prefixed with its length
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
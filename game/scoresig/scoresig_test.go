@@ -0,0 +1,46 @@
+package scoresig
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"testing"
+)
+
+func TestPayloadsNeverCollide(t *testing.T) {
+	base := Fields{PlayerName: "Ace", Country: "NO", Score: 100, Wave: 2, Difficulty: "Normal", Version: "1.1.0", Seed: 7}
+	variants := []Fields{
+		// Text moved between neighbouring fields
+		{PlayerName: "Ace|NO", Score: 100, Wave: 2, Difficulty: "Normal", Version: "1.1.0", Seed: 7},
+		{PlayerName: "Ace", Country: "NO", Score: 100, Wave: 2, Difficulty: "Normal|1.1.0", Seed: 7},
+		{PlayerName: "Ace", Country: "NO", Score: 100, Wave: 2, Difficulty: "Norma", Version: "l1.1.0", Seed: 7},
+		// The country left out, or numbers swapped
+		{PlayerName: "Ace", Score: 100, Wave: 2, Difficulty: "Normal", Version: "1.1.0", Seed: 7},
+		{PlayerName: "Ace", Country: "NO", Score: 2, Wave: 100, Difficulty: "Normal", Version: "1.1.0", Seed: 7},
+		{PlayerName: "Ace", Country: "NO", Score: 100, Wave: 2, Difficulty: "Normal", Version: "1.1.0", Seed: -7},
+	}
+	for _, v := range variants {
+		if bytes.Equal(v.Payload(), base.Payload()) {
+			t.Errorf("%+v has the payload of %+v", v, base)
+		}
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(nil)
+	f := Fields{PlayerName: "Ace", Country: "NO", Score: 100, Wave: 2, Difficulty: "Normal", Version: "1.1.0", Seed: 7}
+	pub, sig := Sign(key, f)
+	if !Verify(pub, sig, f) {
+		t.Fatal("signature does not verify")
+	}
+
+	changed := f
+	changed.Country = ""
+	if Verify(pub, sig, changed) {
+		t.Error("signature verifies without the country")
+	}
+	_, other, _ := ed25519.GenerateKey(nil)
+	otherPub, _ := Sign(other, f)
+	if Verify(otherPub, sig, f) || Verify("zz", sig, f) || Verify(pub, "zz", f) {
+		t.Error("signature verifies under the wrong key or encoding")
+	}
+}
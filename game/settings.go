@@ -0,0 +1,51 @@
+package game
+
+import (
+	"log"
+	"time"
+
+	"stellar-siege/game/systems"
+)
+
+// loadSettings applies the settings saved in an earlier session
+func (g *Game) loadSettings() {
+	s, err := systems.LoadSettings(g.profileStore)
+	if err != nil {
+		log.Printf("Failed to load settings: %v", err)
+	}
+	g.applySettings(s)
+}
+
+// applySettings puts settings into effect
+func (g *Game) applySettings(s systems.Settings) {
+	g.menu.SoundEnabled = s.SoundEnabled
+	g.menu.SelectedDifficulty = s.Difficulty
+	g.selectedDifficulty = DifficultyMode(s.Difficulty)
+	g.sound.SetEnabled(s.SoundEnabled)
+	g.sound.SetVolume(s.Volume)
+	g.keys = s.Keys
+	g.menu.Keys = s.Keys
+	g.country = s.Country
+	g.settingsModified = s.Modified
+}
+
+// currentSettings returns the settings in effect
+func (g *Game) currentSettings() systems.Settings {
+	return systems.Settings{
+		SoundEnabled: g.menu.SoundEnabled,
+		Volume:       g.sound.GetVolume(),
+		Difficulty:   g.menu.SelectedDifficulty,
+		Keys:         g.keys,
+		Country:      g.country,
+		Modified:     g.settingsModified,
+	}
+}
+
+// saveSettings stores the settings after the player changed them, for the
+// next session
+func (g *Game) saveSettings() {
+	g.settingsModified = time.Now()
+	if err := systems.SaveSettings(g.profileStore, g.currentSettings()); err != nil {
+		log.Printf("Failed to save settings: %v", err)
+	}
+}
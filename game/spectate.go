@@ -0,0 +1,70 @@
+package game
+
+import (
+	"log"
+	"math"
+
+	"stellar-siege/game/spectate"
+)
+
+// StartSpectatorServer serves a live view of every run on addr, such as
+// ":8090", for teammates to watch in a browser on another screen
+func (g *Game) StartSpectatorServer(addr string) error {
+	s := spectate.NewServer(spectate.Info{Width: ScreenWidth, Height: ScreenHeight, Version: Version})
+	if err := s.Listen(addr); err != nil {
+		return err
+	}
+	g.spectators = s
+	log.Printf("Spectator view on http://%s/", s.Addr())
+	return nil
+}
+
+// broadcastSpectatorFrame sends the tick just simulated to the spectators
+func (g *Game) broadcastSpectatorFrame() {
+	if g.spectators == nil || g.spectators.Viewers() == 0 {
+		return
+	}
+	g.spectators.Broadcast(g.spectatorFrame())
+}
+
+// spectatorFrame captures what spectators see of the current tick
+func (g *Game) spectatorFrame() *spectate.Frame {
+	px := func(v float64) int { return int(math.Round(v)) }
+	f := &spectate.Frame{
+		Tick:    g.tick,
+		Score:   g.score,
+		Wave:    g.wave,
+		Over:    g.state == StateGameOver,
+		Ships:   make([]spectate.Ship, 0, len(g.pilots)),
+		Enemies: make([]spectate.Body, 0, len(g.enemies)),
+		Shots:   make([]spectate.Body, 0, len(g.projectiles)),
+	}
+	for _, pl := range g.pilots {
+		s := pl.ship
+		f.Ships = append(f.Ships, spectate.Ship{
+			X: px(s.X), Y: px(s.Y),
+			Health: max(0, s.Health), MaxHealth: s.MaxHealth,
+			Shield: s.Shield, MaxShield: s.MaxShield,
+			Down: !s.Active,
+		})
+	}
+	for _, e := range g.enemies {
+		if e.Active {
+			f.Enemies = append(f.Enemies, spectate.Body{px(e.X), px(e.Y), px(e.Radius), int(e.Type)})
+		}
+	}
+	for _, p := range g.projectiles {
+		if !p.Active {
+			continue
+		}
+		friendly := 0
+		if p.Friendly {
+			friendly = 1
+		}
+		f.Shots = append(f.Shots, spectate.Body{px(p.X), px(p.Y), px(p.Radius), friendly})
+	}
+	if b := g.boss; b != nil && b.Active {
+		f.Boss = &spectate.Boss{X: px(b.X), Y: px(b.Y), Radius: px(b.Radius), Health: max(0, b.Health), MaxHealth: b.MaxHealth}
+	}
+	return f
+}
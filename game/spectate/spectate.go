@@ -0,0 +1,250 @@
+// Package spectate serves a live view of a run to other screens on the local
+// network: a bundled HTML/canvas viewer at "/" and a WebSocket at "/ws" that
+// pushes the state of every simulated tick.
+package spectate
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Viewers that fall this many frames behind skip frames until they catch up
+const viewerQueue = 8
+
+// writeTimeout drops viewers whose connection stopped taking frames
+const writeTimeout = 5 * time.Second
+
+//go:embed viewer.html
+var viewerPage []byte
+
+// Info describes the game to viewers as they connect
+type Info struct {
+	Width   int    `json:"w"`
+	Height  int    `json:"h"`
+	Version string `json:"v"`
+}
+
+// Frame is the state of one tick as spectators see it. Positions are
+// rounded to whole pixels and bodies packed into arrays to keep the frames
+// sent 60 times a second small.
+type Frame struct {
+	Tick    int    `json:"t"`
+	Score   int64  `json:"s"`
+	Wave    int    `json:"w"`
+	Over    bool   `json:"o,omitempty"` // The run has ended
+	Ships   []Ship `json:"p"`
+	Enemies []Body `json:"e"`
+	Shots   []Body `json:"x"`
+	Boss    *Boss  `json:"b,omitempty"`
+}
+
+// Ship is a pilot's ship
+type Ship struct {
+	X         int  `json:"x"`
+	Y         int  `json:"y"`
+	Health    int  `json:"h"`
+	MaxHealth int  `json:"mh"`
+	Shield    int  `json:"s"`
+	MaxShield int  `json:"ms"`
+	Down      bool `json:"d,omitempty"`
+}
+
+// Body is an enemy or projectile: x, y, radius and kind. An enemy's kind is
+// its type; a projectile's is 1 when a pilot fired it and 0 otherwise.
+type Body [4]int
+
+// Boss is the boss of a boss wave
+type Boss struct {
+	X         int `json:"x"`
+	Y         int `json:"y"`
+	Radius    int `json:"r"`
+	Health    int `json:"h"`
+	MaxHealth int `json:"mh"`
+}
+
+// viewer is a connected WebSocket client
+type viewer struct {
+	conn net.Conn
+	send chan []byte
+
+	mu sync.Mutex // Serializes writes from the frame writer and control replies
+	w  *bufio.Writer
+}
+
+// write sends one frame, dropping the viewer if its connection stalls
+func (v *viewer) write(op byte, payload []byte) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return writeFrame(v.w, op, payload)
+}
+
+// Server pushes frames to every connected viewer
+type Server struct {
+	info []byte // Sent to each viewer as it connects
+
+	mu      sync.Mutex
+	viewers map[*viewer]struct{}
+	http    *http.Server
+	addr    net.Addr
+	closed  bool
+}
+
+// NewServer creates a server for the game described by info. Serve its
+// Handler, or call Listen.
+func NewServer(info Info) *Server {
+	msg, _ := json.Marshal(struct {
+		Info Info `json:"info"`
+	}{info})
+	return &Server{info: msg, viewers: make(map[*viewer]struct{})}
+}
+
+// Handler serves the viewer page at "/" and the frames at "/ws"
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(viewerPage)
+	})
+	mux.HandleFunc("/ws", s.handleWebSocket)
+	return mux
+}
+
+// Listen serves the viewer on addr, such as ":8090", in the background
+func (s *Server) Listen(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.addr = ln.Addr()
+	s.http = &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	srv := s.http
+	s.mu.Unlock()
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Spectator server stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Addr returns the address Listen serves on
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addr
+}
+
+// Viewers returns the number of connected viewers
+func (s *Server) Viewers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.viewers)
+}
+
+// Broadcast sends a frame to every viewer. It never blocks the game: a
+// viewer whose queue is full misses the frame.
+func (s *Server) Broadcast(f *Frame) {
+	if s.Viewers() == 0 {
+		return
+	}
+	msg, err := json.Marshal(f)
+	if err != nil {
+		log.Printf("Failed to encode spectator frame: %v", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for v := range s.viewers {
+		select {
+		case v.send <- msg:
+		default:
+		}
+	}
+}
+
+// Close stops the server and disconnects every viewer
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	srv := s.http
+	for v := range s.viewers {
+		v.conn.Close()
+	}
+	s.mu.Unlock()
+	if srv != nil {
+		return srv.Close()
+	}
+	return nil
+}
+
+// handleWebSocket upgrades a viewer's connection and streams frames to it
+// until either side closes
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, rw, err := upgrade(w, r)
+	if err != nil {
+		return
+	}
+	v := &viewer{conn: conn, send: make(chan []byte, viewerQueue), w: rw.Writer}
+	v.send <- s.info
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		conn.Close()
+		return
+	}
+	s.viewers[v] = struct{}{}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.readControl(v, rw.Reader)
+	}()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.viewers, v)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+	for {
+		select {
+		case msg := <-v.send:
+			if err := v.write(opText, msg); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// readControl answers a viewer's pings and close until it disconnects.
+// Viewers send nothing else.
+func (s *Server) readControl(v *viewer, r *bufio.Reader) {
+	for {
+		op, payload, err := readFrame(r)
+		if err != nil {
+			return
+		}
+		switch op {
+		case opPing:
+			if v.write(opPong, payload) != nil {
+				return
+			}
+		case opClose:
+			v.write(opClose, payload)
+			return
+		}
+	}
+}
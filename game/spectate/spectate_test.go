@@ -0,0 +1,217 @@
+package spectate
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testViewer is a minimal WebSocket client
+type testViewer struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func dial(t *testing.T, srv *httptest.Server) *testViewer {
+	t.Helper()
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	io.WriteString(conn, "GET /ws HTTP/1.1\r\nHost: "+srv.Listener.Addr().String()+"\r\n"+
+		"Upgrade: websocket\r\nConnection: keep-alive, Upgrade\r\n"+
+		"Sec-WebSocket-Key: "+key+"\r\nSec-WebSocket-Version: 13\r\n\r\n")
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake answered %s", resp.Status)
+	}
+	// The accept key from RFC 6455's own example
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("accept key %q", got)
+	}
+	return &testViewer{conn: conn, r: r}
+}
+
+// read returns the next server frame
+func (v *testViewer) read(t *testing.T) (byte, []byte) {
+	t.Helper()
+	var hdr [2]byte
+	if _, err := io.ReadFull(v.r, hdr[:]); err != nil {
+		t.Fatal(err)
+	}
+	if hdr[1]&0x80 != 0 {
+		t.Fatal("server frame is masked")
+	}
+	n := uint64(hdr[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		io.ReadFull(v.r, ext[:])
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		io.ReadFull(v.r, ext[:])
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(v.r, payload); err != nil {
+		t.Fatal(err)
+	}
+	return hdr[0] & 0x0F, payload
+}
+
+// write sends a masked frame, as browsers do
+func (v *testViewer) write(op byte, payload []byte) {
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | op, 0x80 | byte(len(payload))}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	v.conn.Write(frame)
+}
+
+func waitForViewers(t *testing.T, s *Server, n int) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for s.Viewers() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d viewers connected, want %d", s.Viewers(), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestViewersReceiveFrames(t *testing.T) {
+	s := NewServer(Info{Width: 1280, Height: 720, Version: "1.1.0"})
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	defer s.Close()
+
+	v := dial(t, srv)
+	if op, msg := v.read(t); op != opText || string(msg) != `{"info":{"w":1280,"h":720,"v":"1.1.0"}}` {
+		t.Fatalf("first message %q", msg)
+	}
+	waitForViewers(t, s, 1)
+
+	// Large enough to need the 16-bit length
+	want := &Frame{
+		Tick: 42, Score: 1500, Wave: 3,
+		Ships: []Ship{{X: 640, Y: 620, Health: 80, MaxHealth: 100, Shield: 10, MaxShield: 50}, {X: 300, Y: 600, MaxHealth: 100, Down: true}},
+		Boss:  &Boss{X: 640, Y: 120, Radius: 80, Health: 900, MaxHealth: 1000},
+	}
+	for i := 0; i < 40; i++ {
+		want.Enemies = append(want.Enemies, Body{i * 30, 100, 15, i % 4})
+		want.Shots = append(want.Shots, Body{i * 30, 500, 4, 1})
+	}
+	s.Broadcast(want)
+
+	op, msg := v.read(t)
+	if op != opText || len(msg) < 126 {
+		t.Fatalf("frame op %d of %d bytes", op, len(msg))
+	}
+	var got Frame
+	if err := json.Unmarshal(msg, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Tick != 42 || got.Score != 1500 || len(got.Ships) != 2 || !got.Ships[1].Down ||
+		len(got.Enemies) != 40 || got.Enemies[5] != (Body{150, 100, 15, 1}) || got.Boss == nil || got.Boss.Health != 900 {
+		t.Errorf("viewer got %+v", got)
+	}
+
+	// Pings are answered, and a close is echoed before the viewer is dropped
+	v.write(opPing, []byte("hi"))
+	if op, msg := v.read(t); op != opPong || string(msg) != "hi" {
+		t.Errorf("ping answered with op %d %q", op, msg)
+	}
+	v.write(opClose, []byte{0x03, 0xE8})
+	if op, _ := v.read(t); op != opClose {
+		t.Errorf("close answered with op %d", op)
+	}
+	waitForViewers(t, s, 0)
+}
+
+func TestViewerPageAndPlainRequests(t *testing.T) {
+	s := NewServer(Info{Width: 1280, Height: 720})
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "<canvas") {
+		t.Errorf("viewer page: %s, %d bytes", resp.Status, len(body))
+	}
+
+	// A plain request to the socket is turned away
+	resp, err = http.Get(srv.URL + "/ws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("plain request to /ws answered %s", resp.Status)
+	}
+}
+
+func TestSlowViewerDoesNotBlockBroadcast(t *testing.T) {
+	s := NewServer(Info{})
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+	defer s.Close()
+
+	dial(t, srv) // Never reads
+	waitForViewers(t, s, 1)
+
+	frame := &Frame{Ships: []Ship{{}}}
+	for i := 0; i < 200; i++ {
+		frame.Enemies = append(frame.Enemies, Body{i, i, 10, 0})
+	}
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 1000; i++ {
+			frame.Tick = i
+			s.Broadcast(frame)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("broadcast blocked on a viewer that does not read")
+	}
+}
+
+func TestListen(t *testing.T) {
+	s := NewServer(Info{})
+	if err := s.Listen("127.0.0.1:0"); err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer s.Close()
+	resp, err := http.Get("http://" + s.Addr().String() + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("viewer page: %s", resp.Status)
+	}
+}
@@ -0,0 +1,35 @@
+package game
+
+import (
+	"testing"
+
+	"stellar-siege/game/entities"
+)
+
+func TestSpectatorFrameFollowsTheRun(t *testing.T) {
+	g := NewHeadlessCoopGame(7, DifficultyNormal, 2)
+	for i := 0; i < 300; i++ {
+		g.Step(entities.CombineInputs(entities.InputShoot, entities.InputLeft))
+	}
+	g.pilots[1].ship.Active = false
+
+	f := g.spectatorFrame()
+	if f.Tick != g.Tick() || f.Score != g.Score() || f.Wave != g.Wave() || f.Over {
+		t.Errorf("frame tick %d score %d wave %d over %v", f.Tick, f.Score, f.Wave, f.Over)
+	}
+	if len(f.Ships) != 2 || f.Ships[0].Down || !f.Ships[1].Down || f.Ships[1].X != int(g.pilots[1].ship.X+0.5) {
+		t.Errorf("ships = %+v", f.Ships)
+	}
+	friendly := 0
+	for _, s := range f.Shots {
+		friendly += s[3]
+	}
+	if friendly == 0 {
+		t.Errorf("shots = %v, want pilot 1's among them", f.Shots)
+	}
+	for _, e := range f.Enemies {
+		if e[2] <= 0 {
+			t.Errorf("enemy %v has no radius", e)
+		}
+	}
+}
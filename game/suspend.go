@@ -0,0 +1,288 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"stellar-siege/game/core"
+	"stellar-siege/game/entities"
+	"stellar-siege/game/rng"
+	"stellar-siege/game/systems"
+)
+
+// SuspendFormatVersion is the version of the suspended run file layout.
+// Saves with a different format version cannot be resumed.
+const SuspendFormatVersion = 2
+
+// suspendFileName is the data file a suspended run is kept in
+const suspendFileName = "suspended_run.json"
+
+// SuspendedRun is a snapshot of everything that determines how a run
+// continues: resuming it and feeding the same input reproduces the run
+// exactly, so its replay stays verifiable.
+type SuspendedRun struct {
+	FormatVersion int            `json:"format_version"`
+	GameVersion   string         `json:"game_version"`
+	SavedAt       time.Time      `json:"saved_at"`
+	Difficulty    DifficultyMode `json:"difficulty"`
+
+	// Simulation clock and randomness
+	Seed     int64       `json:"seed"`
+	RNGState []byte      `json:"rng_state"`
+	Tick     int         `json:"tick"`
+	GameTime float64     `json:"game_time"`
+	Clock    *core.Clock `json:"clock"`
+
+	// Scoring and wave progress
+	Score                int64                `json:"score"`
+	Wave                 int                  `json:"wave"`
+	Multiplier           float64              `json:"multiplier"`
+	ComboTimer           float64              `json:"combo_timer"`
+	BossWave             bool                 `json:"boss_wave"`
+	AsteroidSpawn        float64              `json:"asteroid_spawn"`
+	MiniBossSpawnTimer   float64              `json:"mini_boss_spawn_timer"`
+	MiniBossesSpawned    int                  `json:"mini_bosses_spawned"`
+	LastLowHealthWarning float64              `json:"last_low_health_warning"`
+	Spawner              systems.SpawnerState `json:"spawner"`
+
+	// Entities; the player includes its weapon and ability managers
+	Player      *entities.Player       `json:"player"`
+	Boss        *entities.Boss         `json:"boss,omitempty"`
+	Enemies     []*entities.Enemy      `json:"enemies"`
+	Projectiles []*entities.Projectile `json:"projectiles"`
+	PowerUps    []*entities.PowerUp    `json:"powerups"`
+	Asteroids   []*entities.Asteroid   `json:"asteroids"`
+	Hazards     []*entities.Hazard     `json:"hazards,omitempty"`
+
+	// Developer console use; such runs stay unranked after resuming
+	ConsoleUsed bool `json:"console_used,omitempty"`
+	GodMode     bool `json:"god_mode,omitempty"`
+
+	Replay *systems.Replay   `json:"replay,omitempty"`
+	Stats  *systems.RunStats `json:"stats,omitempty"`
+}
+
+// canSuspend reports whether the current run can be suspended. Co-op runs
+// need every pilot back at the keyboard, so they are not kept.
+func (g *Game) canSuspend() bool {
+	return !g.headless && !g.demoMode && !g.coop() && g.player != nil && g.player.Active &&
+		(g.state == StatePlaying || g.state == StatePaused)
+}
+
+// snapshotRun captures the current run
+func (g *Game) snapshotRun() (*SuspendedRun, error) {
+	rngState, err := g.rng.State()
+	if err != nil {
+		return nil, err
+	}
+	return &SuspendedRun{
+		FormatVersion:        SuspendFormatVersion,
+		GameVersion:          Version,
+		SavedAt:              time.Now(),
+		Difficulty:           g.selectedDifficulty,
+		Seed:                 g.seed,
+		RNGState:             rngState,
+		Tick:                 g.tick,
+		GameTime:             g.gameTime,
+		Clock:                g.clock,
+		Score:                g.score,
+		Wave:                 g.wave,
+		Multiplier:           g.multiplier,
+		ComboTimer:           g.comboTimer,
+		BossWave:             g.bossWave,
+		AsteroidSpawn:        g.asteroidSpawn,
+		MiniBossSpawnTimer:   g.miniBossSpawnTimer,
+		MiniBossesSpawned:    g.miniBossesSpawned,
+		LastLowHealthWarning: g.lastLowHealthWarning,
+		Spawner:              g.spawner.State(),
+		Player:               g.player,
+		Boss:                 g.boss,
+		Enemies:              activeOnly(g.enemies, (*entities.Enemy).IsActive),
+		Projectiles:          activeOnly(g.projectiles, (*entities.Projectile).IsActive),
+		PowerUps:             activeOnly(g.powerups, (*entities.PowerUp).IsActive),
+		Asteroids:            activeOnly(g.asteroids, (*entities.Asteroid).IsActive),
+		Hazards:              g.hazards,
+		ConsoleUsed:          g.consoleUsed,
+		GodMode:              g.godMode,
+		Replay:               g.replay,
+		Stats:                g.runStats,
+	}, nil
+}
+
+// restoreRun replaces the current run with a suspended one
+func (g *Game) restoreRun(run *SuspendedRun) error {
+	if run.FormatVersion != SuspendFormatVersion {
+		return fmt.Errorf("unsupported suspended run format %d", run.FormatVersion)
+	}
+	if run.Player == nil || run.Player.WeaponMgr == nil || run.Player.AbilityMgr == nil {
+		return errors.New("suspended run has no player")
+	}
+	r := rng.New(run.Seed)
+	if err := r.SetState(run.RNGState); err != nil {
+		return fmt.Errorf("failed to restore RNG state: %w", err)
+	}
+
+	g.selectedDifficulty = run.Difficulty
+	g.difficultyConfig = GetDifficultyConfig(run.Difficulty)
+	g.seed = run.Seed
+	g.rng = r
+	rng.Use(g.rng)
+	g.tick = run.Tick
+	g.gameTime = run.GameTime
+	g.clock = run.Clock
+	if g.clock == nil {
+		g.clock = core.NewClock()
+	}
+
+	g.score = run.Score
+	g.wave = run.Wave
+	g.multiplier = run.Multiplier
+	g.comboTimer = run.ComboTimer
+	g.bossWave = run.BossWave
+	g.asteroidSpawn = run.AsteroidSpawn
+	g.miniBossSpawnTimer = run.MiniBossSpawnTimer
+	g.miniBossesSpawned = run.MiniBossesSpawned
+	g.lastLowHealthWarning = run.LastLowHealthWarning
+
+	g.spawner = systems.NewWaveSpawner(ScreenWidth, ScreenHeight)
+	g.spawner.SetDifficultyMultipliers(g.difficultyConfig.SpawnMultiplier, g.difficultyConfig.EnemyHealthMult, g.difficultyConfig.EnemySpeedMult, g.difficultyConfig.DamageMultiplier)
+	g.spawner.RestoreState(run.Spawner)
+
+	g.player = run.Player
+	g.pilots = []*pilot{{ship: run.Player}}
+	g.players = 1
+	g.boss = run.Boss
+	g.enemies = append(g.enemies[:0], run.Enemies...)
+	g.projectiles = append(g.projectiles[:0], run.Projectiles...)
+	g.powerups = append(g.powerups[:0], run.PowerUps...)
+	g.asteroids = append(g.asteroids[:0], run.Asteroids...)
+	g.explosions = g.explosions[:0]
+	g.floatingTexts = g.floatingTexts[:0]
+	g.impactEffects = g.impactEffects[:0]
+	g.hazards = run.Hazards
+	g.consoleUsed = run.ConsoleUsed
+	g.godMode = run.GodMode
+
+	// A replay recorded by another version cannot be verified after resuming,
+	// so the run continues unrecorded
+	g.replay = run.Replay
+	if g.replay != nil && g.replay.GameVersion != Version {
+		g.replay = nil
+	}
+	g.runStats = run.Stats
+	if g.runStats == nil {
+		g.runStats = systems.NewRunStats()
+		g.runStats.StartWave(g.wave, g.gameTime)
+	}
+	g.deathCause = ""
+
+	g.demoMode = false
+	g.autopilot = nil
+	g.screenShake = 0
+	g.damageFlash = 0
+	g.hud = systems.NewHUD()
+	g.nameInputMode = false
+	g.playerName = ""
+	g.submitScorePrompt = false
+	g.scoreSubmitted = false
+	g.runSubmissionID = ""
+	return nil
+}
+
+// activeOnly returns the active entities of a slice
+func activeOnly[T any](list []T, active func(T) bool) []T {
+	out := make([]T, 0, len(list))
+	for _, item := range list {
+		if active(item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// Save writes the suspended run to the named file of store
+func (r *SuspendedRun) Save(store systems.Storage, name string) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode suspended run: %w", err)
+	}
+	if err := store.Write(name, data); err != nil {
+		return fmt.Errorf("failed to write suspended run: %w", err)
+	}
+	return nil
+}
+
+// LoadSuspendedRun reads a suspended run from the named file of store
+func LoadSuspendedRun(store systems.Storage, name string) (*SuspendedRun, error) {
+	data, err := store.Read(name)
+	if err != nil {
+		return nil, err
+	}
+	var run SuspendedRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to decode suspended run: %w", err)
+	}
+	if run.FormatVersion != SuspendFormatVersion {
+		return nil, fmt.Errorf("unsupported suspended run format %d", run.FormatVersion)
+	}
+	return &run, nil
+}
+
+// suspendRun saves the current run so it can be continued from the menu
+func (g *Game) suspendRun() error {
+	if !g.canSuspend() {
+		return nil
+	}
+	run, err := g.snapshotRun()
+	if err != nil {
+		return err
+	}
+	if err := run.Save(g.profileStore, suspendFileName); err != nil {
+		return err
+	}
+	g.refreshContinueEntry()
+	return nil
+}
+
+// resumeSuspendedRun continues the suspended run, starting paused. The save
+// is removed so a run can only be continued once.
+func (g *Game) resumeSuspendedRun() error {
+	run, err := LoadSuspendedRun(g.profileStore, suspendFileName)
+	if err != nil {
+		return err
+	}
+	if err := g.restoreRun(run); err != nil {
+		return err
+	}
+	g.profileStore.Remove(suspendFileName)
+	g.refreshContinueEntry()
+
+	g.transitionToState(StatePlaying)
+	g.transitionToState(StatePaused)
+	return nil
+}
+
+// discardSuspendedRun abandons the suspended run, if any
+func (g *Game) discardSuspendedRun() {
+	if g.headless {
+		return
+	}
+	g.profileStore.Remove(suspendFileName)
+	g.refreshContinueEntry()
+}
+
+// refreshContinueEntry updates the main menu's "Continue run" entry
+func (g *Game) refreshContinueEntry() {
+	if g.menu == nil {
+		return
+	}
+	g.menu.ContinueRun = ""
+	run, err := LoadSuspendedRun(g.profileStore, suspendFileName)
+	if err != nil {
+		return
+	}
+	g.menu.ContinueRun = fmt.Sprintf("%s - Wave %d - %s",
+		GetDifficultyName(run.Difficulty), run.Wave, systems.FormatNumber(run.Score))
+}
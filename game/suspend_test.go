@@ -0,0 +1,76 @@
+package game
+
+import (
+	"testing"
+
+	"stellar-siege/game/systems"
+)
+
+func TestSuspendedRunResumesIdentically(t *testing.T) {
+	const suspendAt, total = 60 * 40, 60 * 80
+
+	// Reference: one uninterrupted run
+	ref := NewHeadlessGame(3, DifficultyNormal)
+	for i := 0; i < total && ref.Step(scriptedInput(i)); i++ {
+	}
+
+	// Same run, suspended to disk half way and resumed in a fresh game
+	g := NewHeadlessGame(3, DifficultyNormal)
+	for i := 0; i < suspendAt; i++ {
+		if !g.Step(scriptedInput(i)) {
+			t.Fatalf("run ended at tick %d before it could be suspended", g.Tick())
+		}
+	}
+	snapshot, err := g.snapshotRun()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := systems.NewMemoryStorage()
+	if err := snapshot.Save(store, suspendFileName); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSuspendedRun(store, suspendFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resumed := NewHeadlessGame(999, DifficultyEasy)
+	if err := resumed.restoreRun(loaded); err != nil {
+		t.Fatal(err)
+	}
+	for i := suspendAt; i < total && resumed.Step(scriptedInput(i)); i++ {
+	}
+
+	if resumed.Score() != ref.Score() || resumed.Wave() != ref.Wave() || resumed.Tick() != ref.Tick() {
+		t.Fatalf("resumed run diverged: score %d/%d, wave %d/%d, ticks %d/%d",
+			resumed.Score(), ref.Score(), resumed.Wave(), ref.Wave(), resumed.Tick(), ref.Tick())
+	}
+	if resumed.player.X != ref.player.X || resumed.player.Health != ref.player.Health || len(resumed.enemies) != len(ref.enemies) {
+		t.Fatal("resumed run's entities diverged from the uninterrupted run")
+	}
+
+	// The replay carried across the suspend still verifies
+	if resumed.state != StatePlaying {
+		if record := VerifyReplay(resumed.Replay()); !record.Verified {
+			t.Fatalf("replay of resumed run failed verification: %s", record.Reason)
+		}
+	}
+}
+
+func TestLoadSuspendedRunRejectsOtherFormats(t *testing.T) {
+	g := NewHeadlessGame(1, DifficultyNormal)
+	g.Step(0)
+	snapshot, err := g.snapshotRun()
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot.FormatVersion = SuspendFormatVersion + 1
+
+	store := systems.NewMemoryStorage()
+	if err := snapshot.Save(store, suspendFileName); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSuspendedRun(store, suspendFileName); err == nil {
+		t.Fatal("suspended run with a newer format was loaded")
+	}
+}
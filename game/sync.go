@@ -0,0 +1,136 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"stellar-siege/game/systems"
+)
+
+// syncResult is the outcome of a save sync run in the background
+type syncResult struct {
+	profileID string
+	local     *systems.SyncSnapshot // Local data the sync started from
+	merged    *systems.SyncSnapshot
+	conflicts []systems.SyncConflict
+	err       error
+}
+
+// syncFileName names the gist file that holds a pilot's synced save
+func syncFileName(p systems.Profile) string {
+	return "pilot_" + p.SyncKey + ".json"
+}
+
+// syncDeviceName identifies this install in synced saves
+func syncDeviceName() string {
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return host
+	}
+	return "unknown"
+}
+
+// initSaveSync enables save sync when a sync gist and token are configured
+func (g *Game) initSaveSync(config *systems.GistConfig) {
+	if config.SyncGistID == "" || config.GitHubToken == "" {
+		return
+	}
+	g.saveSync = systems.NewGistSync(config.SyncGistID, config.GitHubToken)
+	g.syncDevice = syncDeviceName()
+	g.syncDone = make(chan syncResult, 1)
+}
+
+// captureSyncSnapshot captures the active pilot's synced data
+func (g *Game) captureSyncSnapshot(base *systems.SyncSnapshot) *systems.SyncSnapshot {
+	return systems.CaptureSyncSnapshot(base, g.syncDevice, g.progression, g.achievements, g.currentSettings())
+}
+
+// startSaveSync syncs the active pilot's save with the gist in the
+// background. The network work only sees snapshots; pollSaveSync applies
+// the result on the game's goroutine.
+func (g *Game) startSaveSync() {
+	if g.saveSync == nil || g.syncing || g.progression == nil {
+		return
+	}
+	p := g.profiles.Active()
+	if g.profiles.SyncKeyShared(p.ID) {
+		// Two pilots saved under one name before sync keys existed; neither
+		// may overwrite the other's save
+		log.Printf("Not syncing %s: another pilot syncs to %s", p.Name, syncFileName(p))
+		g.menu.SyncStatus = "Cloud save: off, another pilot uses the same save (rename one)"
+		return
+	}
+	base := systems.LoadSyncBase(g.profileStore)
+	local := g.captureSyncSnapshot(base)
+	g.syncing = true
+	g.menu.SyncStatus = "Cloud save: syncing..."
+
+	client := g.saveSync
+	done := g.syncDone
+	go func() {
+		merged, conflicts, err := client.Sync(syncFileName(p), base, local)
+		done <- syncResult{profileID: p.ID, local: local, merged: merged, conflicts: conflicts, err: err}
+	}()
+}
+
+// pollSaveSync applies a finished save sync. Results wait while a run is in
+// progress so progression never changes under the player.
+func (g *Game) pollSaveSync() {
+	if g.pendingSync == nil && g.syncDone != nil {
+		select {
+		case res := <-g.syncDone:
+			g.syncing = false
+			g.pendingSync = &res
+		default:
+		}
+	}
+	if g.pendingSync == nil || g.state == StatePlaying || g.state == StatePaused {
+		return
+	}
+	res := *g.pendingSync
+	g.pendingSync = nil
+	g.finishSaveSync(res)
+}
+
+// finishSaveSync stores the merged save and puts it into effect
+func (g *Game) finishSaveSync(res syncResult) {
+	if res.err != nil {
+		log.Printf("Failed to sync save: %v", res.err)
+		g.menu.SyncStatus = "Cloud save: offline"
+		return
+	}
+
+	// The pilot changed or played on while the sync ran; sync again with
+	// the current data rather than overwrite it
+	base := systems.LoadSyncBase(g.profileStore)
+	if res.profileID != g.profiles.Active().ID || !g.captureSyncSnapshot(base).Equal(res.local) {
+		g.startSaveSync()
+		return
+	}
+
+	s := g.currentSettings()
+	systems.ApplySyncSnapshot(res.merged, g.progression, g.achievements, &s)
+	if err := g.progression.Save(); err != nil {
+		log.Printf("Failed to save synced progression: %v", err)
+	}
+	if err := g.achievements.Save(); err != nil {
+		log.Printf("Failed to save synced achievements: %v", err)
+	}
+	if err := systems.SaveSettings(g.profileStore, s); err != nil {
+		log.Printf("Failed to save synced settings: %v", err)
+	}
+	g.applySettings(s)
+	if err := systems.SaveSyncBase(g.profileStore, res.merged); err != nil {
+		log.Printf("Failed to save sync base: %v", err)
+	}
+
+	g.syncConflicts = res.conflicts
+	for _, c := range res.conflicts {
+		log.Printf("Save sync conflict: %s", c)
+	}
+	if len(res.conflicts) > 0 {
+		g.menu.SyncStatus = fmt.Sprintf("Cloud save: synced, %d conflict(s) resolved (P for details)", len(res.conflicts))
+	} else {
+		g.menu.SyncStatus = "Cloud save: synced"
+	}
+}
@@ -1,7 +1,6 @@
 package systems
 
 import (
-	"encoding/json"
 	"os"
 	"sort"
 	"time"
@@ -43,14 +42,24 @@ type AchievementReward struct {
 // AchievementManager manages all achievements
 type AchievementManager struct {
 	Achievements map[string]*Achievement
-	dataPath     string
+	store        Storage
+	fileName     string
 }
 
-// NewAchievementManager creates a new achievement manager
-func NewAchievementManager(dataPath string) *AchievementManager {
+// achievementSchema is the save format of achievement progress. Version 1
+// wrapped the achievement list in a versioned envelope.
+var achievementSchema = SaveSchema{
+	Kind:       "achievements",
+	Version:    1,
+	Migrations: map[int]SaveMigration{0: unversioned},
+}
+
+// NewAchievementManager creates an achievement manager kept in the named file of store
+func NewAchievementManager(store Storage, fileName string) *AchievementManager {
 	am := &AchievementManager{
 		Achievements: make(map[string]*Achievement),
-		dataPath:     dataPath,
+		store:        store,
+		fileName:     fileName,
 	}
 	am.initializeAchievements()
 	am.Load()
@@ -356,18 +365,13 @@ func (am *AchievementManager) Save() error {
 		achievements = append(achievements, ach)
 	}
 
-	data, err := json.MarshalIndent(achievements, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(am.dataPath, data, 0644)
+	return WriteSaveFile(am.store, am.fileName, achievementSchema, achievements)
 }
 
 // Load loads achievements from JSON file
 func (am *AchievementManager) Load() error {
-	data, err := os.ReadFile(am.dataPath)
-	if err != nil {
+	var achievements []*Achievement
+	if err := ReadSaveFile(am.store, am.fileName, achievementSchema, &achievements); err != nil {
 		// File doesn't exist yet, that's okay
 		if os.IsNotExist(err) {
 			return nil
@@ -375,11 +379,6 @@ func (am *AchievementManager) Load() error {
 		return err
 	}
 
-	var achievements []*Achievement
-	if err := json.Unmarshal(data, &achievements); err != nil {
-		return err
-	}
-
 	// Update loaded data into our achievements map
 	for _, ach := range achievements {
 		if existing, exists := am.Achievements[ach.ID]; exists {
@@ -3,6 +3,8 @@ package systems
 import (
 	"math"
 	"math/rand"
+
+	"stellar-siege/game/entities"
 )
 
 // CameraSystem manages camera zoom, shake, and cinematic effects
@@ -38,17 +40,18 @@ func NewCameraSystem(screenWidth, screenHeight int) *CameraSystem {
 
 // Update updates camera state (zoom transitions, shake decay, cinematic mode)
 // Parameters:
+//   - deltaTime: seconds to advance by
 //   - bossWave: whether a boss wave is active
 //   - bossActive: whether the boss entity is active
 //   - enemyCount: number of active enemies
 //   - playerHealthRatio: player health / max health (0.0 to 1.0)
 //   - waveCompleted: whether the current wave is completed
-func (c *CameraSystem) Update(bossWave bool, bossActive bool, enemyCount int, playerHealthRatio float64, waveCompleted bool) {
+func (c *CameraSystem) Update(deltaTime float64, bossWave bool, bossActive bool, enemyCount int, playerHealthRatio float64, waveCompleted bool) {
 	// Smooth zoom transitions
 	zoomDifference := c.TargetZoom - c.Zoom
 	if math.Abs(zoomDifference) > 0.01 {
 		// Smooth interpolation towards target zoom
-		c.Zoom += zoomDifference * 0.1 // Smooth lerp
+		c.Zoom += zoomDifference * (1 - entities.Decay(0.9, deltaTime)) // Smooth lerp
 	} else {
 		c.Zoom = c.TargetZoom
 	}
@@ -64,7 +67,7 @@ func (c *CameraSystem) Update(bossWave bool, bossActive bool, enemyCount int, pl
 
 	// Update cinematic mode timer
 	if c.CinematicMode {
-		c.CinematicTimer += 1.0 / 60.0
+		c.CinematicTimer += deltaTime
 		if c.CinematicTimer > 2.5 {
 			// Exit cinematic mode after 2.5 seconds
 			c.CinematicMode = false
@@ -103,7 +106,7 @@ func (c *CameraSystem) Update(bossWave bool, bossActive bool, enemyCount int, pl
 
 	// Decay screen shake more gradually for better feel
 	if c.ShakeAmount > 0.1 {
-		c.ShakeAmount *= 0.92 // Slightly slower decay for better feel
+		c.ShakeAmount *= entities.Decay(0.92, deltaTime) // Slightly slower decay for better feel
 	} else {
 		c.ShakeAmount = 0
 	}
@@ -17,6 +17,30 @@ const (
 	ChallengeModeDaily
 )
 
+// ChallengeModes lists every challenge mode in menu order
+var ChallengeModes = []ChallengeMode{
+	ChallengeModeEndless,
+	ChallengeModeBossRush,
+	ChallengeModeTimeAttack,
+	ChallengeModeSurvival,
+	ChallengeModeDaily,
+}
+
+// String returns the mode's display name, as used in its configuration
+func (m ChallengeMode) String() string {
+	switch m {
+	case ChallengeModeBossRush:
+		return "Boss Rush"
+	case ChallengeModeTimeAttack:
+		return "Time Attack"
+	case ChallengeModeSurvival:
+		return "Survival"
+	case ChallengeModeDaily:
+		return "Daily Challenge"
+	}
+	return "Endless"
+}
+
 // ChallengeConfig represents configuration for a challenge mode
 type ChallengeConfig struct {
 	Mode              ChallengeMode `json:"mode"`
@@ -51,15 +75,39 @@ type ChallengeScore struct {
 type ChallengeManager struct {
 	Config       map[ChallengeMode]ChallengeConfig
 	Leaderboards map[ChallengeMode][]*ChallengeScore
-	dataPath     string
+	store        Storage
+	fileName     string
+}
+
+// challengeSaveData is the saved part of the challenge manager; the
+// challenge configuration is defined in code
+type challengeSaveData struct {
+	Leaderboards map[ChallengeMode][]*ChallengeScore `json:"leaderboards"`
+}
+
+// challengeSchema is the save format of challenge leaderboards. Version 1
+// dropped the configuration that version 0 saved alongside the leaderboards.
+var challengeSchema = SaveSchema{
+	Kind:    "challenges",
+	Version: 1,
+	Migrations: map[int]SaveMigration{
+		0: func(data json.RawMessage) (json.RawMessage, error) {
+			var old map[string]json.RawMessage
+			if err := json.Unmarshal(data, &old); err != nil {
+				return nil, err
+			}
+			return json.Marshal(map[string]json.RawMessage{"leaderboards": old["leaderboards"]})
+		},
+	},
 }
 
-// NewChallengeManager creates a new challenge manager
-func NewChallengeManager(dataPath string) *ChallengeManager {
+// NewChallengeManager creates a challenge manager kept in the named file of store
+func NewChallengeManager(store Storage, fileName string) *ChallengeManager {
 	cm := &ChallengeManager{
 		Config:       make(map[ChallengeMode]ChallengeConfig),
 		Leaderboards: make(map[ChallengeMode][]*ChallengeScore),
-		dataPath:     dataPath,
+		store:        store,
+		fileName:     fileName,
 	}
 
 	cm.initializeChallenges()
@@ -260,23 +308,13 @@ func (cm *ChallengeManager) GetTotalUnlockedChallenges() int {
 
 // Save saves challenge data to file
 func (cm *ChallengeManager) Save() error {
-	// Save leaderboards
-	data := make(map[string]interface{})
-	data["leaderboards"] = cm.Leaderboards
-	data["config"] = cm.Config
-
-	jsonData, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(cm.dataPath, jsonData, 0644)
+	return WriteSaveFile(cm.store, cm.fileName, challengeSchema, challengeSaveData{Leaderboards: cm.Leaderboards})
 }
 
 // Load loads challenge data from file
 func (cm *ChallengeManager) Load() error {
-	jsonData, err := os.ReadFile(cm.dataPath)
-	if err != nil {
+	var data challengeSaveData
+	if err := ReadSaveFile(cm.store, cm.fileName, challengeSchema, &data); err != nil {
 		// File doesn't exist, that's okay
 		if os.IsNotExist(err) {
 			return nil
@@ -284,20 +322,9 @@ func (cm *ChallengeManager) Load() error {
 		return err
 	}
 
-	var data map[string]interface{}
-	if err := json.Unmarshal(jsonData, &data); err != nil {
-		return err
-	}
-
 	// Load leaderboards
-	if lbData, exists := data["leaderboards"]; exists {
-		if lbMap, ok := lbData.(map[string]interface{}); ok {
-			for modeStr, scores := range lbMap {
-				// Would need proper unmarshaling here
-				_ = modeStr
-				_ = scores
-			}
-		}
+	for mode, scores := range data.Leaderboards {
+		cm.Leaderboards[mode] = scores
 	}
 
 	return nil
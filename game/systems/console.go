@@ -0,0 +1,256 @@
+package systems
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Console layout and limits
+const (
+	consoleMaxOutput  = 200 // Lines of scrollback kept
+	consoleMaxHistory = 50  // Commands kept in history
+	consoleVisible    = 12  // Output lines shown above the prompt
+	consoleLineHeight = 18
+	consolePrompt     = "> "
+)
+
+// ConsoleCommand is a command that can be run from the developer console
+type ConsoleCommand struct {
+	Name  string
+	Usage string
+	// Args returns the completions for the argument at argIndex (0 is the
+	// first argument after the command name); nil means no completion
+	Args func(argIndex int) []string
+	// Run executes the command and returns a line to print
+	Run func(args []string) (string, error)
+}
+
+// Console is a toggleable command line overlay with history and tab completion
+type Console struct {
+	Open bool
+
+	input      string
+	history    []string
+	historyPos int // Index into history while browsing; len(history) when not
+	output     []string
+	commands   map[string]*ConsoleCommand
+}
+
+// NewConsole creates an empty, closed console
+func NewConsole() *Console {
+	return &Console{
+		commands: make(map[string]*ConsoleCommand),
+	}
+}
+
+// Register adds a command to the console, replacing one with the same name
+func (c *Console) Register(cmd ConsoleCommand) {
+	c.commands[cmd.Name] = &cmd
+}
+
+// Commands returns the registered commands sorted by name
+func (c *Console) Commands() []*ConsoleCommand {
+	cmds := make([]*ConsoleCommand, 0, len(c.commands))
+	for _, cmd := range c.commands {
+		cmds = append(cmds, cmd)
+	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].Name < cmds[j].Name })
+	return cmds
+}
+
+// Toggle opens or closes the console
+func (c *Console) Toggle() {
+	c.Open = !c.Open
+}
+
+// Input returns the current contents of the command line
+func (c *Console) Input() string {
+	return c.input
+}
+
+// Output returns the scrollback, oldest line first
+func (c *Console) Output() []string {
+	return c.output
+}
+
+// Print appends a line to the scrollback
+func (c *Console) Print(line string) {
+	c.output = append(c.output, line)
+	if len(c.output) > consoleMaxOutput {
+		c.output = c.output[len(c.output)-consoleMaxOutput:]
+	}
+}
+
+// Clear empties the scrollback
+func (c *Console) Clear() {
+	c.output = c.output[:0]
+}
+
+// Type appends a character to the command line
+func (c *Console) Type(r rune) {
+	c.input += string(r)
+}
+
+// SetInput replaces the command line
+func (c *Console) SetInput(s string) {
+	c.input = s
+}
+
+// Backspace removes the last character of the command line
+func (c *Console) Backspace() {
+	if r := []rune(c.input); len(r) > 0 {
+		c.input = string(r[:len(r)-1])
+	}
+}
+
+// Execute runs the command line, records it in the history and prints its result
+func (c *Console) Execute() {
+	line := strings.TrimSpace(c.input)
+	c.input = ""
+	if line == "" {
+		return
+	}
+	c.Print(consolePrompt + line)
+	if len(c.history) == 0 || c.history[len(c.history)-1] != line {
+		c.history = append(c.history, line)
+		if len(c.history) > consoleMaxHistory {
+			c.history = c.history[1:]
+		}
+	}
+	c.historyPos = len(c.history)
+
+	fields := strings.Fields(line)
+	cmd, ok := c.commands[strings.ToLower(fields[0])]
+	if !ok {
+		c.Print(fmt.Sprintf("unknown command %q (try help)", fields[0]))
+		return
+	}
+	result, err := cmd.Run(fields[1:])
+	if err != nil {
+		c.Print("error: " + err.Error())
+		if cmd.Usage != "" {
+			c.Print("usage: " + cmd.Usage)
+		}
+		return
+	}
+	if result != "" {
+		c.Print(result)
+	}
+}
+
+// HistoryUp replaces the command line with the previous command in the history
+func (c *Console) HistoryUp() {
+	if c.historyPos > 0 {
+		c.historyPos--
+		c.input = c.history[c.historyPos]
+	}
+}
+
+// HistoryDown replaces the command line with the next command in the history
+func (c *Console) HistoryDown() {
+	if c.historyPos < len(c.history)-1 {
+		c.historyPos++
+		c.input = c.history[c.historyPos]
+	} else {
+		c.historyPos = len(c.history)
+		c.input = ""
+	}
+}
+
+// Complete completes the word being typed. A single match is completed in
+// full; several matches are extended to their common prefix and listed.
+func (c *Console) Complete() {
+	fields := strings.Fields(c.input)
+	if len(fields) == 0 || strings.HasSuffix(c.input, " ") {
+		fields = append(fields, "")
+	}
+	word := fields[len(fields)-1]
+
+	var candidates []string
+	if len(fields) == 1 {
+		for _, cmd := range c.Commands() {
+			candidates = append(candidates, cmd.Name)
+		}
+	} else if cmd, ok := c.commands[strings.ToLower(fields[0])]; ok && cmd.Args != nil {
+		candidates = cmd.Args(len(fields) - 2)
+	}
+
+	var matches []string
+	for _, cand := range candidates {
+		if strings.HasPrefix(cand, strings.ToLower(word)) {
+			matches = append(matches, cand)
+		}
+	}
+	if len(matches) == 0 {
+		return
+	}
+
+	fields[len(fields)-1] = commonPrefix(matches)
+	c.input = strings.Join(fields, " ")
+	if len(matches) == 1 {
+		c.input += " "
+	} else {
+		c.Print(strings.Join(matches, "  "))
+	}
+}
+
+// commonPrefix returns the longest prefix shared by all words
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// Update handles keyboard input while the console is open
+func (c *Console) Update() {
+	if !c.Open {
+		return
+	}
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if r != '`' && r != '~' {
+			c.Type(r)
+		}
+	}
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		c.Execute()
+	case inpututil.IsKeyJustPressed(ebiten.KeyBackspace):
+		c.Backspace()
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab):
+		c.Complete()
+	case inpututil.IsKeyJustPressed(ebiten.KeyUp):
+		c.HistoryUp()
+	case inpututil.IsKeyJustPressed(ebiten.KeyDown):
+		c.HistoryDown()
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		c.Open = false
+	}
+}
+
+// Draw renders the console across the top of the screen
+func (c *Console) Draw(screen *ebiten.Image, width int) {
+	if !c.Open {
+		return
+	}
+	height := float32((consoleVisible+1)*consoleLineHeight + 16)
+	vector.DrawFilledRect(screen, 0, 0, float32(width), height, color.RGBA{10, 10, 25, 220}, false)
+	vector.StrokeLine(screen, 0, height, float32(width), height, 1, color.RGBA{100, 200, 255, 255}, false)
+
+	start := max(0, len(c.output)-consoleVisible)
+	y := 8
+	for _, line := range c.output[start:] {
+		DrawText(screen, line, 10, y, 1, color.RGBA{200, 200, 200, 255})
+		y += consoleLineHeight
+	}
+	DrawText(screen, consolePrompt+c.input+"_", 10, consoleVisible*consoleLineHeight+8, 1, color.RGBA{100, 255, 100, 255})
+}
@@ -0,0 +1,78 @@
+package systems
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestConsole(ran *[]string) *Console {
+	c := NewConsole()
+	c.Register(ConsoleCommand{
+		Name:  "spawn",
+		Usage: "spawn <enemy> [count]",
+		Args: func(argIndex int) []string {
+			if argIndex == 0 {
+				return []string{"scout", "shield_bearer", "sniper"}
+			}
+			return nil
+		},
+		Run: func(args []string) (string, error) {
+			*ran = append(*ran, strings.Join(args, " "))
+			return "ok", nil
+		},
+	})
+	c.Register(ConsoleCommand{Name: "shake", Run: func([]string) (string, error) { return "", nil }})
+	return c
+}
+
+func TestConsoleTabCompletion(t *testing.T) {
+	var ran []string
+	c := newTestConsole(&ran)
+
+	c.SetInput("sp")
+	c.Complete()
+	if c.Input() != "spawn " {
+		t.Fatalf("completing a unique command gave %q", c.Input())
+	}
+
+	c.Complete()
+	if c.Input() != "spawn s" {
+		t.Fatalf("completing to the common prefix gave %q", c.Input())
+	}
+	if last := c.Output()[len(c.Output())-1]; last != "scout  shield_bearer  sniper" {
+		t.Fatalf("ambiguous completion listed %q", last)
+	}
+
+	c.SetInput("spawn sh")
+	c.Complete()
+	if c.Input() != "spawn shield_bearer " {
+		t.Fatalf("completing an argument gave %q", c.Input())
+	}
+}
+
+func TestConsoleHistory(t *testing.T) {
+	var ran []string
+	c := newTestConsole(&ran)
+
+	for _, line := range []string{"spawn scout 2", "spawn sniper", "bogus"} {
+		c.SetInput(line)
+		c.Execute()
+	}
+	if len(ran) != 2 || ran[0] != "scout 2" || ran[1] != "sniper" {
+		t.Fatalf("commands ran with %q", ran)
+	}
+	if !strings.Contains(c.Output()[len(c.Output())-1], "unknown command") {
+		t.Fatalf("unknown command was not reported: %q", c.Output())
+	}
+
+	c.HistoryUp()
+	c.HistoryUp()
+	if c.Input() != "spawn sniper" {
+		t.Fatalf("two steps back in history gave %q", c.Input())
+	}
+	c.HistoryDown()
+	c.HistoryDown()
+	if c.Input() != "" {
+		t.Fatalf("stepping past the newest entry gave %q", c.Input())
+	}
+}
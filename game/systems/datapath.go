@@ -5,29 +5,11 @@ import (
 	"path/filepath"
 )
 
-// GetDataPath returns the appropriate path for saving user data
-// When running from .app bundle, uses ~/Library/Application Support/StellarSiege/
-// When running from terminal, uses ./data/
+// GetDataPath returns the path of a file in the data directory chosen by
+// DefaultDataDir, creating the directory if needed. It is used for files
+// written outside of Storage, such as performance traces and crash reports.
 func GetDataPath(filename string) string {
-	// Check if running from .app bundle
-	exePath, err := os.Executable()
-	if err == nil {
-		exeDir := filepath.Dir(exePath)
-
-		// If running from .app/Contents/MacOS, use Application Support
-		if filepath.Base(exeDir) == "MacOS" {
-			homeDir, err := os.UserHomeDir()
-			if err == nil {
-				appSupportDir := filepath.Join(homeDir, "Library", "Application Support", "StellarSiege")
-				// Ensure directory exists
-				os.MkdirAll(appSupportDir, 0755)
-				return filepath.Join(appSupportDir, filename)
-			}
-		}
-	}
-
-	// Default: use local data directory
-	// Ensure directory exists
-	os.MkdirAll("data", 0755)
-	return filepath.Join("data", filename)
+	dir := DefaultDataDir()
+	os.MkdirAll(dir, 0755)
+	return filepath.Join(dir, filename)
 }
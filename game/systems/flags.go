@@ -0,0 +1,158 @@
+package systems
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Flag size at scale 1, matching the height of a line of text
+const (
+	FlagWidth  = 18
+	FlagHeight = 12
+)
+
+// Country is a country a pilot can show next to their name, with a simple
+// drawing of its flag
+type Country struct {
+	Code string // ISO 3166-1 alpha-2
+	Name string
+	flag flagDesign
+}
+
+// flagDesign describes a flag as equal stripes with an optional cross,
+// canton or disc on top
+type flagDesign struct {
+	stripes  []color.RGBA
+	vertical bool
+	canton   color.RGBA // Top left quarter, if set
+	cross    color.RGBA // Cross through the flag, if set
+	border   color.RGBA // Border around the cross, if set
+	nordic   bool       // The cross is shifted towards the hoist
+	disc     color.RGBA // Disc in the middle, if set
+}
+
+// Flag colours
+var (
+	flagRed    = color.RGBA{206, 17, 38, 255}
+	flagWhite  = color.RGBA{255, 255, 255, 255}
+	flagBlue   = color.RGBA{0, 56, 147, 255}
+	flagSky    = color.RGBA{0, 161, 222, 255}
+	flagGreen  = color.RGBA{0, 135, 81, 255}
+	flagYellow = color.RGBA{252, 209, 22, 255}
+	flagOrange = color.RGBA{255, 136, 62, 255}
+	flagBlack  = color.RGBA{0, 0, 0, 255}
+	flagNavy   = color.RGBA{0, 36, 125, 255}
+)
+
+// Countries are the countries a pilot can pick, by name
+var Countries = []Country{
+	{"AT", "Austria", flagDesign{stripes: []color.RGBA{flagRed, flagWhite, flagRed}}},
+	{"BD", "Bangladesh", flagDesign{stripes: []color.RGBA{flagGreen}, disc: flagRed}},
+	{"BE", "Belgium", flagDesign{stripes: []color.RGBA{flagBlack, flagYellow, flagRed}, vertical: true}},
+	{"BG", "Bulgaria", flagDesign{stripes: []color.RGBA{flagWhite, flagGreen, flagRed}}},
+	{"CA", "Canada", flagDesign{stripes: []color.RGBA{flagRed, flagWhite, flagWhite, flagRed}, vertical: true, disc: flagRed}},
+	{"CH", "Switzerland", flagDesign{stripes: []color.RGBA{flagRed}, cross: flagWhite}},
+	{"DE", "Germany", flagDesign{stripes: []color.RGBA{flagBlack, flagRed, flagYellow}}},
+	{"DK", "Denmark", flagDesign{stripes: []color.RGBA{flagRed}, cross: flagWhite, nordic: true}},
+	{"EE", "Estonia", flagDesign{stripes: []color.RGBA{flagSky, flagBlack, flagWhite}}},
+	{"ES", "Spain", flagDesign{stripes: []color.RGBA{flagRed, flagYellow, flagYellow, flagRed}}},
+	{"FI", "Finland", flagDesign{stripes: []color.RGBA{flagWhite}, cross: flagNavy, nordic: true}},
+	{"FR", "France", flagDesign{stripes: []color.RGBA{flagNavy, flagWhite, flagRed}, vertical: true}},
+	{"GB", "United Kingdom", flagDesign{stripes: []color.RGBA{flagNavy}, cross: flagRed, border: flagWhite}},
+	{"HU", "Hungary", flagDesign{stripes: []color.RGBA{flagRed, flagWhite, flagGreen}}},
+	{"ID", "Indonesia", flagDesign{stripes: []color.RGBA{flagRed, flagWhite}}},
+	{"IE", "Ireland", flagDesign{stripes: []color.RGBA{flagGreen, flagWhite, flagOrange}, vertical: true}},
+	{"IN", "India", flagDesign{stripes: []color.RGBA{flagOrange, flagWhite, flagGreen}, disc: flagNavy}},
+	{"IS", "Iceland", flagDesign{stripes: []color.RGBA{flagBlue}, cross: flagRed, border: flagWhite, nordic: true}},
+	{"IT", "Italy", flagDesign{stripes: []color.RGBA{flagGreen, flagWhite, flagRed}, vertical: true}},
+	{"JP", "Japan", flagDesign{stripes: []color.RGBA{flagWhite}, disc: flagRed}},
+	{"LT", "Lithuania", flagDesign{stripes: []color.RGBA{flagYellow, flagGreen, flagRed}}},
+	{"LU", "Luxembourg", flagDesign{stripes: []color.RGBA{flagRed, flagWhite, flagSky}}},
+	{"MX", "Mexico", flagDesign{stripes: []color.RGBA{flagGreen, flagWhite, flagRed}, vertical: true}},
+	{"NG", "Nigeria", flagDesign{stripes: []color.RGBA{flagGreen, flagWhite, flagGreen}, vertical: true}},
+	{"NL", "Netherlands", flagDesign{stripes: []color.RGBA{flagRed, flagWhite, flagBlue}}},
+	{"NO", "Norway", flagDesign{stripes: []color.RGBA{flagRed}, cross: flagNavy, border: flagWhite, nordic: true}},
+	{"PL", "Poland", flagDesign{stripes: []color.RGBA{flagWhite, flagRed}}},
+	{"PT", "Portugal", flagDesign{stripes: []color.RGBA{flagGreen, flagGreen, flagRed, flagRed, flagRed}, vertical: true}},
+	{"RO", "Romania", flagDesign{stripes: []color.RGBA{flagNavy, flagYellow, flagRed}, vertical: true}},
+	{"SE", "Sweden", flagDesign{stripes: []color.RGBA{flagBlue}, cross: flagYellow, nordic: true}},
+	{"UA", "Ukraine", flagDesign{stripes: []color.RGBA{flagBlue, flagYellow}}},
+	{"US", "United States", flagDesign{stripes: []color.RGBA{flagRed, flagWhite, flagRed, flagWhite, flagRed, flagWhite, flagRed}, canton: flagNavy}},
+}
+
+// FindCountry returns the country with the given code
+func FindCountry(code string) (Country, bool) {
+	code = strings.ToUpper(code)
+	for _, c := range Countries {
+		if c.Code == code {
+			return c, true
+		}
+	}
+	return Country{}, false
+}
+
+// NextCountry returns the code of the country after code in Countries, or
+// before it when step is negative. "" (no country) comes before the first.
+func NextCountry(code string, step int) string {
+	i := 0 // Index into "" followed by Countries
+	for j, c := range Countries {
+		if c.Code == code {
+			i = j + 1
+		}
+	}
+	n := len(Countries) + 1
+	i = ((i+step)%n + n) % n
+	if i == 0 {
+		return ""
+	}
+	return Countries[i-1].Code
+}
+
+// DrawFlag draws the flag of a country code with its top left corner at
+// x, y. Codes without a drawing are shown as text; "" draws nothing.
+func DrawFlag(screen *ebiten.Image, code string, x, y int, scale float64) {
+	if code == "" {
+		return
+	}
+	c, ok := FindCountry(code)
+	if !ok {
+		DrawText(screen, strings.ToUpper(code), x, y, scale, color.RGBA{170, 170, 170, 255})
+		return
+	}
+
+	fx, fy := float32(x), float32(y)
+	w, h := float32(FlagWidth*scale), float32(FlagHeight*scale)
+	f := c.flag
+	n := float32(len(f.stripes))
+	for i, s := range f.stripes {
+		if f.vertical {
+			vector.DrawFilledRect(screen, fx+w*float32(i)/n, fy, w/n, h, s, false)
+		} else {
+			vector.DrawFilledRect(screen, fx, fy+h*float32(i)/n, w, h/n, s, false)
+		}
+	}
+	if f.canton.A > 0 {
+		vector.DrawFilledRect(screen, fx, fy, w*0.4, h*4/7, f.canton, false)
+	}
+	if f.cross.A > 0 {
+		cx := fx + w/2
+		if f.nordic {
+			cx = fx + w*3/8
+		}
+		cy := fy + h/2
+		bar := h / 5
+		if f.border.A > 0 {
+			vector.DrawFilledRect(screen, cx-bar, fy, bar*2, h, f.border, false)
+			vector.DrawFilledRect(screen, fx, cy-bar, w, bar*2, f.border, false)
+		}
+		vector.DrawFilledRect(screen, cx-bar/2, fy, bar, h, f.cross, false)
+		vector.DrawFilledRect(screen, fx, cy-bar/2, w, bar, f.cross, false)
+	}
+	if f.disc.A > 0 {
+		vector.DrawFilledCircle(screen, fx+w/2, fy+h/2, h*0.3, f.disc, true)
+	}
+	vector.StrokeRect(screen, fx, fy, w, h, 1, color.RGBA{60, 60, 70, 255}, false)
+}
@@ -0,0 +1,33 @@
+package systems
+
+import "testing"
+
+func TestNextCountryCyclesThroughNone(t *testing.T) {
+	first, last := Countries[0].Code, Countries[len(Countries)-1].Code
+	if got := NextCountry("", 1); got != first {
+		t.Errorf("after none comes %q, want %q", got, first)
+	}
+	if got := NextCountry("", -1); got != last {
+		t.Errorf("before none comes %q, want %q", got, last)
+	}
+	if got := NextCountry(last, 1); got != "" {
+		t.Errorf("after the last country comes %q, want none", got)
+	}
+	if _, ok := FindCountry("no"); !ok {
+		t.Error("country codes are not matched case-insensitively")
+	}
+}
+
+func TestLoadSettingsDropsUnknownCountry(t *testing.T) {
+	store := NewMemoryStorage()
+	if err := SaveSettings(store, Settings{Volume: 0.5, Country: "ZZ"}); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSettings(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Country != "" {
+		t.Errorf("country %q kept", s.Country)
+	}
+}
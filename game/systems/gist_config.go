@@ -11,6 +11,15 @@ type GistConfig struct {
 	GistID      string `json:"gist_id"`
 	GitHubToken string `json:"github_token"`
 	Enabled     bool   `json:"enabled"`
+	SyncGistID  string `json:"sync_gist_id"` // Private gist pilot saves are synced to; empty disables sync
+
+	// Self-hosted leaderboard server used instead of the gist when set; the
+	// admin token is only needed by the replay verifier
+	ServerURL        string `json:"leaderboard_url"`
+	ServerAdminToken string `json:"leaderboard_admin_token"`
+
+	// Whether the game asks GitHub for a newer release when it starts
+	CheckUpdates bool `json:"check_updates"`
 }
 
 // LoadGistConfig loads the Gist configuration from environment variables first,
@@ -22,10 +31,16 @@ func LoadGistConfig(filePath string) (*GistConfig, error) {
 		GistID:      os.Getenv("GIST_ID"),
 		GitHubToken: os.Getenv("GH_GIST_TOKEN"),
 		Enabled:     parseEnvBool("GIST_ENABLED", false),
+		SyncGistID:  os.Getenv("GIST_SYNC_ID"),
+
+		ServerURL:        os.Getenv("LEADERBOARD_URL"),
+		ServerAdminToken: os.Getenv("LEADERBOARD_ADMIN_TOKEN"),
+
+		CheckUpdates: parseEnvBool("UPDATE_CHECK", false),
 	}
 
 	// If env vars are not set, try to load from JSON file
-	if config.GistID == "" || config.GitHubToken == "" {
+	if config.GistID == "" || config.GitHubToken == "" || config.SyncGistID == "" || config.ServerURL == "" {
 		if filePath == "" {
 			filePath = "config/gist_config.json"
 		}
@@ -41,10 +56,22 @@ func LoadGistConfig(filePath string) (*GistConfig, error) {
 				if config.GitHubToken == "" {
 					config.GitHubToken = jsonConfig.GitHubToken
 				}
+				if config.SyncGistID == "" {
+					config.SyncGistID = jsonConfig.SyncGistID
+				}
+				if config.ServerURL == "" {
+					config.ServerURL = jsonConfig.ServerURL
+				}
+				if config.ServerAdminToken == "" {
+					config.ServerAdminToken = jsonConfig.ServerAdminToken
+				}
 				// Use JSON enabled flag only if env var wasn't set
 				if !config.Enabled && jsonConfig.Enabled {
 					config.Enabled = jsonConfig.Enabled
 				}
+				if !config.CheckUpdates && jsonConfig.CheckUpdates {
+					config.CheckUpdates = jsonConfig.CheckUpdates
+				}
 			}
 		}
 	}
@@ -2,11 +2,15 @@ package systems
 
 import (
 	"bytes"
+	"crypto/ed25519"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"sort"
+	"sync"
 	"time"
 )
 
@@ -17,13 +21,50 @@ type OnlineScore struct {
 	Difficulty string    `json:"difficulty"`
 	Date       time.Time `json:"date"`
 	Wave       int       `json:"wave"`
+	Country    string    `json:"country,omitempty"` // Chosen by the pilot; empty if not shared
+
+	// Replay verification (see VerifyReplay); both are empty for entries
+	// submitted by clients that predate replay recording
+	ReplayID     string              `json:"replay_id,omitempty"`
+	Verification *VerificationRecord `json:"verification,omitempty"`
+
+	// The submitting install's signature over the entry (see Sign), with
+	// the game version and seed of the run; empty for older clients
+	Version   string `json:"version,omitempty"`
+	Seed      int64  `json:"seed,omitempty"`
+	PublicKey string `json:"public_key,omitempty"`
+	Signature string `json:"signature,omitempty"`
+
+	// Set by CheckScoreSignatures, never stored
+	Trust ScoreTrust `json:"-"`
 }
 
+// DefaultGistRawURL serves the raw content of gist files
+const DefaultGistRawURL = "https://gist.githubusercontent.com"
+
+// leaderboardFileName is the gist file holding the scores
+const leaderboardFileName = "leaderboard.json"
+
+// Updates of the leaderboard are retried when another client changed the
+// gist in between, with a growing, jittered delay
+const (
+	gistUpdateAttempts = 8
+	gistRetryDelay     = 50 * time.Millisecond
+)
+
+// errGistChanged means the gist changed since it was read
+var errGistChanged = errors.New("gist changed since it was read")
+
 // GistLeaderboard manages the online leaderboard via GitHub Gist
 type GistLeaderboard struct {
 	GistID      string
 	GitHubToken string
+	APIURL      string             // GitHub API root, DefaultGistAPIURL unless testing
+	RawURL      string             // Raw file host, DefaultGistRawURL unless testing
+	Key         ed25519.PrivateKey // Signs submitted scores; they go unsigned without one
 	client      *http.Client
+
+	mu          sync.Mutex // Guards the cache
 	cachedData  []OnlineScore
 	lastFetch   time.Time
 	cacheExpiry time.Duration
@@ -34,6 +75,8 @@ func NewGistLeaderboard(gistID, githubToken string) *GistLeaderboard {
 	return &GistLeaderboard{
 		GistID:      gistID,
 		GitHubToken: githubToken,
+		APIURL:      DefaultGistAPIURL,
+		RawURL:      DefaultGistRawURL,
 		client:      &http.Client{Timeout: 10 * time.Second},
 		cachedData:  make([]OnlineScore, 0),
 		cacheExpiry: 30 * time.Second, // Cache for 30 seconds
@@ -42,6 +85,9 @@ func NewGistLeaderboard(gistID, githubToken string) *GistLeaderboard {
 
 // GetTopScores fetches the top scores from the online leaderboard (with local caching)
 func (gl *GistLeaderboard) GetTopScores(limit int) ([]OnlineScore, error) {
+	gl.mu.Lock()
+	defer gl.mu.Unlock()
+
 	// Check if cache is still valid
 	if time.Since(gl.lastFetch) < gl.cacheExpiry && len(gl.cachedData) > 0 {
 		// Return from cache
@@ -64,10 +110,7 @@ func (gl *GistLeaderboard) GetTopScores(limit int) ([]OnlineScore, error) {
 		return nil, err
 	}
 
-	// Sort by score (highest first)
-	sort.Slice(scores, func(i, j int) bool {
-		return scores[i].Score > scores[j].Score
-	})
+	sortScores(scores)
 
 	// Update cache
 	gl.cachedData = scores
@@ -80,72 +123,387 @@ func (gl *GistLeaderboard) GetTopScores(limit int) ([]OnlineScore, error) {
 	return scores[:limit], nil
 }
 
-// SubmitScore adds a new score to the online leaderboard
-func (gl *GistLeaderboard) SubmitScore(playerName string, score int64, difficulty string, wave int) error {
+// SubmitScore adds a new score to the online leaderboard. The run's replay is
+// uploaded next to the leaderboard so the verifier can re-simulate it.
+// Concurrent submissions from other players are merged rather than lost, and
+// submitting the same entry twice stores it once. A signed score registers
+// its key for the player name, and fails if the name belongs to another key.
+func (gl *GistLeaderboard) SubmitScore(playerName, country string, score int64, difficulty string, wave int, replay *Replay) error {
 	if gl.GitHubToken == "" {
 		return fmt.Errorf("GitHub token not configured")
 	}
 
-	// Fetch current scores
-	scores, err := gl.fetchFromGist()
-	if err != nil {
-		return fmt.Errorf("failed to fetch current scores: %w", err)
-	}
-
-	// Add new score
 	newScore := OnlineScore{
 		PlayerName: playerName,
+		Country:    country,
 		Score:      score,
 		Difficulty: difficulty,
 		Date:       time.Now(),
 		Wave:       wave,
 	}
-	scores = append(scores, newScore)
 
-	// Keep only top 100 scores to prevent gist from getting too large
-	if len(scores) > 100 {
-		sort.Slice(scores, func(i, j int) bool {
+	files := make(map[string]string)
+	if replay != nil {
+		replayData, err := replay.Encode()
+		if err != nil {
+			return fmt.Errorf("failed to encode replay: %w", err)
+		}
+		newScore.ReplayID = replay.ID()
+		newScore.Version = replay.GameVersion
+		newScore.Seed = replay.Seed
+		files[replayFileName(newScore.ReplayID)] = string(replayData)
+	}
+	if gl.Key != nil {
+		newScore.Sign(gl.Key)
+	}
+
+	// The entry counts as submitted once the leaderboard holds it, or when
+	// it is too low to make the top 100 at all
+	submitted := func(scores []OnlineScore) bool {
+		return containsScore(scores, newScore) ||
+			len(scores) >= maxOnlineScores && scores[len(scores)-1].Score >= newScore.Score
+	}
+	return gl.updateScores(files, submitted, func(board *gistBoard) error {
+		if newScore.Signature != "" {
+			if err := RegisterPilotKey(board.Keys, playerName, newScore.PublicKey); err != nil {
+				return err
+			}
+		}
+		if !containsScore(board.Scores, newScore) {
+			board.Scores = append(board.Scores, newScore)
+		}
+		return nil
+	})
+}
+
+// GetAllScores fetches every entry from the gist, bypassing the cache
+func (gl *GistLeaderboard) GetAllScores() ([]OnlineScore, error) {
+	return gl.fetchFromGist()
+}
+
+// GetPilotKeys fetches the public key registered for each player name
+func (gl *GistLeaderboard) GetPilotKeys() (map[string]string, error) {
+	data, found, err := gl.fetchGistFile(PilotKeysFileName)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]string)
+	if !found {
+		return keys, nil
+	}
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", PilotKeysFileName, err)
+	}
+	return keys, nil
+}
+
+// FetchReplay downloads the replay stored for a leaderboard entry
+func (gl *GistLeaderboard) FetchReplay(replayID string) (*Replay, error) {
+	data, found, err := gl.fetchGistFile(replayFileName(replayID))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("replay %s not found", replayID)
+	}
+	return DecodeReplay(data)
+}
+
+// RecordVerifications stores signed verification records on every entry
+// that references a verified replay
+func (gl *GistLeaderboard) RecordVerifications(records []*VerificationRecord) error {
+	if gl.GitHubToken == "" {
+		return fmt.Errorf("GitHub token not configured")
+	}
+
+	byReplay := make(map[string]*VerificationRecord, len(records))
+	for _, record := range records {
+		byReplay[record.ReplayID] = record
+	}
+	recorded := func(scores []OnlineScore) bool {
+		for _, s := range scores {
+			if record, ok := byReplay[s.ReplayID]; ok && (s.Verification == nil || s.Verification.Signature != record.Signature) {
+				return false
+			}
+		}
+		return true
+	}
+	return gl.updateScores(nil, recorded, func(board *gistBoard) error {
+		for i := range board.Scores {
+			if record, ok := byReplay[board.Scores[i].ReplayID]; ok {
+				board.Scores[i].Verification = record
+			}
+		}
+		return nil
+	})
+}
+
+// maxOnlineScores keeps the gist from getting too large
+const maxOnlineScores = 100
+
+// sortScores orders scores highest first, earlier entries first on ties
+func sortScores(scores []OnlineScore) {
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
 			return scores[i].Score > scores[j].Score
-		})
-		scores = scores[:100]
+		}
+		return scores[i].Date.Before(scores[j].Date)
+	})
+}
+
+// sameScore reports whether two entries record the same run: the same
+// replay, or for entries without one the same player, result and difficulty
+func sameScore(a, b OnlineScore) bool {
+	if a.ReplayID != "" || b.ReplayID != "" {
+		return a.ReplayID == b.ReplayID
+	}
+	return a.PlayerName == b.PlayerName && a.Score == b.Score && a.Wave == b.Wave && a.Difficulty == b.Difficulty
+}
+
+// containsScore reports whether scores holds an entry for the same run
+func containsScore(scores []OnlineScore, entry OnlineScore) bool {
+	for _, s := range scores {
+		if sameScore(s, entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// dedupeScores drops later entries for runs already listed
+func dedupeScores(scores []OnlineScore) []OnlineScore {
+	kept := scores[:0]
+	for _, s := range scores {
+		if !containsScore(kept, s) {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// gistBoard is the leaderboard as kept in the gist
+type gistBoard struct {
+	Scores []OnlineScore
+	Keys   map[string]string // Registered pilot keys, see RegisterPilotKey
+	Files  map[string]bool   // Names of every file in the gist
+}
+
+// updateScores changes the leaderboard without losing concurrent updates.
+// It reads the gist with its ETag, applies change, and writes the result
+// with If-Match, starting over when another client wrote in between. As
+// GitHub may not enforce If-Match, every write is also read back and the
+// update retried unless done reports that the leaderboard holds the change;
+// that catches overwrites landing before the read-back.
+func (gl *GistLeaderboard) updateScores(extraFiles map[string]string, done func([]OnlineScore) bool, change func(*gistBoard) error) error {
+	var lastErr error
+	for attempt := 0; attempt < gistUpdateAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt)*gistRetryDelay + time.Duration(rand.Int63n(int64(gistRetryDelay))))
+		}
+
+		board, etag, err := gl.fetchForUpdate()
+		if err != nil {
+			return fmt.Errorf("failed to fetch current scores: %w", err)
+		}
+		if done(board.Scores) {
+			return nil
+		}
+
+		registered := len(board.Keys)
+		if err := change(board); err != nil {
+			return err
+		}
+		all := board.Scores
+		sortScores(all)
+		all = dedupeScores(all)
+		scores := all
+		if len(scores) > maxOnlineScores {
+			scores = scores[:maxOnlineScores]
+		}
+
+		files := make(map[string]string, len(extraFiles)+1)
+		for name, content := range extraFiles {
+			files[name] = content
+		}
+		// Keys are only ever added, so a new one shows in the count
+		if len(board.Keys) != registered {
+			keyData, err := json.MarshalIndent(board.Keys, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal pilot keys: %w", err)
+			}
+			files[PilotKeysFileName] = string(keyData)
+		}
+
+		// Replays of entries that fell out of the top 100 are deleted in the
+		// same write, unless an entry that stays still refers to them
+		var removed []string
+		for _, name := range evictedReplays(all[len(scores):], scores) {
+			delete(files, name)
+			if board.Files[name] {
+				removed = append(removed, name)
+			}
+		}
+
+		err = gl.uploadToGist(scores, files, removed, etag)
+		if errors.Is(err, errGistChanged) {
+			lastErr = err
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		extraFiles = nil // Uploaded; only the scores may need writing again
+
+		// Read back in case a concurrent write slipped past the precondition
+		board, _, err = gl.fetchForUpdate()
+		if err != nil {
+			return fmt.Errorf("failed to confirm update: %w", err)
+		}
+		if done(board.Scores) {
+			return nil
+		}
+		lastErr = errGistChanged
+	}
+	return fmt.Errorf("failed to update leaderboard after %d attempts: %w", gistUpdateAttempts, lastErr)
+}
+
+// evictedReplays returns the replay files of evicted entries that no kept
+// entry refers to
+func evictedReplays(evicted, kept []OnlineScore) []string {
+	inUse := make(map[string]bool, len(kept))
+	for _, s := range kept {
+		inUse[s.ReplayID] = true
+	}
+	var names []string
+	for _, s := range evicted {
+		if s.ReplayID == "" || inUse[s.ReplayID] {
+			continue
+		}
+		inUse[s.ReplayID] = true
+		names = append(names, replayFileName(s.ReplayID))
 	}
+	return names
+}
 
-	// Upload to gist
-	return gl.uploadToGist(scores)
+// replayFileName returns the gist file name used to store a replay
+func replayFileName(replayID string) string {
+	return "replay_" + replayID + ".json"
 }
 
 // fetchFromGist retrieves the score data from GitHub Gist
 func (gl *GistLeaderboard) fetchFromGist() ([]OnlineScore, error) {
+	data, found, err := gl.fetchGistFile(leaderboardFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle 404 (gist doesn't exist yet)
+	if !found {
+		return []OnlineScore{}, nil
+	}
+
+	var scores []OnlineScore
+	if err := json.Unmarshal(data, &scores); err != nil {
+		// If decode fails, return empty slice (gist might be empty)
+		return []OnlineScore{}, nil
+	}
+
+	return scores, nil
+}
+
+// fetchForUpdate reads the leaderboard through the API, which unlike the raw
+// file host is never stale, along with the gist's ETag
+func (gl *GistLeaderboard) fetchForUpdate() (*gistBoard, string, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/gists/%s", gl.APIURL, gl.GistID), nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	gl.authorize(req)
+
+	resp, err := gl.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch gist: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, "", fmt.Errorf("gist fetch failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var gist struct {
+		Files map[string]struct {
+			Content   string `json:"content"`
+			Truncated bool   `json:"truncated"`
+		} `json:"files"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&gist); err != nil {
+		return nil, "", fmt.Errorf("failed to parse gist: %w", err)
+	}
+	board := &gistBoard{Scores: []OnlineScore{}, Keys: make(map[string]string), Files: make(map[string]bool, len(gist.Files))}
+	for name := range gist.Files {
+		board.Files[name] = true
+	}
+	if file, ok := gist.Files[leaderboardFileName]; ok {
+		if file.Truncated {
+			return nil, "", fmt.Errorf("%s is too large", leaderboardFileName)
+		}
+		// Never start over here either: writing back an empty board would
+		// wipe every score
+		if err := json.Unmarshal([]byte(file.Content), &board.Scores); err != nil {
+			return nil, "", fmt.Errorf("failed to parse %s: %w", leaderboardFileName, err)
+		}
+		if board.Scores == nil {
+			board.Scores = []OnlineScore{}
+		}
+	}
+	if file, ok := gist.Files[PilotKeysFileName]; ok {
+		// Never start over here: that would let anyone take a name
+		if file.Truncated {
+			return nil, "", fmt.Errorf("%s is too large", PilotKeysFileName)
+		}
+		if err := json.Unmarshal([]byte(file.Content), &board.Keys); err != nil {
+			return nil, "", fmt.Errorf("failed to parse %s: %w", PilotKeysFileName, err)
+		}
+		if board.Keys == nil {
+			board.Keys = make(map[string]string)
+		}
+	}
+	return board, resp.Header.Get("ETag"), nil
+}
+
+// fetchGistFile retrieves the raw content of a single gist file.
+// found is false if the file does not exist.
+func (gl *GistLeaderboard) fetchGistFile(name string) (data []byte, found bool, err error) {
 	// Construct the raw content URL
-	url := fmt.Sprintf("https://gist.githubusercontent.com/raw/%s/leaderboard.json", gl.GistID)
+	url := fmt.Sprintf("%s/raw/%s/%s", gl.RawURL, gl.GistID, name)
 
 	resp, err := gl.client.Get(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch gist: %w", err)
+		return nil, false, fmt.Errorf("failed to fetch gist: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// Handle 404 (gist doesn't exist yet)
 	if resp.StatusCode == 404 {
-		return []OnlineScore{}, nil
+		return nil, false, nil
 	}
 
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("gist fetch failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, false, fmt.Errorf("gist fetch failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
-	var scores []OnlineScore
-	if err := json.NewDecoder(resp.Body).Decode(&scores); err != nil {
-		// If decode fails, return empty slice (gist might be empty)
-		return []OnlineScore{}, nil
+	data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read gist: %w", err)
 	}
-
-	return scores, nil
+	return data, true, nil
 }
 
-// uploadToGist updates the score data in GitHub Gist
-func (gl *GistLeaderboard) uploadToGist(scores []OnlineScore) error {
+// uploadToGist updates the score data in GitHub Gist, along with any extra
+// files (such as replays) keyed by file name, and deletes the removed files.
+// With an etag the update only applies if the gist has not changed since;
+// otherwise it fails with errGistChanged.
+func (gl *GistLeaderboard) uploadToGist(scores []OnlineScore, extraFiles map[string]string, removed []string, etag string) error {
 	// Prepare the JSON payload
 	jsonData, err := json.MarshalIndent(scores, "", "  ")
 	if err != nil {
@@ -153,13 +511,22 @@ func (gl *GistLeaderboard) uploadToGist(scores []OnlineScore) error {
 	}
 
 	// Prepare the Gist update request
-	updatePayload := map[string]interface{}{
-		"files": map[string]interface{}{
-			"leaderboard.json": map[string]interface{}{
-				"content": string(jsonData),
-			},
+	files := map[string]interface{}{
+		leaderboardFileName: map[string]interface{}{
+			"content": string(jsonData),
 		},
 	}
+	for name, content := range extraFiles {
+		files[name] = map[string]interface{}{
+			"content": content,
+		}
+	}
+	for _, name := range removed {
+		files[name] = nil // GitHub deletes files set to null
+	}
+	updatePayload := map[string]interface{}{
+		"files": files,
+	}
 
 	payloadBytes, err := json.Marshal(updatePayload)
 	if err != nil {
@@ -167,15 +534,18 @@ func (gl *GistLeaderboard) uploadToGist(scores []OnlineScore) error {
 	}
 
 	// Make the API request
-	url := fmt.Sprintf("https://api.github.com/gists/%s", gl.GistID)
+	url := fmt.Sprintf("%s/gists/%s", gl.APIURL, gl.GistID)
 	req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Add authentication header
-	req.Header.Set("Authorization", fmt.Sprintf("token %s", gl.GitHubToken))
+	gl.authorize(req)
 	req.Header.Set("Content-Type", "application/json")
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
 
 	resp, err := gl.client.Do(req)
 	if err != nil {
@@ -183,17 +553,26 @@ func (gl *GistLeaderboard) uploadToGist(scores []OnlineScore) error {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		return errGistChanged
+	}
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("gist update failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
 	// Invalidate cache to force refresh on next read
-	gl.lastFetch = time.Time{}
+	gl.ClearCache()
 
 	return nil
 }
 
+// authorize adds the GitHub token to a request
+func (gl *GistLeaderboard) authorize(req *http.Request) {
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", gl.GitHubToken))
+	req.Header.Set("Accept", "application/vnd.github+json")
+}
+
 // GetPlayerRank returns the rank of a player if they're on the leaderboard
 func (gl *GistLeaderboard) GetPlayerRank(playerName string, minScore int64) (int, int64, bool) {
 	scores, err := gl.GetTopScores(100)
@@ -212,6 +591,8 @@ func (gl *GistLeaderboard) GetPlayerRank(playerName string, minScore int64) (int
 
 // ClearCache forces a refresh on next fetch
 func (gl *GistLeaderboard) ClearCache() {
+	gl.mu.Lock()
+	defer gl.mu.Unlock()
 	gl.lastFetch = time.Time{}
 	gl.cachedData = make([]OnlineScore, 0)
 }
@@ -0,0 +1,219 @@
+package systems
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"stellar-siege/game/entities"
+	"stellar-siege/game/systems/gisttest"
+)
+
+// newTestGistLeaderboard creates a client of the fake Gist API
+func newTestGistLeaderboard(srv *gisttest.Server) *GistLeaderboard {
+	gl := NewGistLeaderboard("scores", srv.Token)
+	gl.APIURL = srv.URL
+	gl.RawURL = srv.URL
+	return gl
+}
+
+func TestGistLeaderboardConcurrentSubmissions(t *testing.T) {
+	srv := gisttest.NewServer("secret")
+	defer srv.Close()
+	srv.CreateGist("scores", map[string]string{leaderboardFileName: "[]"})
+
+	// Every player submits from their own client at the same time
+	const players = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, players)
+	for i := 0; i < players; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- newTestGistLeaderboard(srv).SubmitScore(fmt.Sprintf("P%d", i), "", int64(1000+i), "Normal", 3, nil)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	t.Logf("%d writes rejected by If-Match", srv.Conflicts())
+	scores, err := newTestGistLeaderboard(srv).GetAllScores()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scores) != players {
+		t.Fatalf("leaderboard holds %d of %d submissions: %+v", len(scores), players, scores)
+	}
+	for i := 1; i < len(scores); i++ {
+		if scores[i].Score > scores[i-1].Score {
+			t.Errorf("scores out of order: %+v", scores)
+		}
+	}
+}
+
+func TestGistLeaderboardRecoversLostUpdate(t *testing.T) {
+	srv := gisttest.NewServer("secret")
+	defer srv.Close()
+	srv.CreateGist("scores", map[string]string{leaderboardFileName: "[]"})
+
+	// A server that does not enforce If-Match lets another client's stale
+	// write replace the submission once, right after it was made
+	srv.IgnoreIfMatch = true
+	overwritten := false
+	srv.AfterPatch = func(id string) {
+		if !overwritten {
+			overwritten = true
+			srv.SetFile(id, leaderboardFileName, `[{"player_name":"Other","score":50,"difficulty":"Easy","wave":1}]`)
+		}
+	}
+
+	if err := newTestGistLeaderboard(srv).SubmitScore("Ace", "", 900, "Hard", 5, nil); err != nil {
+		t.Fatal(err)
+	}
+	scores, err := newTestGistLeaderboard(srv).GetAllScores()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scores) != 2 || scores[0].PlayerName != "Ace" || scores[1].PlayerName != "Other" {
+		t.Errorf("scores = %+v, want both entries", scores)
+	}
+}
+
+func TestGistLeaderboardDeduplicates(t *testing.T) {
+	srv := gisttest.NewServer("secret")
+	defer srv.Close()
+	srv.CreateGist("scores", nil)
+	gl := newTestGistLeaderboard(srv)
+
+	replay := NewReplay("test", 7, 1)
+	replay.Record(entities.InputShoot)
+	replay.Finish(500, 2)
+	for i := 0; i < 2; i++ {
+		if err := gl.SubmitScore("Ace", "", 500, "Normal", 2, replay); err != nil {
+			t.Fatal(err)
+		}
+		if err := gl.SubmitScore("Rookie", "", 100, "Easy", 1, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A different run with the same result is kept
+	if err := gl.SubmitScore("Rookie", "", 100, "Hard", 1, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	scores, err := gl.GetAllScores()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scores) != 3 {
+		t.Errorf("scores = %+v, want 3 entries", scores)
+	}
+	if _, ok := srv.File("scores", replayFileName(replay.ID())); !ok {
+		t.Error("replay was not uploaded")
+	}
+
+	// Submitting without the token fails
+	gl.GitHubToken = "wrong"
+	if err := gl.SubmitScore("Mallory", "", 9999, "Hard", 9, nil); err == nil {
+		t.Error("submission with a bad token succeeded")
+	}
+}
+
+func TestGistLeaderboardRegistersPilotKeys(t *testing.T) {
+	srv := gisttest.NewServer("secret")
+	defer srv.Close()
+	srv.CreateGist("scores", nil)
+
+	_, ace, _ := ed25519.GenerateKey(nil)
+	_, mallory, _ := ed25519.GenerateKey(nil)
+	gl := newTestGistLeaderboard(srv)
+	gl.Key = ace
+	replay := NewReplay("test", 7, 1)
+	replay.Finish(500, 2)
+	if err := gl.SubmitScore("Ace", "", 500, "Normal", 2, replay); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another install cannot take the name
+	other := newTestGistLeaderboard(srv)
+	other.Key = mallory
+	if err := other.SubmitScore("ace", "", 9000, "Hard", 9, nil); err == nil {
+		t.Error("submitted a score under a name registered to another key")
+	}
+
+	// Editing the gist by hand is caught by the signature
+	content, _ := srv.File("scores", leaderboardFileName)
+	srv.SetFile("scores", leaderboardFileName, strings.Replace(content, `"score": 500`, `"score": 50000`, 1))
+
+	scores, err := gl.GetAllScores()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := gl.GetPilotKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	CheckScoreSignatures(scores, keys)
+	if len(scores) != 1 || scores[0].Score != 50000 || scores[0].Seed != 7 || scores[0].Trust != ScoreForged {
+		t.Errorf("scores = %+v, want the edited entry flagged as forged", scores)
+	}
+}
+
+func TestGistLeaderboardKeepsUnreadableLeaderboard(t *testing.T) {
+	srv := gisttest.NewServer("secret")
+	defer srv.Close()
+	const broken = `[{"player_name":"Ace","score":900,`
+	srv.CreateGist("scores", map[string]string{leaderboardFileName: broken})
+
+	// Submitting fails rather than writing back an empty board
+	if err := newTestGistLeaderboard(srv).SubmitScore("Rookie", "", 100, "Easy", 1, nil); err == nil {
+		t.Error("submitted to a leaderboard that does not parse")
+	}
+	if content, _ := srv.File("scores", leaderboardFileName); content != broken {
+		t.Errorf("leaderboard replaced with %s", content)
+	}
+}
+
+func TestGistLeaderboardRemovesEvictedReplays(t *testing.T) {
+	srv := gisttest.NewServer("secret")
+	defer srv.Close()
+
+	// A full leaderboard where every entry has its own replay, except the
+	// second lowest, which shares the top entry's
+	files := make(map[string]string)
+	var scores []OnlineScore
+	for i := 0; i < maxOnlineScores; i++ {
+		id := fmt.Sprintf("%016d", i)
+		if i == maxOnlineScores-2 {
+			id = scores[0].ReplayID
+		} else {
+			files[replayFileName(id)] = "{}"
+		}
+		scores = append(scores, OnlineScore{PlayerName: fmt.Sprintf("P%d", i), Score: int64(10000 - i), Difficulty: "Normal", Wave: 3, ReplayID: id})
+	}
+	data, _ := json.Marshal(scores)
+	files[leaderboardFileName] = string(data)
+	srv.CreateGist("scores", files)
+
+	// Two new entries evict the two lowest
+	gl := newTestGistLeaderboard(srv)
+	for i, score := range []int64{20000, 20001} {
+		if err := gl.SubmitScore(fmt.Sprintf("New%d", i), "", score, "Hard", 9, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, want := range map[int]bool{0: true, maxOnlineScores - 3: true, maxOnlineScores - 1: false} {
+		if _, ok := srv.File("scores", replayFileName(scores[i].ReplayID)); ok != want {
+			t.Errorf("replay of entry %d kept = %v, want %v", i, ok, want)
+		}
+	}
+}
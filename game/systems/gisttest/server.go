@@ -0,0 +1,210 @@
+// Package gisttest provides a local stand-in for the parts of the GitHub
+// Gist API the game uses, for tests that must not touch the network.
+package gisttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Server is an in-memory Gist API served over HTTP. Point a client's base
+// URL at Server.URL. Writes need the server's token.
+//
+// Every gist has an ETag that changes with each write. A PATCH with an
+// If-Match header that no longer matches fails with 412 Precondition Failed,
+// unless IgnoreIfMatch is set to mimic a server that does not enforce it.
+type Server struct {
+	*httptest.Server
+	Token         string
+	IgnoreIfMatch bool
+
+	// AfterPatch, if set, runs after each successful PATCH and before its
+	// response is sent, to interleave another client's write
+	AfterPatch func(id string)
+
+	mu       sync.Mutex
+	gists    map[string]map[string]string // Gist ID -> file name -> content
+	versions map[string]int               // Gist ID -> number of writes
+	requests int
+	conflict int // PATCHes rejected by If-Match
+}
+
+// gistFile is a file in a gist as the API reports it
+type gistFile struct {
+	Filename string `json:"filename"`
+	Content  string `json:"content"`
+	RawURL   string `json:"raw_url"`
+}
+
+// gistResponse is a gist as the API reports it
+type gistResponse struct {
+	ID    string              `json:"id"`
+	Files map[string]gistFile `json:"files"`
+}
+
+// NewServer starts a server that accepts writes made with token
+func NewServer(token string) *Server {
+	s := &Server{Token: token, gists: make(map[string]map[string]string), versions: make(map[string]int)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /gists/{id}", s.handleGet)
+	mux.HandleFunc("PATCH /gists/{id}", s.handlePatch)
+	mux.HandleFunc("GET /raw/{id}/{name}", s.handleRaw)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// CreateGist adds a gist with the given files
+func (s *Server) CreateGist(id string, files map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	gist := make(map[string]string, len(files))
+	for name, content := range files {
+		gist[name] = content
+	}
+	s.gists[id] = gist
+	s.versions[id]++
+}
+
+// File returns the content of a gist file
+func (s *Server) File(id, name string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	content, ok := s.gists[id][name]
+	return content, ok
+}
+
+// SetFile replaces the content of a gist file, as another client would
+func (s *Server) SetFile(id, name, content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.gists[id] == nil {
+		s.gists[id] = make(map[string]string)
+	}
+	s.gists[id][name] = content
+	s.versions[id]++
+}
+
+// Requests returns how many requests the server has handled
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// Conflicts returns how many writes were rejected because the gist had
+// changed since the writer read it
+func (s *Server) Conflicts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conflict
+}
+
+// etag returns the current ETag of a gist; the caller holds s.mu
+func (s *Server) etag(id string) string {
+	return fmt.Sprintf(`"%s-%d"`, id, s.versions[id])
+}
+
+// handleGet serves a gist with the content of all of its files
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	id := r.PathValue("id")
+	gist, ok := s.gists[id]
+	if !ok {
+		http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+		return
+	}
+	if match := r.Header.Get("If-None-Match"); match != "" && match == s.etag(id) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	s.writeGist(w, id, gist)
+}
+
+// handlePatch updates files of a gist; a file set to null is deleted
+func (s *Server) handlePatch(w http.ResponseWriter, r *http.Request) {
+	patched := false
+	defer func() {
+		if patched && s.AfterPatch != nil {
+			s.AfterPatch(r.PathValue("id"))
+		}
+	}()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if !s.authorized(r) {
+		http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+		return
+	}
+	id := r.PathValue("id")
+	gist, ok := s.gists[id]
+	if !ok {
+		http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+		return
+	}
+	if match := r.Header.Get("If-Match"); match != "" && match != s.etag(id) && !s.IgnoreIfMatch {
+		s.conflict++
+		http.Error(w, `{"message":"Precondition Failed"}`, http.StatusPreconditionFailed)
+		return
+	}
+
+	var update struct {
+		Files map[string]*struct {
+			Content string `json:"content"`
+		} `json:"files"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, fmt.Sprintf(`{"message":"Problems parsing JSON: %v"}`, err), http.StatusBadRequest)
+		return
+	}
+	for name, file := range update.Files {
+		if file == nil {
+			delete(gist, name)
+		} else {
+			gist[name] = file.Content
+		}
+	}
+	s.versions[id]++
+	patched = true
+	s.writeGist(w, id, gist)
+}
+
+// handleRaw serves the raw content of one gist file
+func (s *Server) handleRaw(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	content, ok := s.gists[r.PathValue("id")][r.PathValue("name")]
+	if !ok {
+		http.Error(w, "404: Not Found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(content))
+}
+
+// authorized reports whether a request carries the server's token
+func (s *Server) authorized(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	token, ok := strings.CutPrefix(auth, "token ")
+	if !ok {
+		token, ok = strings.CutPrefix(auth, "Bearer ")
+	}
+	return ok && token == s.Token
+}
+
+// writeGist encodes a gist response; the caller holds s.mu
+func (s *Server) writeGist(w http.ResponseWriter, id string, gist map[string]string) {
+	resp := gistResponse{ID: id, Files: make(map[string]gistFile, len(gist))}
+	for name, content := range gist {
+		resp.Files[name] = gistFile{Filename: name, Content: content, RawURL: s.URL + "/raw/" + id + "/" + name}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("ETag", s.etag(id))
+	json.NewEncoder(w).Encode(resp)
+}
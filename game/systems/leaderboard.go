@@ -2,9 +2,9 @@ package systems
 
 import (
 	"encoding/json"
+	"fmt"
 	"image/color"
-	"io"
-	"net/http"
+	"log"
 	"os"
 	"sort"
 	"sync"
@@ -13,42 +13,146 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+// DefaultLeaderboardDepth is how many entries each local table keeps
+// unless the player picks another of LeaderboardDepths
+const DefaultLeaderboardDepth = 10
+
+// LeaderboardDepths are the table depths the player can choose between
+var LeaderboardDepths = []int{10, 25, 50, 100}
+
 type LeaderboardEntry struct {
-	Rank    int       `json:"rank"`
+	Rank    int       `json:"rank"` // Within the entry's table
 	Name    string    `json:"name"`
 	Score   int64     `json:"score"`
 	Wave    int       `json:"wave"`
-	Country string    `json:"country"`
+	Country string    `json:"country"` // Chosen in the pilot's settings; empty if not shared
 	Date    time.Time `json:"date"`
+
+	// The table the entry is ranked in. Entries saved before tables were
+	// kept per difficulty have no difficulty.
+	Difficulty string `json:"difficulty,omitempty"`
+	Mode       string `json:"mode,omitempty"` // Challenge mode name; empty for Endless
+
+	Details *RunDetails `json:"details,omitempty"` // Nil for entries from older versions
+}
+
+// ModeName returns the challenge mode the entry was played in
+func (e *LeaderboardEntry) ModeName() string {
+	if e.Mode == "" {
+		return ChallengeModeEndless.String()
+	}
+	return e.Mode
+}
+
+// LeaderboardModeCoop is the mode co-op runs are ranked in, apart from solo
+// runs of any challenge mode
+const LeaderboardModeCoop = "Co-op"
+
+// LeaderboardModes returns the modes tables are kept for: every challenge
+// mode, then co-op
+func LeaderboardModes() []string {
+	modes := make([]string, 0, len(ChallengeModes)+1)
+	for _, m := range ChallengeModes {
+		modes = append(modes, m.String())
+	}
+	return append(modes, LeaderboardModeCoop)
+}
+
+// RunDetails describe the run behind a local leaderboard entry
+type RunDetails struct {
+	Duration      float64        `json:"duration"` // Seconds of game time
+	Kills         int            `json:"kills"`
+	KillsByWeapon map[string]int `json:"kills_by_weapon,omitempty"`
+	DeathCause    string         `json:"death_cause,omitempty"`
+	Pilots        []PilotStats   `json:"pilots,omitempty"` // Per-pilot stats of a co-op run
+	Seed          int64          `json:"seed"`
+	ReplayID      string         `json:"replay_id,omitempty"`
+	ReplayFile    string         `json:"replay_file,omitempty"` // Where the replay was saved; old replays are pruned
+}
+
+// NewRunDetails summarizes a finished run for the leaderboard. replay and
+// replayFile may be empty when the run was not recorded.
+func NewRunDetails(stats *RunStats, seed int64, replay *Replay, replayFile string) *RunDetails {
+	d := &RunDetails{
+		Seed:          seed,
+		KillsByWeapon: make(map[string]int),
+		ReplayFile:    replayFile,
+	}
+	if stats != nil {
+		d.Duration = stats.Duration
+		d.DeathCause = stats.DeathCause
+		d.Pilots = append([]PilotStats(nil), stats.Pilots...)
+		for weapon, kills := range stats.KillsByWeapon {
+			d.KillsByWeapon[weapon] = kills
+			d.Kills += kills
+		}
+	}
+	if replay != nil {
+		d.ReplayID = replay.ID()
+	}
+	return d
 }
 
-// IP API response structure
-type ipApiResponse struct {
-	CountryCode string `json:"countryCode"`
-	Status      string `json:"status"`
+// WeaponsUsed returns the weapons that destroyed enemies, most kills first
+func (d *RunDetails) WeaponsUsed() []string {
+	weapons := make([]string, 0, len(d.KillsByWeapon))
+	for weapon := range d.KillsByWeapon {
+		weapons = append(weapons, weapon)
+	}
+	sort.Slice(weapons, func(i, j int) bool {
+		if d.KillsByWeapon[weapons[i]] != d.KillsByWeapon[weapons[j]] {
+			return d.KillsByWeapon[weapons[i]] > d.KillsByWeapon[weapons[j]]
+		}
+		return weapons[i] < weapons[j]
+	})
+	return weapons
 }
 
+// Leaderboard keeps the best local runs in one table per difficulty and
+// challenge mode, each up to Depth entries deep
 type Leaderboard struct {
 	Entries    []LeaderboardEntry `json:"entries"`
-	FilePath   string             `json:"-"`
-	ipCache    map[string]string  `json:"-"` // Cache IP -> Country mappings
-	cacheMux   sync.Mutex         `json:"-"` // Protects ipCache
-	entriesMux sync.RWMutex       `json:"-"` // Protects Entries slice
+	Depth      int                `json:"depth"`
+	store      Storage
+	fileName   string
+	entriesMux sync.RWMutex `json:"-"` // Protects Entries slice
+}
+
+// leaderboardSaveData is the saved form of the local leaderboard
+type leaderboardSaveData struct {
+	Depth   int                `json:"depth"`
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+// leaderboardSchema is the save format of the local leaderboard. Version 1
+// wrapped the entry list in a versioned envelope; version 2 stores the
+// table depth alongside the entries.
+var leaderboardSchema = SaveSchema{
+	Kind:    "leaderboard",
+	Version: 2,
+	Migrations: map[int]SaveMigration{
+		0: unversioned,
+		1: func(data json.RawMessage) (json.RawMessage, error) {
+			return json.Marshal(map[string]json.RawMessage{"entries": data})
+		},
+	},
 }
 
-func NewLeaderboard(filePath string) *Leaderboard {
+// NewLeaderboard creates a leaderboard kept in the named file of store
+func NewLeaderboard(store Storage, fileName string) *Leaderboard {
 	lb := &Leaderboard{
 		Entries:  make([]LeaderboardEntry, 0),
-		FilePath: filePath,
-		ipCache:  make(map[string]string),
+		Depth:    DefaultLeaderboardDepth,
+		store:    store,
+		fileName: fileName,
 	}
 	lb.Load()
 	return lb
 }
 
 func (lb *Leaderboard) Load() error {
-	data, err := os.ReadFile(lb.FilePath)
-	if err != nil {
+	var data leaderboardSaveData
+	if err := ReadSaveFile(lb.store, lb.fileName, leaderboardSchema, &data); err != nil {
 		// Check if it's specifically a "file not found" error
 		if os.IsNotExist(err) {
 			return nil
@@ -59,105 +163,78 @@ func (lb *Leaderboard) Load() error {
 	lb.entriesMux.Lock()
 	defer lb.entriesMux.Unlock()
 
-	if err := json.Unmarshal(data, &lb.Entries); err != nil {
-		return err
+	lb.Entries = data.Entries
+	for i := range lb.Entries {
+		// Older versions saved "XX" while looking the country up
+		if lb.Entries[i].Country == "XX" {
+			lb.Entries[i].Country = ""
+		}
+	}
+	if data.Depth > 0 {
+		lb.Depth = data.Depth
 	}
-
 	lb.updateRanksUnsafe() // Call unsafe version since we hold the lock
 	return nil
 }
 
 func (lb *Leaderboard) Save() error {
-	// Ensure directory exists
-	dir := "data"
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
-	}
-
 	lb.entriesMux.RLock()
-	data, err := json.MarshalIndent(lb.Entries, "", "  ")
-	lb.entriesMux.RUnlock()
-
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(lb.FilePath, data, 0644)
+	defer lb.entriesMux.RUnlock()
+	return WriteSaveFile(lb.store, lb.fileName, leaderboardSchema, leaderboardSaveData{Depth: lb.Depth, Entries: lb.Entries})
 }
 
-// GetCountryFromIP fetches the country code for an IP address using IP geolocation service
-func (lb *Leaderboard) GetCountryFromIP() string {
-	lb.cacheMux.Lock()
-	defer lb.cacheMux.Unlock()
-
-	// Try to fetch country from IP using ip-api.com (free service)
-	// Fallback available at ipapi.io if needed
-	resp, err := http.Get("http://ip-api.com/json/?fields=countryCode")
-	if err != nil {
-		// Fallback to ipapi.io
-		resp, err = http.Get("https://ipapi.co/json/")
-		if err != nil {
-			return "XX" // Unknown country
-		}
-		defer resp.Body.Close()
-
-		var data map[string]interface{}
-		body, _ := io.ReadAll(resp.Body)
-		json.Unmarshal(body, &data)
+// AddEntry enters a run in the table of its difficulty and mode, and returns
+// its rank there, or 0 if it did not make the table
+func (lb *Leaderboard) AddEntry(entry LeaderboardEntry) int {
+	name, score := entry.Name, entry.Score
+	if entry.Date.IsZero() {
+		entry.Date = time.Now()
+	}
+	if entry.Mode == ChallengeModeEndless.String() {
+		entry.Mode = ""
+	}
 
-		if country, ok := data["country_code"].(string); ok {
-			return country
+	lb.entriesMux.Lock()
+	lb.Entries = append(lb.Entries, entry)
+	lb.updateRanksUnsafe() // Call unsafe version since we hold the lock
+	rank := 0
+	for _, e := range lb.Entries {
+		if e.Date.Equal(entry.Date) && e.Name == name && e.Score == score {
+			rank = e.Rank
 		}
-		return "XX"
 	}
-	defer resp.Body.Close()
-
-	var result ipApiResponse
-	body, _ := io.ReadAll(resp.Body)
-	json.Unmarshal(body, &result)
+	lb.entriesMux.Unlock()
 
-	if result.Status == "success" && result.CountryCode != "" {
-		return result.CountryCode
+	if err := lb.Save(); err != nil {
+		log.Printf("Failed to save leaderboard: %v", err)
 	}
-
-	return "XX" // Unknown country
+	return rank
 }
 
-func (lb *Leaderboard) AddEntry(name string, score int64, wave int) {
-	entry := LeaderboardEntry{
-		Name:    name,
-		Score:   score,
-		Wave:    wave,
-		Country: "XX", // Default, will be updated asynchronously
-		Date:    time.Now(),
+// SetDepth changes how many entries each table keeps, dropping the entries
+// beyond the new depth
+func (lb *Leaderboard) SetDepth(depth int) error {
+	if depth <= 0 {
+		return fmt.Errorf("invalid leaderboard depth %d", depth)
 	}
-
 	lb.entriesMux.Lock()
-	lb.Entries = append(lb.Entries, entry)
-	lb.updateRanksUnsafe() // Call unsafe version since we hold the lock
-
-	// Keep only top 10
-	if len(lb.Entries) > 10 {
-		lb.Entries = lb.Entries[:10]
-	}
+	lb.Depth = depth
+	lb.updateRanksUnsafe()
 	lb.entriesMux.Unlock()
+	return lb.Save()
+}
 
-	lb.Save()
-
-	// Get country from IP in background (don't block)
-	go func() {
-		country := lb.GetCountryFromIP()
-		// Update entry with country if we got it
-		lb.entriesMux.Lock()
-		for i := range lb.Entries {
-			if lb.Entries[i].Name == name && lb.Entries[i].Score == score {
-				lb.Entries[i].Country = country
-				break
-			}
+// Table returns the entries of one difficulty and challenge mode, best first
+func (lb *Leaderboard) Table(difficulty, mode string) []LeaderboardEntry {
+	lb.entriesMux.RLock()
+	defer lb.entriesMux.RUnlock()
+	var table []LeaderboardEntry
+	for _, e := range lb.Entries {
+		if e.Difficulty == difficulty && e.ModeName() == mode {
+			table = append(table, e)
 		}
-		lb.entriesMux.Unlock()
-		lb.Save()
-	}()
+	}
+	return table
 }
 
 // updateRanks updates ranks with lock protection (safe for concurrent use)
@@ -167,17 +244,28 @@ func (lb *Leaderboard) updateRanks() {
 	lb.updateRanksUnsafe()
 }
 
-// updateRanksUnsafe updates ranks without lock (caller must hold lock)
+// updateRanksUnsafe ranks every entry within its table and drops those
+// beyond the table depth (caller must hold lock)
 func (lb *Leaderboard) updateRanksUnsafe() {
 	// Sort by score descending
-	sort.Slice(lb.Entries, func(i, j int) bool {
+	sort.SliceStable(lb.Entries, func(i, j int) bool {
 		return lb.Entries[i].Score > lb.Entries[j].Score
 	})
 
-	// Update ranks
-	for i := range lb.Entries {
-		lb.Entries[i].Rank = i + 1
+	// Update ranks, keeping the best Depth entries of each table
+	type table struct{ difficulty, mode string }
+	counts := make(map[table]int)
+	kept := lb.Entries[:0]
+	for _, e := range lb.Entries {
+		t := table{e.Difficulty, e.ModeName()}
+		if counts[t] == lb.Depth {
+			continue
+		}
+		counts[t]++
+		e.Rank = counts[t]
+		kept = append(kept, e)
 	}
+	lb.Entries = kept
 }
 
 func (lb *Leaderboard) GetHighScore() int64 {
@@ -190,38 +278,31 @@ func (lb *Leaderboard) GetHighScore() int64 {
 	return lb.Entries[0].Score
 }
 
-func (lb *Leaderboard) Draw(screen *ebiten.Image, centerX, startY int, currentScore int64) {
+// Draw renders the first rows of one table, highlighting currentScore
+func (lb *Leaderboard) Draw(screen *ebiten.Image, centerX, startY int, difficulty, mode string, rows int, currentScore int64) {
 	DrawTextCentered(screen, "=== LEADERBOARD ===", centerX, startY, 2, color.RGBA{255, 200, 50, 255})
 
-	lb.entriesMux.RLock()
-	entriesCount := len(lb.Entries)
-	if entriesCount == 0 {
-		lb.entriesMux.RUnlock()
+	// Table copies the entries, so no lock is held while drawing
+	entries := lb.Table(difficulty, mode)
+	if len(entries) == 0 {
 		DrawTextCentered(screen, "No scores yet!", centerX, startY+50, 1.5, color.RGBA{150, 150, 150, 255})
 		return
 	}
 
-	// Copy entries for rendering to minimize lock time
-	entriesCopy := make([]LeaderboardEntry, entriesCount)
-	copy(entriesCopy, lb.Entries)
-	lb.entriesMux.RUnlock()
-
 	y := startY + 40
-	for _, entry := range entriesCopy {
+	for _, entry := range entries[:min(rows, len(entries))] {
 		// Highlight if this is the current score
 		clr := color.RGBA{200, 200, 200, 255}
 		if entry.Score == currentScore {
 			clr = color.RGBA{100, 255, 100, 255}
 		}
 
-		// Build leaderboard entry with country code
-		country := entry.Country
-		if country == "" || country == "XX" {
-			country = "??"
-		}
-
-		line := FormatNumber(int64(entry.Rank)) + ". " + entry.Name + " (" + country + ") - " + FormatNumber(entry.Score) + " (Wave " + FormatNumber(int64(entry.Wave)) + ")"
+		line := FormatNumber(int64(entry.Rank)) + ". " + entry.Name + " - " + FormatNumber(entry.Score) + " (Wave " + FormatNumber(int64(entry.Wave)) + ")"
 		DrawTextCentered(screen, line, centerX, y, 1.5, clr)
+
+		// The pilot's flag goes in front of the line
+		left := centerX - len(line)*7*3/4
+		DrawFlag(screen, entry.Country, left-FlagWidth*3/2-8, y+2, 1.5)
 		y += 30
 	}
 }
@@ -0,0 +1,81 @@
+package systems
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLeaderboardKeepsTablesPerDifficultyAndMode(t *testing.T) {
+	lb := NewLeaderboard(NewMemoryStorage(), "leaderboard.json")
+	for i := 0; i < 30; i++ {
+		lb.Entries = append(lb.Entries,
+			LeaderboardEntry{Name: fmt.Sprintf("N%d", i), Score: int64(i), Difficulty: "Normal"},
+			LeaderboardEntry{Name: fmt.Sprintf("H%d", i), Score: int64(1000 + i), Difficulty: "Hard"},
+		)
+	}
+	lb.Entries = append(lb.Entries, LeaderboardEntry{Name: "BOSS", Score: 5, Difficulty: "Normal", Mode: "Boss Rush"})
+	if err := lb.SetDepth(25); err != nil {
+		t.Fatal(err)
+	}
+
+	normal := lb.Table("Normal", "Endless")
+	if len(normal) != 25 {
+		t.Fatalf("normal table holds %d entries, want 25", len(normal))
+	}
+	// A crowded Hard table must not push Normal entries out
+	if normal[0].Name != "N29" || normal[0].Rank != 1 || normal[24].Name != "N5" || normal[24].Rank != 25 {
+		t.Errorf("normal table runs from %s (#%d) to %s (#%d)", normal[0].Name, normal[0].Rank, normal[24].Name, normal[24].Rank)
+	}
+	if hard := lb.Table("Hard", "Endless"); len(hard) != 25 {
+		t.Errorf("hard table holds %d entries, want 25", len(hard))
+	}
+	if boss := lb.Table("Normal", "Boss Rush"); len(boss) != 1 || boss[0].Rank != 1 {
+		t.Errorf("boss rush table = %+v", boss)
+	}
+
+	if err := lb.SetDepth(10); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := NewLeaderboard(lb.store, lb.fileName)
+	if reloaded.Depth != 10 {
+		t.Errorf("reloaded depth %d, want 10", reloaded.Depth)
+	}
+	if n := len(reloaded.Table("Normal", "Endless")); n != 10 {
+		t.Errorf("reloaded normal table holds %d entries, want 10", n)
+	}
+	if err := lb.SetDepth(0); err == nil {
+		t.Error("depth 0 accepted")
+	}
+}
+
+func TestLeaderboardMigratesVersion1(t *testing.T) {
+	store, name := NewMemoryStorage(), "leaderboard.json"
+	v1 := `{"kind":"leaderboard","version":1,"data":[{"rank":1,"name":"ACE","score":5000,"wave":7,"country":"XX"}]}`
+	if err := store.Write(name, []byte(v1)); err != nil {
+		t.Fatal(err)
+	}
+
+	lb := NewLeaderboard(store, name)
+	if lb.Depth != DefaultLeaderboardDepth {
+		t.Errorf("depth %d, want %d", lb.Depth, DefaultLeaderboardDepth)
+	}
+	// Entries from before tables were kept per difficulty form their own table
+	older := lb.Table("", "Endless")
+	if len(older) != 1 || older[0].Name != "ACE" || older[0].Details != nil {
+		t.Fatalf("migrated entries = %+v", lb.Entries)
+	}
+	// "XX" stood for a country still being looked up
+	if older[0].Country != "" {
+		t.Errorf("migrated country %q, want none", older[0].Country)
+	}
+}
+
+func TestRunDetailsWeaponsUsed(t *testing.T) {
+	d := NewRunDetails(&RunStats{KillsByWeapon: map[string]int{"Laser": 4, "Missile": 9, "Bomb": 4}}, 42, nil, "")
+	if d.Kills != 17 || d.Seed != 42 {
+		t.Errorf("details = %+v", d)
+	}
+	if got := fmt.Sprint(d.WeaponsUsed()); got != "[Missile Bomb Laser]" {
+		t.Errorf("weapons used = %s", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"image/color"
 	"math"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
@@ -11,12 +12,18 @@ import (
 )
 
 type Menu struct {
-	ShowDifficultySelect bool // Exported so Game can access it
-	SelectedDifficulty   int  // 0=Easy, 1=Normal, 2=Hard
-	showLeaderboard      bool
+	ShowDifficultySelect bool      // Exported so Game can access it
+	SelectedDifficulty   int       // 0=Easy, 1=Normal, 2=Hard
+	Coop                 bool      // Start the run with a second pilot on this machine
 	InfoMenu             *InfoMenu // Pointer to info menu - exported
 	animTimer            float64
 	SoundEnabled         bool           // Track sound toggle state
+	ContinueRun          string         // Summary of the suspended run, empty if there is none
+	Pilot                string         // Name of the active pilot profile
+	Keys                 KeyBindings    // The active pilot's controls, for the controls summary
+	SyncStatus           string         // State of the cloud save sync, empty if it is off
+	QueuedScores         int            // Scores waiting to be submitted online
+	OnlineLeaderboard    bool           // Whether the global leaderboard can be browsed
 	spriteManager        *SpriteManager // For info menu sprites
 
 	// Update banner fields
@@ -31,29 +38,23 @@ type Menu struct {
 func NewMenu(spriteManager *SpriteManager) *Menu {
 	infoMenu := NewInfoMenu(spriteManager)
 	return &Menu{
-		showLeaderboard:      false,
 		ShowDifficultySelect: false,
 		SelectedDifficulty:   1, // Default to normal
 		InfoMenu:             infoMenu,
 		animTimer:            0,
 		SoundEnabled:         true, // Sound enabled by default
+		Keys:                 DefaultKeyBindings(),
 		spriteManager:        spriteManager,
 	}
 }
 
-func (m *Menu) ToggleLeaderboard() {
-	m.showLeaderboard = !m.showLeaderboard
-}
-
 func (m *Menu) ShowDifficultySelectMenu() {
 	m.ShowDifficultySelect = true
-	m.showLeaderboard = false
 	m.InfoMenu.Hide()
 }
 
 func (m *Menu) ShowInfo() {
 	m.InfoMenu.Show()
-	m.showLeaderboard = false
 }
 
 // SetUpdateManager sets the update manager reference for the menu
@@ -112,10 +113,13 @@ func (m *Menu) Update() {
 				m.SelectedDifficulty = 0 // Easy
 			}
 		}
+		if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+			m.Coop = !m.Coop
+		}
 	}
 }
 
-func (m *Menu) Draw(screen *ebiten.Image, leaderboard *Leaderboard, screenWidth, screenHeight int) {
+func (m *Menu) Draw(screen *ebiten.Image, screenWidth, screenHeight int) {
 	m.animTimer += 0.02
 
 	// Title with pulsing effect
@@ -135,26 +139,37 @@ func (m *Menu) Draw(screen *ebiten.Image, leaderboard *Leaderboard, screenWidth,
 	if m.ShowDifficultySelect {
 		// Draw difficulty selection screen
 		m.drawDifficultySelection(screen, screenWidth, screenHeight)
-	} else if m.showLeaderboard {
-		// Draw leaderboard
-		leaderboard.Draw(screen, screenWidth/2, 280, 0)
-		DrawTextCentered(screen, "Press L to return to menu", screenWidth/2, screenHeight-60, 1.5, color.RGBA{150, 150, 150, 255})
 	} else {
 		// Menu options
 		y := 350
 
+		if m.Pilot != "" {
+			DrawTextCentered(screen, "Pilot: "+m.Pilot+"  (P to change)", screenWidth/2, y-90, 1.5, color.RGBA{150, 220, 255, 255})
+		}
+
+		if m.ContinueRun != "" {
+			DrawTextCentered(screen, "Press C to Continue Run: "+m.ContinueRun, screenWidth/2, y-50, 1.5, color.RGBA{255, 220, 100, 255})
+		}
+
 		// Pulsing "Press ENTER to Start"
 		pulse := 0.8 + 0.2*math.Sin(m.animTimer*4)
 		startColor := color.RGBA{uint8(100 * pulse), uint8(255 * pulse), uint8(100 * pulse), 255}
 		DrawTextCentered(screen, ">> Press ENTER to Start <<", screenWidth/2, y, 2.5, startColor)
 
-		y += 80
-		DrawTextCentered(screen, "Press L for Leaderboard", screenWidth/2, y, 1.5, color.RGBA{150, 150, 200, 255})
+		y += 70
+		leaderboardHint := "Press L for Leaderboard"
+		if m.OnlineLeaderboard {
+			leaderboardHint = "Press L for Leaderboard, G for Global Leaderboard"
+		}
+		DrawTextCentered(screen, leaderboardHint, screenWidth/2, y, 1.5, color.RGBA{150, 150, 200, 255})
 
-		y += 60
+		y += 40
 		DrawTextCentered(screen, "Press I for Information", screenWidth/2, y, 1.5, color.RGBA{150, 200, 150, 255})
 
-		y += 60
+		y += 40
+		DrawTextCentered(screen, "Press N for Online Co-op, D to Watch a Demo", screenWidth/2, y, 1.5, color.RGBA{200, 150, 200, 255})
+
+		y += 40
 		// Sound toggle display
 		soundStatus := "ON"
 		soundColor := color.RGBA{100, 255, 100, 255}
@@ -168,11 +183,19 @@ func (m *Menu) Draw(screen *ebiten.Image, leaderboard *Leaderboard, screenWidth,
 		y = screenHeight - 150
 		DrawTextCentered(screen, "=== CONTROLS ===", screenWidth/2, y, 1.5, color.RGBA{255, 200, 100, 255})
 		y += 30
-		DrawTextCentered(screen, "WASD / Arrow Keys - Move", screenWidth/2, y, 1.2, color.RGBA{180, 180, 180, 255})
+		k := m.Keys
+		DrawTextCentered(screen, keyLabel(k.Up, k.Left, k.Down, k.Right)+" / Arrow Keys - Move", screenWidth/2, y, 1.2, color.RGBA{180, 180, 180, 255})
 		y += 25
-		DrawTextCentered(screen, "SPACE / Left Click - Fire", screenWidth/2, y, 1.2, color.RGBA{180, 180, 180, 255})
+		DrawTextCentered(screen, keyLabel(k.Shoot)+" / Left Click - Fire", screenWidth/2, y, 1.2, color.RGBA{180, 180, 180, 255})
 		y += 25
 		DrawTextCentered(screen, "P / ESC - Pause", screenWidth/2, y, 1.2, color.RGBA{180, 180, 180, 255})
+
+		if m.SyncStatus != "" {
+			DrawTextCentered(screen, m.SyncStatus, screenWidth/2, screenHeight-40, 1.0, color.RGBA{150, 180, 220, 255})
+		}
+		if m.QueuedScores > 0 {
+			DrawTextCentered(screen, fmt.Sprintf("%d score(s) waiting to be submitted online - press O to view", m.QueuedScores), screenWidth/2, screenHeight-18, 1.0, color.RGBA{255, 200, 100, 255})
+		}
 	}
 
 	// Decorative elements
@@ -294,6 +317,27 @@ func (m *Menu) drawDifficultySelection(screen *ebiten.Image, screenWidth, screen
 		DrawTextCentered(screen, difficulties[i], int(x), y, 2, textColor)
 	}
 
-	DrawTextCentered(screen, "Use LEFT/RIGHT or A/D to select", screenWidth/2, screenHeight-150, 1.5, color.RGBA{200, 200, 200, 255})
+	players, playersColor := "SOLO", color.RGBA{180, 180, 180, 255}
+	if m.Coop {
+		players, playersColor = "CO-OP - 2 PILOTS", color.RGBA{100, 220, 255, 255}
+		DrawTextCentered(screen, "Pilot 2: ARROWS + ENTER/RIGHT CTRL, or a gamepad", screenWidth/2, 480, 1.2, color.RGBA{150, 150, 150, 255})
+	}
+	DrawTextCentered(screen, "Players: "+players, screenWidth/2, 445, 1.5, playersColor)
+
+	DrawTextCentered(screen, "Use LEFT/RIGHT or A/D to select, TAB for co-op", screenWidth/2, screenHeight-150, 1.5, color.RGBA{200, 200, 200, 255})
 	DrawTextCentered(screen, "Press ENTER to confirm", screenWidth/2, screenHeight-100, 1.5, color.RGBA{100, 255, 100, 255})
 }
+
+// keyLabel names a group of keys for the controls summary, such as "WASD"
+func keyLabel(keys ...ebiten.Key) string {
+	names := make([]string, len(keys))
+	short := true
+	for i, k := range keys {
+		names[i] = strings.ToUpper(k.String())
+		short = short && len(names[i]) == 1
+	}
+	if short {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names, "/")
+}
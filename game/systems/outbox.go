@@ -0,0 +1,262 @@
+package systems
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// ScoreOutboxFileName is the file holding scores not yet submitted online
+const ScoreOutboxFileName = "score_outbox.json"
+
+// scoreOutboxSchema is the save format of the score outbox
+var scoreOutboxSchema = SaveSchema{
+	Kind:    "score_outbox",
+	Version: 1,
+}
+
+// Failed submissions are retried after OutboxRetryDelay, doubling with
+// every attempt up to OutboxMaxRetryDelay. After OutboxMaxAttempts a score
+// is only retried when the player asks.
+const (
+	OutboxRetryDelay    = 30 * time.Second
+	OutboxMaxRetryDelay = time.Hour
+	OutboxMaxAttempts   = 8
+)
+
+// SubmissionState is where a queued score is in its submission
+type SubmissionState string
+
+const (
+	SubmissionPending   SubmissionState = "pending"   // Waiting for its next attempt
+	SubmissionSending   SubmissionState = "sending"   // Attempt in progress
+	SubmissionSubmitted SubmissionState = "submitted" // On the online leaderboard
+	SubmissionFailed    SubmissionState = "failed"    // Out of automatic attempts
+)
+
+// QueuedScore is a score waiting to be submitted to the online leaderboard
+type QueuedScore struct {
+	ID          string          `json:"id"`
+	PlayerName  string          `json:"player_name"`
+	Country     string          `json:"country,omitempty"`
+	Score       int64           `json:"score"`
+	Difficulty  string          `json:"difficulty"`
+	Wave        int             `json:"wave"`
+	Replay      *Replay         `json:"replay,omitempty"`
+	Queued      time.Time       `json:"queued"`
+	State       SubmissionState `json:"state"`
+	Attempts    int             `json:"attempts"`
+	NextAttempt time.Time       `json:"next_attempt"`
+	LastError   string          `json:"last_error,omitempty"`
+}
+
+// ScoreOutbox keeps scores until the online leaderboard has accepted them,
+// so scores set while offline are submitted on a later launch. It is safe
+// for use by the game and its submission goroutine at once.
+type ScoreOutbox struct {
+	store   Storage
+	mu      sync.Mutex
+	entries []QueuedScore
+}
+
+// NewScoreOutbox loads the outbox kept in store
+func NewScoreOutbox(store Storage) *ScoreOutbox {
+	o := &ScoreOutbox{store: store}
+	if err := ReadSaveFile(store, ScoreOutboxFileName, scoreOutboxSchema, &o.entries); err != nil && !os.IsNotExist(err) {
+		log.Printf("Failed to load score outbox: %v", err)
+	}
+	// An attempt cut short by quitting the game counts as not made
+	for i := range o.entries {
+		if o.entries[i].State == SubmissionSending {
+			o.entries[i].State = SubmissionPending
+		}
+	}
+	return o
+}
+
+// Add queues a score for submission as soon as possible and returns its ID
+func (o *ScoreOutbox) Add(playerName, country string, score int64, difficulty string, wave int, replay *Replay) (string, error) {
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", fmt.Errorf("failed to create submission id: %w", err)
+	}
+	now := time.Now()
+	entry := QueuedScore{
+		ID:          hex.EncodeToString(id[:]),
+		PlayerName:  playerName,
+		Country:     country,
+		Score:       score,
+		Difficulty:  difficulty,
+		Wave:        wave,
+		Replay:      replay,
+		Queued:      now,
+		State:       SubmissionPending,
+		NextAttempt: now,
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries = append(o.entries, entry)
+	return entry.ID, o.saveLocked()
+}
+
+// Get returns a queued score; submitted scores stay available until the
+// game exits
+func (o *ScoreOutbox) Get(id string) (QueuedScore, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if i := o.indexLocked(id); i >= 0 {
+		return o.entries[i], true
+	}
+	return QueuedScore{}, false
+}
+
+// Entries returns the scores not yet submitted, oldest first
+func (o *ScoreOutbox) Entries() []QueuedScore {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var entries []QueuedScore
+	for _, e := range o.entries {
+		if e.State != SubmissionSubmitted {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Pending returns how many scores are not yet submitted
+func (o *ScoreOutbox) Pending() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	n := 0
+	for _, e := range o.entries {
+		if e.State != SubmissionSubmitted {
+			n++
+		}
+	}
+	return n
+}
+
+// Retry makes a queued score due now, with a fresh set of attempts
+func (o *ScoreOutbox) Retry(id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	i := o.indexLocked(id)
+	if i < 0 || o.entries[i].State == SubmissionSubmitted || o.entries[i].State == SubmissionSending {
+		return nil
+	}
+	o.entries[i].State = SubmissionPending
+	o.entries[i].Attempts = 0
+	o.entries[i].NextAttempt = time.Now()
+	return o.saveLocked()
+}
+
+// Discard drops a queued score without submitting it
+func (o *ScoreOutbox) Discard(id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	i := o.indexLocked(id)
+	if i < 0 || o.entries[i].State == SubmissionSending {
+		return nil
+	}
+	o.entries = append(o.entries[:i], o.entries[i+1:]...)
+	return o.saveLocked()
+}
+
+// Flush submits every score that is due with submit, one at a time, and
+// schedules the next attempt of those that fail. It returns how many scores
+// were submitted.
+func (o *ScoreOutbox) Flush(submit func(QueuedScore) error) int {
+	sent := 0
+	for {
+		entry, ok := o.nextDue(time.Now())
+		if !ok {
+			return sent
+		}
+		err := submit(entry)
+
+		o.mu.Lock()
+		if i := o.indexLocked(entry.ID); i >= 0 {
+			e := &o.entries[i]
+			e.Attempts++
+			if err == nil {
+				e.State, e.LastError = SubmissionSubmitted, ""
+				sent++
+			} else {
+				e.LastError = truncateRunes(err.Error(), outboxErrorMaxLen)
+				e.NextAttempt = time.Now().Add(OutboxBackoff(e.Attempts))
+				e.State = SubmissionPending
+				if e.Attempts >= OutboxMaxAttempts {
+					e.State = SubmissionFailed
+				}
+			}
+		}
+		if err := o.saveLocked(); err != nil {
+			log.Printf("Failed to save score outbox: %v", err)
+		}
+		o.mu.Unlock()
+	}
+}
+
+// outboxErrorMaxLen caps the error kept for a failed attempt
+const outboxErrorMaxLen = 120
+
+// truncateRunes shortens s to at most n runes
+func truncateRunes(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-3]) + "..."
+	}
+	return s
+}
+
+// OutboxBackoff returns the delay before the next attempt after the given
+// number of failed attempts
+func OutboxBackoff(attempts int) time.Duration {
+	delay := OutboxRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= OutboxMaxRetryDelay {
+			return OutboxMaxRetryDelay
+		}
+	}
+	return delay
+}
+
+// nextDue marks the oldest due score as being sent and returns it
+func (o *ScoreOutbox) nextDue(now time.Time) (QueuedScore, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for i := range o.entries {
+		e := &o.entries[i]
+		if e.State == SubmissionPending && !e.NextAttempt.After(now) {
+			e.State = SubmissionSending
+			return *e, true
+		}
+	}
+	return QueuedScore{}, false
+}
+
+// indexLocked returns the index of a queued score or -1; the caller holds o.mu
+func (o *ScoreOutbox) indexLocked(id string) int {
+	for i := range o.entries {
+		if o.entries[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// saveLocked stores the scores not yet submitted; the caller holds o.mu
+func (o *ScoreOutbox) saveLocked() error {
+	pending := make([]QueuedScore, 0, len(o.entries))
+	for _, e := range o.entries {
+		if e.State != SubmissionSubmitted {
+			pending = append(pending, e)
+		}
+	}
+	return WriteSaveFile(o.store, ScoreOutboxFileName, scoreOutboxSchema, pending)
+}
@@ -0,0 +1,82 @@
+package systems
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScoreOutboxRetriesWithBackoff(t *testing.T) {
+	store := NewMemoryStorage()
+	outbox := NewScoreOutbox(store)
+	id, err := outbox.Add("Ace", "", 1500, "Normal", 4, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Offline: the attempt fails and the next one is scheduled
+	offline := func(QueuedScore) error { return errors.New("no route to host") }
+	if sent := outbox.Flush(offline); sent != 0 {
+		t.Fatalf("sent %d scores while offline", sent)
+	}
+	entry, _ := outbox.Get(id)
+	if entry.State != SubmissionPending || entry.Attempts != 1 || entry.LastError != "no route to host" {
+		t.Fatalf("entry after a failed attempt = %+v", entry)
+	}
+	if wait := time.Until(entry.NextAttempt); wait <= 0 || wait > OutboxRetryDelay {
+		t.Errorf("next attempt in %s, want within %s", wait, OutboxRetryDelay)
+	}
+
+	// Not due yet, so nothing is attempted
+	calls := 0
+	outbox.Flush(func(QueuedScore) error { calls++; return nil })
+	if calls != 0 {
+		t.Errorf("a score was attempted %d times before it was due", calls)
+	}
+
+	// A later launch still has the score and submits it when retried
+	reloaded := NewScoreOutbox(store)
+	if reloaded.Pending() != 1 {
+		t.Fatalf("reloaded outbox has %d scores, want 1", reloaded.Pending())
+	}
+	if err := reloaded.Retry(id); err != nil {
+		t.Fatal(err)
+	}
+	var submitted QueuedScore
+	if sent := reloaded.Flush(func(e QueuedScore) error { submitted = e; return nil }); sent != 1 {
+		t.Fatalf("sent %d scores, want 1", sent)
+	}
+	if submitted.PlayerName != "Ace" || submitted.Score != 1500 {
+		t.Errorf("submitted %+v", submitted)
+	}
+	if entry, _ := reloaded.Get(id); entry.State != SubmissionSubmitted {
+		t.Errorf("state = %s, want submitted", entry.State)
+	}
+	if NewScoreOutbox(store).Pending() != 0 {
+		t.Error("submitted score is still queued on the next launch")
+	}
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	want := []time.Duration{OutboxRetryDelay, 2 * OutboxRetryDelay, 4 * OutboxRetryDelay}
+	for i, w := range want {
+		if got := OutboxBackoff(i + 1); got != w {
+			t.Errorf("backoff after %d attempts = %s, want %s", i+1, got, w)
+		}
+	}
+	if got := OutboxBackoff(50); got != OutboxMaxRetryDelay {
+		t.Errorf("backoff after 50 attempts = %s, want %s", got, OutboxMaxRetryDelay)
+	}
+
+	// Automatic attempts stop after OutboxMaxAttempts
+	outbox := NewScoreOutbox(NewMemoryStorage())
+	id, _ := outbox.Add("Ace", "", 10, "Easy", 1, nil)
+	for i := 0; i < OutboxMaxAttempts; i++ {
+		outbox.Retry(id)
+		outbox.entries[0].Attempts = i // Retry resets the count; keep counting
+		outbox.Flush(func(QueuedScore) error { return errors.New("down") })
+	}
+	if entry, _ := outbox.Get(id); entry.State != SubmissionFailed {
+		t.Errorf("state after %d failures = %s, want failed", OutboxMaxAttempts, entry.State)
+	}
+}
@@ -0,0 +1,149 @@
+package systems
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Performance HUD layout
+const (
+	perfGraphHeight    = 60
+	perfGraphMax       = 50 * time.Millisecond // Frame time at the top of the graph
+	perfHUDLineHeight  = 16
+	perfHUDTextScale   = 0.8
+	perfFrameBudget60  = time.Second / 60
+	perfFrameBudget30  = time.Second / 30
+	perfHUDPoolColumns = 2
+)
+
+// PerformanceHUDWidth is the width of the performance HUD panel in pixels
+const PerformanceHUDWidth = FrameHistoryLength + 20
+
+// Performance HUD colors
+var (
+	perfPanelColor  = color.RGBA{0, 0, 0, 180}
+	perfTextColor   = color.RGBA{220, 220, 220, 255}
+	perfTitleColor  = color.RGBA{100, 200, 255, 255}
+	perfGoodColor   = color.RGBA{100, 255, 100, 255}
+	perfWarnColor   = color.RGBA{255, 220, 80, 255}
+	perfBadColor    = color.RGBA{255, 80, 80, 255}
+	perfBudgetColor = color.RGBA{255, 255, 255, 80}
+	perfRecordColor = color.RGBA{255, 60, 60, 255}
+)
+
+// PerformanceHUD draws a live view of a PerformanceMonitor: a scrolling
+// frame-time graph, the collision/render breakdown, GC pauses and pool reuse
+type PerformanceHUD struct {
+	Visible bool
+
+	monitor *PerformanceMonitor
+	history []time.Duration // Reused between frames
+}
+
+// NewPerformanceHUD creates a hidden HUD for the given monitor
+func NewPerformanceHUD(monitor *PerformanceMonitor) *PerformanceHUD {
+	return &PerformanceHUD{
+		monitor: monitor,
+		history: make([]time.Duration, 0, FrameHistoryLength),
+	}
+}
+
+// Toggle shows or hides the HUD
+func (h *PerformanceHUD) Toggle() {
+	h.Visible = !h.Visible
+}
+
+// frameTimeColor grades a frame time against the 60 and 30 FPS budgets
+func frameTimeColor(d time.Duration) color.RGBA {
+	switch {
+	case d <= perfFrameBudget60:
+		return perfGoodColor
+	case d <= perfFrameBudget30:
+		return perfWarnColor
+	default:
+		return perfBadColor
+	}
+}
+
+// Draw renders the HUD with its top-left corner at x, y
+func (h *PerformanceHUD) Draw(screen *ebiten.Image, x, y int) {
+	if !h.Visible {
+		return
+	}
+	pm := h.monitor
+	pools := pm.GetPoolStats()
+	poolNames := sortedNames(pools)
+	poolRows := (len(poolNames) + perfHUDPoolColumns - 1) / perfHUDPoolColumns
+	height := perfGraphHeight + (6+poolRows)*perfHUDLineHeight + 24
+
+	vector.DrawFilledRect(screen, float32(x), float32(y), PerformanceHUDWidth, float32(height), perfPanelColor, false)
+	x += 10
+	y += 8
+
+	// Title line with FPS and the trace indicator
+	title := fmt.Sprintf("PERF  %.0f FPS", pm.GetFPS())
+	DrawText(screen, title, x, y, perfHUDTextScale, perfTitleColor)
+	if pm.IsTracing() {
+		DrawText(screen, "REC", x+FrameHistoryLength-30, y, perfHUDTextScale, perfRecordColor)
+	}
+	y += perfHUDLineHeight + 4
+
+	// Scrolling frame-time graph, newest frame on the right
+	graphBottom := float32(y + perfGraphHeight)
+	scale := float32(perfGraphHeight) / float32(perfGraphMax)
+	h.history = pm.GetFrameTimeHistory(h.history[:0])
+	for i, ft := range h.history {
+		if ft <= 0 {
+			continue
+		}
+		barHeight := float32(ft) * scale
+		if barHeight > perfGraphHeight {
+			barHeight = perfGraphHeight
+		}
+		vector.StrokeLine(screen, float32(x+i), graphBottom, float32(x+i), graphBottom-barHeight, 1, frameTimeColor(ft), false)
+	}
+	for _, budget := range []time.Duration{perfFrameBudget60, perfFrameBudget30} {
+		by := graphBottom - float32(budget)*scale
+		vector.StrokeLine(screen, float32(x), by, float32(x+FrameHistoryLength), by, 1, perfBudgetColor, false)
+	}
+	y += perfGraphHeight + 4
+
+	// Frame time, collision and render breakdown
+	minFT, maxFT, avgFT := pm.GetFrameTimeStats()
+	DrawText(screen, fmt.Sprintf("frame avg %s  min %s  max %s", formatDuration(avgFT), formatDuration(minFT), formatDuration(maxFT)),
+		x, y, perfHUDTextScale, frameTimeColor(avgFT))
+	y += perfHUDLineHeight
+	DrawText(screen, fmt.Sprintf("collision %s  render %s", formatDuration(pm.GetCollisionTime()), formatDuration(pm.GetRenderTime())),
+		x, y, perfHUDTextScale, perfTextColor)
+	y += perfHUDLineHeight
+
+	// Memory and garbage collection
+	alloc, _, _, allocRate := pm.GetMemoryStats()
+	DrawText(screen, fmt.Sprintf("heap %s  alloc %s/s", formatBytes(alloc), formatBytes(uint64(allocRate))),
+		x, y, perfHUDTextScale, perfTextColor)
+	y += perfHUDLineHeight
+	numGC, gcRate, lastPause, maxPause := pm.GetGCStats()
+	DrawText(screen, fmt.Sprintf("GC %d (%.1f/s)  pause %s  max %s", numGC, gcRate, formatDuration(lastPause), formatDuration(maxPause)),
+		x, y, perfHUDTextScale, frameTimeColor(maxPause*4))
+	y += perfHUDLineHeight
+
+	// Pool reuse rates, two per line
+	DrawText(screen, "pool reuse", x, y, perfHUDTextScale, perfTitleColor)
+	y += perfHUDLineHeight
+	for i, name := range poolNames {
+		col := i % perfHUDPoolColumns
+		row := i / perfHUDPoolColumns
+		stats := pools[name]
+		DrawText(screen, fmt.Sprintf("%s %.0f%% (%d)", name, stats.ReuseRate, stats.CurrentActive),
+			x+col*(FrameHistoryLength/perfHUDPoolColumns), y+row*perfHUDLineHeight, perfHUDTextScale, perfTextColor)
+	}
+}
+
+// formatDuration formats a duration in milliseconds for the HUD
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+}
@@ -1,12 +1,44 @@
 package systems
 
 import (
+	"encoding/csv"
 	"fmt"
+	"os"
 	"runtime"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 )
 
+// FrameHistoryLength is how many frame times the performance graph shows
+const FrameHistoryLength = 240
+
+// gcPauseWindow is how many recent GC pauses are kept for the maximum
+const gcPauseWindow = 16
+
+// FrameTimeBuckets are the upper bounds of the frame time histogram
+var FrameTimeBuckets = []time.Duration{
+	4 * time.Millisecond,
+	8 * time.Millisecond,
+	12 * time.Millisecond,
+	time.Second / 60,
+	20 * time.Millisecond,
+	25 * time.Millisecond,
+	time.Second / 30,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+}
+
+// FrameTimeHistogram counts frames by frame time since the monitor started
+type FrameTimeHistogram struct {
+	Buckets []time.Duration // Upper bounds, as in FrameTimeBuckets
+	Counts  []uint64        // Frames per bucket; the extra last entry counts slower frames
+	Sum     time.Duration
+	Count   uint64
+}
+
 // PerformanceMonitor tracks game performance metrics
 type PerformanceMonitor struct {
 	// FPS tracking
@@ -21,14 +53,31 @@ type PerformanceMonitor struct {
 	maxFrameTime time.Duration
 	minFrameTime time.Duration
 
+	// Longer frame time history for the performance HUD graph
+	frameHistory    [FrameHistoryLength]time.Duration
+	frameHistoryIdx int
+
+	// Frame time histogram over the whole session
+	histogram FrameTimeHistogram
+
 	// Memory tracking
 	lastMemStats     runtime.MemStats
 	lastMemStatsTime time.Time
 	allocRate        float64 // Bytes per second
 
+	// Garbage collector pauses
+	gcPauses   [gcPauseWindow]time.Duration // Most recent pauses
+	gcPauseIdx int
+	gcRate     float64 // Collections per second
+
 	// Entity counts
 	entityCounts map[string]int
 
+	// Time spent in each simulation subsystem: the latest tick and the total
+	// since the last ResetSubsystemTotals
+	subsystemTimes  map[string]time.Duration
+	subsystemTotals map[string]time.Duration
+
 	// Pool statistics
 	poolStats map[string]PoolStatsSnapshot
 
@@ -37,6 +86,15 @@ type PerformanceMonitor struct {
 	collisionTime time.Duration
 	renderTime    time.Duration
 
+	// CSV trace of per-frame metrics, recorded on demand
+	traceFile    *os.File
+	trace        *csv.Writer
+	tracePath    string
+	traceStart   time.Time
+	traceEntity  []string // Entity count columns, fixed when the trace starts
+	tracePools   []string // Pool reuse columns, fixed when the trace starts
+	traceStarted bool     // Header written
+
 	mu sync.RWMutex
 }
 
@@ -57,8 +115,14 @@ func NewPerformanceMonitor() *PerformanceMonitor {
 		frameTimes:       make([]time.Duration, 60), // Track last 60 frames
 		minFrameTime:     time.Hour,                 // Start with large value
 		entityCounts:     make(map[string]int),
+		subsystemTimes:   make(map[string]time.Duration),
+		subsystemTotals:  make(map[string]time.Duration),
 		poolStats:        make(map[string]PoolStatsSnapshot),
 		lastMemStatsTime: time.Now(),
+		histogram: FrameTimeHistogram{
+			Buckets: FrameTimeBuckets,
+			Counts:  make([]uint64, len(FrameTimeBuckets)+1),
+		},
 	}
 }
 
@@ -70,6 +134,12 @@ func (pm *PerformanceMonitor) RecordFrame(frameTime time.Duration) {
 	pm.frameCount++
 	pm.frameTimes[pm.frameTimeIdx] = frameTime
 	pm.frameTimeIdx = (pm.frameTimeIdx + 1) % len(pm.frameTimes)
+	pm.frameHistory[pm.frameHistoryIdx] = frameTime
+	pm.frameHistoryIdx = (pm.frameHistoryIdx + 1) % FrameHistoryLength
+	bucket := sort.Search(len(FrameTimeBuckets), func(i int) bool { return frameTime <= FrameTimeBuckets[i] })
+	pm.histogram.Counts[bucket]++
+	pm.histogram.Sum += frameTime
+	pm.histogram.Count++
 
 	// Update min/max
 	if frameTime > pm.maxFrameTime {
@@ -116,6 +186,14 @@ func (pm *PerformanceMonitor) RecordRenderTime(duration time.Duration) {
 	pm.mu.Unlock()
 }
 
+// RecordSubsystemTime records time spent in a named simulation subsystem
+func (pm *PerformanceMonitor) RecordSubsystemTime(name string, duration time.Duration) {
+	pm.mu.Lock()
+	pm.subsystemTimes[name] = duration
+	pm.subsystemTotals[name] += duration
+	pm.mu.Unlock()
+}
+
 // UpdateEntityCount updates the count for a specific entity type
 func (pm *PerformanceMonitor) UpdateEntityCount(entityType string, count int) {
 	pm.mu.Lock()
@@ -158,6 +236,17 @@ func (pm *PerformanceMonitor) UpdateMemoryStats() {
 		allocDiff := memStats.TotalAlloc - pm.lastMemStats.TotalAlloc
 		pm.allocRate = float64(allocDiff) / elapsed.Seconds()
 
+		// Collect the pauses of collections since the last sample; the
+		// runtime keeps the most recent 256 in a ring indexed by GC number
+		for n := pm.lastMemStats.NumGC + 1; n <= memStats.NumGC; n++ {
+			if memStats.NumGC-n >= uint32(len(memStats.PauseNs)) {
+				continue
+			}
+			pm.gcPauses[pm.gcPauseIdx] = time.Duration(memStats.PauseNs[(n+255)%256])
+			pm.gcPauseIdx = (pm.gcPauseIdx + 1) % gcPauseWindow
+		}
+		pm.gcRate = float64(memStats.NumGC-pm.lastMemStats.NumGC) / elapsed.Seconds()
+
 		pm.lastMemStats = memStats
 		pm.lastMemStatsTime = now
 	}
@@ -191,6 +280,37 @@ func (pm *PerformanceMonitor) GetMemoryStats() (alloc, totalAlloc, sys uint64, a
 	return pm.lastMemStats.Alloc, pm.lastMemStats.TotalAlloc, pm.lastMemStats.Sys, pm.allocRate
 }
 
+// GetFrameTimeHistory appends the recent frame times to buf, oldest first
+func (pm *PerformanceMonitor) GetFrameTimeHistory(buf []time.Duration) []time.Duration {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	buf = append(buf, pm.frameHistory[pm.frameHistoryIdx:]...)
+	return append(buf, pm.frameHistory[:pm.frameHistoryIdx]...)
+}
+
+// GetFrameTimeHistogram returns a copy of the frame time histogram
+func (pm *PerformanceMonitor) GetFrameTimeHistogram() FrameTimeHistogram {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	h := pm.histogram
+	h.Counts = append([]uint64(nil), pm.histogram.Counts...)
+	return h
+}
+
+// GetGCStats returns the number of collections so far, collections per
+// second, and the latest and longest of the recent GC pauses
+func (pm *PerformanceMonitor) GetGCStats() (numGC uint32, rate float64, lastPause, maxPause time.Duration) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	lastPause = pm.gcPauses[(pm.gcPauseIdx+gcPauseWindow-1)%gcPauseWindow]
+	for _, p := range pm.gcPauses {
+		if p > maxPause {
+			maxPause = p
+		}
+	}
+	return pm.lastMemStats.NumGC, pm.gcRate, lastPause, maxPause
+}
+
 // GetEntityCounts returns entity counts
 func (pm *PerformanceMonitor) GetEntityCounts() map[string]int {
 	pm.mu.RLock()
@@ -203,6 +323,38 @@ func (pm *PerformanceMonitor) GetEntityCounts() map[string]int {
 	return counts
 }
 
+// GetSubsystemTimes returns the time each subsystem took in the latest tick
+func (pm *PerformanceMonitor) GetSubsystemTimes() map[string]time.Duration {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	times := make(map[string]time.Duration, len(pm.subsystemTimes))
+	for k, v := range pm.subsystemTimes {
+		times[k] = v
+	}
+	return times
+}
+
+// GetSubsystemTotals returns the time each subsystem has taken since the
+// totals were last reset
+func (pm *PerformanceMonitor) GetSubsystemTotals() map[string]time.Duration {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	totals := make(map[string]time.Duration, len(pm.subsystemTotals))
+	for k, v := range pm.subsystemTotals {
+		totals[k] = v
+	}
+	return totals
+}
+
+// ResetSubsystemTotals clears the accumulated subsystem times
+func (pm *PerformanceMonitor) ResetSubsystemTotals() {
+	pm.mu.Lock()
+	clear(pm.subsystemTotals)
+	pm.mu.Unlock()
+}
+
 // GetCollisionTime returns time spent in collision detection
 func (pm *PerformanceMonitor) GetCollisionTime() time.Duration {
 	pm.mu.RLock()
@@ -283,3 +435,110 @@ func formatBytes(bytes uint64) string {
 	}
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
+
+// StartTrace starts recording per-frame metrics to a CSV file at path
+func (pm *PerformanceMonitor) StartTrace(path string) error {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	if pm.trace != nil {
+		return fmt.Errorf("trace already recording to %s", pm.tracePath)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create trace file: %w", err)
+	}
+	pm.traceFile = f
+	pm.trace = csv.NewWriter(f)
+	pm.tracePath = path
+	pm.traceStart = time.Now()
+	pm.traceStarted = false
+	return nil
+}
+
+// StopTrace finishes the CSV trace and returns the path it was written to
+func (pm *PerformanceMonitor) StopTrace() (string, error) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	if pm.trace == nil {
+		return "", nil
+	}
+	pm.trace.Flush()
+	err := pm.trace.Error()
+	if closeErr := pm.traceFile.Close(); err == nil {
+		err = closeErr
+	}
+	path := pm.tracePath
+	pm.trace, pm.traceFile, pm.tracePath = nil, nil, ""
+	if err != nil {
+		return path, fmt.Errorf("failed to write trace: %w", err)
+	}
+	return path, nil
+}
+
+// IsTracing reports whether a CSV trace is being recorded
+func (pm *PerformanceMonitor) IsTracing() bool {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.trace != nil
+}
+
+// TraceFrame appends the latest frame's metrics to the CSV trace, if one is
+// recording. Call it once per frame after all metrics have been updated.
+func (pm *PerformanceMonitor) TraceFrame() {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	if pm.trace == nil {
+		return
+	}
+
+	if !pm.traceStarted {
+		pm.traceEntity = sortedNames(pm.entityCounts)
+		pm.tracePools = sortedNames(pm.poolStats)
+		header := []string{"elapsed_ms", "frame_ms", "fps", "collision_ms", "render_ms",
+			"heap_alloc_bytes", "alloc_rate_bytes_per_s", "num_gc", "gc_pause_ms"}
+		for _, name := range pm.traceEntity {
+			header = append(header, "entities_"+name)
+		}
+		for _, name := range pm.tracePools {
+			header = append(header, "pool_reuse_"+name)
+		}
+		pm.trace.Write(header)
+		pm.traceStarted = true
+	}
+
+	last := pm.frameHistory[(pm.frameHistoryIdx+FrameHistoryLength-1)%FrameHistoryLength]
+	lastPause := pm.gcPauses[(pm.gcPauseIdx+gcPauseWindow-1)%gcPauseWindow]
+	row := []string{
+		formatMillis(time.Since(pm.traceStart)),
+		formatMillis(last),
+		strconv.FormatFloat(pm.fps, 'f', 1, 64),
+		formatMillis(pm.collisionTime),
+		formatMillis(pm.renderTime),
+		strconv.FormatUint(pm.lastMemStats.HeapAlloc, 10),
+		strconv.FormatFloat(pm.allocRate, 'f', 0, 64),
+		strconv.FormatUint(uint64(pm.lastMemStats.NumGC), 10),
+		formatMillis(lastPause),
+	}
+	for _, name := range pm.traceEntity {
+		row = append(row, strconv.Itoa(pm.entityCounts[name]))
+	}
+	for _, name := range pm.tracePools {
+		row = append(row, strconv.FormatFloat(pm.poolStats[name].ReuseRate, 'f', 1, 64))
+	}
+	pm.trace.Write(row)
+}
+
+// formatMillis formats a duration as fractional milliseconds
+func formatMillis(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+}
+
+// sortedNames returns the keys of a metrics map in alphabetical order
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,63 @@
+package systems
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFrameTimeHistoryIsOldestFirst(t *testing.T) {
+	pm := NewPerformanceMonitor()
+	for i := 1; i <= FrameHistoryLength+5; i++ {
+		pm.RecordFrame(time.Duration(i) * time.Millisecond)
+	}
+	history := pm.GetFrameTimeHistory(nil)
+	if len(history) != FrameHistoryLength {
+		t.Fatalf("history holds %d frames, want %d", len(history), FrameHistoryLength)
+	}
+	if history[0] != 6*time.Millisecond || history[len(history)-1] != time.Duration(FrameHistoryLength+5)*time.Millisecond {
+		t.Fatalf("history runs %v..%v, want the latest %d frames oldest first", history[0], history[len(history)-1], FrameHistoryLength)
+	}
+}
+
+func TestPerformanceTraceWritesCSV(t *testing.T) {
+	pm := NewPerformanceMonitor()
+	pm.UpdateEntityCount("enemies", 3)
+	pm.UpdatePoolStats("projectiles", PoolStatsSnapshot{ReuseRate: 75})
+
+	path := filepath.Join(t.TempDir(), "trace.csv")
+	if err := pm.StartTrace(path); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		pm.RecordFrame(16 * time.Millisecond)
+		pm.TraceFrame()
+	}
+	if got, err := pm.StopTrace(); err != nil || got != path {
+		t.Fatalf("StopTrace = %q, %v", got, err)
+	}
+	pm.TraceFrame() // No trace recording: ignored
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("trace has %d rows, want a header and 3 frames", len(rows))
+	}
+	header, first := rows[0], rows[1]
+	columns := map[string]string{}
+	for i, name := range header {
+		columns[name] = first[i]
+	}
+	if columns["frame_ms"] != "16.000" || columns["entities_enemies"] != "3" || columns["pool_reuse_projectiles"] != "75.0" {
+		t.Fatalf("unexpected first frame %v", columns)
+	}
+}
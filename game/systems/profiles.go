@@ -0,0 +1,493 @@
+package systems
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Profile names and files
+const (
+	ProfileNameMaxLen   = 16
+	DefaultProfileName  = "Pilot"
+	profilesFileName    = "profiles.json"
+	profilesDir         = "profiles"
+	profileArchiveMeta  = "profile.json"
+	profileArchiveLimit = 32 << 20 // Largest total size accepted when importing
+)
+
+// ProfileArchiveExt is the file extension of exported profiles
+const ProfileArchiveExt = ".pilot"
+
+// ProfileArchiveFormat is bumped whenever the archive layout changes in a
+// way that older readers cannot understand
+const ProfileArchiveFormat = 1
+
+// profileDirs are the directories of a profile's storage that hold its
+// files, relative to the profile's root
+var profileDirs = []string{".", ReplayHistoryDir}
+
+// Profile errors
+var (
+	ErrProfileNameEmpty = errors.New("pilot name is empty")
+	ErrProfileNameTaken = errors.New("pilot name is already taken")
+	ErrProfileNotFound  = errors.New("pilot not found")
+	ErrLastProfile      = errors.New("the last pilot cannot be deleted")
+)
+
+// Profile is a named pilot with its own progression, settings and replays
+type Profile struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+
+	// Names the pilot's synced save. It is made once and kept across
+	// renames and export and import, so the pilot keeps its cloud save.
+	SyncKey string `json:"sync_key"`
+}
+
+// profileIndex lists the profiles of an install
+type profileIndex struct {
+	Active   string    `json:"active"`
+	Profiles []Profile `json:"profiles"`
+	NextID   int       `json:"next_id"`
+}
+
+// profilesSchema is the save format of the profile index. Version 2 added
+// sync keys.
+var profilesSchema = SaveSchema{
+	Kind:    "profiles",
+	Version: 2,
+	Migrations: map[int]SaveMigration{
+		1: func(data json.RawMessage) (json.RawMessage, error) {
+			// Saves used to be synced under the pilot's name, so keep
+			// syncing the existing pilots there
+			var index profileIndex
+			if err := json.Unmarshal(data, &index); err != nil {
+				return nil, err
+			}
+			for i := range index.Profiles {
+				index.Profiles[i].SyncKey = ProfileSlug(index.Profiles[i].Name)
+			}
+			return json.Marshal(index)
+		},
+	},
+}
+
+// profileArchiveInfo is the metadata file of an exported profile
+type profileArchiveInfo struct {
+	Format  int       `json:"format"`
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+	SyncKey string    `json:"sync_key,omitempty"`
+}
+
+// ProfileManager keeps the list of profiles and which one is active. Each
+// profile's files live in their own directory of the storage.
+type ProfileManager struct {
+	store Storage
+	index profileIndex
+}
+
+// NewProfileManager loads the profiles kept in store. An install without
+// profiles gets a default one, and legacyFiles from before profiles existed
+// are moved into it so their progress is kept. The manager is usable even
+// when an error is returned for failing to save the new index.
+func NewProfileManager(store Storage, legacyFiles []string) (*ProfileManager, error) {
+	pm := &ProfileManager{store: store}
+	err := ReadSaveFile(store, profilesFileName, profilesSchema, &pm.index)
+	if err != nil && !os.IsNotExist(err) {
+		// Starting over reuses the first profile's directory, so at least
+		// that pilot keeps its progress
+		log.Printf("Failed to load profiles, starting over: %v", err)
+		pm.index = profileIndex{}
+	}
+	if len(pm.index.Profiles) > 0 {
+		if _, ok := pm.find(pm.index.Active); !ok {
+			pm.index.Active = pm.index.Profiles[0].ID
+		}
+		return pm, nil
+	}
+
+	p, err := pm.Create(DefaultProfileName)
+	if err != nil {
+		return pm, err
+	}
+	for _, name := range legacyFiles {
+		store.Rename(name, path.Join(profilesDir, p.ID, name))
+		for n := 1; n <= SaveBackupCount; n++ {
+			store.Rename(saveBackupName(name, n), path.Join(profilesDir, p.ID, saveBackupName(name, n)))
+		}
+	}
+	return pm, pm.Select(p.ID)
+}
+
+// Profiles returns the profiles in the order they were created
+func (pm *ProfileManager) Profiles() []Profile {
+	return append([]Profile(nil), pm.index.Profiles...)
+}
+
+// Active returns the selected profile
+func (pm *ProfileManager) Active() Profile {
+	p, _ := pm.find(pm.index.Active)
+	return p
+}
+
+// Storage returns the storage holding a profile's files
+func (pm *ProfileManager) Storage(id string) Storage {
+	return NewSubStorage(pm.store, path.Join(profilesDir, id))
+}
+
+// Select makes a profile the active one
+func (pm *ProfileManager) Select(id string) error {
+	if _, ok := pm.find(id); !ok {
+		return ErrProfileNotFound
+	}
+	pm.index.Active = id
+	return pm.save()
+}
+
+// Create adds a profile with the given name
+func (pm *ProfileManager) Create(name string) (Profile, error) {
+	name, err := pm.checkName(name, "")
+	if err != nil {
+		return Profile{}, err
+	}
+	key, err := newSyncKey()
+	if err != nil {
+		return Profile{}, err
+	}
+	pm.index.NextID++
+	p := Profile{
+		ID:      fmt.Sprintf("pilot%d", pm.index.NextID),
+		Name:    name,
+		Created: time.Now().UTC(),
+		SyncKey: key,
+	}
+	pm.index.Profiles = append(pm.index.Profiles, p)
+	if pm.index.Active == "" {
+		pm.index.Active = p.ID
+	}
+	return p, pm.save()
+}
+
+// Rename changes a profile's name. A profile that shares its sync key with
+// another (see SyncKeyShared) gets a key of its own.
+func (pm *ProfileManager) Rename(id, name string) error {
+	i, ok := pm.indexOf(id)
+	if !ok {
+		return ErrProfileNotFound
+	}
+	name, err := pm.checkName(name, id)
+	if err != nil {
+		return err
+	}
+	if pm.SyncKeyShared(id) {
+		key, err := newSyncKey()
+		if err != nil {
+			return err
+		}
+		pm.index.Profiles[i].SyncKey = key
+	}
+	pm.index.Profiles[i].Name = name
+	return pm.save()
+}
+
+// Delete removes a profile and its files. Deleting the active profile makes
+// the first remaining profile active.
+func (pm *ProfileManager) Delete(id string) error {
+	i, ok := pm.indexOf(id)
+	if !ok {
+		return ErrProfileNotFound
+	}
+	if len(pm.index.Profiles) == 1 {
+		return ErrLastProfile
+	}
+
+	store := pm.Storage(id)
+	for _, dir := range profileDirs {
+		names, err := store.List(dir)
+		if err != nil {
+			return fmt.Errorf("failed to delete pilot files: %w", err)
+		}
+		for _, name := range names {
+			if err := store.Remove(name); err != nil {
+				return fmt.Errorf("failed to delete pilot files: %w", err)
+			}
+		}
+	}
+
+	pm.index.Profiles = append(pm.index.Profiles[:i], pm.index.Profiles[i+1:]...)
+	if pm.index.Active == id {
+		pm.index.Active = pm.index.Profiles[0].ID
+	}
+	return pm.save()
+}
+
+// Export packs a profile and all of its files into a single zip archive
+func (pm *ProfileManager) Export(id string) ([]byte, error) {
+	p, ok := pm.find(id)
+	if !ok {
+		return nil, ErrProfileNotFound
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	meta, err := json.MarshalIndent(profileArchiveInfo{Format: ProfileArchiveFormat, Name: p.Name, Created: p.Created, SyncKey: p.SyncKey}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode pilot: %w", err)
+	}
+	if err := writeZipFile(zw, profileArchiveMeta, meta); err != nil {
+		return nil, err
+	}
+
+	store := pm.Storage(id)
+	for _, dir := range profileDirs {
+		names, err := store.List(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pilot files: %w", err)
+		}
+		for _, name := range names {
+			data, err := store.Read(name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", name, err)
+			}
+			if err := writeZipFile(zw, path.Join("files", name), data); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write pilot archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeZipFile adds one file to a zip archive
+func writeZipFile(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to write pilot archive: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write pilot archive: %w", err)
+	}
+	return nil
+}
+
+// Import adds the profile packed in an archive made by Export. A name that
+// is already taken gets a numbered suffix, and a copy of a pilot already on
+// this install gets a sync key of its own. The archive is checked in full
+// before anything is written.
+func (pm *ProfileManager) Import(archive []byte) (Profile, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return Profile{}, fmt.Errorf("failed to open pilot archive: %w", err)
+	}
+
+	var info *profileArchiveInfo
+	files := make(map[string][]byte)
+	total := 0
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		data, err := readZipFile(f, profileArchiveLimit-total)
+		if err != nil {
+			return Profile{}, err
+		}
+		total += len(data)
+
+		if f.Name == profileArchiveMeta {
+			info = &profileArchiveInfo{}
+			if err := json.Unmarshal(data, info); err != nil {
+				return Profile{}, fmt.Errorf("failed to parse pilot archive: %w", err)
+			}
+			continue
+		}
+		name, ok := strings.CutPrefix(f.Name, "files/")
+		if !ok || !validProfileFile(name) {
+			return Profile{}, fmt.Errorf("pilot archive holds unexpected file %q", f.Name)
+		}
+		files[name] = data
+	}
+	if info == nil {
+		return Profile{}, fmt.Errorf("not a pilot archive: %s is missing", profileArchiveMeta)
+	}
+	if info.Format > ProfileArchiveFormat {
+		return Profile{}, fmt.Errorf("pilot archive format %d is newer than the supported format %d", info.Format, ProfileArchiveFormat)
+	}
+
+	p, err := pm.Create(pm.uniqueName(info.Name))
+	if err != nil {
+		return Profile{}, err
+	}
+	i, _ := pm.indexOf(p.ID)
+	if !info.Created.IsZero() {
+		pm.index.Profiles[i].Created = info.Created
+		p.Created = info.Created
+	}
+	if validSyncKey(info.SyncKey) && pm.syncKeyOwner(info.SyncKey) == "" {
+		pm.index.Profiles[i].SyncKey = info.SyncKey
+		p.SyncKey = info.SyncKey
+	}
+	store := pm.Storage(p.ID)
+	for _, name := range sortedNames(files) {
+		if err := store.Write(name, files[name]); err != nil {
+			return Profile{}, fmt.Errorf("failed to import %s: %w", name, err)
+		}
+	}
+	return p, pm.save()
+}
+
+// SyncKeyShared reports whether another profile has the same sync key as
+// the given one, so syncing either would overwrite the other's save
+func (pm *ProfileManager) SyncKeyShared(id string) bool {
+	p, ok := pm.find(id)
+	if !ok {
+		return false
+	}
+	for _, other := range pm.index.Profiles {
+		if other.ID != id && other.SyncKey == p.SyncKey {
+			return true
+		}
+	}
+	return false
+}
+
+// syncKeyOwner returns the ID of the profile with a sync key, or ""
+func (pm *ProfileManager) syncKeyOwner(key string) string {
+	for _, p := range pm.index.Profiles {
+		if p.SyncKey == key {
+			return p.ID
+		}
+	}
+	return ""
+}
+
+// newSyncKey returns a random sync key
+func newSyncKey() (string, error) {
+	var key [8]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		return "", fmt.Errorf("failed to create sync key: %w", err)
+	}
+	return hex.EncodeToString(key[:]), nil
+}
+
+// validSyncKey reports whether a sync key is safe to use in a file name
+func validSyncKey(key string) bool {
+	return key != "" && len(key) <= 64 && ProfileSlug(key) == key
+}
+
+// ProfileSlug turns a pilot name into a form safe for file names
+func ProfileSlug(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, name)
+}
+
+// readZipFile reads one file of an archive, failing if it is larger than limit
+func readZipFile(f *zip.File, limit int) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from pilot archive: %w", f.Name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, int64(limit)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from pilot archive: %w", f.Name, err)
+	}
+	if len(data) > limit {
+		return nil, fmt.Errorf("pilot archive is larger than %d MB", profileArchiveLimit>>20)
+	}
+	return data, nil
+}
+
+// validProfileFile reports whether name is a plain file in one of the
+// profile's directories, so an archive cannot write anywhere else
+func validProfileFile(name string) bool {
+	if name == "" || path.Clean(name) != name || path.IsAbs(name) || strings.Contains(name, "\\") {
+		return false
+	}
+	dir := path.Dir(name)
+	for _, d := range profileDirs {
+		if dir == d {
+			return true
+		}
+	}
+	return false
+}
+
+// checkName validates a profile name, ignoring the profile being renamed
+func (pm *ProfileManager) checkName(name, exceptID string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrProfileNameEmpty
+	}
+	if r := []rune(name); len(r) > ProfileNameMaxLen {
+		name = string(r[:ProfileNameMaxLen])
+	}
+	for _, p := range pm.index.Profiles {
+		if p.ID != exceptID && strings.EqualFold(p.Name, name) {
+			return "", ErrProfileNameTaken
+		}
+	}
+	return name, nil
+}
+
+// uniqueName returns name, or name with a numbered suffix if it is taken
+func (pm *ProfileManager) uniqueName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = DefaultProfileName
+	}
+	candidate := name
+	for n := 2; ; n++ {
+		if _, err := pm.checkName(candidate, ""); err == nil {
+			return candidate
+		}
+		suffix := fmt.Sprintf(" %d", n)
+		base := []rune(name)
+		if len(base)+len(suffix) > ProfileNameMaxLen {
+			base = base[:ProfileNameMaxLen-len(suffix)]
+		}
+		candidate = string(base) + suffix
+	}
+}
+
+// find returns the profile with the given ID
+func (pm *ProfileManager) find(id string) (Profile, bool) {
+	if i, ok := pm.indexOf(id); ok {
+		return pm.index.Profiles[i], true
+	}
+	return Profile{}, false
+}
+
+// indexOf returns the position of a profile in the index
+func (pm *ProfileManager) indexOf(id string) (int, bool) {
+	for i, p := range pm.index.Profiles {
+		if p.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// save writes the profile index
+func (pm *ProfileManager) save() error {
+	return WriteSaveFile(pm.store, profilesFileName, profilesSchema, pm.index)
+}
@@ -0,0 +1,227 @@
+package systems
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestProfileManagerAdoptsLegacyFiles(t *testing.T) {
+	store := NewMemoryStorage()
+	pm := NewProgressionManager(store, "progression.json")
+	pm.AddScrap(250)
+	if err := pm.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := NewProfileManager(store, []string{"progression.json", SettingsFileName})
+	if err != nil {
+		t.Fatal(err)
+	}
+	active := profiles.Active()
+	if active.Name != DefaultProfileName || len(profiles.Profiles()) != 1 {
+		t.Fatalf("profiles = %+v", profiles.Profiles())
+	}
+	if _, err := store.Read("progression.json"); err == nil {
+		t.Error("legacy progression was left at the top level")
+	}
+	if got := NewProgressionManager(profiles.Storage(active.ID), "progression.json").GetTotalScrap(); got != 250 {
+		t.Errorf("adopted profile has %d scrap, want 250", got)
+	}
+
+	// Reloading keeps the index and does not create another profile
+	reloaded, err := NewProfileManager(store, []string{"progression.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.Profiles()) != 1 || reloaded.Active().ID != active.ID {
+		t.Errorf("reloaded profiles = %+v", reloaded.Profiles())
+	}
+}
+
+func TestProfileCreateRenameDelete(t *testing.T) {
+	store := NewMemoryStorage()
+	profiles, err := NewProfileManager(store, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := profiles.Active()
+	if err := profiles.Delete(first.ID); !errors.Is(err, ErrLastProfile) {
+		t.Fatalf("deleting the last pilot gave %v", err)
+	}
+
+	second, err := profiles.Create("  Maverick ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Name != "Maverick" {
+		t.Errorf("name = %q, want it trimmed", second.Name)
+	}
+	if _, err := profiles.Create("MAVERICK"); !errors.Is(err, ErrProfileNameTaken) {
+		t.Errorf("duplicate name gave %v", err)
+	}
+	if err := profiles.Rename(first.ID, "maverick"); !errors.Is(err, ErrProfileNameTaken) {
+		t.Errorf("renaming onto a taken name gave %v", err)
+	}
+	if err := profiles.Rename(second.ID, "Goose"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each profile has its own files
+	if err := profiles.Select(second.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveSettings(profiles.Storage(second.ID), Settings{Volume: 0.1, Difficulty: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := LoadSettings(profiles.Storage(first.ID)); s != DefaultSettings() {
+		t.Errorf("first pilot's settings changed to %+v", s)
+	}
+
+	if err := profiles.Delete(second.ID); err != nil {
+		t.Fatal(err)
+	}
+	if profiles.Active().ID != first.ID {
+		t.Errorf("active pilot after deleting it is %q", profiles.Active().ID)
+	}
+	if names, _ := store.List("profiles/" + second.ID); len(names) != 0 {
+		t.Errorf("deleted pilot's files remain: %v", names)
+	}
+}
+
+func TestProfileExportImport(t *testing.T) {
+	store := NewMemoryStorage()
+	profiles, err := NewProfileManager(store, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := profiles.Active()
+	pm := NewProgressionManager(profiles.Storage(p.ID), "progression.json")
+	pm.AddScrap(900)
+	if err := pm.Save(); err != nil {
+		t.Fatal(err)
+	}
+	replay := NewReplay("test", 7, 1)
+	replay.Finish(1234, 5)
+	if _, err := replay.SaveToHistory(profiles.Storage(p.ID)); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := profiles.Export(p.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Importing into the same install renames the copy
+	imported, err := profiles.Import(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.ID == p.ID || imported.Name != DefaultProfileName+" 2" {
+		t.Errorf("imported profile = %+v", imported)
+	}
+	copyStore := profiles.Storage(imported.ID)
+	if got := NewProgressionManager(copyStore, "progression.json").GetTotalScrap(); got != 900 {
+		t.Errorf("imported scrap = %d, want 900", got)
+	}
+	if names, _ := ReplayHistory(copyStore); len(names) != 1 {
+		t.Errorf("imported replays = %v", names)
+	}
+}
+
+func TestProfileImportRejectsEscapingPaths(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	writeZipFile(zw, profileArchiveMeta, []byte(`{"format":1,"name":"Evil"}`))
+	writeZipFile(zw, "files/../../leaderboard.json", []byte(`[]`))
+	zw.Close()
+
+	store := NewMemoryStorage()
+	profiles, err := NewProfileManager(store, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := profiles.Import(buf.Bytes()); err == nil {
+		t.Fatal("archive writing outside its profile was imported")
+	}
+	if len(profiles.Profiles()) != 1 {
+		t.Errorf("rejected archive still created a profile: %+v", profiles.Profiles())
+	}
+}
+
+func TestProfileSyncKeys(t *testing.T) {
+	store := NewMemoryStorage()
+	profiles, err := NewProfileManager(store, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := profiles.Active()
+	second, err := profiles.Create("A-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.SyncKey == "" || first.SyncKey == second.SyncKey {
+		t.Fatalf("sync keys %q and %q", first.SyncKey, second.SyncKey)
+	}
+
+	// Renaming keeps the key, and so does moving to another install
+	if err := profiles.Rename(second.ID, "Goose"); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := profiles.Export(second.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewProfileManager(NewMemoryStorage(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moved, err := other.Import(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.SyncKey != second.SyncKey {
+		t.Errorf("imported sync key %q, want %q", moved.SyncKey, second.SyncKey)
+	}
+
+	// A copy on the same install syncs on its own
+	copied, err := profiles.Import(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied.SyncKey == second.SyncKey || profiles.SyncKeyShared(copied.ID) {
+		t.Errorf("copy shares sync key %q", copied.SyncKey)
+	}
+}
+
+func TestProfileSyncKeysMigrateFromNames(t *testing.T) {
+	// Before sync keys, saves were synced under the name's slug, and these
+	// two pilots shared one
+	store := NewMemoryStorage()
+	index := profileIndex{Active: "pilot1", NextID: 2, Profiles: []Profile{{ID: "pilot1", Name: "A-1"}, {ID: "pilot2", Name: "A 1"}}}
+	if err := WriteSaveFile(store, profilesFileName, SaveSchema{Kind: profilesSchema.Kind, Version: 1}, index); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := NewProfileManager(store, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range profiles.Profiles() {
+		if p.SyncKey != "a_1" {
+			t.Errorf("%s has sync key %q, want a_1", p.Name, p.SyncKey)
+		}
+	}
+	if !profiles.SyncKeyShared("pilot1") || !profiles.SyncKeyShared("pilot2") {
+		t.Fatal("shared sync key was not reported")
+	}
+
+	// Renaming one of them gives it a key of its own
+	if err := profiles.Rename("pilot2", "B 2"); err != nil {
+		t.Fatal(err)
+	}
+	if profiles.SyncKeyShared("pilot1") || profiles.SyncKeyShared("pilot2") {
+		t.Errorf("profiles still share a sync key: %+v", profiles.Profiles())
+	}
+}
@@ -1,7 +1,6 @@
 package systems
 
 import (
-	"encoding/json"
 	"os"
 	"time"
 )
@@ -14,6 +13,10 @@ type ProgressionData struct {
 	Upgrades          map[string]UpgradeLevel `json:"upgrades"`
 	UnlockedCosmetics map[string]bool         `json:"unlocked_cosmetics"`
 	LastUpdated       time.Time               `json:"last_updated"`
+
+	// Lifetime records; unlike the scrap balance these never go down
+	ScrapEarned int `json:"scrap_earned"`
+	MaxWave     int `json:"max_wave"`
 }
 
 // UpgradeLevel represents the level of a specific upgrade
@@ -24,24 +27,41 @@ type UpgradeLevel struct {
 	CurrentCost  int `json:"current_cost"`
 }
 
+// nextCost returns the cost of the upgrade's next level, which scales with
+// its current level
+func (u UpgradeLevel) nextCost() int {
+	return u.CostPerLevel + u.Level*(u.CostPerLevel/2)
+}
+
 // ProgressionManager manages persistent progression
 type ProgressionManager struct {
 	data      *ProgressionData
-	dataPath  string
+	store     Storage
+	fileName  string
 	scrapGain int // Scrap gained this session
 }
 
-// NewProgressionManager creates a new progression manager
-func NewProgressionManager(dataPath string) *ProgressionManager {
+// progressionSchema is the save format of player progression. Version 1
+// wrapped the progression data in a versioned envelope.
+var progressionSchema = SaveSchema{
+	Kind:       "progression",
+	Version:    1,
+	Migrations: map[int]SaveMigration{0: unversioned},
+}
+
+// NewProgressionManager creates a progression manager kept in the named file of store
+func NewProgressionManager(store Storage, fileName string) *ProgressionManager {
 	pm := &ProgressionManager{
-		dataPath: dataPath,
+		store:    store,
+		fileName: fileName,
 		data: &ProgressionData{
 			TotalScrap:        0,
 			Prestige:          0,
 			PrestigePoints:    0,
 			Upgrades:          make(map[string]UpgradeLevel),
 			UnlockedCosmetics: make(map[string]bool),
-			LastUpdated:       time.Now(),
+			// LastUpdated stays zero until the first save, so a fresh
+			// profile never wins a sync against saved progress
 		},
 	}
 
@@ -119,8 +139,7 @@ func (pm *ProgressionManager) BuyUpgrade(upgradeID string) bool {
 	pm.data.TotalScrap -= upgrade.CurrentCost
 	upgrade.Level++
 
-	// Calculate next cost (scales with level)
-	upgrade.CurrentCost = upgrade.CostPerLevel + (upgrade.Level * (upgrade.CostPerLevel / 2))
+	upgrade.CurrentCost = upgrade.nextCost()
 
 	pm.data.Upgrades[upgradeID] = upgrade
 	pm.data.LastUpdated = time.Now()
@@ -136,9 +155,23 @@ func (pm *ProgressionManager) AddScrap(amount int) {
 	finalAmount := int(float64(amount) * prestigeMultiplier)
 
 	pm.data.TotalScrap += finalAmount
+	pm.data.ScrapEarned += finalAmount
 	pm.scrapGain += finalAmount
 }
 
+// RecordWave notes the wave a run reached, keeping the best
+func (pm *ProgressionManager) RecordWave(wave int) {
+	if wave > pm.data.MaxWave {
+		pm.data.MaxWave = wave
+		pm.Save()
+	}
+}
+
+// GetMaxWave returns the best wave reached
+func (pm *ProgressionManager) GetMaxWave() int {
+	return pm.data.MaxWave
+}
+
 // GetUpgradeBonus returns the bonus value from an upgrade
 func (pm *ProgressionManager) GetUpgradeBonus(upgradeID string) float64 {
 	upgrade, exists := pm.data.Upgrades[upgradeID]
@@ -204,7 +237,7 @@ func (pm *ProgressionManager) Prestige() bool {
 	// Reset upgrades
 	for key, upgrade := range pm.data.Upgrades {
 		upgrade.Level = 0
-		upgrade.CurrentCost = upgrade.CostPerLevel
+		upgrade.CurrentCost = upgrade.nextCost()
 		pm.data.Upgrades[key] = upgrade
 	}
 
@@ -256,18 +289,12 @@ func (pm *ProgressionManager) GetAllUpgrades() map[string]UpgradeLevel {
 // Save saves progression to JSON file
 func (pm *ProgressionManager) Save() error {
 	pm.data.LastUpdated = time.Now()
-	data, err := json.MarshalIndent(pm.data, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(pm.dataPath, data, 0644)
+	return WriteSaveFile(pm.store, pm.fileName, progressionSchema, pm.data)
 }
 
 // Load loads progression from JSON file
 func (pm *ProgressionManager) Load() error {
-	data, err := os.ReadFile(pm.dataPath)
-	if err != nil {
+	if err := ReadSaveFile(pm.store, pm.fileName, progressionSchema, pm.data); err != nil {
 		// File doesn't exist yet, that's okay
 		if os.IsNotExist(err) {
 			return nil
@@ -275,10 +302,6 @@ func (pm *ProgressionManager) Load() error {
 		return err
 	}
 
-	if err := json.Unmarshal(data, pm.data); err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -0,0 +1,120 @@
+package systems
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// MetricsPrefix is prepended to the name of every exported metric
+const MetricsPrefix = "stellar_siege_"
+
+// PrometheusWriter writes metrics in the Prometheus text exposition format
+type PrometheusWriter struct {
+	w *bufio.Writer
+}
+
+// NewPrometheusWriter creates a writer emitting to w; call Flush when done
+func NewPrometheusWriter(w io.Writer) *PrometheusWriter {
+	return &PrometheusWriter{w: bufio.NewWriter(w)}
+}
+
+// Header writes the HELP and TYPE lines of a metric family
+func (p *PrometheusWriter) Header(name, metricType, help string) {
+	fmt.Fprintf(p.w, "# HELP %s%s %s\n# TYPE %s%s %s\n", MetricsPrefix, name, help, MetricsPrefix, name, metricType)
+}
+
+// Sample writes one sample; labels alternate names and values
+func (p *PrometheusWriter) Sample(name string, value float64, labels ...string) {
+	p.w.WriteString(MetricsPrefix + name)
+	if len(labels) > 0 {
+		p.w.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				p.w.WriteByte(',')
+			}
+			fmt.Fprintf(p.w, "%s=%q", labels[i], labels[i+1])
+		}
+		p.w.WriteByte('}')
+	}
+	p.w.WriteByte(' ')
+	p.w.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	p.w.WriteByte('\n')
+}
+
+// Gauge writes a single unlabelled gauge
+func (p *PrometheusWriter) Gauge(name, help string, value float64) {
+	p.Header(name, "gauge", help)
+	p.Sample(name, value)
+}
+
+// Flush writes any buffered output
+func (p *PrometheusWriter) Flush() error {
+	return p.w.Flush()
+}
+
+// WritePrometheus writes the monitor's metrics: the frame time histogram,
+// frame, collision, render and subsystem timings, memory and GC statistics, entity
+// counts per type and object pool statistics
+func (pm *PerformanceMonitor) WritePrometheus(p *PrometheusWriter) {
+	hist := pm.GetFrameTimeHistogram()
+	p.Header("frame_time_seconds", "histogram", "Time taken by each game update.")
+	var cumulative uint64
+	for i, bound := range hist.Buckets {
+		cumulative += hist.Counts[i]
+		p.Sample("frame_time_seconds_bucket", float64(cumulative), "le", strconv.FormatFloat(bound.Seconds(), 'g', -1, 64))
+	}
+	p.Sample("frame_time_seconds_bucket", float64(hist.Count), "le", "+Inf")
+	p.Sample("frame_time_seconds_sum", hist.Sum.Seconds())
+	p.Sample("frame_time_seconds_count", float64(hist.Count))
+
+	p.Gauge("fps", "Frames per second over the last second.", pm.GetFPS())
+	p.Gauge("collision_time_seconds", "Time spent in collision detection in the latest update.", pm.GetCollisionTime().Seconds())
+	p.Gauge("render_time_seconds", "Time spent drawing the latest frame.", pm.GetRenderTime().Seconds())
+
+	times := pm.GetSubsystemTimes()
+	p.Header("subsystem_time_seconds", "gauge", "Time spent in each simulation subsystem in the latest tick.")
+	for _, name := range sortedNames(times) {
+		p.Sample("subsystem_time_seconds", times[name].Seconds(), "subsystem", name)
+	}
+
+	alloc, totalAlloc, sys, allocRate := pm.GetMemoryStats()
+	p.Gauge("heap_alloc_bytes", "Bytes of allocated heap objects.", float64(alloc))
+	p.Gauge("memory_sys_bytes", "Bytes of memory obtained from the OS.", float64(sys))
+	p.Header("alloc_bytes_total", "counter", "Cumulative bytes allocated for heap objects.")
+	p.Sample("alloc_bytes_total", float64(totalAlloc))
+	p.Gauge("alloc_rate_bytes_per_second", "Heap allocation rate over the last second.", allocRate)
+
+	numGC, _, lastPause, maxPause := pm.GetGCStats()
+	p.Header("gc_total", "counter", "Completed garbage collection cycles.")
+	p.Sample("gc_total", float64(numGC))
+	p.Gauge("gc_last_pause_seconds", "Stop-the-world pause of the latest garbage collection.", lastPause.Seconds())
+	p.Gauge("gc_max_pause_seconds", "Longest of the recent garbage collection pauses.", maxPause.Seconds())
+
+	counts := pm.GetEntityCounts()
+	p.Header("entities", "gauge", "Active entities by type.")
+	for _, name := range sortedNames(counts) {
+		p.Sample("entities", float64(counts[name]), "type", name)
+	}
+
+	pools := pm.GetPoolStats()
+	names := sortedNames(pools)
+	poolGauges := []struct {
+		name, metricType, help string
+		value                  func(PoolStatsSnapshot) float64
+	}{
+		{"pool_active", "gauge", "Entities currently handed out by the pool.", func(s PoolStatsSnapshot) float64 { return float64(s.CurrentActive) }},
+		{"pool_max_active", "gauge", "Most entities handed out by the pool at once.", func(s PoolStatsSnapshot) float64 { return float64(s.MaxActive) }},
+		{"pool_size", "gauge", "Entities held by the pool.", func(s PoolStatsSnapshot) float64 { return float64(s.PoolSize) }},
+		{"pool_created_total", "counter", "Entities the pool has allocated.", func(s PoolStatsSnapshot) float64 { return float64(s.TotalCreated) }},
+		{"pool_reused_total", "counter", "Entities the pool has handed out again.", func(s PoolStatsSnapshot) float64 { return float64(s.TotalReused) }},
+		{"pool_reuse_ratio", "gauge", "Share of pool requests served by reuse.", func(s PoolStatsSnapshot) float64 { return s.ReuseRate / 100 }},
+	}
+	for _, g := range poolGauges {
+		p.Header(g.name, g.metricType, g.help)
+		for _, name := range names {
+			p.Sample(g.name, g.value(pools[name]), "pool", name)
+		}
+	}
+}
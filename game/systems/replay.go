@@ -0,0 +1,205 @@
+package systems
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"stellar-siege/game/entities"
+)
+
+// ReplayFormatVersion is bumped whenever the replay layout changes in a way
+// that older readers cannot understand
+const ReplayFormatVersion = 1
+
+// InputRun is a run-length encoded span of identical input frames
+type InputRun struct {
+	Frame entities.InputFrame `json:"f"`
+	Count int                 `json:"n"`
+}
+
+// Replay records everything needed to re-simulate a run: the RNG seed, the
+// difficulty and the player's input for every simulation tick
+type Replay struct {
+	FormatVersion int        `json:"format_version"`
+	GameVersion   string     `json:"game_version"`
+	Seed          int64      `json:"seed"`
+	Difficulty    int        `json:"difficulty"`
+	Pilots        int        `json:"pilots,omitempty"` // Ships in a co-op run; 0 for solo runs
+	Ticks         int        `json:"ticks"`
+	Score         int64      `json:"score"`
+	Wave          int        `json:"wave"`
+	Finished      bool       `json:"finished"`
+	RecordedAt    time.Time  `json:"recorded_at"`
+	Inputs        []InputRun `json:"inputs"`
+}
+
+// NewReplay creates an empty replay for a run started with the given seed
+func NewReplay(gameVersion string, seed int64, difficulty int) *Replay {
+	return &Replay{
+		FormatVersion: ReplayFormatVersion,
+		GameVersion:   gameVersion,
+		Seed:          seed,
+		Difficulty:    difficulty,
+		RecordedAt:    time.Now(),
+		Inputs:        make([]InputRun, 0, 256),
+	}
+}
+
+// Record appends the input for one simulation tick
+func (r *Replay) Record(frame entities.InputFrame) {
+	r.Ticks++
+	if n := len(r.Inputs); n > 0 && r.Inputs[n-1].Frame == frame {
+		r.Inputs[n-1].Count++
+		return
+	}
+	r.Inputs = append(r.Inputs, InputRun{Frame: frame, Count: 1})
+}
+
+// Finish stores the final result of the run
+func (r *Replay) Finish(score int64, wave int) {
+	r.Score = score
+	r.Wave = wave
+	r.Finished = true
+}
+
+// Frames expands the run-length encoded inputs into one frame per tick
+func (r *Replay) Frames() []entities.InputFrame {
+	frames := make([]entities.InputFrame, 0, r.Ticks)
+	for _, run := range r.Inputs {
+		for i := 0; i < run.Count; i++ {
+			frames = append(frames, run.Frame)
+		}
+	}
+	return frames
+}
+
+// Validate checks that the replay is internally consistent
+func (r *Replay) Validate() error {
+	if r.FormatVersion != ReplayFormatVersion {
+		return fmt.Errorf("unsupported replay format version %d", r.FormatVersion)
+	}
+	total := 0
+	for _, run := range r.Inputs {
+		if run.Count <= 0 {
+			return fmt.Errorf("invalid input run length %d", run.Count)
+		}
+		total += run.Count
+	}
+	if total != r.Ticks {
+		return fmt.Errorf("replay has %d input frames but claims %d ticks", total, r.Ticks)
+	}
+	if r.Pilots < 0 || r.Pilots > entities.MaxPilots {
+		return fmt.Errorf("invalid pilot count %d", r.Pilots)
+	}
+	return nil
+}
+
+// ID returns a stable identifier derived from the replay contents
+func (r *Replay) ID() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d|%s|%d|%d|%d|%d|%d|", r.FormatVersion, r.GameVersion, r.Seed, r.Difficulty, r.Ticks, r.Score, r.Wave)
+	for _, run := range r.Inputs {
+		fmt.Fprintf(h, "%d:%d,", run.Frame, run.Count)
+	}
+	if r.Pilots > 1 {
+		fmt.Fprintf(h, "pilots=%d", r.Pilots) // Solo replays keep their IDs
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Encode serializes the replay to JSON
+func (r *Replay) Encode() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// DecodeReplay parses a replay previously produced by Encode
+func DecodeReplay(data []byte) (*Replay, error) {
+	var r Replay
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to decode replay: %w", err)
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Save writes the replay to a file, creating parent directories as needed
+func (r *Replay) Save(path string) error {
+	data, err := r.Encode()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadReplay reads a replay from a file
+func LoadReplay(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeReplay(data)
+}
+
+// SaveTo writes the replay to the named file of store
+func (r *Replay) SaveTo(store Storage, name string) error {
+	data, err := r.Encode()
+	if err != nil {
+		return err
+	}
+	return store.Write(name, data)
+}
+
+// LoadReplayFrom reads a replay from the named file of store
+func LoadReplayFrom(store Storage, name string) (*Replay, error) {
+	data, err := store.Read(name)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeReplay(data)
+}
+
+// Replay history of a profile
+const (
+	ReplayHistoryDir   = "replays"
+	ReplayHistoryLimit = 20 // Oldest replays are removed beyond this many
+)
+
+// SaveToHistory adds the replay to the history kept in store, removing the
+// oldest replays beyond ReplayHistoryLimit, and returns its name
+func (r *Replay) SaveToHistory(store Storage) (string, error) {
+	name := path.Join(ReplayHistoryDir, fmt.Sprintf("%s_%d.json", time.Now().Format("20060102_150405"), r.Score))
+	if err := r.SaveTo(store, name); err != nil {
+		return "", fmt.Errorf("failed to save replay: %w", err)
+	}
+	names, err := ReplayHistory(store)
+	if err != nil {
+		return name, nil
+	}
+	for len(names) > ReplayHistoryLimit {
+		store.Remove(names[len(names)-1])
+		names = names[:len(names)-1]
+	}
+	return name, nil
+}
+
+// ReplayHistory returns the names of the replays kept in store, newest first
+func ReplayHistory(store Storage) ([]string, error) {
+	names, err := store.List(ReplayHistoryDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replays: %w", err)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	return names, nil
+}
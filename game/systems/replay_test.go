@@ -0,0 +1,106 @@
+package systems
+
+import (
+	"crypto/ed25519"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"stellar-siege/game/entities"
+)
+
+func TestReplayRecordRunLengthEncodes(t *testing.T) {
+	r := NewReplay("1.0.0", 99, 1)
+	frames := []entities.InputFrame{0, 0, entities.InputShoot, entities.InputShoot, entities.InputShoot, entities.InputLeft}
+	for _, f := range frames {
+		r.Record(f)
+	}
+
+	if len(r.Inputs) != 3 {
+		t.Fatalf("expected 3 input runs, got %d", len(r.Inputs))
+	}
+	if r.Ticks != len(frames) {
+		t.Fatalf("Ticks = %d, want %d", r.Ticks, len(frames))
+	}
+	got := r.Frames()
+	for i := range frames {
+		if got[i] != frames[i] {
+			t.Fatalf("frame %d = %v, want %v", i, got[i], frames[i])
+		}
+	}
+}
+
+func TestReplaySaveLoadRoundTrip(t *testing.T) {
+	r := NewReplay("1.0.0", 12345, 2)
+	for i := 0; i < 100; i++ {
+		r.Record(entities.InputFrame(i / 10))
+	}
+	r.Finish(4200, 3)
+
+	path := filepath.Join(t.TempDir(), "replays", "run.json")
+	if err := r.Save(path); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	loaded, err := LoadReplay(path)
+	if err != nil {
+		t.Fatalf("LoadReplay() error: %v", err)
+	}
+	if loaded.ID() != r.ID() {
+		t.Errorf("loaded replay ID %s, want %s", loaded.ID(), r.ID())
+	}
+}
+
+func TestDecodeReplayRejectsInconsistentTicks(t *testing.T) {
+	r := NewReplay("1.0.0", 1, 1)
+	r.Record(0)
+	r.Ticks = 10
+
+	data, _ := r.Encode()
+	if _, err := DecodeReplay(data); err == nil {
+		t.Error("DecodeReplay() accepted a replay whose tick count does not match its inputs")
+	}
+}
+
+func TestReplayPilots(t *testing.T) {
+	r := NewReplay("1.0.0", 1, 1)
+	r.Record(entities.CombineInputs(entities.InputShoot, entities.InputLeft))
+	solo := r.ID()
+
+	r.Pilots = 1
+	if r.ID() != solo {
+		t.Error("a pilot count of 1 changed the ID of a solo replay")
+	}
+	r.Pilots = 2
+	if r.ID() == solo {
+		t.Error("co-op replay has the ID of the same inputs played solo")
+	}
+	data, _ := r.Encode()
+	decoded, err := DecodeReplay(data)
+	if err != nil || decoded.Pilots != 2 || decoded.Frames()[0].Pilot(1) != entities.InputLeft {
+		t.Fatalf("decoded co-op replay = %+v, %v", decoded, err)
+	}
+
+	r.Pilots = entities.MaxPilots + 1
+	if err := r.Validate(); err == nil {
+		t.Error("replay with more pilots than an input frame holds was accepted")
+	}
+}
+
+func TestVerificationRecordSignature(t *testing.T) {
+	key, err := LoadVerifierKey(filepath.Join(t.TempDir(), "verifier.key"))
+	if err != nil {
+		t.Fatalf("LoadVerifierKey() error: %v", err)
+	}
+	pub := key.Public().(ed25519.PublicKey)
+
+	record := &VerificationRecord{ReplayID: "abc", Verified: true, Score: 1000, Wave: 4, Ticks: 600, Verifier: "1.0.0", VerifiedAt: time.Now()}
+	record.Sign(key)
+	if !record.CheckSignature(pub) {
+		t.Fatal("signature did not verify")
+	}
+
+	record.Score = 99999
+	if record.CheckSignature(pub) {
+		t.Error("signature verified after the score was changed")
+	}
+}
@@ -0,0 +1,77 @@
+package systems
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// VerificationRecord is the verifier's signed statement about a submitted replay.
+// It is stored next to the leaderboard entry it vouches for.
+type VerificationRecord struct {
+	ReplayID   string    `json:"replay_id"`
+	Verified   bool      `json:"verified"`
+	Reason     string    `json:"reason,omitempty"`
+	Score      int64     `json:"score"`
+	Wave       int       `json:"wave"`
+	Ticks      int       `json:"ticks"`
+	Verifier   string    `json:"verifier"` // Game version that re-simulated the replay
+	VerifiedAt time.Time `json:"verified_at"`
+	PublicKey  string    `json:"public_key"`
+	Signature  string    `json:"signature"`
+}
+
+// signedPayload returns the canonical bytes covered by the signature
+func (v *VerificationRecord) signedPayload() []byte {
+	return []byte(fmt.Sprintf("%s|%t|%s|%d|%d|%d|%s|%d",
+		v.ReplayID, v.Verified, v.Reason, v.Score, v.Wave, v.Ticks, v.Verifier, v.VerifiedAt.Unix()))
+}
+
+// Sign signs the record with the verifier's private key
+func (v *VerificationRecord) Sign(key ed25519.PrivateKey) {
+	v.PublicKey = hex.EncodeToString(key.Public().(ed25519.PublicKey))
+	v.Signature = hex.EncodeToString(ed25519.Sign(key, v.signedPayload()))
+}
+
+// CheckSignature returns true if the record was signed by the given public key
+// and has not been modified since
+func (v *VerificationRecord) CheckSignature(pub ed25519.PublicKey) bool {
+	if v.PublicKey != hex.EncodeToString(pub) {
+		return false
+	}
+	sig, err := hex.DecodeString(v.Signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(pub, v.signedPayload(), sig)
+}
+
+// LoadVerifierKey reads a hex-encoded ed25519 seed from a file, generating and
+// saving a new key if the file does not exist yet
+func LoadVerifierKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate verifier key: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(hex.EncodeToString(key.Seed())+"\n"), 0600); err != nil {
+			return nil, fmt.Errorf("failed to save verifier key: %w", err)
+		}
+		return key, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seed, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid verifier key in %s", path)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
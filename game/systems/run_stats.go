@@ -0,0 +1,155 @@
+package systems
+
+// Damage and kill sources that are not an enemy type or a weapon
+const (
+	SourceBoss     = "Boss"
+	SourceAsteroid = "Asteroid"
+	SourceHazard   = "Hazard"
+	SourceUnknown  = "Unknown"
+)
+
+// WaveStats records how a single wave of a run played out
+type WaveStats struct {
+	Wave        int     `json:"wave"`
+	Start       float64 `json:"start"`    // Game time in seconds
+	Duration    float64 `json:"duration"` // Seconds until the next wave started or the run ended
+	DamageTaken int     `json:"damage_taken"`
+	Kills       int     `json:"kills"`
+	Completed   bool    `json:"completed"`
+}
+
+// BossFight records a single boss encounter
+type BossFight struct {
+	Wave     int     `json:"wave"`
+	Level    int     `json:"level"`
+	Start    float64 `json:"start"`
+	Duration float64 `json:"duration"`
+	Defeated bool    `json:"defeated"`
+}
+
+// RunStats collects gameplay statistics over a single run. It is fed by the
+// simulation and only observes it, so collecting stats never changes the
+// outcome of a run.
+type RunStats struct {
+	Duration       float64        `json:"duration"` // Seconds of game time
+	Waves          []WaveStats    `json:"waves"`
+	DamageBySource map[string]int `json:"damage_by_source"`
+	KillsByWeapon  map[string]int `json:"kills_by_weapon"`
+	BossFights     []BossFight    `json:"boss_fights"`
+	DeathCause     string         `json:"death_cause,omitempty"`
+	BossFightOpen  bool           `json:"boss_fight_open,omitempty"` // The last boss fight is still in progress
+	Pilots         []PilotStats   `json:"pilots,omitempty"`          // Each pilot's share of a co-op run
+}
+
+// PilotStats records one pilot's part in a co-op run. The score is shared;
+// Score holds the points earned by the pilot's own kills.
+type PilotStats struct {
+	Score       int64 `json:"score"`
+	Kills       int   `json:"kills"`
+	DamageTaken int   `json:"damage_taken"`
+	Downs       int   `json:"downs"`   // Times the pilot's ship was destroyed
+	Revives     int   `json:"revives"` // Downed partners the pilot brought back
+}
+
+// NewRunStats creates an empty stats collector
+func NewRunStats() *RunStats {
+	return &RunStats{
+		Waves:          make([]WaveStats, 0, 16),
+		DamageBySource: make(map[string]int),
+		KillsByWeapon:  make(map[string]int),
+	}
+}
+
+// StartWave closes the current wave and opens the next one
+func (s *RunStats) StartWave(wave int, gameTime float64) {
+	if cur := s.currentWave(); cur != nil {
+		cur.Duration = gameTime - cur.Start
+		cur.Completed = true
+	}
+	s.Waves = append(s.Waves, WaveStats{Wave: wave, Start: gameTime})
+}
+
+// RecordDamage records damage the player took from the given source
+func (s *RunStats) RecordDamage(source string, amount int) {
+	if amount <= 0 {
+		return
+	}
+	if source == "" {
+		source = SourceUnknown
+	}
+	s.DamageBySource[source] += amount
+	if cur := s.currentWave(); cur != nil {
+		cur.DamageTaken += amount
+	}
+}
+
+// RecordKill records an enemy destroyed by the given weapon
+func (s *RunStats) RecordKill(weapon string) {
+	if weapon == "" {
+		weapon = SourceUnknown
+	}
+	s.KillsByWeapon[weapon]++
+	if cur := s.currentWave(); cur != nil {
+		cur.Kills++
+	}
+}
+
+// Pilot returns the stats of pilot i, adding pilots up to i as needed
+func (s *RunStats) Pilot(i int) *PilotStats {
+	for len(s.Pilots) <= i {
+		s.Pilots = append(s.Pilots, PilotStats{})
+	}
+	return &s.Pilots[i]
+}
+
+// StartBossFight records that a boss has appeared
+func (s *RunStats) StartBossFight(wave, level int, gameTime float64) {
+	s.BossFights = append(s.BossFights, BossFight{Wave: wave, Level: level, Start: gameTime})
+	s.BossFightOpen = true
+}
+
+// EndBossFight closes the current boss fight
+func (s *RunStats) EndBossFight(gameTime float64, defeated bool) {
+	if !s.BossFightOpen {
+		return
+	}
+	s.BossFightOpen = false
+	fight := &s.BossFights[len(s.BossFights)-1]
+	fight.Duration = gameTime - fight.Start
+	fight.Defeated = defeated
+}
+
+// Finish closes any open wave or boss fight when the run ends
+func (s *RunStats) Finish(gameTime float64, deathCause string) {
+	s.Duration = gameTime
+	s.DeathCause = deathCause
+	if cur := s.currentWave(); cur != nil && !cur.Completed {
+		cur.Duration = gameTime - cur.Start
+	}
+	s.EndBossFight(gameTime, false)
+}
+
+// TotalDamage returns the total damage taken over the run
+func (s *RunStats) TotalDamage() int {
+	total := 0
+	for _, amount := range s.DamageBySource {
+		total += amount
+	}
+	return total
+}
+
+// TotalKills returns the number of enemies destroyed over the run
+func (s *RunStats) TotalKills() int {
+	total := 0
+	for _, kills := range s.KillsByWeapon {
+		total += kills
+	}
+	return total
+}
+
+func (s *RunStats) currentWave() *WaveStats {
+	if len(s.Waves) == 0 {
+		return nil
+	}
+	return &s.Waves[len(s.Waves)-1]
+}
@@ -0,0 +1,151 @@
+package systems
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+)
+
+// SaveBackupCount is how many previous versions of a save file are kept,
+// as <name>.1 (newest) through <name>.N (oldest)
+const SaveBackupCount = 3
+
+// SaveMigration upgrades a save payload by one schema version
+type SaveMigration func(data json.RawMessage) (json.RawMessage, error)
+
+// SaveSchema describes a versioned save file format. Migrations[v] upgrades
+// a payload from version v to v+1; version 0 is the bare JSON written before
+// save files carried a version.
+type SaveSchema struct {
+	Kind       string
+	Version    int
+	Migrations map[int]SaveMigration
+}
+
+// saveEnvelope is the on-disk layout of a versioned save file
+type saveEnvelope struct {
+	Kind    string          `json:"kind"`
+	Version int             `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// unversioned is the migration from version 0 for formats whose payload did
+// not change when the version envelope was introduced
+func unversioned(data json.RawMessage) (json.RawMessage, error) {
+	return data, nil
+}
+
+// saveBackupName returns the name of the n-th backup of a save file
+func saveBackupName(name string, n int) string {
+	return fmt.Sprintf("%s.%d", name, n)
+}
+
+// WriteSaveFile saves v under the schema's current version. The previous
+// valid contents are kept as rolling backups, and the storage replaces the
+// file atomically, so a crash mid-write never leaves a torn file.
+func WriteSaveFile(store Storage, name string, schema SaveSchema, v any) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", schema.Kind, err)
+	}
+	data, err := json.MarshalIndent(saveEnvelope{Kind: schema.Kind, Version: schema.Version, Data: payload}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", schema.Kind, err)
+	}
+
+	rotateSaveBackups(store, name, schema)
+	if err := store.Write(name, data); err != nil {
+		return fmt.Errorf("failed to save %s: %w", schema.Kind, err)
+	}
+	return nil
+}
+
+// rotateSaveBackups shifts the backups down by one and moves the current
+// file into the first slot. A current file that does not parse is left in
+// place to be overwritten, so it never pushes a good backup out.
+func rotateSaveBackups(store Storage, name string, schema SaveSchema) {
+	if _, err := decodeSaveFile(store, name, schema); err != nil {
+		return
+	}
+	for n := SaveBackupCount - 1; n >= 1; n-- {
+		store.Rename(saveBackupName(name, n), saveBackupName(name, n+1))
+	}
+	store.Rename(name, saveBackupName(name, 1))
+}
+
+// ReadSaveFile loads a save file into v, migrating it to the schema's current
+// version. If the file is missing or does not parse, the newest valid backup
+// is used instead and a corrupt file is kept as <name>.corrupt. The error
+// matches fs.ErrNotExist when there is neither a file nor a backup.
+func ReadSaveFile(store Storage, name string, schema SaveSchema, v any) error {
+	err := loadSaveFile(store, name, schema, v)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("Save file %s is unreadable: %v", name, err)
+		store.Rename(name, name+".corrupt")
+	}
+
+	for n := 1; n <= SaveBackupCount; n++ {
+		backup := saveBackupName(name, n)
+		if loadSaveFile(store, backup, schema, v) == nil {
+			log.Printf("Recovered %s from backup %s", schema.Kind, backup)
+			return nil
+		}
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return fmt.Errorf("failed to load %s and no valid backup was found: %w", schema.Kind, err)
+}
+
+// decodeSaveFile reads a save file and returns its payload migrated to the
+// schema's current version
+func decodeSaveFile(store Storage, name string, schema SaveSchema) (json.RawMessage, error) {
+	data, err := store.Read(name)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("%s is not valid JSON", name)
+	}
+
+	// Files without an envelope predate versioning
+	var env saveEnvelope
+	if err := json.Unmarshal(data, &env); err != nil || env.Kind == "" {
+		env = saveEnvelope{Kind: schema.Kind, Version: 0, Data: data}
+	}
+	if env.Kind != schema.Kind {
+		return nil, fmt.Errorf("%s holds %s data, not %s", name, env.Kind, schema.Kind)
+	}
+	if env.Version > schema.Version {
+		return nil, fmt.Errorf("%s version %d is newer than the supported version %d", schema.Kind, env.Version, schema.Version)
+	}
+
+	payload := env.Data
+	for version := env.Version; version < schema.Version; version++ {
+		migrate, ok := schema.Migrations[version]
+		if !ok {
+			return nil, fmt.Errorf("no migration for %s version %d", schema.Kind, version)
+		}
+		if payload, err = migrate(payload); err != nil {
+			return nil, fmt.Errorf("failed to migrate %s from version %d: %w", schema.Kind, version, err)
+		}
+	}
+	return payload, nil
+}
+
+// loadSaveFile reads, migrates and decodes one save file without recovery
+func loadSaveFile(store Storage, name string, schema SaveSchema, v any) error {
+	payload, err := decodeSaveFile(store, name, schema)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", schema.Kind, err)
+	}
+	return nil
+}
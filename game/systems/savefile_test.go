@@ -0,0 +1,97 @@
+package systems
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestSaveFileMigratesUnversionedLeaderboard(t *testing.T) {
+	store, name := NewMemoryStorage(), "leaderboard.json"
+	legacy := `[{"rank":1,"name":"ACE","score":5000,"wave":7,"country":"NZ"}]`
+	if err := store.Write(name, []byte(legacy)); err != nil {
+		t.Fatal(err)
+	}
+
+	lb := NewLeaderboard(store, name)
+	if len(lb.Entries) != 1 || lb.Entries[0].Name != "ACE" || lb.Entries[0].Score != 5000 {
+		t.Fatalf("legacy entries = %+v", lb.Entries)
+	}
+	if err := lb.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := store.Read(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var env saveEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		t.Fatal(err)
+	}
+	if env.Kind != "leaderboard" || env.Version != leaderboardSchema.Version {
+		t.Fatalf("saved envelope is %s v%d", env.Kind, env.Version)
+	}
+	if reloaded := NewLeaderboard(store, name); len(reloaded.Entries) != 1 {
+		t.Fatalf("reloaded %d entries, want 1", len(reloaded.Entries))
+	}
+}
+
+func TestSaveFileRecoversFromNewestValidBackup(t *testing.T) {
+	store, name := NewMemoryStorage(), "progression.json"
+	pm := NewProgressionManager(store, name)
+	for scrap := 1; scrap <= SaveBackupCount+2; scrap++ {
+		pm.data.TotalScrap = scrap * 100
+		if err := pm.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for n := 1; n <= SaveBackupCount; n++ {
+		if _, err := store.Read(saveBackupName(name, n)); err != nil {
+			t.Errorf("backup %d missing: %v", n, err)
+		}
+	}
+	if _, err := store.Read(saveBackupName(name, SaveBackupCount+1)); !os.IsNotExist(err) {
+		t.Errorf("more than %d backups kept", SaveBackupCount)
+	}
+
+	// Truncated write of the main file and a corrupt newest backup
+	if err := store.Write(name, []byte(`{"kind":"progression","version":1,"data":{"total_sc`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Write(saveBackupName(name, 1), []byte("garbage")); err != nil {
+		t.Fatal(err)
+	}
+
+	recovered := NewProgressionManager(store, name)
+	if want := SaveBackupCount * 100; recovered.data.TotalScrap != want {
+		t.Errorf("recovered %d scrap, want %d from the second backup", recovered.data.TotalScrap, want)
+	}
+	if _, err := store.Read(name + ".corrupt"); err != nil {
+		t.Errorf("corrupt file not kept aside: %v", err)
+	}
+}
+
+func TestChallengeSaveMigrationDropsConfig(t *testing.T) {
+	store, name := NewMemoryStorage(), "challenges.json"
+	legacy := `{"config":{"0":{"name":"Endless"}},"leaderboards":{"1":[{"player_name":"ACE","score":900}]}}`
+	if err := store.Write(name, []byte(legacy)); err != nil {
+		t.Fatal(err)
+	}
+
+	cm := NewChallengeManager(store, name)
+	scores := cm.Leaderboards[ChallengeModeBossRush]
+	if len(scores) != 1 || scores[0].PlayerName != "ACE" || scores[0].Score != 900 {
+		t.Fatalf("boss rush scores = %+v", scores)
+	}
+}
+
+func TestSaveFileRejectsNewerVersion(t *testing.T) {
+	store, name := NewMemoryStorage(), "leaderboard.json"
+	if err := store.Write(name, []byte(`{"kind":"leaderboard","version":99,"data":[]}`)); err != nil {
+		t.Fatal(err)
+	}
+	var entries []LeaderboardEntry
+	if err := ReadSaveFile(store, name, leaderboardSchema, &entries); err == nil || os.IsNotExist(err) {
+		t.Fatalf("newer save file loaded without error: %v", err)
+	}
+}
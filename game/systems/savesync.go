@@ -0,0 +1,459 @@
+package systems
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SyncFormatVersion is bumped whenever the synced document changes in a way
+// that older clients cannot merge
+const SyncFormatVersion = 1
+
+// syncBaseFileName is the profile file holding the data as of the last sync
+const syncBaseFileName = "sync_base.json"
+
+// syncBaseSchema is the save format of the last synced data
+var syncBaseSchema = SaveSchema{
+	Kind:    "sync_base",
+	Version: 1,
+}
+
+// DefaultGistAPIURL is the GitHub API the sync client talks to
+const DefaultGistAPIURL = "https://api.github.com"
+
+// SyncField is a last-writer-wins value with the time it was last changed
+type SyncField struct {
+	Value    json.RawMessage `json:"value"`
+	Modified time.Time       `json:"modified"`
+	Device   string          `json:"device"`
+}
+
+// SyncSnapshot is a pilot's synced data. Fields are merged last writer wins;
+// the rest only ever grows and is merged by union or maximum.
+type SyncSnapshot struct {
+	Format      int                  `json:"format"`
+	Fields      map[string]SyncField `json:"fields"`
+	Unlocked    map[string]time.Time `json:"unlocked"` // Achievement unlock times
+	Progress    map[string]int       `json:"progress"` // Achievement progress
+	Cosmetics   map[string]bool      `json:"cosmetics"`
+	MaxWave     int                  `json:"max_wave"`
+	ScrapEarned int                  `json:"scrap_earned"`
+}
+
+// SyncConflict is a field changed both locally and remotely since the last
+// sync; the newer change was kept
+type SyncConflict struct {
+	Field     string
+	Local     string
+	Remote    string
+	RemoteWon bool
+}
+
+// String describes the conflict for the UI
+func (c SyncConflict) String() string {
+	if c.RemoteWon {
+		return fmt.Sprintf("%s: kept %s from another device over %s", c.Field, c.Remote, c.Local)
+	}
+	return fmt.Sprintf("%s: kept %s from this device over %s", c.Field, c.Local, c.Remote)
+}
+
+// newSyncSnapshot creates an empty snapshot
+func newSyncSnapshot() *SyncSnapshot {
+	return &SyncSnapshot{
+		Format:    SyncFormatVersion,
+		Fields:    make(map[string]SyncField),
+		Unlocked:  make(map[string]time.Time),
+		Progress:  make(map[string]int),
+		Cosmetics: make(map[string]bool),
+	}
+}
+
+// syncFieldValues returns the last-writer-wins values of a pilot, grouped
+// by the time each group was last changed
+func syncFieldValues(pm *ProgressionManager, s Settings) (map[string]any, map[string]time.Time) {
+	values := map[string]any{
+		"progression.scrap":           pm.data.TotalScrap,
+		"progression.prestige":        pm.data.Prestige,
+		"progression.prestige_points": pm.data.PrestigePoints,
+		"settings.sound_enabled":      s.SoundEnabled,
+		"settings.volume":             s.Volume,
+		"settings.difficulty":         s.Difficulty,
+		"settings.keys":               s.Keys,
+		"settings.country":            s.Country,
+	}
+	for id, upgrade := range pm.data.Upgrades {
+		values["progression.upgrade."+id] = upgrade.Level
+	}
+	modified := map[string]time.Time{
+		"progression": pm.data.LastUpdated,
+		"settings":    s.Modified,
+	}
+	return values, modified
+}
+
+// CaptureSyncSnapshot takes a pilot's current data. Fields whose value is
+// unchanged since base keep base's modification time; the others take the
+// time their group was last saved on this device.
+func CaptureSyncSnapshot(base *SyncSnapshot, device string, pm *ProgressionManager, am *AchievementManager, s Settings) *SyncSnapshot {
+	snap := newSyncSnapshot()
+	values, modified := syncFieldValues(pm, s)
+	for key, v := range values {
+		value, _ := json.Marshal(v)
+		if b, ok := base.Fields[key]; ok && sameValue(b.Value, value) {
+			snap.Fields[key] = b
+			continue
+		}
+		group, _, _ := strings.Cut(key, ".")
+		snap.Fields[key] = SyncField{Value: value, Modified: modified[group], Device: device}
+	}
+
+	for id, ach := range am.Achievements {
+		if ach.Unlocked && ach.UnlockedAt != nil {
+			snap.Unlocked[id] = *ach.UnlockedAt
+		}
+		if ach.Progress > 0 {
+			snap.Progress[id] = ach.Progress
+		}
+	}
+	for id, unlocked := range pm.data.UnlockedCosmetics {
+		if unlocked {
+			snap.Cosmetics[id] = true
+		}
+	}
+	snap.MaxWave = pm.data.MaxWave
+	snap.ScrapEarned = pm.data.ScrapEarned
+	return snap
+}
+
+// MergeSync merges the local and remote data of a pilot given their common
+// base, the data as of the last sync. A field changed on only one side takes
+// that side's value. A field changed differently on both sides is a conflict
+// won by the newer change. Achievement unlocks, cosmetics, progress, the best
+// wave and lifetime scrap are merged by union or maximum and never conflict.
+func MergeSync(base, local, remote *SyncSnapshot) (*SyncSnapshot, []SyncConflict) {
+	merged := newSyncSnapshot()
+	var conflicts []SyncConflict
+
+	keys := make(map[string]bool)
+	for key := range local.Fields {
+		keys[key] = true
+	}
+	for key := range remote.Fields {
+		keys[key] = true
+	}
+	for _, key := range sortedNames(keys) {
+		l, lok := local.Fields[key]
+		r, rok := remote.Fields[key]
+		switch {
+		case !rok:
+			merged.Fields[key] = l
+			continue
+		case !lok:
+			merged.Fields[key] = r
+			continue
+		case sameValue(l.Value, r.Value):
+			merged.Fields[key] = newerField(l, r)
+			continue
+		}
+
+		// Without a base, a value that was never saved counts as unchanged
+		b, bok := base.Fields[key]
+		localChanged := bok && !sameValue(l.Value, b.Value) || !bok && !l.Modified.IsZero()
+		remoteChanged := bok && !sameValue(r.Value, b.Value) || !bok && !r.Modified.IsZero()
+		switch {
+		case localChanged && !remoteChanged:
+			merged.Fields[key] = l
+		case remoteChanged && !localChanged:
+			merged.Fields[key] = r
+		case !localChanged && !remoteChanged:
+			merged.Fields[key] = newerField(l, r)
+		default:
+			winner := newerField(l, r)
+			merged.Fields[key] = winner
+			conflicts = append(conflicts, SyncConflict{
+				Field:     key,
+				Local:     string(l.Value),
+				Remote:    string(r.Value),
+				RemoteWon: reflect.DeepEqual(winner, r),
+			})
+		}
+	}
+
+	for _, side := range []*SyncSnapshot{local, remote} {
+		for id, at := range side.Unlocked {
+			if prev, ok := merged.Unlocked[id]; !ok || at.Before(prev) {
+				merged.Unlocked[id] = at
+			}
+		}
+		for id, progress := range side.Progress {
+			merged.Progress[id] = max(merged.Progress[id], progress)
+		}
+		for id := range side.Cosmetics {
+			merged.Cosmetics[id] = true
+		}
+		merged.MaxWave = max(merged.MaxWave, side.MaxWave)
+		merged.ScrapEarned = max(merged.ScrapEarned, side.ScrapEarned)
+	}
+	return merged, conflicts
+}
+
+// sameValue reports whether two JSON values are equal, ignoring layout
+func sameValue(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+// newerField returns the more recently changed of two values. Ties go to the
+// device that sorts first so every device picks the same winner.
+func newerField(a, b SyncField) SyncField {
+	if b.Modified.After(a.Modified) || (b.Modified.Equal(a.Modified) && b.Device < a.Device) {
+		return b
+	}
+	return a
+}
+
+// ApplySyncSnapshot writes merged data into a pilot's progression,
+// achievements and settings. The caller saves them.
+func ApplySyncSnapshot(snap *SyncSnapshot, pm *ProgressionManager, am *AchievementManager, s *Settings) {
+	for key, field := range snap.Fields {
+		var err error
+		switch {
+		case key == "progression.scrap":
+			err = json.Unmarshal(field.Value, &pm.data.TotalScrap)
+		case key == "progression.prestige":
+			err = json.Unmarshal(field.Value, &pm.data.Prestige)
+		case key == "progression.prestige_points":
+			err = json.Unmarshal(field.Value, &pm.data.PrestigePoints)
+		case strings.HasPrefix(key, "progression.upgrade."):
+			id := strings.TrimPrefix(key, "progression.upgrade.")
+			upgrade, ok := pm.data.Upgrades[id]
+			if !ok {
+				continue // From a newer version
+			}
+			if err = json.Unmarshal(field.Value, &upgrade.Level); err == nil {
+				upgrade.Level = min(upgrade.Level, upgrade.MaxLevel)
+				upgrade.CurrentCost = upgrade.nextCost()
+				pm.data.Upgrades[id] = upgrade
+			}
+		case key == "settings.sound_enabled":
+			err = json.Unmarshal(field.Value, &s.SoundEnabled)
+		case key == "settings.volume":
+			err = json.Unmarshal(field.Value, &s.Volume)
+		case key == "settings.difficulty":
+			err = json.Unmarshal(field.Value, &s.Difficulty)
+		case key == "settings.keys":
+			err = json.Unmarshal(field.Value, &s.Keys)
+		case key == "settings.country":
+			err = json.Unmarshal(field.Value, &s.Country)
+		}
+		if err != nil {
+			log.Printf("Ignoring synced %s: %v", key, err)
+		}
+	}
+
+	for id, at := range snap.Unlocked {
+		if ach, ok := am.Achievements[id]; ok && (!ach.Unlocked || ach.UnlockedAt == nil || at.Before(*ach.UnlockedAt)) {
+			at := at
+			ach.Unlocked = true
+			ach.UnlockedAt = &at
+		}
+	}
+	for id, progress := range snap.Progress {
+		if ach, ok := am.Achievements[id]; ok {
+			ach.Progress = max(ach.Progress, progress)
+		}
+	}
+	for id := range snap.Cosmetics {
+		pm.data.UnlockedCosmetics[id] = true
+	}
+	pm.data.MaxWave = max(pm.data.MaxWave, snap.MaxWave)
+	pm.data.ScrapEarned = max(pm.data.ScrapEarned, snap.ScrapEarned)
+}
+
+// LoadSyncBase reads the data of a pilot's last sync, or an empty snapshot
+// if the pilot has never been synced
+func LoadSyncBase(store Storage) *SyncSnapshot {
+	snap := newSyncSnapshot()
+	if err := ReadSaveFile(store, syncBaseFileName, syncBaseSchema, snap); err != nil && !os.IsNotExist(err) {
+		log.Printf("Failed to load last sync: %v", err)
+		return newSyncSnapshot()
+	}
+	return snap
+}
+
+// SaveSyncBase records the data of a completed sync
+func SaveSyncBase(store Storage, snap *SyncSnapshot) error {
+	return WriteSaveFile(store, syncBaseFileName, syncBaseSchema, snap)
+}
+
+// GistSync keeps pilots' synced data in a private GitHub gist, one file per pilot
+type GistSync struct {
+	GistID      string
+	GitHubToken string
+	BaseURL     string // GitHub API root, DefaultGistAPIURL unless testing
+	client      *http.Client
+}
+
+// NewGistSync creates a sync client for the given gist
+func NewGistSync(gistID, githubToken string) *GistSync {
+	return &GistSync{
+		GistID:      gistID,
+		GitHubToken: githubToken,
+		BaseURL:     DefaultGistAPIURL,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Sync merges a pilot's local data with the copy in the gist file name and
+// uploads the result when it differs from the remote copy. It returns the
+// merged data, which the caller applies locally and keeps as the next base.
+// As with the leaderboard (see GistLeaderboard.updateScores), the upload
+// only applies if the gist is unchanged since it was read, and is read back;
+// when another device synced in between, its data is merged in as well.
+func (gs *GistSync) Sync(name string, base, local *SyncSnapshot) (*SyncSnapshot, []SyncConflict, error) {
+	var lastErr error
+	for attempt := 0; attempt < gistUpdateAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt)*gistRetryDelay + time.Duration(rand.Int63n(int64(gistRetryDelay))))
+		}
+
+		remote, etag, err := gs.pull(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		merged, conflicts := MergeSync(base, local, remote)
+		if merged.Equal(remote) {
+			return merged, conflicts, nil
+		}
+		err = gs.push(name, merged, etag)
+		if errors.Is(err, errGistChanged) {
+			lastErr = err
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Read back in case a concurrent write slipped past the precondition
+		remote, _, err = gs.pull(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to confirm sync: %w", err)
+		}
+		if merged.Equal(remote) {
+			return merged, conflicts, nil
+		}
+		lastErr = errGistChanged
+	}
+	return nil, nil, fmt.Errorf("failed to sync save after %d attempts: %w", gistUpdateAttempts, lastErr)
+}
+
+// Equal reports whether two snapshots hold the same data
+func (s *SyncSnapshot) Equal(other *SyncSnapshot) bool {
+	a, errA := json.Marshal(s)
+	b, errB := json.Marshal(other)
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
+
+// pull downloads a pilot's synced data, empty if there is none yet, with
+// the gist's ETag
+func (gs *GistSync) pull(name string) (*SyncSnapshot, string, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/gists/%s", gs.BaseURL, gs.GistID), nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	gs.authorize(req)
+
+	resp, err := gs.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch sync gist: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, "", fmt.Errorf("sync gist fetch failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var gist struct {
+		Files map[string]struct {
+			Content   string `json:"content"`
+			Truncated bool   `json:"truncated"`
+		} `json:"files"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&gist); err != nil {
+		return nil, "", fmt.Errorf("failed to parse sync gist: %w", err)
+	}
+	etag := resp.Header.Get("ETag")
+	file, ok := gist.Files[name]
+	if !ok {
+		return newSyncSnapshot(), etag, nil
+	}
+	if file.Truncated {
+		return nil, "", fmt.Errorf("synced save %s is too large", name)
+	}
+	snap := newSyncSnapshot()
+	if err := json.Unmarshal([]byte(file.Content), snap); err != nil {
+		return nil, "", fmt.Errorf("failed to parse synced save %s: %w", name, err)
+	}
+	if snap.Format > SyncFormatVersion {
+		return nil, "", fmt.Errorf("synced save format %d is newer than the supported format %d", snap.Format, SyncFormatVersion)
+	}
+	return snap, etag, nil
+}
+
+// push uploads a pilot's synced data. With an etag the upload only applies
+// if the gist has not changed since; otherwise it fails with errGistChanged.
+func (gs *GistSync) push(name string, snap *SyncSnapshot, etag string) error {
+	content, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode synced save: %w", err)
+	}
+	payload, err := json.Marshal(map[string]any{
+		"files": map[string]any{name: map[string]string{"content": string(content)}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("PATCH", fmt.Sprintf("%s/gists/%s", gs.BaseURL, gs.GistID), bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	gs.authorize(req)
+	req.Header.Set("Content-Type", "application/json")
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+
+	resp, err := gs.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update sync gist: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		return errGistChanged
+	}
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("sync gist update failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// authorize adds the GitHub token to a request
+func (gs *GistSync) authorize(req *http.Request) {
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", gs.GitHubToken))
+	req.Header.Set("Accept", "application/vnd.github+json")
+}
@@ -0,0 +1,197 @@
+package systems
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"stellar-siege/game/systems/gisttest"
+)
+
+// syncField builds a synced value changed at the given minute
+func syncField(value string, minute int, device string) SyncField {
+	return SyncField{
+		Value:    json.RawMessage(value),
+		Modified: time.Date(2026, 1, 1, 12, minute, 0, 0, time.UTC),
+		Device:   device,
+	}
+}
+
+func TestMergeSync(t *testing.T) {
+	base := newSyncSnapshot()
+	base.Fields["progression.scrap"] = syncField("100", 0, "a")
+	base.Fields["settings.volume"] = syncField("0.5", 0, "a")
+	base.Fields["settings.difficulty"] = syncField("1", 0, "a")
+
+	local := newSyncSnapshot()
+	local.Fields["progression.scrap"] = syncField("150", 5, "a") // Changed here only
+	local.Fields["settings.volume"] = syncField("0.8", 3, "a")   // Changed on both, older
+	local.Fields["settings.difficulty"] = base.Fields["settings.difficulty"]
+	local.Unlocked["wave_5"] = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	local.MaxWave, local.ScrapEarned = 12, 900
+
+	remote := newSyncSnapshot()
+	remote.Fields["progression.scrap"] = base.Fields["progression.scrap"]
+	remote.Fields["settings.volume"] = syncField("0.2", 4, "b")
+	remote.Fields["settings.difficulty"] = syncField("2", 1, "b") // Changed there only
+	remote.Unlocked["first_victory"] = time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	remote.MaxWave, remote.ScrapEarned = 8, 1200
+
+	merged, conflicts := MergeSync(base, local, remote)
+	want := map[string]string{
+		"progression.scrap":   "150",
+		"settings.volume":     "0.2",
+		"settings.difficulty": "2",
+	}
+	for key, value := range want {
+		if got := string(merged.Fields[key].Value); got != value {
+			t.Errorf("%s = %s, want %s", key, got, value)
+		}
+	}
+	if len(conflicts) != 1 || conflicts[0].Field != "settings.volume" || !conflicts[0].RemoteWon {
+		t.Errorf("conflicts = %+v, want the volume won by the remote", conflicts)
+	}
+	if len(merged.Unlocked) != 2 || merged.MaxWave != 12 || merged.ScrapEarned != 1200 {
+		t.Errorf("monotonic data = %v, wave %d, scrap %d", merged.Unlocked, merged.MaxWave, merged.ScrapEarned)
+	}
+
+	// A device that never saved a value does not conflict with one that did
+	fresh := newSyncSnapshot()
+	fresh.Fields["settings.volume"] = SyncField{Value: json.RawMessage("0.7"), Device: "c"}
+	merged, conflicts = MergeSync(newSyncSnapshot(), fresh, remote)
+	if len(conflicts) != 0 || string(merged.Fields["settings.volume"].Value) != "0.2" {
+		t.Errorf("fresh device merge = %s, conflicts %+v", merged.Fields["settings.volume"].Value, conflicts)
+	}
+}
+
+// syncDevice is one install taking part in a sync test
+type syncDevice struct {
+	name  string
+	store Storage
+	pm    *ProgressionManager
+	am    *AchievementManager
+	s     Settings
+}
+
+func newSyncDevice(name string) *syncDevice {
+	store := NewMemoryStorage()
+	return &syncDevice{
+		name:  name,
+		store: store,
+		pm:    NewProgressionManager(store, "progression.json"),
+		am:    NewAchievementManager(store, "achievements.json"),
+		s:     Settings{SoundEnabled: true, Volume: 0.7, Difficulty: 1, Keys: DefaultKeyBindings()},
+	}
+}
+
+// sync runs a sync the way the game does and applies the result
+func (d *syncDevice) sync(t *testing.T, gs *GistSync) []SyncConflict {
+	t.Helper()
+	base := LoadSyncBase(d.store)
+	merged, conflicts, err := gs.Sync("pilot.json", base, CaptureSyncSnapshot(base, d.name, d.pm, d.am, d.s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ApplySyncSnapshot(merged, d.pm, d.am, &d.s)
+	if err := SaveSyncBase(d.store, merged); err != nil {
+		t.Fatal(err)
+	}
+	return conflicts
+}
+
+func TestGistSyncBetweenDevices(t *testing.T) {
+	srv := gisttest.NewServer("secret")
+	defer srv.Close()
+	srv.CreateGist("saves", nil)
+	gs := NewGistSync("saves", "secret")
+	gs.BaseURL = srv.URL
+
+	a, b := newSyncDevice("a"), newSyncDevice("b")
+	a.pm.AddScrap(300)
+	a.pm.RecordWave(9)
+	a.am.Unlock("wave_5")
+	if conflicts := a.sync(t, gs); len(conflicts) != 0 {
+		t.Errorf("first sync conflicts = %+v", conflicts)
+	}
+	if _, ok := srv.File("saves", "pilot.json"); !ok {
+		t.Fatal("first sync did not upload the save")
+	}
+
+	// A fresh device takes everything without conflicts
+	if conflicts := b.sync(t, gs); len(conflicts) != 0 {
+		t.Errorf("fresh device conflicts = %+v", conflicts)
+	}
+	if b.pm.GetTotalScrap() != 300 || b.pm.GetMaxWave() != 9 || !b.am.GetAchievementByID("wave_5").Unlocked {
+		t.Errorf("device b has scrap %d, wave %d", b.pm.GetTotalScrap(), b.pm.GetMaxWave())
+	}
+
+	// Both change the volume; the later change wins on both devices
+	a.s.Volume, a.s.Modified = 0.1, time.Now()
+	b.s.Volume, b.s.Modified = 0.9, time.Now().Add(time.Minute)
+	a.sync(t, gs)
+	conflicts := b.sync(t, gs)
+	if len(conflicts) != 1 || conflicts[0].Field != "settings.volume" || conflicts[0].RemoteWon {
+		t.Errorf("conflicts = %+v, want the volume kept from device b", conflicts)
+	}
+	a.sync(t, gs)
+	if a.s.Volume != 0.9 || b.s.Volume != 0.9 {
+		t.Errorf("volumes = %v and %v, want 0.9", a.s.Volume, b.s.Volume)
+	}
+
+	// Writes need the token
+	gs.GitHubToken = "wrong"
+	a.pm.AddScrap(10)
+	a.pm.Save()
+	if _, _, err := gs.Sync("pilot.json", LoadSyncBase(a.store), CaptureSyncSnapshot(LoadSyncBase(a.store), "a", a.pm, a.am, a.s)); err == nil {
+		t.Error("sync with a bad token succeeded")
+	}
+}
+
+// interceptTransport runs before ahead of the first request with the method
+type interceptTransport struct {
+	method string
+	before func()
+}
+
+func (it *interceptTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == it.method && it.before != nil {
+		before := it.before
+		it.before = nil
+		before()
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestGistSyncMergesConcurrentSync(t *testing.T) {
+	srv := gisttest.NewServer("secret")
+	defer srv.Close()
+	srv.CreateGist("saves", nil)
+	newClient := func() *GistSync {
+		gs := NewGistSync("saves", "secret")
+		gs.BaseURL = srv.URL
+		return gs
+	}
+
+	a, b := newSyncDevice("a"), newSyncDevice("b")
+	a.sync(t, newClient())
+	b.sync(t, newClient())
+
+	// Device b syncs after a read the gist but before a writes it
+	a.am.Unlock("wave_5")
+	b.pm.RecordWave(12)
+	racing := newClient()
+	racing.client = &http.Client{Transport: &interceptTransport{method: "PATCH", before: func() { b.sync(t, newClient()) }}}
+	a.sync(t, racing)
+	if srv.Conflicts() == 0 {
+		t.Fatal("the racing write was not refused")
+	}
+	if a.pm.GetMaxWave() != 12 {
+		t.Errorf("device a has wave %d, want b's 12", a.pm.GetMaxWave())
+	}
+
+	b.sync(t, newClient())
+	if !b.am.GetAchievementByID("wave_5").Unlocked {
+		t.Error("device a's unlock was lost")
+	}
+}
@@ -0,0 +1,64 @@
+package systems
+
+import "time"
+
+// ScorePeriod limits a leaderboard view to recent scores
+type ScorePeriod int
+
+const (
+	PeriodAllTime ScorePeriod = iota
+	PeriodToday
+	PeriodWeek
+)
+
+// String returns the period's display name
+func (p ScorePeriod) String() string {
+	switch p {
+	case PeriodToday:
+		return "Today"
+	case PeriodWeek:
+		return "This Week"
+	}
+	return "All Time"
+}
+
+// Next returns the period that follows p when cycling through them
+func (p ScorePeriod) Next() ScorePeriod {
+	return (p + 1) % (PeriodWeek + 1)
+}
+
+// Since returns the earliest date in the period as of now: local midnight
+// for today, seven days back for the week, and the zero time for all time
+func (p ScorePeriod) Since(now time.Time) time.Time {
+	switch p {
+	case PeriodToday:
+		y, m, d := now.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	case PeriodWeek:
+		return now.AddDate(0, 0, -7)
+	}
+	return time.Time{}
+}
+
+// ScoreFilter selects the online scores shown in a leaderboard view
+type ScoreFilter struct {
+	Difficulty string // Empty for every difficulty
+	Period     ScorePeriod
+}
+
+// Apply returns the scores that pass the filter, in their original order.
+// Entries whose signature hides them (see ScoreTrust.Hidden) never pass.
+func (f ScoreFilter) Apply(scores []OnlineScore, now time.Time) []OnlineScore {
+	since := f.Period.Since(now)
+	var kept []OnlineScore
+	for _, s := range scores {
+		switch {
+		case s.Trust.Hidden():
+		case f.Difficulty != "" && s.Difficulty != f.Difficulty:
+		case s.Date.Before(since):
+		default:
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
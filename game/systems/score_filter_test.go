@@ -0,0 +1,37 @@
+package systems
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScoreFilter(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	scores := []OnlineScore{
+		{PlayerName: "A", Score: 900, Difficulty: "Hard", Date: now.Add(-time.Hour)},
+		{PlayerName: "B", Score: 800, Difficulty: "Easy", Date: now.Add(-20 * time.Hour)},
+		{PlayerName: "C", Score: 700, Difficulty: "Hard", Date: now.AddDate(0, 0, -3)},
+		{PlayerName: "D", Score: 600, Difficulty: "Hard", Date: now.AddDate(0, -2, 0)},
+		{PlayerName: "E", Score: 500, Difficulty: "Hard", Date: now, Trust: ScoreForged},
+	}
+
+	tests := []struct {
+		filter ScoreFilter
+		want   string
+	}{
+		{ScoreFilter{}, "ABCD"},
+		{ScoreFilter{Difficulty: "Hard"}, "ACD"},
+		{ScoreFilter{Period: PeriodToday}, "A"},
+		{ScoreFilter{Period: PeriodWeek}, "ABC"},
+		{ScoreFilter{Difficulty: "Normal"}, ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		for _, s := range tt.filter.Apply(scores, now) {
+			got += s.PlayerName
+		}
+		if got != tt.want {
+			t.Errorf("%+v: got %q, want %q", tt.filter, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package systems
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"stellar-siege/game/scoresig"
+)
+
+// ScoreKeyFileName is the file holding this install's score signing key.
+// It stays on the machine; it is neither synced nor part of a pilot profile.
+const ScoreKeyFileName = "score_key.json"
+
+// scoreKeySchema is the save format of the score signing key
+var scoreKeySchema = SaveSchema{
+	Kind:    "score_key",
+	Version: 1,
+}
+
+// PilotKeysFileName is the gist file registering the public key each player
+// name was first submitted with
+const PilotKeysFileName = "pilot_keys.json"
+
+// ScoreTrust is what a client concluded from an entry's signature
+type ScoreTrust int
+
+const (
+	ScoreUnsigned     ScoreTrust = iota // No signature, from a client that predates signing
+	ScoreVerified                       // Signed by the key registered for its player
+	ScoreUnregistered                   // Signed, but its player has no key on record
+	ScoreImpostor                       // Claims a registered player without that player's key
+	ScoreForged                         // The signature does not match the entry
+)
+
+// Hidden reports whether an entry is left off the leaderboard: it was
+// changed after signing, or submitted in someone else's name
+func (t ScoreTrust) Hidden() bool {
+	return t == ScoreForged || t == ScoreImpostor
+}
+
+// LoadScoreKey returns this install's score signing key, generating and
+// saving one on first use
+func LoadScoreKey(store Storage) (ed25519.PrivateKey, error) {
+	var seed string
+	err := ReadSaveFile(store, ScoreKeyFileName, scoreKeySchema, &seed)
+	if errors.Is(err, fs.ErrNotExist) {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate score key: %w", err)
+		}
+		if err := WriteSaveFile(store, ScoreKeyFileName, scoreKeySchema, hex.EncodeToString(key.Seed())); err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := hex.DecodeString(seed)
+	if err != nil || len(b) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid score key in %s", ScoreKeyFileName)
+	}
+	return ed25519.NewKeyFromSeed(b), nil
+}
+
+// signedFields returns the fields covered by an entry's signature
+func (s *OnlineScore) signedFields() scoresig.Fields {
+	return scoresig.Fields{
+		PlayerName: s.PlayerName,
+		Country:    s.Country,
+		Score:      s.Score,
+		Wave:       s.Wave,
+		Difficulty: s.Difficulty,
+		Version:    s.Version,
+		Seed:       s.Seed,
+	}
+}
+
+// Sign signs the entry with this install's key
+func (s *OnlineScore) Sign(key ed25519.PrivateKey) {
+	s.PublicKey, s.Signature = scoresig.Sign(key, s.signedFields())
+}
+
+// CheckSignature reports whether the entry is signed by its public key and
+// has not been modified since
+func (s *OnlineScore) CheckSignature() bool {
+	return scoresig.Verify(s.PublicKey, s.Signature, s.signedFields())
+}
+
+// PilotKeyName is the name a player's key is registered under; names that
+// differ only in case or surrounding space belong to the same player
+func PilotKeyName(playerName string) string {
+	return strings.ToLower(strings.TrimSpace(playerName))
+}
+
+// RegisterPilotKey records publicKey for a player name not registered yet.
+// It fails if the name is registered to another key.
+func RegisterPilotKey(keys map[string]string, playerName, publicKey string) error {
+	name := PilotKeyName(playerName)
+	if registered, ok := keys[name]; ok && registered != publicKey {
+		return fmt.Errorf("player name %q is registered to another install", playerName)
+	}
+	keys[name] = publicKey
+	return nil
+}
+
+// CheckScoreSignatures sets the Trust of every entry from its signature and
+// the registered pilot keys
+func CheckScoreSignatures(scores []OnlineScore, keys map[string]string) {
+	for i := range scores {
+		s := &scores[i]
+		registered, ok := keys[PilotKeyName(s.PlayerName)]
+		switch {
+		case s.Signature == "" && ok:
+			// Dropping the signature must not get around the registration
+			s.Trust = ScoreImpostor
+		case s.Signature == "":
+			s.Trust = ScoreUnsigned
+		case !s.CheckSignature():
+			s.Trust = ScoreForged
+		case !ok:
+			s.Trust = ScoreUnregistered
+		case registered != s.PublicKey:
+			s.Trust = ScoreImpostor
+		default:
+			s.Trust = ScoreVerified
+		}
+	}
+}
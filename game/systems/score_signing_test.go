@@ -0,0 +1,69 @@
+package systems
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+)
+
+func TestCheckScoreSignatures(t *testing.T) {
+	_, ace, _ := ed25519.GenerateKey(nil)
+	_, mallory, _ := ed25519.GenerateKey(nil)
+	keys := map[string]string{"ace": hex.EncodeToString(ace.Public().(ed25519.PublicKey))}
+
+	sign := func(name string, score int64, key ed25519.PrivateKey) OnlineScore {
+		s := OnlineScore{PlayerName: name, Score: score, Difficulty: "Hard", Wave: 6, Version: "1.1.0", Seed: 42}
+		s.Sign(key)
+		return s
+	}
+	forged := sign("Ace", 100, ace)
+	forged.Score = 99999
+
+	scores := []OnlineScore{
+		sign("Ace", 500, ace),
+		sign("Rookie", 300, mallory),
+		{PlayerName: "Oldtimer", Score: 200},
+		sign("ACE", 900, mallory),
+		{PlayerName: "Ace", Score: 800},
+		forged,
+	}
+	CheckScoreSignatures(scores, keys)
+
+	want := []ScoreTrust{ScoreVerified, ScoreUnregistered, ScoreUnsigned, ScoreImpostor, ScoreImpostor, ScoreForged}
+	for i, w := range want {
+		if scores[i].Trust != w {
+			t.Errorf("%s %d: trust %d, want %d", scores[i].PlayerName, scores[i].Score, scores[i].Trust, w)
+		}
+	}
+	if err := RegisterPilotKey(keys, " Ace", scores[1].PublicKey); err == nil {
+		t.Error("registered a taken name to another key")
+	}
+}
+
+func TestSignatureCoversCountry(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(nil)
+	s := OnlineScore{PlayerName: "Ace", Country: "NO", Score: 500, Difficulty: "Hard", Wave: 6}
+	s.Sign(key)
+	if !s.CheckSignature() {
+		t.Fatal("signature with a country does not verify")
+	}
+	s.Country = "SE"
+	if s.CheckSignature() {
+		t.Error("changing the country kept the signature valid")
+	}
+}
+
+func TestLoadScoreKeyIsKeptPerInstall(t *testing.T) {
+	store := NewMemoryStorage()
+	first, err := LoadScoreKey(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := LoadScoreKey(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.Equal(again) {
+		t.Error("a new key was generated on the second launch")
+	}
+}
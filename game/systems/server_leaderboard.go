@@ -0,0 +1,225 @@
+package systems
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LeaderboardAPIPrefix is the path under which a leaderboard server serves its API
+const LeaderboardAPIPrefix = "/api/v1"
+
+// ScoreSubmission is the body of a score submitted to a leaderboard server
+type ScoreSubmission struct {
+	PlayerName string  `json:"player_name"`
+	Country    string  `json:"country,omitempty"`
+	Score      int64   `json:"score"`
+	Difficulty string  `json:"difficulty"`
+	Wave       int     `json:"wave"`
+	ReplayID   string  `json:"replay_id,omitempty"`
+	Replay     *Replay `json:"replay,omitempty"`
+
+	// Signature over the entry, as in OnlineScore
+	Version   string `json:"version,omitempty"`
+	Seed      int64  `json:"seed,omitempty"`
+	PublicKey string `json:"public_key,omitempty"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// ServerLeaderboard is the online leaderboard kept by a self-hosted
+// leaderboard server (see cmd/leaderboard-server). Clients need no
+// credentials to read or submit; only the verifier needs the admin token.
+type ServerLeaderboard struct {
+	BaseURL    string
+	AdminToken string             // Needed to store verification records
+	Key        ed25519.PrivateKey // Signs submitted scores; they go unsigned without one
+	client     *http.Client
+
+	mu          sync.Mutex
+	cachedData  []OnlineScore
+	lastFetch   time.Time
+	cacheExpiry time.Duration
+}
+
+// NewServerLeaderboard creates a client for the leaderboard server at baseURL
+func NewServerLeaderboard(baseURL, adminToken string) *ServerLeaderboard {
+	return &ServerLeaderboard{
+		BaseURL:     strings.TrimSuffix(baseURL, "/"),
+		AdminToken:  adminToken,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		cacheExpiry: 30 * time.Second,
+	}
+}
+
+// GetTopScores fetches the best scores, highest first (with local caching)
+func (sl *ServerLeaderboard) GetTopScores(limit int) ([]OnlineScore, error) {
+	sl.mu.Lock()
+	if time.Since(sl.lastFetch) < sl.cacheExpiry && len(sl.cachedData) > 0 {
+		scores := sl.cachedData[:min(limit, len(sl.cachedData))]
+		sl.mu.Unlock()
+		return scores, nil
+	}
+	sl.mu.Unlock()
+
+	scores, err := sl.fetchScores(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	sl.mu.Lock()
+	sl.cachedData = scores
+	sl.lastFetch = time.Now()
+	sl.mu.Unlock()
+	return scores, nil
+}
+
+// GetAllScores fetches every entry from the server, bypassing the cache
+func (sl *ServerLeaderboard) GetAllScores() ([]OnlineScore, error) {
+	return sl.fetchScores(0)
+}
+
+// SubmitScore sends a new score, with the run's replay for verification.
+// The server registers the key of a signed score for the player name, and
+// rejects it if the name belongs to another key.
+func (sl *ServerLeaderboard) SubmitScore(playerName, country string, score int64, difficulty string, wave int, replay *Replay) error {
+	// The server stores names trimmed, so sign them that way
+	playerName = strings.TrimSpace(playerName)
+	entry := OnlineScore{
+		PlayerName: playerName,
+		Country:    country,
+		Score:      score,
+		Difficulty: difficulty,
+		Wave:       wave,
+	}
+	if replay != nil {
+		entry.Version = replay.GameVersion
+		entry.Seed = replay.Seed
+	}
+	if sl.Key != nil {
+		entry.Sign(sl.Key)
+	}
+
+	submission := ScoreSubmission{
+		PlayerName: playerName,
+		Country:    country,
+		Score:      score,
+		Difficulty: difficulty,
+		Wave:       wave,
+		Version:    entry.Version,
+		Seed:       entry.Seed,
+		PublicKey:  entry.PublicKey,
+		Signature:  entry.Signature,
+	}
+	if replay != nil {
+		submission.ReplayID = replay.ID()
+		submission.Replay = replay
+	}
+	if err := sl.do("POST", "/scores", submission, nil, false); err != nil {
+		return fmt.Errorf("failed to submit score: %w", err)
+	}
+	sl.ClearCache()
+	return nil
+}
+
+// GetPilotKeys fetches the public key registered for each player name
+func (sl *ServerLeaderboard) GetPilotKeys() (map[string]string, error) {
+	keys := make(map[string]string)
+	if err := sl.do("GET", "/keys", nil, &keys, false); err != nil {
+		return nil, fmt.Errorf("failed to fetch pilot keys: %w", err)
+	}
+	return keys, nil
+}
+
+// FetchReplay downloads the replay stored for a leaderboard entry
+func (sl *ServerLeaderboard) FetchReplay(replayID string) (*Replay, error) {
+	var replay Replay
+	if err := sl.do("GET", "/replays/"+url.PathEscape(replayID), nil, &replay, false); err != nil {
+		return nil, fmt.Errorf("failed to fetch replay %s: %w", replayID, err)
+	}
+	if err := replay.Validate(); err != nil {
+		return nil, err
+	}
+	return &replay, nil
+}
+
+// RecordVerifications stores signed verification records on the entries
+// whose replays they vouch for
+func (sl *ServerLeaderboard) RecordVerifications(records []*VerificationRecord) error {
+	if sl.AdminToken == "" {
+		return fmt.Errorf("leaderboard admin token not configured")
+	}
+	if err := sl.do("POST", "/verifications", records, nil, true); err != nil {
+		return fmt.Errorf("failed to store verification records: %w", err)
+	}
+	sl.ClearCache()
+	return nil
+}
+
+// ClearCache forces a refresh on next fetch
+func (sl *ServerLeaderboard) ClearCache() {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.lastFetch = time.Time{}
+	sl.cachedData = nil
+}
+
+// fetchScores fetches up to limit scores; 0 fetches all of them
+func (sl *ServerLeaderboard) fetchScores(limit int) ([]OnlineScore, error) {
+	path := "/scores"
+	if limit > 0 {
+		path += fmt.Sprintf("?limit=%d", limit)
+	}
+	var scores []OnlineScore
+	if err := sl.do("GET", path, nil, &scores, false); err != nil {
+		return nil, fmt.Errorf("failed to fetch scores: %w", err)
+	}
+	return scores, nil
+}
+
+// do sends a JSON request to the server API and decodes the JSON response
+// into out, if it is not nil
+func (sl *ServerLeaderboard) do(method, path string, body, out any, admin bool) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, sl.BaseURL+LeaderboardAPIPrefix+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if admin {
+		req.Header.Set("Authorization", "Bearer "+sl.AdminToken)
+	}
+
+	resp, err := sl.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("server returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
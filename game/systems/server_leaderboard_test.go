@@ -0,0 +1,68 @@
+package systems
+
+import (
+	"crypto/ed25519"
+	"net/http/httptest"
+	"testing"
+
+	"stellar-siege/game/entities"
+	"stellar-siege/game/scoreserver"
+)
+
+func TestServerLeaderboardRoundTrip(t *testing.T) {
+	server, err := scoreserver.New(t.TempDir(), "admin", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+	client := NewServerLeaderboard(srv.URL+"/", "")
+
+	replay := NewReplay("test", 42, 1)
+	replay.Record(entities.InputShoot)
+	replay.Finish(1500, 3)
+	if err := client.SubmitScore("Ace", "NO", 1500, "Normal", 3, replay); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SubmitScore("Rookie", "", 200, "Easy", 1, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SubmitScore("", "", 1, "Easy", 1, nil); err == nil {
+		t.Error("submission without a name was accepted")
+	}
+
+	scores, err := client.GetTopScores(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scores) != 2 || scores[0].PlayerName != "Ace" || scores[0].Country != "NO" || scores[0].ReplayID != replay.ID() {
+		t.Fatalf("scores = %+v", scores)
+	}
+	fetched, err := client.FetchReplay(scores[0].ReplayID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched.ID() != replay.ID() {
+		t.Errorf("fetched replay %s, want %s", fetched.ID(), replay.ID())
+	}
+
+	// Storing verification records needs the admin token; stored records
+	// keep their signature
+	_, key, _ := ed25519.GenerateKey(nil)
+	record := &VerificationRecord{ReplayID: replay.ID(), Verified: true, Score: 1500, Wave: 3}
+	record.Sign(key)
+	if err := client.RecordVerifications([]*VerificationRecord{record}); err == nil {
+		t.Error("verification records were stored without the admin token")
+	}
+	client.AdminToken = "admin"
+	if err := client.RecordVerifications([]*VerificationRecord{record}); err != nil {
+		t.Fatal(err)
+	}
+	all, err := client.GetAllScores()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := all[0].Verification; v == nil || !v.CheckSignature(key.Public().(ed25519.PublicKey)) {
+		t.Errorf("verification = %+v", v)
+	}
+}
@@ -0,0 +1,82 @@
+package systems
+
+import (
+	"os"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// SettingsFileName is the file settings are kept in
+const SettingsFileName = "settings.json"
+
+// Settings are the player's preferences, kept between sessions
+type Settings struct {
+	SoundEnabled bool        `json:"sound_enabled"`
+	Volume       float64     `json:"volume"`
+	Difficulty   int         `json:"difficulty"` // Last difficulty picked on the menu
+	Keys         KeyBindings `json:"keys"`
+	Country      string      `json:"country,omitempty"` // Shown next to the pilot's scores; empty shares none
+	Modified     time.Time   `json:"modified"`          // When the player last changed a setting
+}
+
+// KeyBindings are the keys the player's controls are bound to. The arrow
+// keys and the left mouse button work as well, whatever the bindings.
+type KeyBindings struct {
+	Up    ebiten.Key `json:"up"`
+	Down  ebiten.Key `json:"down"`
+	Left  ebiten.Key `json:"left"`
+	Right ebiten.Key `json:"right"`
+	Shoot ebiten.Key `json:"shoot"`
+}
+
+// DefaultKeyBindings returns WASD movement with space to fire
+func DefaultKeyBindings() KeyBindings {
+	return KeyBindings{
+		Up:    ebiten.KeyW,
+		Down:  ebiten.KeyS,
+		Left:  ebiten.KeyA,
+		Right: ebiten.KeyD,
+		Shoot: ebiten.KeySpace,
+	}
+}
+
+// settingsSchema is the save format of the settings
+var settingsSchema = SaveSchema{
+	Kind:    "settings",
+	Version: 1,
+}
+
+// DefaultSettings returns the settings of a fresh install
+func DefaultSettings() Settings {
+	return Settings{
+		SoundEnabled: true,
+		Volume:       0.5,
+		Difficulty:   1, // Normal
+		Keys:         DefaultKeyBindings(),
+	}
+}
+
+// LoadSettings reads the settings from store, falling back to the defaults
+// for a fresh install
+func LoadSettings(store Storage) (Settings, error) {
+	s := DefaultSettings()
+	if err := ReadSaveFile(store, SettingsFileName, settingsSchema, &s); err != nil {
+		if os.IsNotExist(err) {
+			return DefaultSettings(), nil
+		}
+		return DefaultSettings(), err
+	}
+	if s.Difficulty < 0 || s.Difficulty > 2 {
+		s.Difficulty = DefaultSettings().Difficulty
+	}
+	if _, ok := FindCountry(s.Country); !ok {
+		s.Country = ""
+	}
+	return s, nil
+}
+
+// SaveSettings writes the settings to store
+func SaveSettings(store Storage, s Settings) error {
+	return WriteSaveFile(store, SettingsFileName, settingsSchema, s)
+}
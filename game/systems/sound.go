@@ -97,9 +97,21 @@ func NewSoundManager() (*SoundManager, error) {
 	}, nil
 }
 
+// NewSilentSoundManager creates a sound manager without an audio device.
+// It is used by headless simulations, where every sound is dropped.
+func NewSilentSoundManager() *SoundManager {
+	return &SoundManager{
+		enabled:        false,
+		volume:         0,
+		players:        make([]*audio.Player, 0),
+		lastPlayTime:   make(map[SoundType]time.Time),
+		soundCooldowns: make(map[SoundType]time.Duration),
+	}
+}
+
 // PlaySound plays a procedural sound effect
 func (sm *SoundManager) PlaySound(soundType SoundType) {
-	if !sm.enabled {
+	if !sm.enabled || sm.audioContext == nil {
 		return
 	}
 
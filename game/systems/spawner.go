@@ -2,9 +2,9 @@ package systems
 
 import (
 	"math"
-	"math/rand"
 
 	"stellar-siege/game/entities"
+	"stellar-siege/game/rng"
 )
 
 type WaveDefinition struct {
@@ -52,6 +52,40 @@ func (ws *WaveSpawner) SetDifficultyMultipliers(spawnMult, healthMult, speedMult
 	ws.damageMultiplier = damageMult
 }
 
+// SpawnerState is the progress of a WaveSpawner through its current wave,
+// saved with suspended runs
+type SpawnerState struct {
+	CurrentWave   int     `json:"current_wave"`
+	SpawnTimer    float64 `json:"spawn_timer"`
+	SpawnDelay    float64 `json:"spawn_delay"`
+	EnemiesLeft   int     `json:"enemies_left"`
+	WaveCompleted bool    `json:"wave_completed"`
+	WaveStarted   bool    `json:"wave_started"`
+}
+
+// State returns the spawner's progress through its current wave
+func (ws *WaveSpawner) State() SpawnerState {
+	return SpawnerState{
+		CurrentWave:   ws.currentWave,
+		SpawnTimer:    ws.spawnTimer,
+		SpawnDelay:    ws.spawnDelay,
+		EnemiesLeft:   ws.enemiesLeft,
+		WaveCompleted: ws.WaveCompleted,
+		WaveStarted:   ws.waveStarted,
+	}
+}
+
+// RestoreState resumes the spawner from a saved state. Difficulty
+// multipliers are not part of the state and must be set separately.
+func (ws *WaveSpawner) RestoreState(state SpawnerState) {
+	ws.currentWave = state.CurrentWave
+	ws.spawnTimer = state.SpawnTimer
+	ws.spawnDelay = state.SpawnDelay
+	ws.enemiesLeft = state.EnemiesLeft
+	ws.WaveCompleted = state.WaveCompleted
+	ws.waveStarted = state.WaveStarted
+}
+
 func (ws *WaveSpawner) StartWave(wave int) {
 	ws.currentWave = wave
 	ws.WaveCompleted = false
@@ -72,7 +106,7 @@ func (ws *WaveSpawner) StartWave(wave int) {
 	ws.spawnTimer = 0
 }
 
-func (ws *WaveSpawner) Update(gameTime float64, currentWave int) []*entities.Enemy {
+func (ws *WaveSpawner) Update(deltaTime, gameTime float64, currentWave int) []*entities.Enemy {
 	// Start first wave
 	if !ws.waveStarted && currentWave == 0 {
 		ws.StartWave(1)
@@ -82,7 +116,7 @@ func (ws *WaveSpawner) Update(gameTime float64, currentWave int) []*entities.Ene
 		return nil
 	}
 
-	ws.spawnTimer += 1.0 / 60.0
+	ws.spawnTimer += deltaTime
 
 	if ws.spawnTimer >= ws.spawnDelay && ws.enemiesLeft > 0 {
 		ws.spawnTimer = 0
@@ -121,7 +155,7 @@ func (ws *WaveSpawner) spawnEnemyBatch() []*entities.Enemy {
 // calculateSpawnPosition returns a random spawn position at the top of the screen
 func (ws *WaveSpawner) calculateSpawnPosition() (float64, float64) {
 	margin := 50.0
-	x := margin + rand.Float64()*(float64(ws.width)-margin*2)
+	x := margin + rng.Float64()*(float64(ws.width)-margin*2)
 	y := -30.0
 	return x, y
 }
@@ -160,7 +194,7 @@ func (ws *WaveSpawner) SpawnFormation(wave int) []*entities.Enemy {
 
 // selectFormationType chooses appropriate formation based on wave
 func (ws *WaveSpawner) selectFormationType(wave int) entities.FormationType {
-	r := rand.Float64()
+	r := rng.Float64()
 
 	// Earlier waves: simpler formations
 	if wave < 8 {
@@ -195,8 +229,8 @@ func setFormationProperties(enemy *entities.Enemy, formationType entities.Format
 
 // spawnVFormation creates a V-shaped formation
 func (ws *WaveSpawner) spawnVFormation(wave int) []*entities.Enemy {
-	formationID := rand.Intn(10000)
-	count := 3 + rand.Intn(3) // 3-5 enemies
+	formationID := rng.Intn(10000)
+	count := 3 + rng.Intn(3) // 3-5 enemies
 
 	// Center position
 	centerX := float64(ws.width) / 2.0
@@ -233,8 +267,8 @@ func (ws *WaveSpawner) spawnVFormation(wave int) []*entities.Enemy {
 
 // spawnCircularFormation creates enemies in a circular pattern
 func (ws *WaveSpawner) spawnCircularFormation(wave int) []*entities.Enemy {
-	formationID := rand.Intn(10000)
-	count := 4 + rand.Intn(3) // 4-6 enemies
+	formationID := rng.Intn(10000)
+	count := 4 + rng.Intn(3) // 4-6 enemies
 
 	centerX := float64(ws.width) / 2.0
 	centerY := 100.0 // Start lower for circular formation
@@ -259,8 +293,8 @@ func (ws *WaveSpawner) spawnCircularFormation(wave int) []*entities.Enemy {
 
 // spawnWaveFormation creates enemies in a wave pattern
 func (ws *WaveSpawner) spawnWaveFormation(wave int) []*entities.Enemy {
-	formationID := rand.Intn(10000)
-	count := 5 + rand.Intn(3) // 5-7 enemies
+	formationID := rng.Intn(10000)
+	count := 5 + rng.Intn(3) // 5-7 enemies
 
 	spacing := 70.0
 	startX := float64(ws.width)/2.0 - (float64(count-1)*spacing)/2.0
@@ -282,8 +316,8 @@ func (ws *WaveSpawner) spawnWaveFormation(wave int) []*entities.Enemy {
 
 // spawnPincerFormation creates two groups attacking from sides
 func (ws *WaveSpawner) spawnPincerFormation(wave int) []*entities.Enemy {
-	formationID := rand.Intn(10000)
-	countPerSide := 2 + rand.Intn(2) // 2-3 per side
+	formationID := rng.Intn(10000)
+	countPerSide := 2 + rng.Intn(2) // 2-3 per side
 	totalCount := countPerSide * 2
 
 	enemies := make([]*entities.Enemy, totalCount)
@@ -313,8 +347,8 @@ func (ws *WaveSpawner) spawnPincerFormation(wave int) []*entities.Enemy {
 
 // spawnConvoyFormation creates a line of enemies with a leader
 func (ws *WaveSpawner) spawnConvoyFormation(wave int) []*entities.Enemy {
-	formationID := rand.Intn(10000)
-	count := 3 + rand.Intn(3) // 3-5 enemies
+	formationID := rng.Intn(10000)
+	count := 3 + rng.Intn(3) // 3-5 enemies
 
 	centerX := float64(ws.width) / 2.0
 	spacing := 50.0
@@ -345,7 +379,7 @@ func (ws *WaveSpawner) spawnConvoyFormation(wave int) []*entities.Enemy {
 // selectFormationEnemyType chooses enemy type suitable for formations
 func (ws *WaveSpawner) selectFormationEnemyType(wave int) entities.EnemyType {
 	// Formations use more organized enemy types (not splitters/bombers)
-	r := rand.Float64()
+	r := rng.Float64()
 
 	if wave <= 5 {
 		if r < 0.5 {
@@ -373,7 +407,7 @@ func (ws *WaveSpawner) selectFormationEnemyType(wave int) entities.EnemyType {
 
 // selectToughFormationEnemyType chooses tougher enemy for formation leaders
 func (ws *WaveSpawner) selectToughFormationEnemyType(wave int) entities.EnemyType {
-	r := rand.Float64()
+	r := rng.Float64()
 
 	if wave <= 8 {
 		if r < 0.5 {
@@ -12,7 +12,7 @@ import (
 type Star struct {
 	X, Y    float64
 	Size    float64
-	Speed   float64
+	Speed   float64 // Pixels per second
 	Bright  float64
 	Twinkle float64 // Pre-computed twinkle value to avoid per-frame math.Sin
 }
@@ -39,7 +39,7 @@ func NewStarField(width, height int) *StarField {
 			X:       x,
 			Y:       y,
 			Size:    rand.Float64()*1 + 0.5,
-			Speed:   0.3,
+			Speed:   18,
 			Bright:  rand.Float64()*0.3 + 0.2,
 			Twinkle: 0.8 + 0.2*math.Sin(x+y+100), // Pre-compute twinkle for layer 0
 		}
@@ -54,7 +54,7 @@ func NewStarField(width, height int) *StarField {
 			X:       x,
 			Y:       y,
 			Size:    rand.Float64()*1.5 + 1,
-			Speed:   0.8,
+			Speed:   48,
 			Bright:  rand.Float64()*0.4 + 0.4,
 			Twinkle: 0.8 + 0.2*math.Sin(x+y+200), // Pre-compute twinkle for layer 1
 		}
@@ -69,7 +69,7 @@ func NewStarField(width, height int) *StarField {
 			X:       x,
 			Y:       y,
 			Size:    rand.Float64()*2 + 1.5,
-			Speed:   1.5,
+			Speed:   90,
 			Bright:  rand.Float64()*0.3 + 0.7,
 			Twinkle: 0.8 + 0.2*math.Sin(x+y+300), // Pre-compute twinkle for layer 2
 		}
@@ -78,11 +78,12 @@ func NewStarField(width, height int) *StarField {
 	return sf
 }
 
-func (sf *StarField) Update() {
+// Update scrolls the stars by deltaTime seconds
+func (sf *StarField) Update(deltaTime float64) {
 	for l := range sf.layers {
 		layerOffset := float64((l + 1) * 100) // 100, 200, 300 for layers 0, 1, 2
 		for i := range sf.layers[l] {
-			sf.layers[l][i].Y += sf.layers[l][i].Speed
+			sf.layers[l][i].Y += sf.layers[l][i].Speed * deltaTime
 			if sf.layers[l][i].Y > float64(sf.height) {
 				sf.layers[l][i].Y = 0
 				sf.layers[l][i].X = rand.Float64() * float64(sf.width)
@@ -0,0 +1,341 @@
+package systems
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Storage persists the game's data files. Names are slash-separated paths
+// relative to the storage root, such as "leaderboard.json".
+type Storage interface {
+	// Read returns the contents of a file; a missing file gives an error
+	// matching fs.ErrNotExist
+	Read(name string) ([]byte, error)
+	// Write replaces a file atomically, creating parent directories as needed
+	Write(name string, data []byte) error
+	// Rename moves a file, replacing any file at the destination
+	Rename(from, to string) error
+	// Remove deletes a file; removing a missing file is not an error
+	Remove(name string) error
+	// List returns the names of the files directly inside dir, sorted
+	List(dir string) ([]string, error)
+}
+
+// portableMarker is the file next to the executable that turns on portable mode
+const portableMarker = "portable"
+
+// dataDirName is the directory data is kept in by portable and legacy installs
+const dataDirName = "data"
+
+// legacyDataFiles are files older versions kept in ./data; one of them marks
+// a ./data directory in the working directory as the game's
+var legacyDataFiles = []string{"leaderboard.json", "achievements.json", SettingsFileName, profilesFileName}
+
+// legacyMigration copies the legacy data directory once per process
+var legacyMigration sync.Once
+
+// FileStorage keeps data files in a directory on disk
+type FileStorage struct {
+	Root string
+}
+
+// NewFileStorage creates a storage rooted at dir
+func NewFileStorage(dir string) *FileStorage {
+	return &FileStorage{Root: dir}
+}
+
+// DefaultDataDir returns the directory game data is kept in:
+//   - portable mode, a "data" directory next to the executable, when a
+//     "portable" file or an existing "data" directory sits beside it
+//   - ~/Library/Application Support/StellarSiege inside a macOS app bundle
+//   - $XDG_DATA_HOME/stellar-siege (default ~/.local/share) on Linux and BSD
+//   - %AppData%\StellarSiege on Windows, the user config directory elsewhere
+//
+// It falls back to ./data when none of these can be determined. On first
+// use of a per-user directory, a ./data directory left in the working
+// directory by an older version is copied into it.
+func DefaultDataDir() string {
+	dir := defaultDataDir()
+	if dir != dataDirName {
+		legacyMigration.Do(func() {
+			if err := migrateLegacyData(dataDirName, dir); err != nil {
+				log.Printf("Failed to copy data from %s to %s: %v", dataDirName, dir, err)
+			}
+		})
+	}
+	return dir
+}
+
+// defaultDataDir picks the data directory as described on DefaultDataDir
+func defaultDataDir() string {
+	exePath, err := os.Executable()
+	if err == nil {
+		exeDir := filepath.Dir(exePath)
+		if fileExists(filepath.Join(exeDir, portableMarker)) || fileExists(filepath.Join(exeDir, dataDirName)) {
+			return filepath.Join(exeDir, dataDirName)
+		}
+		if filepath.Base(exeDir) == "MacOS" {
+			if home, err := os.UserHomeDir(); err == nil {
+				return filepath.Join(home, "Library", "Application Support", "StellarSiege")
+			}
+		}
+	}
+
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd", "dragonfly":
+		if dir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+			return filepath.Join(dir, "stellar-siege")
+		}
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, ".local", "share", "stellar-siege")
+		}
+	default:
+		if dir, err := os.UserConfigDir(); err == nil {
+			return filepath.Join(dir, "StellarSiege")
+		}
+	}
+	return dataDirName
+}
+
+// migrateLegacyData copies the legacy data directory into dir when dir does
+// not exist yet and legacy holds the game's files. The copy is assembled
+// aside and renamed into place, so an interrupted copy is retried on the
+// next launch. The legacy directory is left as it was.
+func migrateLegacyData(legacy, dir string) error {
+	if fileExists(dir) || !isLegacyDataDir(legacy) {
+		return nil
+	}
+
+	tmp := dir + ".migrating"
+	if err := os.RemoveAll(tmp); err != nil {
+		return fmt.Errorf("failed to clear %s: %w", tmp, err)
+	}
+	defer os.RemoveAll(tmp) // No-op once renamed into place
+	if err := os.CopyFS(tmp, os.DirFS(legacy)); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return fmt.Errorf("failed to move %s into place: %w", dir, err)
+	}
+	log.Printf("Copied data from %s to %s; the old directory is no longer used", legacy, dir)
+	return nil
+}
+
+// isLegacyDataDir reports whether dir holds data files of an older version
+func isLegacyDataDir(dir string) bool {
+	for _, name := range legacyDataFiles {
+		if fileExists(filepath.Join(dir, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileExists reports whether a file or directory exists
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+// Path returns the filesystem path a file is stored at
+func (s *FileStorage) Path(name string) string {
+	return filepath.Join(s.Root, filepath.FromSlash(name))
+}
+
+// Read returns the contents of a file
+func (s *FileStorage) Read(name string) ([]byte, error) {
+	return os.ReadFile(s.Path(name))
+}
+
+// Write replaces a file by writing a temporary file and renaming it over
+// the original, so a crash leaves either the old or the new contents
+func (s *FileStorage) Write(name string, data []byte) error {
+	target := s.Path(name)
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(target)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed into place
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to flush %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", name, err)
+	}
+	return nil
+}
+
+// Rename moves a file
+func (s *FileStorage) Rename(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path(to)), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	return os.Rename(s.Path(from), s.Path(to))
+}
+
+// Remove deletes a file
+func (s *FileStorage) Remove(name string) error {
+	if err := os.Remove(s.Path(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// List returns the files directly inside dir
+func (s *FileStorage) List(dir string) ([]string, error) {
+	entries, err := os.ReadDir(s.Path(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, path.Join(dir, e.Name()))
+		}
+	}
+	return names, nil
+}
+
+// StoragePath describes where a file of store is kept: its filesystem path
+// for file storage, otherwise its name
+func StoragePath(store Storage, name string) string {
+	switch s := store.(type) {
+	case *FileStorage:
+		return s.Path(name)
+	case *SubStorage:
+		return StoragePath(s.base, path.Join(s.dir, name))
+	}
+	return name
+}
+
+// MemoryStorage keeps data files in memory, for tests and headless runs
+type MemoryStorage struct {
+	files map[string][]byte
+	mu    sync.RWMutex
+}
+
+// NewMemoryStorage creates an empty in-memory storage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{files: make(map[string][]byte)}
+}
+
+// Read returns a copy of a file's contents
+func (s *MemoryStorage) Read(name string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.files[path.Clean(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Write stores a copy of data
+func (s *MemoryStorage) Write(name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[path.Clean(name)] = append([]byte(nil), data...)
+	return nil
+}
+
+// Rename moves a file
+func (s *MemoryStorage) Rename(from, to string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.files[path.Clean(from)]
+	if !ok {
+		return &fs.PathError{Op: "rename", Path: from, Err: fs.ErrNotExist}
+	}
+	delete(s.files, path.Clean(from))
+	s.files[path.Clean(to)] = data
+	return nil
+}
+
+// Remove deletes a file
+func (s *MemoryStorage) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, path.Clean(name))
+	return nil
+}
+
+// List returns the files directly inside dir
+func (s *MemoryStorage) List(dir string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	dir = path.Clean(dir)
+	var names []string
+	for name := range s.files {
+		if path.Dir(name) == dir {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// SubStorage is a view of a directory inside another storage
+type SubStorage struct {
+	base Storage
+	dir  string
+}
+
+// NewSubStorage creates a storage whose files live in dir of base
+func NewSubStorage(base Storage, dir string) *SubStorage {
+	return &SubStorage{base: base, dir: path.Clean(dir)}
+}
+
+// Read returns the contents of a file
+func (s *SubStorage) Read(name string) ([]byte, error) {
+	return s.base.Read(path.Join(s.dir, name))
+}
+
+// Write replaces a file
+func (s *SubStorage) Write(name string, data []byte) error {
+	return s.base.Write(path.Join(s.dir, name), data)
+}
+
+// Rename moves a file within the directory
+func (s *SubStorage) Rename(from, to string) error {
+	return s.base.Rename(path.Join(s.dir, from), path.Join(s.dir, to))
+}
+
+// Remove deletes a file
+func (s *SubStorage) Remove(name string) error {
+	return s.base.Remove(path.Join(s.dir, name))
+}
+
+// List returns the files directly inside dir, relative to the sub-storage
+func (s *SubStorage) List(dir string) ([]string, error) {
+	names, err := s.base.List(path.Join(s.dir, dir))
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range names {
+		names[i] = strings.TrimPrefix(name, s.dir+"/")
+	}
+	return names, nil
+}
@@ -0,0 +1,122 @@
+package systems
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStorageImplementations(t *testing.T) {
+	stores := map[string]Storage{
+		"file":   NewFileStorage(t.TempDir()),
+		"memory": NewMemoryStorage(),
+		"sub":    NewSubStorage(NewMemoryStorage(), "profiles/pilot1"),
+	}
+	for kind, store := range stores {
+		t.Run(kind, func(t *testing.T) {
+			if _, err := store.Read("missing.json"); !errors.Is(err, fs.ErrNotExist) || !os.IsNotExist(err) {
+				t.Fatalf("reading a missing file gave %v", err)
+			}
+
+			if err := store.Write("replays/a.json", []byte("one")); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Write("replays/a.json", []byte("two")); err != nil {
+				t.Fatal(err)
+			}
+			if data, err := store.Read("replays/a.json"); err != nil || string(data) != "two" {
+				t.Fatalf("read %q, %v after overwrite", data, err)
+			}
+
+			if err := store.Rename("replays/a.json", "replays/b.json"); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Write("top.json", []byte("{}")); err != nil {
+				t.Fatal(err)
+			}
+			names, err := store.List("replays")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []string{"replays/b.json"}; !reflect.DeepEqual(names, want) {
+				t.Errorf("List(replays) = %v, want %v", names, want)
+			}
+
+			if err := store.Remove("replays/b.json"); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Remove("replays/b.json"); err != nil {
+				t.Errorf("removing a missing file: %v", err)
+			}
+			if _, err := store.Read("replays/b.json"); !os.IsNotExist(err) {
+				t.Errorf("removed file still readable: %v", err)
+			}
+		})
+	}
+}
+
+func TestSettingsRoundTrip(t *testing.T) {
+	store := NewMemoryStorage()
+	if s, err := LoadSettings(store); err != nil || s != DefaultSettings() {
+		t.Fatalf("LoadSettings on empty storage = %+v, %v", s, err)
+	}
+	want := Settings{SoundEnabled: false, Volume: 0.25, Difficulty: 2}
+	if err := SaveSettings(store, want); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := LoadSettings(store); err != nil || s != want {
+		t.Fatalf("LoadSettings = %+v, %v, want %+v", s, err, want)
+	}
+}
+
+func TestMigrateLegacyData(t *testing.T) {
+	legacy := filepath.Join(t.TempDir(), "data")
+	legacyStore := NewFileStorage(legacy)
+	for name, data := range map[string]string{"leaderboard.json": "[1]", "profiles/pilot1/settings.json": "{}"} {
+		if err := legacyStore.Write(name, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first launch copies every file, keeping the legacy directory
+	dir := filepath.Join(t.TempDir(), "stellar-siege")
+	if err := migrateLegacyData(legacy, dir); err != nil {
+		t.Fatal(err)
+	}
+	store := NewFileStorage(dir)
+	for name, want := range map[string]string{"leaderboard.json": "[1]", "profiles/pilot1/settings.json": "{}"} {
+		if data, err := store.Read(name); err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v after the copy, want %q", name, data, err, want)
+		}
+	}
+	if _, err := legacyStore.Read("leaderboard.json"); err != nil {
+		t.Errorf("legacy file gone: %v", err)
+	}
+	if fileExists(dir + ".migrating") {
+		t.Error("staging directory left behind")
+	}
+
+	// Later launches leave the new directory alone
+	if err := legacyStore.Write("leaderboard.json", []byte("[2]")); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrateLegacyData(legacy, dir); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := store.Read("leaderboard.json"); string(data) != "[1]" {
+		t.Errorf("second launch copied again: %q", data)
+	}
+
+	// A data directory that is not the game's is not copied
+	other := t.TempDir()
+	if err := os.WriteFile(filepath.Join(other, "notes.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	fresh := filepath.Join(t.TempDir(), "fresh")
+	if err := migrateLegacyData(other, fresh); err != nil || fileExists(fresh) {
+		t.Errorf("copied an unrelated directory: %v", err)
+	}
+}
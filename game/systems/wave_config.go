@@ -1,9 +1,8 @@
 package systems
 
 import (
-	"math/rand"
-
 	"stellar-siege/game/entities"
+	"stellar-siege/game/rng"
 )
 
 // EnemyProbability defines the probability of spawning a specific enemy type
@@ -107,7 +106,7 @@ func getWaveConfig(wave int) *WaveEnemyConfig {
 // selectEnemyType selects an enemy type based on wave configuration
 func selectEnemyType(wave int) entities.EnemyType {
 	config := getWaveConfig(wave)
-	r := rand.Float64()
+	r := rng.Float64()
 
 	for _, prob := range config.Probabilities {
 		if r < prob.Probability {
@@ -126,7 +125,7 @@ func getSpawnCount(wave int) int {
 			if config.MinCount == config.MaxCount {
 				return config.MinCount
 			}
-			return config.MinCount + rand.Intn(config.MaxCount-config.MinCount+1)
+			return config.MinCount + rng.Intn(config.MaxCount-config.MinCount+1)
 		}
 	}
 	// Fallback
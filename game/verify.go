@@ -0,0 +1,108 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"stellar-siege/game/rng"
+	"stellar-siege/game/systems"
+)
+
+// ReplayResult is the outcome of re-simulating a replay
+type ReplayResult struct {
+	Score int64
+	Wave  int
+	Ticks int
+	Ended bool // The player was destroyed within the recorded ticks
+}
+
+// SimulateReplay re-runs a replay headlessly, feeding the recorded input
+// frames to a fresh game seeded like the original run
+func SimulateReplay(replay *systems.Replay) ReplayResult {
+	// Keep whatever source was active before so a running game is unaffected
+	prev := rng.Current()
+	defer rng.Use(prev)
+
+	g := NewHeadlessCoopGame(replay.Seed, DifficultyMode(replay.Difficulty), max(replay.Pilots, 1))
+	for _, run := range replay.Inputs {
+		for i := 0; i < run.Count; i++ {
+			if !g.Step(run.Frame) {
+				// Run ended; any remaining frames are left unplayed so the
+				// tick count exposes replays padded past the player's death
+				return ReplayResult{Score: g.Score(), Wave: g.Wave(), Ticks: g.Tick(), Ended: true}
+			}
+		}
+	}
+	return ReplayResult{Score: g.Score(), Wave: g.Wave(), Ticks: g.Tick(), Ended: false}
+}
+
+// VerifyReplay re-simulates a replay and checks that it reproduces the score,
+// wave and length it claims. The returned record is unsigned; the verifier
+// signs it with its own key before publishing it.
+func VerifyReplay(replay *systems.Replay) *systems.VerificationRecord {
+	record := &systems.VerificationRecord{
+		ReplayID:   replay.ID(),
+		Score:      replay.Score,
+		Wave:       replay.Wave,
+		Ticks:      replay.Ticks,
+		Verifier:   Version,
+		VerifiedAt: time.Now().UTC(),
+	}
+
+	if err := replay.Validate(); err != nil {
+		record.Reason = err.Error()
+		return record
+	}
+	if replay.GameVersion != Version {
+		record.Reason = fmt.Sprintf("replay recorded with version %s, verifier runs %s", replay.GameVersion, Version)
+		return record
+	}
+	if !replay.Finished {
+		record.Reason = "replay does not contain a finished run"
+		return record
+	}
+	if replay.Difficulty < int(DifficultyEasy) || replay.Difficulty > int(DifficultyHard) {
+		record.Reason = fmt.Sprintf("unknown difficulty %d", replay.Difficulty)
+		return record
+	}
+
+	result := SimulateReplay(replay)
+	switch {
+	case !result.Ended:
+		record.Reason = fmt.Sprintf("run did not end after %d ticks", result.Ticks)
+	case result.Ticks != replay.Ticks:
+		record.Reason = fmt.Sprintf("run ended after %d ticks, replay claims %d", result.Ticks, replay.Ticks)
+	case result.Score != replay.Score:
+		record.Reason = fmt.Sprintf("simulated score %d does not match claimed %d", result.Score, replay.Score)
+	case result.Wave != replay.Wave:
+		record.Reason = fmt.Sprintf("simulated wave %d does not match claimed %d", result.Wave, replay.Wave)
+	default:
+		record.Verified = true
+	}
+	return record
+}
+
+// VerifyEntry verifies the replay of an online leaderboard entry, and that
+// the entry claims what the replay proves: its score, wave and difficulty,
+// and its seed and version when the entry carries them
+func VerifyEntry(entry *systems.OnlineScore, replay *systems.Replay) *systems.VerificationRecord {
+	record := VerifyReplay(replay)
+	if !record.Verified {
+		return record
+	}
+	switch difficulty := DifficultyLabel(DifficultyMode(replay.Difficulty)); {
+	case entry.Score != replay.Score || entry.Wave != replay.Wave:
+		record.Reason = fmt.Sprintf("entry claims %d points on wave %d, replay reached %d on wave %d",
+			entry.Score, entry.Wave, replay.Score, replay.Wave)
+	case entry.Difficulty != difficulty:
+		record.Reason = fmt.Sprintf("entry claims difficulty %s, replay was played on %s", entry.Difficulty, difficulty)
+	case entry.Seed != 0 && entry.Seed != replay.Seed:
+		record.Reason = fmt.Sprintf("entry claims seed %d, replay has seed %d", entry.Seed, replay.Seed)
+	case entry.Version != "" && entry.Version != replay.GameVersion:
+		record.Reason = fmt.Sprintf("entry claims version %s, replay was recorded with %s", entry.Version, replay.GameVersion)
+	default:
+		return record
+	}
+	record.Verified = false
+	return record
+}
@@ -0,0 +1,127 @@
+package game
+
+import (
+	"testing"
+
+	"stellar-siege/game/entities"
+	"stellar-siege/game/systems"
+)
+
+// scriptedInput weaves the ship left and right while firing
+func scriptedInput(tick int) entities.InputFrame {
+	frame := entities.InputShoot
+	if (tick/90)%2 == 0 {
+		frame |= entities.InputLeft
+	} else {
+		frame |= entities.InputRight
+	}
+	return frame
+}
+
+// playUntilDeath runs a headless game with idle input until the player dies
+func playUntilDeath(t *testing.T, seed int64) *Game {
+	t.Helper()
+	g := NewHeadlessGame(seed, DifficultyHard)
+	for i := 0; i < 60*60*20; i++ {
+		if !g.Step(0) {
+			return g
+		}
+	}
+	t.Fatalf("player survived %d ticks without input", g.Tick())
+	return nil
+}
+
+func TestHeadlessRunIsDeterministic(t *testing.T) {
+	run := func() *Game {
+		g := NewHeadlessGame(42, DifficultyNormal)
+		for i := 0; i < 60*45 && g.Step(scriptedInput(i)); i++ {
+		}
+		return g
+	}
+
+	a, b := run(), run()
+	if a.Score() != b.Score() || a.Wave() != b.Wave() || a.Tick() != b.Tick() {
+		t.Fatalf("runs diverged: score %d/%d, wave %d/%d, ticks %d/%d",
+			a.Score(), b.Score(), a.Wave(), b.Wave(), a.Tick(), b.Tick())
+	}
+	if a.player.X != b.player.X || a.player.Y != b.player.Y || a.player.Health != b.player.Health {
+		t.Fatalf("player state diverged: (%v,%v,%d) vs (%v,%v,%d)",
+			a.player.X, a.player.Y, a.player.Health, b.player.X, b.player.Y, b.player.Health)
+	}
+	if a.Score() == 0 {
+		t.Error("scripted run scored no points; test input is not exercising the simulation")
+	}
+}
+
+func TestVerifyReplayAcceptsRecordedRun(t *testing.T) {
+	g := playUntilDeath(t, 7)
+
+	replay := g.Replay()
+	if !replay.Finished {
+		t.Fatal("replay was not finished when the run ended")
+	}
+	if replay.Ticks != g.Tick() {
+		t.Fatalf("replay recorded %d ticks, game ran %d", replay.Ticks, g.Tick())
+	}
+
+	record := VerifyReplay(replay)
+	if !record.Verified {
+		t.Fatalf("recorded run failed verification: %s", record.Reason)
+	}
+}
+
+func TestVerifyReplayRejectsTamperedScore(t *testing.T) {
+	replay := playUntilDeath(t, 7).Replay()
+	replay.Score += 5000
+
+	record := VerifyReplay(replay)
+	if record.Verified {
+		t.Fatal("replay with inflated score was verified")
+	}
+}
+
+func TestVerifyReplayRejectsPaddedInput(t *testing.T) {
+	replay := playUntilDeath(t, 7).Replay()
+	replay.Record(entities.InputShoot)
+
+	record := VerifyReplay(replay)
+	if record.Verified {
+		t.Fatal("replay with input past the end of the run was verified")
+	}
+}
+
+func TestVerifyEntryChecksItsClaims(t *testing.T) {
+	replay := playUntilDeath(t, 7).Replay()
+	entry := func() *systems.OnlineScore {
+		return &systems.OnlineScore{
+			Score: replay.Score, Wave: replay.Wave, Difficulty: DifficultyLabel(DifficultyHard),
+			Seed: replay.Seed, Version: replay.GameVersion,
+		}
+	}
+	if record := VerifyEntry(entry(), replay); !record.Verified {
+		t.Fatalf("entry matching its replay failed verification: %s", record.Reason)
+	}
+
+	// Entries from clients that sent no seed or version are still checked
+	old := entry()
+	old.Seed, old.Version = 0, ""
+	if record := VerifyEntry(old, replay); !record.Verified {
+		t.Errorf("entry without seed and version failed verification: %s", record.Reason)
+	}
+
+	mislabelled := entry()
+	mislabelled.Difficulty = DifficultyLabel(DifficultyEasy)
+	otherSeed := entry()
+	otherSeed.Seed++
+	otherVersion := entry()
+	otherVersion.Version = "0.9.0"
+	for name, e := range map[string]*systems.OnlineScore{
+		"mislabelled difficulty": mislabelled,
+		"another seed":           otherSeed,
+		"another version":        otherVersion,
+	} {
+		if record := VerifyEntry(e, replay); record.Verified {
+			t.Errorf("entry with %s was verified", name)
+		}
+	}
+}
@@ -18,12 +18,27 @@ import (
 )
 
 var (
-	cpuprofile = flag.String("cpuprofile", "", "write cpu profile to file")
-	memprofile = flag.String("memprofile", "", "write memory profile to file")
-	pprofAddr  = flag.String("pprof", "", "enable pprof server on address (e.g., :6060)")
+	cpuprofile   = flag.String("cpuprofile", "", "write cpu profile to file")
+	memprofile   = flag.String("memprofile", "", "write memory profile to file")
+	pprofAddr    = flag.String("pprof", "", "enable pprof server on address (e.g., :6060)")
+	spectateAddr = flag.String("spectate", "", "serve a live view of every run for other screens on address (e.g., :8090)")
 )
 
 func main() {
+	// Subcommands run without opening a window
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "verify":
+			os.Exit(runVerify(os.Args[2:]))
+		case "balance":
+			os.Exit(runBalance(os.Args[2:]))
+		case "bench":
+			os.Exit(runBench(os.Args[2:]))
+		case "profile":
+			os.Exit(runProfile(os.Args[2:]))
+		}
+	}
+
 	flag.Parse()
 
 	// Start CPU profiling if requested
@@ -70,9 +85,23 @@ func main() {
 
 	g := game.NewGame()
 
+	// Game metrics share the pprof server's default mux
+	if *pprofAddr != "" {
+		http.Handle("/metrics", g.MetricsHandler())
+		http.Handle("/state", g.StateHandler())
+	}
+
+	// Teammates watch in a browser at http://<this machine>:<port>/
+	if *spectateAddr != "" {
+		if err := g.StartSpectatorServer(*spectateAddr); err != nil {
+			log.Printf("Failed to start spectator server: %v", err)
+		}
+	}
+
 	ebiten.SetWindowSize(game.ScreenWidth, game.ScreenHeight)
 	ebiten.SetWindowTitle("STELLAR SIEGE - Defend the Frontier")
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	ebiten.SetWindowClosingHandled(true) // Lets the game suspend a run in progress
 
 	if err := ebiten.RunGame(g); err != nil {
 		log.Fatal(err)
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"stellar-siege/game"
+	"stellar-siege/game/systems"
+)
+
+// runProfile implements the "profile" subcommand. It lists the pilot
+// profiles of this install and moves them between installs as archives.
+func runProfile(args []string) int {
+	fs := flag.NewFlagSet("profile", flag.ExitOnError)
+	dataDir := fs.String("data", systems.DefaultDataDir(), "data directory holding the profiles")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: stellar-siege profile [-data dir] list")
+		fmt.Fprintln(fs.Output(), "       stellar-siege profile [-data dir] export <pilot> <file"+systems.ProfileArchiveExt+">")
+		fmt.Fprintln(fs.Output(), "       stellar-siege profile [-data dir] import <file"+systems.ProfileArchiveExt+">")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	profiles, err := systems.NewProfileManager(systems.NewFileStorage(*dataDir), game.ProfileFileNames)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "profile:", err)
+		return 1
+	}
+
+	switch {
+	case fs.NArg() == 1 && fs.Arg(0) == "list":
+		active := profiles.Active().ID
+		for _, p := range profiles.Profiles() {
+			marker := " "
+			if p.ID == active {
+				marker = "*"
+			}
+			fmt.Printf("%s %-16s created %s\n", marker, p.Name, p.Created.Format("2006-01-02"))
+		}
+		return 0
+
+	case fs.NArg() == 3 && fs.Arg(0) == "export":
+		p, ok := findProfile(profiles, fs.Arg(1))
+		if !ok {
+			fmt.Fprintf(os.Stderr, "profile: no pilot named %q\n", fs.Arg(1))
+			return 1
+		}
+		data, err := profiles.Export(p.ID)
+		if err == nil {
+			err = os.WriteFile(fs.Arg(2), data, 0644)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "profile:", err)
+			return 1
+		}
+		fmt.Printf("Exported %s to %s\n", p.Name, fs.Arg(2))
+		return 0
+
+	case fs.NArg() == 2 && fs.Arg(0) == "import":
+		data, err := os.ReadFile(fs.Arg(1))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "profile:", err)
+			return 1
+		}
+		p, err := profiles.Import(data)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "profile:", err)
+			return 1
+		}
+		fmt.Printf("Imported %s\n", p.Name)
+		return 0
+	}
+
+	fs.Usage()
+	return 2
+}
+
+// findProfile returns the pilot with the given name, ignoring case
+func findProfile(profiles *systems.ProfileManager, name string) (systems.Profile, bool) {
+	for _, p := range profiles.Profiles() {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return systems.Profile{}, false
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"stellar-siege/game"
+	"stellar-siege/game/systems"
+
+	"github.com/joho/godotenv"
+)
+
+// runVerify implements the "verify" subcommand. It re-simulates replays
+// headlessly and prints a signed verification record for each one. With
+// -online it verifies every unverified entry on the online leaderboard, the
+// gist or the leaderboard server, and stores the records next to the entries.
+func runVerify(args []string) int {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	keyPath := fs.String("key", "verifier.key", "ed25519 key used to sign verification records (created if missing)")
+	useOnline := fs.Bool("online", false, "verify pending entries on the online leaderboard")
+	fs.BoolVar(useOnline, "gist", false, "same as -online")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: stellar-siege verify [-key file] replay.json...")
+		fmt.Fprintln(fs.Output(), "       stellar-siege verify [-key file] -online")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	key, err := systems.LoadVerifierKey(*keyPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "verify:", err)
+		return 1
+	}
+
+	if *useOnline {
+		return verifyOnlineEntries(key)
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	status := 0
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	for _, path := range fs.Args() {
+		replay, err := systems.LoadReplay(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "verify: %s: %v\n", path, err)
+			status = 1
+			continue
+		}
+
+		record := game.VerifyReplay(replay)
+		record.Sign(key)
+		if !record.Verified {
+			status = 1
+		}
+		encoder.Encode(record)
+	}
+	return status
+}
+
+// verifyOnlineEntries verifies every leaderboard entry that has a replay
+// but no verification record yet
+func verifyOnlineEntries(key ed25519.PrivateKey) int {
+	// Load .env file for the leaderboard configuration, like the game does
+	_ = godotenv.Load()
+
+	config, _ := systems.LoadGistConfig("")
+	leaderboard := game.NewOnlineLeaderboard(config, nil)
+	if leaderboard == nil {
+		fmt.Fprintln(os.Stderr, "verify: LEADERBOARD_URL, or GIST_ID and GH_GIST_TOKEN, must be configured")
+		return 1
+	}
+
+	scores, err := leaderboard.GetAllScores()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "verify:", err)
+		return 1
+	}
+
+	var records []*systems.VerificationRecord
+	for _, entry := range scores {
+		if entry.ReplayID == "" || entry.Verification != nil {
+			continue
+		}
+
+		var record *systems.VerificationRecord
+		replay, err := leaderboard.FetchReplay(entry.ReplayID)
+		if err != nil {
+			record = &systems.VerificationRecord{ReplayID: entry.ReplayID, Verifier: game.Version, VerifiedAt: time.Now().UTC(), Reason: err.Error()}
+		} else {
+			record = game.VerifyEntry(&entry, replay)
+		}
+		record.Sign(key)
+		records = append(records, record)
+
+		status := "VERIFIED"
+		if !record.Verified {
+			status = "REJECTED: " + record.Reason
+		}
+		fmt.Printf("%-12s %10d  %s\n", entry.PlayerName, entry.Score, status)
+	}
+
+	if len(records) == 0 {
+		fmt.Println("No pending entries")
+		return 0
+	}
+	if err := leaderboard.RecordVerifications(records); err != nil {
+		fmt.Fprintln(os.Stderr, "verify: failed to store verification records:", err)
+		return 1
+	}
+	return 0
+}